@@ -0,0 +1,325 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Easing maps the elapsed part t of an animation, from 0 to 1, to the part
+// of the change made at that time.
+type Easing func(t float64) float64
+
+// EaseLinear makes the change at a constant pace.
+func EaseLinear(t float64) float64 { return t }
+
+// EaseInQuad starts slowly and speeds up.
+func EaseInQuad(t float64) float64 { return t * t }
+
+// EaseOutQuad starts fast and slows down.
+func EaseOutQuad(t float64) float64 { return t * (2 - t) }
+
+// EaseInOutQuad speeds up then slows down.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseOutCubic starts faster than EaseOutQuad and slows down more.
+func EaseOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// AnimationFrame is the interval of the frames of the animations, each
+// one stepping them all and re-rendering their widgets once.
+var AnimationFrame = 50 * time.Millisecond
+
+// Animation is a change over time started by Animate, Flash or
+// AnimateFunc. Its frames come from a timer merged into DefaultEvtStream,
+// so they are drawn while Loop runs.
+type Animation struct {
+	ws     []Bufferer // widgets re-rendered by the frames
+	key    string     // property animated, a later animation of which replaces this one
+	step   func(p float64)
+	settle bool // step(1) when stopped before the end
+	start  time.Time
+	du     time.Duration
+	ease   Easing
+	done   chan struct{}
+	once   sync.Once
+}
+
+// running animations and the path of their frame events, "" until the
+// first one.
+var animations = struct {
+	sync.Mutex
+	running []*Animation
+	path    string
+}{}
+
+// AnimateFunc calls step at every frame for du with the eased part of the
+// animation done, from 0 to 1 at the last frame, and re-renders ws after
+// it. step runs under the render lock like the fn of Update, so it must
+// not call TermWidth, TermHeight, Viewport, Render or Update, which would
+// deadlock. A nil ease is EaseLinear.
+/*
+  // slide the pane in from the right on a tab switch
+  x, w := p.X, termui.TermWidth()
+  termui.AnimateFunc(300*time.Millisecond, termui.EaseOutQuad, func(t float64) {
+      p.X = x + int(float64(w-x)*(1-t))
+  }, p)
+*/
+func AnimateFunc(du time.Duration, ease Easing, step func(p float64), ws ...Bufferer) *Animation {
+	return startAnimation(&Animation{ws: ws, step: step, du: du, ease: ease})
+}
+
+// Animate changes the numeric field property of widget w, e.g. "Percent"
+// of a Gauge or "X" of any widget, from its value to target over du, by
+// the easing ease, re-rendering w at every frame. It replaces a running
+// animation of the same property of w, starting from where it left it.
+/*
+  g.Percent = 20
+  termui.Animate(g, "Percent", 80, time.Second, termui.EaseOutCubic)
+*/
+func Animate(w Bufferer, property string, target float64, du time.Duration, ease Easing) (*Animation, error) {
+	f, err := animField(w, property)
+	if err != nil {
+		return nil, err
+	}
+	blk := blockOf(w)
+	blk.RLock()
+	from := fieldFloat(f)
+	blk.RUnlock()
+
+	a := &Animation{ws: []Bufferer{w}, key: animKey(w, property), du: du, ease: ease}
+	a.step = func(p float64) {
+		blk.Lock()
+		setFieldFloat(f, from+(target-from)*p)
+		blk.Unlock()
+	}
+	return startAnimation(a), nil
+}
+
+// Flash switches the attribute field property of w, e.g. "BorderFg", to on
+// and back n times every interval, then leaves it as it was, to draw the
+// eye to w.
+/*
+  termui.Flash(p, "BorderFg", termui.ColorRed|termui.AttrBold, 3, 200*time.Millisecond)
+*/
+func Flash(w Bufferer, property string, on Attribute, n int, interval time.Duration) (*Animation, error) {
+	f, err := animField(w, property)
+	if err != nil {
+		return nil, err
+	}
+	if f.Type() != reflect.TypeOf(on) {
+		return nil, fmt.Errorf("termui: %s is not an Attribute", property)
+	}
+	blk := blockOf(w)
+	blk.RLock()
+	off := Attribute(f.Uint())
+	blk.RUnlock()
+
+	phases := float64(2 * n)
+	a := &Animation{ws: []Bufferer{w}, key: animKey(w, property), settle: true,
+		du: time.Duration(2*n) * interval}
+	a.step = func(p float64) {
+		v := off
+		if p < 1 && int(p*phases)%2 == 0 {
+			v = on
+		}
+		blk.Lock()
+		f.SetUint(uint64(v))
+		blk.Unlock()
+	}
+	return startAnimation(a), nil
+}
+
+// blockOf returns the Block of w, a throwaway one if it has none.
+func blockOf(w Bufferer) *Block {
+	if b, ok := w.(blocker); ok {
+		return b.block()
+	}
+	return &Block{}
+}
+
+func animKey(w Bufferer, property string) string {
+	return fmt.Sprintf("%p.%s", w, property)
+}
+
+// animField returns the settable numeric field property of w, which must
+// be a pointer to a struct.
+func animField(w Bufferer, property string) (reflect.Value, error) {
+	v := reflect.ValueOf(w)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("termui: cannot animate a %T", w)
+	}
+	f := v.Elem().FieldByName(property)
+	if !f.IsValid() || !f.CanSet() {
+		return reflect.Value{}, fmt.Errorf("termui: %T has no field %s", w, property)
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return f, nil
+	}
+	return reflect.Value{}, fmt.Errorf("termui: %T.%s is not a number", w, property)
+}
+
+func fieldFloat(f reflect.Value) float64 {
+	switch f.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return f.Float()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(f.Uint())
+	}
+	return float64(f.Int())
+}
+
+func setFieldFloat(f reflect.Value, x float64) {
+	switch f.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f.SetFloat(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if x < 0 {
+			x = 0
+		}
+		f.SetUint(uint64(math.Round(x)))
+	default:
+		f.SetInt(int64(math.Round(x)))
+	}
+}
+
+// startAnimation adds a to the running animations, replacing the one of
+// the same key, and hooks the frames on the first one.
+func startAnimation(a *Animation) *Animation {
+	if a.ease == nil {
+		a.ease = EaseLinear
+	}
+	a.done = make(chan struct{})
+	a.start = now()
+
+	var old *Animation
+	animations.Lock()
+	for i, o := range animations.running {
+		if a.key != "" && o.key == a.key {
+			old = o
+			animations.running = append(animations.running[:i], animations.running[i+1:]...)
+			break
+		}
+	}
+	animations.running = append(animations.running, a)
+	if animations.path == "" {
+		animations.path = animTimer(AnimationFrame)
+		Handle(animations.path, func(Event) { stepAnimations(now()) })
+	}
+	animations.Unlock()
+
+	if old != nil {
+		old.end()
+	}
+	return a
+}
+
+// stepAnimations draws the frame of the running animations at t, ending
+// those it completes.
+func stepAnimations(t time.Time) {
+	animations.Lock()
+	running := append([]*Animation{}, animations.running...)
+	animations.Unlock()
+	if len(running) == 0 {
+		return
+	}
+
+	// widgets animated several times are drawn once, a Bufferer that is no
+	// pointer, maybe unhashable, every time
+	var ws []Bufferer
+	seen := make(map[Bufferer]bool)
+	var over []*Animation
+	for _, a := range running {
+		for _, w := range a.ws {
+			if reflect.ValueOf(w).Kind() == reflect.Ptr {
+				if seen[w] {
+					continue
+				}
+				seen[w] = true
+			}
+			ws = append(ws, w)
+		}
+	}
+	Update(func() {
+		for _, a := range running {
+			p := 1.0
+			if a.du > 0 {
+				p = float64(t.Sub(a.start)) / float64(a.du)
+			}
+			if p >= 1 {
+				a.step(1)
+				over = append(over, a)
+				continue
+			}
+			if p < 0 {
+				p = 0
+			}
+			a.step(a.ease(p))
+		}
+	}, ws...)
+
+	for _, a := range over {
+		a.remove()
+		a.once.Do(func() { close(a.done) })
+	}
+}
+
+// remove takes a out of the running animations and reports whether it was
+// running.
+func (a *Animation) remove() bool {
+	animations.Lock()
+	defer animations.Unlock()
+	for i, o := range animations.running {
+		if o == a {
+			animations.running = append(animations.running[:i], animations.running[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// end finishes a stopped before its end.
+func (a *Animation) end() {
+	if a.settle {
+		Update(func() { a.step(1) }, a.ws...)
+	}
+	a.once.Do(func() { close(a.done) })
+}
+
+// Stop ends a where it is, a Flash leaving its attribute as it was.
+func (a *Animation) Stop() {
+	if a.remove() {
+		a.end()
+	}
+}
+
+// Running reports whether a has frames to come.
+func (a *Animation) Running() bool {
+	select {
+	case <-a.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Done returns a channel closed once a ended or was stopped.
+func (a *Animation) Done() <-chan struct{} {
+	return a.done
+}
@@ -0,0 +1,124 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEasing(t *testing.T) {
+	for name, e := range map[string]Easing{
+		"linear": EaseLinear, "in": EaseInQuad, "out": EaseOutQuad,
+		"inout": EaseInOutQuad, "cubic": EaseOutCubic,
+	} {
+		if e(0) != 0 || e(1) != 1 {
+			t.Errorf("%s: expected 0 and 1 at the ends, got %v %v", name, e(0), e(1))
+		}
+	}
+	if EaseInQuad(0.5) >= 0.5 || EaseOutQuad(0.5) <= 0.5 {
+		t.Error("expected ease in to lag and ease out to lead")
+	}
+}
+
+func TestAnimate(t *testing.T) {
+	fb, done := useFakeBackend(20, 5)
+	defer done()
+
+	g := NewGauge()
+	g.Width, g.Height = 20, 3
+	g.Percent = 20
+	a, err := Animate(g, "Percent", 80, time.Second, EaseLinear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stepAnimations(a.start.Add(250 * time.Millisecond))
+	if g.Percent != 35 || fb.flushes != 1 {
+		t.Errorf("expected a quarter of the way rendered, got %d", g.Percent)
+	}
+
+	// a new animation of the property takes over from there
+	b, _ := Animate(g, "Percent", 0, time.Second, EaseLinear)
+	if a.Running() || !b.Running() {
+		t.Fatal("expected the first animation replaced")
+	}
+	stepAnimations(b.start.Add(500 * time.Millisecond))
+	if g.Percent != 18 {
+		t.Errorf("expected half way from 35 to 0, got %d", g.Percent)
+	}
+	stepAnimations(b.start.Add(2 * time.Second))
+	if g.Percent != 0 || b.Running() {
+		t.Errorf("expected the target reached and the animation over, got %d", g.Percent)
+	}
+	select {
+	case <-b.Done():
+	default:
+		t.Error("expected Done closed")
+	}
+
+	if _, err := Animate(g, "Label", 1, time.Second, nil); err == nil {
+		t.Error("expected an error for a field that is not a number")
+	}
+	if _, err := Animate(g, "nope", 1, time.Second, nil); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestFlash(t *testing.T) {
+	_, done := useFakeBackend(20, 5)
+	defer done()
+
+	p := NewPar("alert")
+	p.Width, p.Height = 10, 3
+	off := p.BorderFg
+	a, err := Flash(p, "BorderFg", ColorRed, 2, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []Attribute{ColorRed, off, ColorRed, off} {
+		stepAnimations(a.start.Add(time.Duration(i)*100*time.Millisecond + time.Millisecond))
+		if p.BorderFg != want {
+			t.Errorf("phase %d: expected %v, got %v", i, want, p.BorderFg)
+		}
+	}
+	stepAnimations(a.start.Add(100 * time.Millisecond))
+	a.Stop()
+	if p.BorderFg != off || a.Running() {
+		t.Error("expected Stop to leave the border as it was")
+	}
+	if _, err := Flash(p, "Width", ColorRed, 1, time.Second); err == nil {
+		t.Error("expected an error for a field that is not an Attribute")
+	}
+}
+
+func TestAnimateFunc(t *testing.T) {
+	_, done := useFakeBackend(20, 5)
+	defer done()
+
+	var got []float64
+	a := AnimateFunc(time.Second, EaseInQuad, func(p float64) { got = append(got, p) })
+	stepAnimations(a.start.Add(500 * time.Millisecond))
+	a.Stop()
+	stepAnimations(a.start.Add(2 * time.Second))
+	if len(got) != 1 || got[0] != 0.25 || a.Running() {
+		t.Errorf("expected one eased step before Stop, got %v", got)
+	}
+}
+
+func TestAnimateUnhashable(t *testing.T) {
+	_, done := useFakeBackend(20, 5)
+	defer done()
+
+	p := NewPar("a")
+	p.Width, p.Height = 5, 3
+	f := Prerender(p)
+	var got []float64
+	a := AnimateFunc(time.Second, nil, func(v float64) { got = append(got, v) }, f, p, p)
+	defer a.Stop()
+	stepAnimations(a.start.Add(500 * time.Millisecond))
+	if len(got) != 1 {
+		t.Errorf("expected a frame drawing a PrerenderedFrame, got %v", got)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseANSI turns s, e.g. the coloured output of a command, into cells,
+// applying its SGR escape sequences (ESC [ ... m): bold, underline,
+// reverse and 16, 256 or 24-bit colours. fg and bg are the colours of plain
+// text and of the reset sequence. Other escape sequences and carriage
+// returns are dropped.
+func ParseANSI(s string, fg, bg Attribute) []Cell {
+	cs := []Cell{}
+	p := ansiParser{baseFg: fg, baseBg: bg, fg: fg, bg: bg}
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; r {
+		case '\x1b':
+			i = p.escape(rs, i)
+		case '\r':
+		default:
+			cs = append(cs, Cell{Ch: r, Fg: p.fg, Bg: p.bg})
+		}
+	}
+	return joinCells(cs)
+}
+
+type ansiParser struct {
+	baseFg, baseBg Attribute
+	fg, bg         Attribute
+}
+
+// escape handles the sequence starting with the ESC at rs[i] and returns
+// the index of its last rune.
+func (p *ansiParser) escape(rs []rune, i int) int {
+	if i+1 >= len(rs) {
+		return i
+	}
+	switch rs[i+1] {
+	case '[': // CSI: parameters and intermediates, then a final byte
+		j := i + 2
+		for j < len(rs) && (rs[j] < 0x40 || rs[j] > 0x7e) {
+			j++
+		}
+		if j >= len(rs) {
+			return len(rs) - 1
+		}
+		if rs[j] == 'm' {
+			p.sgr(string(rs[i+2 : j]))
+		}
+		return j
+	case ']': // OSC, ended by BEL or ESC \
+		for j := i + 2; j < len(rs); j++ {
+			if rs[j] == '\a' {
+				return j
+			}
+			if rs[j] == '\x1b' && j+1 < len(rs) && rs[j+1] == '\\' {
+				return j + 1
+			}
+		}
+		return len(rs) - 1
+	}
+	return i + 1
+}
+
+// sgr applies the parameters of a Select Graphic Rendition sequence.
+func (p *ansiParser) sgr(params string) {
+	ps := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	if len(ps) == 0 {
+		ps = []string{"0"}
+	}
+	ns := make([]int, len(ps))
+	for i, s := range ps {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return
+		}
+		ns[i] = n
+	}
+
+	for i := 0; i < len(ns); i++ {
+		switch n := ns[i]; {
+		case n == 0:
+			p.fg, p.bg = p.baseFg, p.baseBg
+		case n == 1:
+			p.fg |= AttrBold
+		case n == 4:
+			p.fg |= AttrUnderline
+		case n == 7:
+			p.fg |= AttrReverse
+		case n == 22:
+			p.fg &^= AttrBold
+		case n == 24:
+			p.fg &^= AttrUnderline
+		case n == 27:
+			p.fg &^= AttrReverse
+		case n >= 30 && n <= 37:
+			p.fg = p.fg&^attrColorMask | Attribute(n-30+1)
+		case n >= 90 && n <= 97:
+			p.fg = p.fg&^attrColorMask | Attribute(n-90+8+1)
+		case n == 39:
+			p.fg = p.fg&^attrColorMask | p.baseFg&attrColorMask
+		case n >= 40 && n <= 47:
+			p.bg = p.bg&^attrColorMask | Attribute(n-40+1)
+		case n >= 100 && n <= 107:
+			p.bg = p.bg&^attrColorMask | Attribute(n-100+8+1)
+		case n == 49:
+			p.bg = p.bg&^attrColorMask | p.baseBg&attrColorMask
+		case n == 38 || n == 48:
+			c, used, ok := ansiColor(ns[i+1:])
+			i += used
+			if !ok {
+				continue
+			}
+			if n == 38 {
+				p.fg = p.fg&^attrColorMask | c
+			} else {
+				p.bg = p.bg&^attrColorMask | c
+			}
+		}
+	}
+}
+
+// ansiColor reads the colour following a 38 or 48 parameter, either 5;n or
+// 2;r;g;b, and returns how many parameters it used.
+func ansiColor(ns []int) (c Attribute, used int, ok bool) {
+	if len(ns) == 0 {
+		return 0, 0, false
+	}
+	switch ns[0] {
+	case 5:
+		if len(ns) < 2 {
+			return 0, len(ns), false
+		}
+		if ns[1] < 0 || ns[1] > 255 {
+			return 0, 2, false
+		}
+		return Attribute(ns[1] + 1), 2, true
+	case 2:
+		if len(ns) < 4 {
+			return 0, len(ns), false
+		}
+		for _, n := range ns[1:4] {
+			if n < 0 || n > 255 {
+				return 0, 4, false
+			}
+		}
+		return ColorRGB24(uint8(ns[1]), uint8(ns[2]), uint8(ns[3])), 4, true
+	}
+	return 0, 1, false
+}
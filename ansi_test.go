@@ -0,0 +1,57 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseANSI(t *testing.T) {
+	s := "a\x1b[1;31mb\x1b[0mc\x1b[38;5;208;48;2;0;0;255md\x1b[39;49;4me\x1b[2Kf\x1b]0;title\ag\r\n\x1b[92mh\x1b[m"
+	cs := ParseANSI(s, ColorWhite, ColorBlack)
+
+	if got := CellsToStr(cs); got != "abcdefg\nh" {
+		t.Fatalf("escape sequences should be stripped, got %q", got)
+	}
+	want := []struct{ fg, bg Attribute }{
+		{ColorWhite, ColorBlack},
+		{ColorRed | AttrBold, ColorBlack},
+		{ColorWhite, ColorBlack},
+		{209, ColorRGB24(0, 0, 255)}, // palette entry 208, plus one
+		{ColorWhite | AttrUnderline, ColorBlack},
+		{ColorWhite | AttrUnderline, ColorBlack},
+		{ColorWhite | AttrUnderline, ColorBlack},
+	}
+	for i, w := range want {
+		if cs[i].Fg != w.fg || cs[i].Bg != w.bg {
+			t.Errorf("cell %d %q: got fg %d bg %d, want %d %d", i, cs[i].Ch, cs[i].Fg, cs[i].Bg, w.fg, w.bg)
+		}
+	}
+	if c := cs[len(cs)-1]; c.Fg != 11|AttrUnderline {
+		t.Errorf("bright green expected, got %d", c.Fg)
+	}
+
+	// truncated or malformed sequences must not leak into the text
+	for _, s := range []string{"x\x1b", "x\x1b[31", "x\x1b[3;?;1m", "x\x1b[38;5m", "x\x1b]0;t"} {
+		if got := CellsToStr(ParseANSI(s, 0, 0)); got != "x" {
+			t.Errorf("ParseANSI(%q) = %q, want %q", s, got, "x")
+		}
+	}
+}
+
+func TestPar_ANSI(t *testing.T) {
+	par := NewPar("\x1b[32mok\x1b[0m [done](fg-red)")
+	par.ANSI = true
+	par.Border = false
+	par.Width, par.Height = 20, 1
+	buf := par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 0, 0, 20), " "); s != "ok [done](fg-red)" {
+		t.Errorf("expected markup kept as text, got %q", s)
+	}
+	if c := buf.At(0, 0); c.Fg != ColorGreen {
+		t.Errorf("expected a green cell, got %+v", c)
+	}
+}
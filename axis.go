@@ -0,0 +1,245 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Axis is the value axis of a chart: how its values are scaled to cells,
+// and which ticks label it. The ticks fall on round values, 1, 2 or 5
+// times a power of ten apart, or on powers of ten for a logarithmic scale,
+// and at least TickGap cells apart. Its zero value scales to the data.
+/*
+  lc.YAxis.Format = termui.FormatSI
+  lc.YAxis.Grid = true
+  bc.ValueAxis.Log = true
+*/
+type Axis struct {
+	Min, Max  float64              // fixed bounds, used when Min < Max
+	TickGap   int                  // least cells between two ticks, 0 for the chart's default
+	Format    func(float64) string // formats the tick labels, see FormatSI, FormatPercent and FormatDuration
+	Log       bool                 // base 10 logarithmic scale, for positive values
+	Grid      bool                 // draw a line across the chart at every tick
+	GridColor Attribute
+}
+
+// Tick is a labelled value of an Axis, Pos cells from its start.
+type Tick struct {
+	Value float64
+	Label string
+	Pos   int
+}
+
+// Scale returns the bounds of a for values from lo to hi on an axis n
+// cells long, its fixed bounds if set or lo and hi rounded out to ticks,
+// and the ticks within them, gap cells apart at least unless TickGap is
+// set.
+func (a Axis) Scale(lo, hi float64, n, gap int) (min, max float64, ticks []Tick) {
+	if a.TickGap > 0 {
+		gap = a.TickGap
+	}
+	if gap < 1 {
+		gap = 1
+	}
+	fixed := a.Min < a.Max
+	if fixed {
+		lo, hi = a.Min, a.Max
+	}
+	if a.Log {
+		if hi <= 0 {
+			hi = 1
+		}
+		if lo <= 0 || lo >= hi {
+			lo = hi / 10
+		}
+		lo, hi = math.Log10(lo), math.Log10(hi)
+	}
+	if hi <= lo {
+		lo, hi = lo-1, hi+1
+	}
+
+	count := n/gap + 1
+	if count < 2 {
+		count = 2
+	}
+	step := niceStep((hi - lo) / float64(count-1))
+	if a.Log && step < 1 {
+		step = 1
+	}
+	// rounded out bounds take two intervals at least when they straddle a
+	// tick
+	intervals := func() float64 {
+		if fixed {
+			return (hi - lo) / step * (1 - 1e-9)
+		}
+		return math.Ceil(hi/step-1e-9) - math.Floor(lo/step+1e-9)
+	}
+	maxIntervals := float64(count - 1)
+	if !fixed && maxIntervals < 2 {
+		maxIntervals = 2
+	}
+	for intervals() > maxIntervals {
+		step = niceStep(step * 1.5)
+	}
+	if !fixed {
+		lo = math.Floor(lo/step+1e-9) * step
+		hi = math.Ceil(hi/step-1e-9) * step
+	}
+
+	for i := math.Ceil(lo/step - 1e-9); i <= math.Floor(hi/step+1e-9); i++ {
+		v := cleanFloat(i * step)
+		if v == 0 {
+			v = 0 // not -0
+		}
+		if a.Log {
+			v = cleanFloat(math.Pow(10, v))
+		}
+		t := Tick{Value: v, Label: a.label(v, step)}
+		t.Pos = int(math.Floor(a.offset(v, lo, hi, n) + 0.5))
+		ticks = append(ticks, t)
+	}
+	if a.Log {
+		lo, hi = math.Pow(10, lo), math.Pow(10, hi)
+	}
+	return lo, hi, ticks
+}
+
+// Offset returns how many cells from the start of an axis n cells long
+// going from min to max v is, out of [0, n] when v is out of the bounds.
+func (a Axis) Offset(v, min, max float64, n int) float64 {
+	if a.Log {
+		if v <= 0 || min <= 0 {
+			return math.Inf(-1)
+		}
+		return a.offset(v, math.Log10(min), math.Log10(max), n)
+	}
+	return a.offset(v, min, max, n)
+}
+
+// offset is Offset with the bounds of a logarithmic axis given as
+// exponents.
+func (a Axis) offset(v, lo, hi float64, n int) float64 {
+	if a.Log {
+		if v <= 0 {
+			return math.Inf(-1)
+		}
+		v = math.Log10(v)
+	}
+	if hi == lo {
+		return 0
+	}
+	return (v - lo) / (hi - lo) * float64(n)
+}
+
+// label formats the value of a tick, ticks being step apart.
+func (a Axis) label(v, step float64) string {
+	if a.Format != nil {
+		return a.Format(v)
+	}
+	prec := 0
+	if !a.Log && step < 1 {
+		prec = int(math.Ceil(-math.Log10(step) - 1e-9))
+	} else if a.Log && v < 1 {
+		prec = int(math.Ceil(-math.Log10(v) - 1e-9))
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// niceStep returns the least of 1, 2 and 5 times a power of ten not
+// below x.
+func niceStep(x float64) float64 {
+	if x <= 0 || math.IsInf(x, 0) || math.IsNaN(x) {
+		return 1
+	}
+	p := math.Pow(10, math.Floor(math.Log10(x)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= x*(1-1e-9) {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// cleanFloat drops the rounding noise of a computed tick value, e.g.
+// 0.30000000000000004.
+func cleanFloat(v float64) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	return f
+}
+
+// FormatSI formats v with an SI prefix and up to 3 significant digits,
+// e.g. 1500 as "1.5k" and 0.002 as "2m".
+func FormatSI(v float64) string {
+	if v == 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	prefixes := []string{"n", "µ", "m", "", "k", "M", "G", "T", "P", "E"}
+	e := int(math.Floor(math.Log10(math.Abs(v)) / 3))
+	if e < -3 {
+		e = -3
+	}
+	if e > 6 {
+		e = 6
+	}
+	m, _ := strconv.ParseFloat(strconv.FormatFloat(v/math.Pow(1000, float64(e)), 'g', 3, 64), 64)
+	if math.Abs(m) >= 1000 && e < 6 {
+		m, e = m/1000, e+1
+	}
+	return strconv.FormatFloat(m, 'f', -1, 64) + prefixes[e+3]
+}
+
+// FormatPercent formats v, a percentage, e.g. 12.5 as "12.5%".
+func FormatPercent(v float64) string {
+	return strconv.FormatFloat(cleanFloat(v), 'f', -1, 64) + "%"
+}
+
+// FormatDuration returns a formatter of values counted in unit as
+// durations, e.g. 90 seconds as "1m30s".
+func FormatDuration(unit time.Duration) func(float64) string {
+	return func(v float64) string {
+		d := time.Duration(v * float64(unit))
+		if d >= time.Second || d <= -time.Second {
+			d = d.Round(time.Millisecond)
+		}
+		return d.String()
+	}
+}
+
+// ticksWidth returns the width of the widest label of ts.
+func ticksWidth(ts []Tick) int {
+	w := 0
+	for _, t := range ts {
+		if n := strWidth(t.Label); n > w {
+			w = n
+		}
+	}
+	return w
+}
+
+// drawGrid draws the grid lines of a at ticks but the one at the start of
+// the axis: rows up from y0 between x0 and x1 for a vertical axis, columns
+// right of x0 between y0 and y1 otherwise.
+func (b *Block) drawGrid(buf Buffer, a Axis, ticks []Tick, vertical bool, x0, x1, y0, y1 int) {
+	if !a.Grid {
+		return
+	}
+	for _, t := range ticks {
+		if t.Pos == 0 {
+			continue
+		}
+		if vertical {
+			for x := x0; x < x1; x++ {
+				buf.Set(x, y0-t.Pos, Cell{'┈', a.GridColor, b.Bg})
+			}
+		} else {
+			for y := y0; y < y1; y++ {
+				buf.Set(x0+t.Pos, y, Cell{'┊', a.GridColor, b.Bg})
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func tickLabels(ts []Tick) (ls []string, ps []int) {
+	for _, t := range ts {
+		ls = append(ls, t.Label)
+		ps = append(ps, t.Pos)
+	}
+	return ls, ps
+}
+
+func TestAxisScale(t *testing.T) {
+	var a Axis
+	min, max, ts := a.Scale(3, 97, 10, 2)
+	ls, ps := tickLabels(ts)
+	if min != 0 || max != 100 || !reflect.DeepEqual(ls, []string{"0", "20", "40", "60", "80", "100"}) {
+		t.Errorf("expected 0 to 100 by 20, got %v to %v with %v", min, max, ls)
+	}
+	if !reflect.DeepEqual(ps, []int{0, 2, 4, 6, 8, 10}) {
+		t.Errorf("unexpected tick positions %v", ps)
+	}
+
+	// fixed bounds are kept, ticks fall within them
+	a = Axis{Min: 0.05, Max: 0.35, TickGap: 3}
+	min, max, ts = a.Scale(0, 1, 10, 2)
+	ls, _ = tickLabels(ts)
+	if min != 0.05 || max != 0.35 || !reflect.DeepEqual(ls, []string{"0.1", "0.2", "0.3"}) {
+		t.Errorf("expected the fixed bounds, got %v to %v with %v", min, max, ls)
+	}
+
+	a = Axis{Log: true, Format: FormatSI}
+	min, max, ts = a.Scale(3, 4000, 8, 2)
+	ls, ps = tickLabels(ts)
+	if min != 1 || max != 10000 || !reflect.DeepEqual(ls, []string{"1", "10", "100", "1k", "10k"}) {
+		t.Errorf("expected powers of ten, got %v to %v with %v", min, max, ls)
+	}
+	if ps[2] != 4 || a.Offset(100, min, max, 8) != 4 {
+		t.Errorf("expected 100 half way, got %v", ps)
+	}
+}
+
+func TestAxisFormats(t *testing.T) {
+	for v, want := range map[float64]string{1500: "1.5k", 0.002: "2m", 999.9: "1k", 12: "12", -2.5e6: "-2.5M"} {
+		if s := FormatSI(v); s != want {
+			t.Errorf("FormatSI(%v): expected %q, got %q", v, want, s)
+		}
+	}
+	if s := FormatPercent(0.1 + 0.2); s != "0.3%" {
+		t.Errorf("unexpected percentage %q", s)
+	}
+	if s := FormatDuration(time.Second)(90); s != "1m30s" {
+		t.Errorf("unexpected duration %q", s)
+	}
+}
+
+func TestLineChartAxis(t *testing.T) {
+	lc := NewLineChart()
+	lc.Border = false
+	lc.Width, lc.Height = 20, 7
+	lc.Mode = "dot"
+	lc.Data = []float64{0, 50, 100}
+	lc.YAxis = Axis{Min: 0, Max: 100, Grid: true, GridColor: ColorBlue, Format: FormatPercent}
+	buf := lc.Buffer()
+
+	// 5 rows from 0% up to 100%
+	if got := string([]rune{buf.At(0, 0).Ch, buf.At(1, 0).Ch, buf.At(2, 0).Ch, buf.At(3, 0).Ch}); got != "100%" {
+		t.Errorf("expected the top tick labelled, got %q", got)
+	}
+	x := lc.innerArea.Min.X + lc.labelYSpace + 1
+	if buf.At(x, 4).Ch != lc.DotStyle || buf.At(x+1, 2).Ch != lc.DotStyle || buf.At(x+2, 0).Ch != lc.DotStyle {
+		t.Error("expected the points scaled to the fixed bounds")
+	}
+	if c := buf.At(x+5, 2); c.Ch != '┈' || c.Fg != ColorBlue {
+		t.Errorf("expected a grid line at 50%%, got %q", c.Ch)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"os"
+
+	tm "github.com/nsf/termbox-go"
+)
+
+// Backend is the terminal termui draws on and reads events from. termbox-go
+// is used unless another Backend is given to InitBackend, e.g. one wrapping
+// tcell.
+type Backend interface {
+	Init() error
+	Close()
+	// Size returns the current size of the terminal in cells.
+	Size() (width, height int)
+	// SetCell writes a cell to the back buffer, the colours are already
+	// reduced to ColorDepth.
+	SetCell(x, y int, ch rune, fg, bg Attribute)
+	// Flush shows the back buffer on the terminal.
+	Flush() error
+	// PollEvent blocks until the next input event. It is called from a
+	// single goroutine.
+	PollEvent() Event
+}
+
+// CombiningBackend is a Backend able to draw grapheme clusters, a rune
+// followed by combining marks, in a cell, as tcell does. Other backends
+// only get the first rune of a cluster.
+type CombiningBackend interface {
+	Backend
+	SetCombiningCell(x, y int, ch rune, comb []rune, fg, bg Attribute)
+}
+
+// InterruptibleBackend is a Backend whose PollEvent can be woken up, so
+// that Close can wait for the goroutine polling it to return before
+// closing it.
+type InterruptibleBackend interface {
+	Backend
+	// Interrupt makes the current or next PollEvent call return an
+	// "interrupt" event, it does not block.
+	Interrupt()
+}
+
+// backend is the Backend in use, all calls to it but PollEvent are made
+// under renderLock.
+var backend Backend = TermboxBackend{}
+
+// TermboxBackend is the default Backend, drawing with termbox-go.
+type TermboxBackend struct{}
+
+// Init implements Backend.
+func (TermboxBackend) Init() error {
+	if err := tm.Init(); err != nil {
+		return err
+	}
+	tm.SetInputMode(tm.InputEsc | tm.InputMouse)
+	// the 16 system colours and above need termbox's palette mode
+	if ColorDepth() >= 16 {
+		tm.SetOutputMode(tm.Output256)
+	}
+	return nil
+}
+
+// Close implements Backend.
+func (TermboxBackend) Close() {
+	tm.Close()
+}
+
+// Size implements Backend, it resyncs termbox with the terminal first.
+func (TermboxBackend) Size() (int, int) {
+	tm.Sync()
+	return tm.Size()
+}
+
+// SetCell implements Backend, 24-bit colours are drawn as their nearest
+// palette entry as termbox has no truecolor mode.
+func (TermboxBackend) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	tm.SetCell(x, y, ch, tm.Attribute(quantizeAttr(fg, 256)), tm.Attribute(quantizeAttr(bg, 256)))
+}
+
+// Flush implements Backend.
+func (TermboxBackend) Flush() error {
+	return tm.Flush()
+}
+
+// DrawSixel implements SixelBackend, writing data to the terminal past
+// termbox, which has already flushed the cells below.
+func (TermboxBackend) DrawSixel(x, y int, data []byte) {
+	fmt.Fprintf(os.Stdout, "\x1b7\x1b[%d;%dH%s\x1b8", y+1, x+1, data)
+}
+
+// PollEvent implements Backend.
+func (TermboxBackend) PollEvent() Event {
+	return crtTermboxEvt(tm.PollEvent())
+}
+
+// Interrupt implements InterruptibleBackend. termbox blocks the interrupt
+// until PollEvent takes it, hence the goroutine.
+func (TermboxBackend) Interrupt() {
+	go tm.Interrupt()
+}
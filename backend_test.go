@@ -0,0 +1,67 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+// fakeBackend records the cells written to it.
+type fakeBackend struct {
+	w, h    int
+	cells   map[image.Point]Cell
+	writes  int
+	flushes int
+}
+
+func (b *fakeBackend) Init() error      { return nil }
+func (b *fakeBackend) Close()           {}
+func (b *fakeBackend) Size() (int, int) { return b.w, b.h }
+func (b *fakeBackend) Flush() error     { b.flushes++; return nil }
+func (b *fakeBackend) PollEvent() Event { select {} }
+func (b *fakeBackend) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	b.writes++
+	b.cells[image.Pt(x, y)] = Cell{ch, fg, bg}
+}
+
+// useFakeBackend draws on a fake w x h terminal until the returned func is
+// called.
+func useFakeBackend(w, h int) (*fakeBackend, func()) {
+	fb := &fakeBackend{w: w, h: h, cells: make(map[image.Point]Cell)}
+	old, ow, oh, os := backend, termWidth, termHeight, screen
+	backend, termWidth, termHeight, screen = fb, w, h, newScreenState()
+	return fb, func() {
+		backend, termWidth, termHeight, screen = old, ow, oh, os
+	}
+}
+
+func TestRenderBackend(t *testing.T) {
+	fb, done := useFakeBackend(20, 5)
+	defer done()
+
+	p := NewPar("abc")
+	p.Width, p.Height = 6, 3
+	Render(p)
+	if fb.writes != 18 || fb.flushes != 1 || fb.cells[image.Pt(1, 1)].Ch != 'a' {
+		t.Fatalf("expected the widget drawn, got %d writes %d flushes", fb.writes, fb.flushes)
+	}
+
+	fb.writes = 0
+	p.Text = "abd"
+	Render(p)
+	if fb.writes != 1 || fb.cells[image.Pt(3, 1)].Ch != 'd' {
+		t.Errorf("expected a single changed cell written, got %d", fb.writes)
+	}
+
+	if w, h := Viewport().Dx(), Viewport().Dy(); w != 20 || h != 5 {
+		t.Errorf("viewport should follow the backend size, got %dx%d", w, h)
+	}
+
+	HardClear()
+	if c := fb.cells[image.Pt(1, 1)]; c.Ch != ' ' {
+		t.Errorf("HardClear should blank the terminal, got %q", c.Ch)
+	}
+}
@@ -4,7 +4,30 @@
 
 package termui
 
-import "fmt"
+import (
+	"fmt"
+	"image"
+)
+
+// Positions of the value labels of BarChart and MBarChart.
+const (
+	LabelPosBase = "base" // at the foot of the bar (default)
+	LabelPosTop  = "top"  // on the top row of the bar
+	LabelPosNone = "none" // no value labels
+)
+
+// barNum formats v for a bar w cells wide with f, fmt.Sprint when nil. It
+// returns nil when the label does not fit.
+func barNum(v int, w int, f func(float64) string) []rune {
+	s := fmt.Sprint(v)
+	if f != nil {
+		s = f(float64(v))
+	}
+	if strWidth(s) > w {
+		return nil
+	}
+	return str2runes(s)
+}
 
 // BarChart creates multiple bars in a widget:
 /*
@@ -20,47 +43,67 @@ import "fmt"
    bc.BarColor = termui.ColorRed
    bc.NumColor = termui.ColorYellow
 */
+// With Horizontal set the bars grow rightwards from their labels, BarWidth
+// rows thick. With StackData set each bar is a stack of segments, one per
+// slice, in StackColors and named by a legend on the top row.
+/*
+   bc.Horizontal = true
+   bc.StackData = [][]int{used, cached, free}
+   bc.StackLabels = []string{"used", "cached", "free"}
+   bc.StackColors = []termui.Attribute{termui.ColorRed, termui.ColorYellow, termui.ColorGreen}
+*/
 type BarChart struct {
 	Block
-	BarColor   Attribute
-	TextColor  Attribute
-	NumColor   Attribute
-	Data       []int
-	DataLabels []string
-	BarWidth   int
-	BarGap     int
-	CellChar   rune
-	labels     [][]rune
-	dataNum    [][]rune
-	numBar     int
-	scale      float64
-	max        int
+	BarColor    Attribute
+	TextColor   Attribute
+	NumColor    Attribute
+	Data        []int
+	DataLabels  []string
+	BarWidth    int
+	BarGap      int
+	CellChar    rune
+	EmptyText   string // shown centered when Data is empty
+	EmptyFg     Attribute
+	NumFmt      func(v float64) string // formats the value labels, labels wider than a bar are dropped
+	LabelPos    string                 // LabelPosBase, LabelPosTop or LabelPosNone
+	OnClick     func(index int)        // called with the index of a bar clicked with the left button
+	Horizontal  bool                   // bars grow from left to right
+	StackData   [][]int                // StackData[s][i] is segment s of bar i, Data is then ignored
+	StackColors []Attribute            // colours of the segments, palette colours when unset
+	StackLabels []string               // names of the segments, shown as a legend
+	ShowScale   bool                   // show 0 and the maximum at the ends of the bars
+	ValueAxis   Axis                   // scales the bars from 0 to the maximum, formats the scale and draws the grid
+	stacks      [][]int
+	numBar      int
+	max         int
 }
 
 // NewBarChart returns a new *BarChart with current theme.
 func NewBarChart() *BarChart {
 	bc := &BarChart{Block: *NewBlock()}
-	bc.BarColor = ThemeAttr("barchart.bar.bg")
-	bc.NumColor = ThemeAttr("barchart.num.fg")
-	bc.TextColor = ThemeAttr("barchart.text.fg")
+	bc.themeAttr(&bc.BarColor, "barchart.bar.bg")
+	bc.themeAttr(&bc.NumColor, "barchart.num.fg")
+	bc.themeAttr(&bc.TextColor, "barchart.text.fg")
+	bc.themeAttr(&bc.ValueAxis.GridColor, "barchart.grid.fg")
 	bc.BarGap = 1
 	bc.BarWidth = 3
 	bc.CellChar = ' '
+	bc.LabelPos = LabelPosBase
 	return bc
 }
 
 func (bc *BarChart) layout() {
 	bc.Lock()
 	defer bc.Unlock()
-	bc.numBar = bc.innerArea.Dx() / (bc.BarGap + bc.BarWidth)
-	bc.labels = make([][]rune, bc.numBar)
-	bc.dataNum = make([][]rune, len(bc.Data))
-
-	for i := 0; i < bc.numBar && i < len(bc.DataLabels) && i < len(bc.Data); i++ {
-		bc.labels[i] = trimStr2Runes(bc.DataLabels[i], bc.BarWidth)
-		n := bc.Data[i]
-		s := fmt.Sprint(n)
-		bc.dataNum[i] = trimStr2Runes(s, bc.BarWidth)
+	bc.stacks = bc.StackData
+	if len(bc.stacks) == 0 {
+		bc.stacks = [][]int{bc.Data}
+	}
+	bc.numBar = len(bc.stacks[0])
+	for _, s := range bc.stacks {
+		if len(s) < bc.numBar {
+			bc.numBar = len(s)
+		}
 	}
 
 	//bc.max = bc.Data[0] //  what if Data is nil? Sometimes when bar graph is nill it produces panic with panic: runtime error: index out of range
@@ -68,12 +111,15 @@ func (bc *BarChart) layout() {
 	if bc.max == 0 {
 		bc.max = -1
 	}
-	for i := 0; i < len(bc.Data); i++ {
-		if bc.max < bc.Data[i] {
-			bc.max = bc.Data[i]
+	for i := 0; i < bc.numBar; i++ {
+		sum := 0
+		for _, s := range bc.stacks {
+			sum += s[i]
+		}
+		if bc.max < sum {
+			bc.max = sum
 		}
 	}
-	bc.scale = float64(bc.max) / float64(bc.innerArea.Dy()-1)
 }
 
 func (bc *BarChart) SetMax(max int) {
@@ -84,6 +130,9 @@ func (bc *BarChart) SetMax(max int) {
 	}
 }
 
+// barPalette colours the segments of stacked bars given no colour.
+var barPalette = []Attribute{ColorRed, ColorGreen, ColorYellow, ColorBlue, ColorMagenta, ColorCyan, ColorWhite}
+
 // Buffer implements Bufferer interface.
 func (bc *BarChart) Buffer() Buffer {
 	buf := bc.Block.Buffer()
@@ -91,64 +140,331 @@ func (bc *BarChart) Buffer() Buffer {
 	bc.RLock()
 	defer bc.RUnlock()
 
-	for i := 0; i < bc.numBar && i < len(bc.Data) && i < len(bc.DataLabels); i++ {
-		h := int(float64(bc.Data[i]) / bc.scale)
-		oftX := i * (bc.BarWidth + bc.BarGap)
+	if bc.numBar == 0 {
+		bc.drawEmptyText(buf, bc.EmptyText, bc.EmptyFg)
+		return buf
+	}
 
-		barBg := bc.Bg
-		barFg := bc.BarColor
+	colors := make([]Attribute, len(bc.stacks))
+	numColors := make([]Attribute, len(bc.stacks))
+	for s := range colors {
+		switch {
+		case s < len(bc.StackColors):
+			colors[s] = bc.StackColors[s]
+		case s == 0:
+			colors[s] = bc.BarColor
+		default:
+			colors[s] = barPalette[s%len(barPalette)]
+		}
+		numColors[s] = bc.NumColor
+	}
+	bc.drawBars(buf, bars{
+		stacks:     bc.stacks,
+		colors:     colors,
+		numColors:  numColors,
+		textColor:  bc.TextColor,
+		labels:     bc.DataLabels,
+		legend:     bc.StackLabels,
+		width:      bc.BarWidth,
+		gap:        bc.BarGap,
+		cellChar:   bc.CellChar,
+		numFmt:     bc.NumFmt,
+		labelPos:   bc.LabelPos,
+		max:        bc.max,
+		horizontal: bc.Horizontal,
+		showScale:  bc.ShowScale,
+		axis:       bc.ValueAxis,
+		onClick:    bc.OnClick,
+	})
+	return buf
+}
 
-		if bc.CellChar == ' ' {
-			barBg = bc.BarColor
-			barFg = ColorDefault
-			if bc.BarColor == ColorDefault { // the same as above
-				barBg |= AttrReverse
+// bars are the bars BarChart and MBarChart draw, each one a stack of one
+// or more segments.
+type bars struct {
+	stacks       [][]int // stacks[s][i] is segment s of bar i
+	colors       []Attribute
+	numColors    []Attribute
+	textColor    Attribute
+	labels       []string // a bar is drawn only if it has one
+	legend       []string
+	width, gap   int
+	cellChar     rune
+	numFmt       func(float64) string
+	labelPos     string
+	max          int
+	horizontal   bool
+	showScale    bool
+	axis         Axis
+	centerLabels bool
+	onClick      func(int)
+}
+
+// segments returns the lengths of the segments of bar i when the maximum
+// takes n cells, rounding the stacked ends so the bar is not shortened.
+func (bs bars) segments(i, n int) []int {
+	ls := make([]int, len(bs.stacks))
+	if bs.max <= 0 || n <= 0 {
+		return ls
+	}
+	sum, prev := 0, 0
+	for s, d := range bs.stacks {
+		if d[i] > 0 {
+			sum += d[i]
+		}
+		end := n
+		switch {
+		case sum >= bs.max:
+		case bs.axis.Log:
+			// a logarithmic scale starts at 1
+			end = 0
+			if f := bs.axis.Offset(float64(sum), 1, float64(bs.max), n); f > 0 {
+				end = int(f)
 			}
+		default:
+			end = sum * n / bs.max
+		}
+		ls[s] = end - prev
+		prev = end
+	}
+	return ls
+}
+
+// cell returns the cell of segment s on bg, colouring the background
+// instead when the bars are drawn with spaces.
+func (bs bars) cell(s int, bg Attribute) Cell {
+	c := Cell{bs.cellChar, bs.colors[s], bg}
+	if bs.cellChar == ' ' {
+		c.Fg, c.Bg = ColorDefault, bs.colors[s]
+		if bs.colors[s] == ColorDefault { // when color is default, space char treated as transparent!
+			c.Bg |= AttrReverse
+		}
+	}
+	return c
+}
+
+// ticks returns the ticks of the value axis of bs, n cells long.
+func (bs bars) ticks(n int) []Tick {
+	a := bs.axis
+	a.Min, a.Max = 0, float64(bs.max)
+	if a.Log {
+		a.Min = 1
+	}
+	_, _, ts := a.Scale(a.Min, a.Max, n, 2)
+	return ts
+}
+
+// scaleLabel formats v for the scale of bs.
+func (bs bars) scaleLabel(v int) []rune {
+	if bs.axis.Format != nil {
+		return str2runes(bs.axis.Format(float64(v)))
+	}
+	return str2runes(fmt.Sprint(v))
+}
+
+// drawBars draws bs in the inner area of b.
+func (b *Block) drawBars(buf Buffer, bs bars) {
+	area := b.innerArea
+	if len(bs.legend) > 0 {
+		b.drawBarLegend(buf, bs)
+		area.Min.Y++
+	}
+	if bs.horizontal {
+		b.drawHBars(buf, area, bs)
+	} else {
+		b.drawVBars(buf, area, bs)
+	}
+}
+
+// drawBarLegend draws the names of the segments right aligned on the top
+// row, each after a mark in its colour.
+func (b *Block) drawBarLegend(buf Buffer, bs bars) {
+	cs := []Cell{}
+	for s, name := range bs.legend {
+		if s >= len(bs.colors) {
+			break
 		}
+		if s > 0 {
+			cs = append(cs, Cell{' ', bs.textColor, b.Bg}, Cell{' ', bs.textColor, b.Bg})
+		}
+		cs = append(cs, Cell{'■', bs.colors[s] &^ AttrReverse, b.Bg}, Cell{' ', bs.textColor, b.Bg})
+		for _, r := range str2runes(name) {
+			cs = append(cs, Cell{r, bs.textColor, b.Bg})
+		}
+	}
+	x := b.innerArea.Max.X - cellsWidth(cs)
+	for _, c := range cs {
+		buf.Set(x, b.innerArea.Min.Y, c)
+		x += c.Width()
+	}
+}
+
+// drawVBars draws bs upwards from the row above their labels in area.
+func (b *Block) drawVBars(buf Buffer, area image.Rectangle, bs bars) {
+	rows := area.Dy() - 1
+	if bs.showScale {
+		area.Min.Y++
+		rows--
+	}
+	base := area.Max.Y - 2
+	numBar := area.Dx() / (bs.gap + bs.width)
+	if bs.max > 0 {
+		b.drawGrid(buf, bs.axis, bs.ticks(rows-1), true, area.Min.X, area.Max.X, base, 0)
+	}
+
+	for i := 0; i < numBar && i < len(bs.stacks[0]) && i < len(bs.labels); i++ {
+		oftX := i * (bs.width + bs.gap)
+		x0 := area.Min.X + oftX
+		segs := bs.segments(i, rows)
 
 		// plot bar
-		for j := 0; j < bc.BarWidth; j++ {
-			for k := 0; k < h; k++ {
-				c := Cell{
-					Ch: bc.CellChar,
-					Bg: barBg,
-					Fg: barFg,
+		for s, y := 0, base; s < len(segs); s++ {
+			c := bs.cell(s, b.Bg)
+			for k := 0; k < segs[s]; k, y = k+1, y-1 {
+				for j := 0; j < bs.width; j++ {
+					buf.Set(x0+j, y, c)
 				}
-
-				x := bc.innerArea.Min.X + i*(bc.BarWidth+bc.BarGap) + j
-				y := bc.innerArea.Min.Y + bc.innerArea.Dy() - 2 - k
-				buf.Set(x, y, c)
 			}
 		}
+		if bs.onClick != nil {
+			r := image.Rect(x0, area.Min.Y, x0+bs.width, area.Max.Y)
+			n, cb := i, bs.onClick
+			b.AddHotspot(r.Intersect(area), func(int, int) { cb(n) })
+		}
 		// plot text
-		for j, k := 0, 0; j < len(bc.labels[i]); j++ {
-			w := charWidth(bc.labels[i][j])
-			c := Cell{
-				Ch: bc.labels[i][j],
-				Bg: bc.Bg,
-				Fg: bc.TextColor,
-			}
-			y := bc.innerArea.Min.Y + bc.innerArea.Dy() - 1
-			x := bc.innerArea.Min.X + oftX + k
-			buf.Set(x, y, c)
-			k += w
+		label := trimStr2Runes(bs.labels[i], bs.width)
+		x := x0
+		if bs.centerLabels {
+			x += (bs.width - runesWidth(label)) / 2
+		}
+		for _, r := range label {
+			c := Cell{Ch: r, Bg: b.Bg, Fg: bs.textColor}
+			buf.Set(x, area.Max.Y-1, c)
+			x += c.Width()
 		}
 		// plot num
-		for j := 0; j < len(bc.dataNum[i]); j++ {
-			c := Cell{
-				Ch: bc.dataNum[i][j],
-				Fg: bc.NumColor,
-				Bg: barBg,
+		for s, y := 0, base; s < len(segs) && bs.labelPos != LabelPosNone; y, s = y-segs[s], s+1 {
+			h := segs[s]
+			// a lone empty bar still shows its value
+			if h == 0 && len(segs) > 1 {
+				continue
 			}
-
+			num := barNum(bs.stacks[s][i], bs.width, bs.numFmt)
+			c := bs.cell(s, b.Bg)
+			c.Fg = bs.numColors[s]
 			if h == 0 {
-				c.Bg = bc.Bg
+				c.Bg = b.Bg
+			}
+			ny := y
+			if bs.labelPos == LabelPosTop && h > 0 {
+				ny -= h - 1
+			}
+			x := x0 + (bs.width-runesWidth(num))/2
+			for _, r := range num {
+				c.Ch = r
+				buf.Set(x, ny, c)
+				x += c.Width()
 			}
-			x := bc.innerArea.Min.X + oftX + (bc.BarWidth-len(bc.dataNum[i]))/2 + j
-			y := bc.innerArea.Min.Y + bc.innerArea.Dy() - 2
-			buf.Set(x, y, c)
 		}
 	}
 
-	return buf
+	if bs.showScale {
+		//Currently bar graph only supprts data range from 0 to MAX
+		for i, r := range bs.scaleLabel(0) {
+			buf.Set(area.Min.X+i, base, Cell{r, bs.textColor, b.Bg})
+		}
+		for i, r := range bs.scaleLabel(bs.max) {
+			buf.Set(area.Min.X+i, area.Min.Y-1, Cell{r, bs.textColor, b.Bg})
+		}
+	}
+}
+
+// drawHBars draws bs rightwards from a column of their labels in area.
+func (b *Block) drawHBars(buf Buffer, area image.Rectangle, bs bars) {
+	if bs.showScale {
+		area.Max.Y--
+	}
+	numBar := (area.Dy() + bs.gap) / (bs.gap + bs.width)
+	if numBar > len(bs.stacks[0]) {
+		numBar = len(bs.stacks[0])
+	}
+	if numBar > len(bs.labels) {
+		numBar = len(bs.labels)
+	}
+	labelW := 0
+	for _, l := range bs.labels[:numBar] {
+		if w := strWidth(l); w > labelW {
+			labelW = w
+		}
+	}
+	if labelW > area.Dx()/2 {
+		labelW = area.Dx() / 2
+	}
+	x0 := area.Min.X + labelW + 1
+	cols := area.Max.X - x0
+	if bs.max > 0 {
+		b.drawGrid(buf, bs.axis, bs.ticks(cols-1), false, x0, 0, area.Min.Y, area.Max.Y)
+	}
+
+	for i := 0; i < numBar; i++ {
+		y0 := area.Min.Y + i*(bs.width+bs.gap)
+		mid := y0 + (bs.width-1)/2
+		segs := bs.segments(i, cols)
+
+		// plot bar
+		for s, x := 0, x0; s < len(segs); s++ {
+			c := bs.cell(s, b.Bg)
+			for k := 0; k < segs[s]; k, x = k+1, x+1 {
+				for j := 0; j < bs.width; j++ {
+					buf.Set(x, y0+j, c)
+				}
+			}
+		}
+		if bs.onClick != nil {
+			r := image.Rect(area.Min.X, y0, area.Max.X, y0+bs.width)
+			n, cb := i, bs.onClick
+			b.AddHotspot(r.Intersect(area), func(int, int) { cb(n) })
+		}
+		// plot text, right aligned against the bars
+		label := trimStr2Runes(bs.labels[i], labelW)
+		x := x0 - 1 - runesWidth(label)
+		for _, r := range label {
+			c := Cell{Ch: r, Bg: b.Bg, Fg: bs.textColor}
+			buf.Set(x, mid, c)
+			x += c.Width()
+		}
+		// plot num
+		for s, x := 0, x0; s < len(segs) && bs.labelPos != LabelPosNone; x, s = x+segs[s], s+1 {
+			w := segs[s]
+			if w == 0 && len(segs) > 1 {
+				continue
+			}
+			c := bs.cell(s, b.Bg)
+			c.Fg = bs.numColors[s]
+			if w == 0 {
+				w, c.Bg = cols, b.Bg
+			}
+			num := barNum(bs.stacks[s][i], w, bs.numFmt)
+			nx := x
+			if bs.labelPos == LabelPosTop && segs[s] > 0 {
+				nx += w - runesWidth(num)
+			}
+			for _, r := range num {
+				c.Ch = r
+				buf.Set(nx, mid, c)
+				nx += c.Width()
+			}
+		}
+	}
+
+	if bs.showScale {
+		y := area.Max.Y
+		for i, r := range bs.scaleLabel(0) {
+			buf.Set(x0+i, y, Cell{r, bs.textColor, b.Bg})
+		}
+		max := bs.scaleLabel(bs.max)
+		for i, r := range max {
+			buf.Set(area.Max.X-len(max)+i, y, Cell{r, bs.textColor, b.Bg})
+		}
+	}
 }
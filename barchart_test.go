@@ -0,0 +1,151 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"testing"
+)
+
+// barHeights returns the plotted height of each bar in bc.
+func barHeights(bc *BarChart) []int {
+	buf := bc.Buffer()
+	hs := make([]int, len(bc.Data))
+	for i := range bc.Data {
+		x := bc.innerArea.Min.X + i*(bc.BarWidth+bc.BarGap)
+		for y := bc.innerArea.Min.Y; y < bc.innerArea.Max.Y-1; y++ {
+			if buf.At(x, y).Bg == bc.BarColor {
+				hs[i]++
+			}
+		}
+	}
+	return hs
+}
+
+func TestBarChartScaling(t *testing.T) {
+	cases := []struct {
+		data []int
+		want []int
+	}{
+		{[]int{0, 0, 0}, []int{0, 0, 0}},
+		{[]int{5, 5, 5}, []int{9, 9, 9}},
+		{[]int{3}, []int{9}},
+		{[]int{2, 4, 8}, []int{2, 4, 9}},
+	}
+
+	for _, c := range cases {
+		bc := NewBarChart()
+		bc.Width = 20
+		bc.Height = 12
+		bc.BarColor = ColorRed
+		bc.Data = c.data
+		bc.DataLabels = []string{"a", "b", "c"}
+
+		hs := barHeights(bc)
+		for i := range hs {
+			if hs[i] != c.want[i] {
+				t.Errorf("%v: expected heights %v but got %v", c.data, c.want, hs)
+				break
+			}
+		}
+	}
+}
+
+func TestBarChartNumLabels(t *testing.T) {
+	bc := NewBarChart()
+	bc.Width = 17
+	bc.Height = 7 // 4 rows of bars above the labels
+	bc.BarWidth = 4
+	bc.Data = []int{6000, 3000, 12000}
+	bc.DataLabels = []string{"a", "b", "c"}
+	bc.NumFmt = func(v float64) string {
+		return fmt.Sprintf("%.1fk", v/1000)
+	}
+	bc.LabelPos = LabelPosTop
+
+	buf := bc.Buffer()
+	row := func(y, x int) string {
+		s := ""
+		for i := 0; i < 4; i++ {
+			s += string(buf.At(x+i, y).Ch)
+		}
+		return s
+	}
+	// bar a is 2 rows high, b is 1 row high, c is full height
+	minX, base := bc.innerArea.Min.X, bc.innerArea.Max.Y-2
+	if s := row(base-1, minX); s != "6.0k" {
+		t.Errorf("expected 6.0k on the top of bar a but got %q", s)
+	}
+	if s := row(base, minX+5); s != "3.0k" {
+		t.Errorf("expected 3.0k on the top of bar b but got %q", s)
+	}
+	for y := bc.innerArea.Min.Y; y <= base; y++ {
+		if s := row(y, minX+10); s != "    " {
+			t.Errorf("a label wider than the bar should be dropped, got %q on row %d", s, y)
+		}
+	}
+}
+
+func TestBarChartHorizontal(t *testing.T) {
+	bc := NewBarChart()
+	bc.Border = false
+	bc.Width, bc.Height = 10, 2
+	bc.Horizontal = true
+	bc.BarWidth, bc.BarGap = 1, 0
+	bc.BarColor = ColorRed
+	bc.Data = []int{5, 10}
+	bc.DataLabels = []string{"a", "bb"}
+	buf := bc.Buffer()
+
+	// labels right aligned in 2 columns, then 7 columns of bars
+	if c := buf.At(1, 0); c.Ch != 'a' {
+		t.Errorf("expected the label a, got %q", c.Ch)
+	}
+	if c := buf.At(3, 0); c.Ch != '5' || c.Bg != ColorRed {
+		t.Errorf("expected the value at the base of the bar, got %+v", c)
+	}
+	if buf.At(5, 0).Bg != ColorRed || buf.At(6, 0).Bg == ColorRed {
+		t.Error("expected bar a 3 columns long")
+	}
+	if buf.At(9, 1).Bg != ColorRed {
+		t.Error("expected bar bb full length")
+	}
+}
+
+func TestBarChartStacked(t *testing.T) {
+	bc := NewBarChart()
+	bc.Border = false
+	bc.Width, bc.Height = 10, 6
+	bc.StackData = [][]int{{2}, {2}}
+	bc.StackColors = []Attribute{ColorRed, ColorBlue}
+	bc.StackLabels = []string{"x", "y"}
+	bc.DataLabels = []string{"a"}
+	buf := bc.Buffer()
+
+	// the legend takes the top row, leaving 4 rows of bars
+	if c := buf.At(2, 0); c.Ch != '■' || c.Fg != ColorRed {
+		t.Errorf("expected the legend right aligned, got %+v", c)
+	}
+	for y, want := range []Attribute{1: ColorBlue, 2: ColorBlue, 3: ColorRed, 4: ColorRed} {
+		if y > 0 && buf.At(1, y).Bg != want {
+			t.Errorf("row %d: expected %v, got %v", y, want, buf.At(1, y).Bg)
+		}
+	}
+}
+
+func TestBarChartLogScale(t *testing.T) {
+	bc := NewBarChart()
+	bc.Width = 20
+	bc.Height = 12
+	bc.BarColor = ColorRed
+	bc.Data = []int{1, 10, 100}
+	bc.DataLabels = []string{"a", "b", "c"}
+	bc.LabelPos = LabelPosNone
+	bc.ValueAxis.Log = true
+	hs := barHeights(bc)
+	if hs[0] != 0 || hs[1] != 4 || hs[2] != 9 {
+		t.Errorf("expected the bars scaled by their exponents, got %v", hs)
+	}
+}
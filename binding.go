@@ -0,0 +1,124 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// BindField sets the field of w that field points to to every value
+// received from ch, then re-renders w, see Bind. ch must be a channel of
+// values assignable to the field, BindField panics otherwise. It returns
+// immediately and stops when ch is closed.
+/*
+  g := ui.NewGauge()
+  percents := make(chan int)
+  ui.BindField(g, &g.Percent, percents)
+  go func() {
+      for p := range download() {
+          percents <- p
+      }
+  }()
+*/
+func BindField(w Bufferer, field, ch interface{}) {
+	BindFieldContext(context.Background(), w, field, ch)
+}
+
+// BindFieldContext is like BindField but also stops when ctx is done.
+func BindFieldContext(ctx context.Context, w Bufferer, field, ch interface{}) {
+	fv := fieldOf(field)
+	cv := reflect.ValueOf(ch)
+	if cv.Kind() != reflect.Chan || cv.Type().ChanDir()&reflect.RecvDir == 0 ||
+		!cv.Type().Elem().AssignableTo(fv.Type()) {
+		panic(fmt.Sprintf("termui: cannot bind a %T to a field of type %v", ch, fv.Type()))
+	}
+
+	fns := make(chan func())
+	go func() {
+		defer close(fns)
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: cv},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		for {
+			i, v, ok := reflect.Select(cases)
+			if i == 1 || !ok {
+				return
+			}
+			select {
+			case fns <- setField(fv, v):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	BindContext(ctx, w, fns)
+}
+
+// PollField calls fn right away and then every interval, setting the field
+// of w that field points to to what it returns and re-rendering w. fn must
+// be a func() T with T assignable to the field, PollField panics otherwise.
+// It runs on its own goroutine, so it may block, e.g. to read a file.
+/*
+  sls := ui.NewSparklines(ui.NewSparkline())
+  ui.PollField(sls, &sls.Lines[0].Data, time.Second, func() []int {
+      return loadHistory()
+  })
+*/
+func PollField(w Bufferer, field interface{}, interval time.Duration, fn interface{}) {
+	PollFieldContext(context.Background(), w, field, interval, fn)
+}
+
+// PollFieldContext is like PollField but stops when ctx is done.
+func PollFieldContext(ctx context.Context, w Bufferer, field interface{}, interval time.Duration, fn interface{}) {
+	fv := fieldOf(field)
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.Type().NumIn() != 0 || f.Type().NumOut() != 1 ||
+		!f.Type().Out(0).AssignableTo(fv.Type()) {
+		panic(fmt.Sprintf("termui: cannot poll a %T into a field of type %v", fn, fv.Type()))
+	}
+
+	fns := make(chan func())
+	tk := DefaultTimeSource.NewTicker(interval)
+	go func() {
+		defer close(fns)
+		defer tk.Stop()
+		for {
+			select {
+			case fns <- setField(fv, f.Call(nil)[0]):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-tk.C():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	BindContext(ctx, w, fns)
+}
+
+// fieldOf returns the value field points to, it panics if field is no
+// pointer.
+func fieldOf(field interface{}) reflect.Value {
+	v := reflect.ValueOf(field)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		panic(fmt.Sprintf("termui: cannot bind to a %T, want a pointer to a field", field))
+	}
+	return v.Elem()
+}
+
+// setField returns the update setting fv to v. BindContext runs it under
+// the render lock, through update, and not under the Block lock of the
+// widget: a Render on another goroutine holds that one while it draws.
+func setField(fv, v reflect.Value) func() {
+	return func() {
+		fv.Set(v)
+	}
+}
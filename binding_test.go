@@ -0,0 +1,169 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBindField(t *testing.T) {
+	_, done := useFakeBackend(10, 3)
+	defer done()
+
+	g := NewGauge()
+	ch := make(chan int)
+	BindField(g, &g.Percent, ch)
+	go func() { ch <- 42 }()
+	(<-updateJobs)()
+	if g.Percent != 42 {
+		t.Errorf("expected the bound value, got %d", g.Percent)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic binding a chan string to an int")
+		}
+	}()
+	BindField(g, &g.Percent, make(chan string))
+}
+
+func TestBindFieldWhileRendering(t *testing.T) {
+	_, done := useFakeBackend(10, 3)
+	defer done()
+
+	g := NewGauge()
+	g.Width, g.Height = 10, 3
+	ch := make(chan int)
+	BindField(g, &g.Percent, ch)
+
+	stop := make(chan struct{})
+	rendered := make(chan struct{})
+	go func() {
+		defer close(rendered)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				Render(g)
+			}
+		}
+	}()
+	applied := make(chan struct{})
+	go func() {
+		defer close(applied)
+		for i := 1; i <= 20; i++ {
+			v := i
+			go func() { ch <- v }()
+			(<-updateJobs)()
+		}
+	}()
+	select {
+	case <-applied:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the bound values applied while rendering")
+	}
+	close(stop)
+	<-rendered
+	if g.Percent != 20 {
+		t.Errorf("expected the last bound value, got %d", g.Percent)
+	}
+}
+
+func TestPollField(t *testing.T) {
+	old := DefaultTimeSource
+	defer func() { DefaultTimeSource = old }()
+	ft := NewFakeTimeSource(time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC))
+	DefaultTimeSource = ft
+	_, done := useFakeBackend(10, 3)
+	defer done()
+
+	l := NewList()
+	n := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	PollFieldContext(ctx, l, &l.Items, time.Second, func() []string {
+		n++
+		return make([]string, n)
+	})
+	(<-updateJobs)()
+	if len(l.Items) != 1 {
+		t.Errorf("expected a first poll right away, got %d items", len(l.Items))
+	}
+	ft.Advance(time.Second)
+	(<-updateJobs)()
+	if len(l.Items) != 2 {
+		t.Errorf("expected a poll every interval, got %d items", len(l.Items))
+	}
+}
+
+// noJob fails t if a Bind job comes within a short while.
+func noJob(t *testing.T, why string) {
+	select {
+	case <-updateJobs:
+		t.Error(why)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestBind(t *testing.T) {
+	fb, done := useFakeBackend(10, 3)
+	defer done()
+
+	g := NewGauge()
+	g.Width, g.Height = 10, 3
+	ch := make(chan func())
+	Bind(g, ch)
+
+	// a Render on another goroutine does not race the bound changes
+	stop := make(chan struct{})
+	rendered := make(chan struct{})
+	go func() {
+		defer close(rendered)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				Render(g)
+			}
+		}
+	}()
+	for i := 1; i <= 10; i++ {
+		p := i * 10
+		go func() { ch <- func() { g.Percent = p } }()
+		(<-updateJobs)()
+	}
+	close(stop)
+	<-rendered
+	if g.Percent != 100 || fb.flushes == 0 {
+		t.Errorf("expected the changes applied and drawn, got %d", g.Percent)
+	}
+
+	close(ch)
+	noJob(t, "expected Bind to stop when its channel is closed")
+}
+
+func TestBindContext(t *testing.T) {
+	_, done := useFakeBackend(10, 3)
+	defer done()
+
+	g := NewGauge()
+	ch := make(chan func(), 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	BindContext(ctx, g, ch)
+	ch <- func() { g.Percent = 5 }
+	(<-updateJobs)()
+	if g.Percent != 5 {
+		t.Fatalf("expected the change applied, got %d", g.Percent)
+	}
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	ch <- func() { g.Percent = 6 }
+	noJob(t, "expected BindContext to stop once ctx is done")
+}
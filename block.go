@@ -33,20 +33,26 @@ type Vline struct {
 func (l *Hline) Buffer() Buffer {
 	l.lock.RLock()
 	defer l.lock.RUnlock()
+	buf := NewBuffer()
 	if l.Len <= 0 {
-		return NewBuffer()
+		return buf
 	}
-	return NewFilledBuffer(l.X, l.Y, l.X+l.Len, l.Y+1, HORIZONTAL_LINE, l.Fg, l.Bg)
+	buf.SetArea(image.Rect(l.X, l.Y, l.X+l.Len, l.Y+1))
+	buf.DrawHLine(l.X, l.Y, l.Len, l.Fg, l.Bg)
+	return buf
 }
 
 // Buffer draws a vertical line.
 func (l *Vline) Buffer() Buffer {
 	l.lock.RLock()
 	defer l.lock.RUnlock()
+	buf := NewBuffer()
 	if l.Len <= 0 {
-		return NewBuffer()
+		return buf
 	}
-	return NewFilledBuffer(l.X, l.Y, l.X+1, l.Y+l.Len, VERTICAL_LINE, l.Fg, l.Bg)
+	buf.SetArea(image.Rect(l.X, l.Y, l.X+1, l.Y+l.Len))
+	buf.DrawVLine(l.X, l.Y, l.Len, l.Fg, l.Bg)
+	return buf
 }
 
 func NewVline(x, y, l int, borderFg, borderBg Attribute) *Vline {
@@ -105,45 +111,96 @@ func (b *Block) drawBorder(buf Buffer) {
 	x1 := max.X - 1
 	y1 := max.Y - 1
 
-	// draw lines
+	sideFg := func(fg Attribute) Attribute {
+		if b.focused && b.FocusBorderFg != ColorDefault {
+			return b.FocusBorderFg
+		}
+		if fg == ColorDefault {
+			return b.BorderFg
+		}
+		return fg
+	}
+
+	// the edges join into corners where they meet
+	g := b.BorderStyle.glyphs()
 	if b.BorderTop {
-		buf.Merge(NewHline(x0, y0, x1-x0, b.BorderFg, b.BorderBg).Buffer())
+		buf.drawHLine(g, x0, y0, x1-x0+1, sideFg(b.BorderTopFg), b.BorderBg)
 	}
 	if b.BorderBottom {
-		buf.Merge(NewHline(x0, y1, x1-x0, b.BorderFg, b.BorderBg).Buffer())
+		buf.drawHLine(g, x0, y1, x1-x0+1, sideFg(b.BorderBottomFg), b.BorderBg)
 	}
 	if b.BorderLeft {
-		buf.Merge(NewVline(x0, y0, y1-y0, b.BorderFg, b.BorderBg).Buffer())
+		buf.drawVLine(g, x0, y0, y1-y0+1, sideFg(b.BorderLeftFg), b.BorderBg)
 	}
 	if b.BorderRight {
-		buf.Merge(NewVline(x1, y0, y1-y0, b.BorderFg, b.BorderBg).Buffer())
+		buf.drawVLine(g, x1, y0, y1-y0+1, sideFg(b.BorderRightFg), b.BorderBg)
 	}
+}
 
-	// draw corners
-	if b.BorderTop && b.BorderLeft && b.area.Dx() > 0 && b.area.Dy() > 0 {
-		buf.Set(x0, y0, Cell{TOP_LEFT, b.BorderFg, b.BorderBg})
-	}
-	if b.BorderTop && b.BorderRight && b.area.Dx() > 1 && b.area.Dy() > 0 {
-		buf.Set(x1, y0, Cell{TOP_RIGHT, b.BorderFg, b.BorderBg})
-	}
-	if b.BorderBottom && b.BorderLeft && b.area.Dx() > 0 && b.area.Dy() > 1 {
-		buf.Set(x0, y1, Cell{BOTTOM_LEFT, b.BorderFg, b.BorderBg})
-	}
-	if b.BorderBottom && b.BorderRight && b.area.Dx() > 1 && b.area.Dy() > 1 {
-		buf.Set(x1, y1, Cell{BOTTOM_RIGHT, b.BorderFg, b.BorderBg})
+func (b *Block) drawBorderLabel(buf Buffer) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	// the label sits on the top border
+	if !b.Border || !b.BorderTop {
+		return
 	}
+	room := b.area.Dx() - 2
+	tx := fitCells(DefaultTxBuilder.Build(b.BorderLabel, b.BorderLabelFg, b.BorderLabelBg), room)
+	x := labelOffset(cellsWidth(tx), room, b.BorderLabelAlign, AlignLeft, AlignCenterHorizontal, AlignRight, false)
+	buf.DrawCells(b.area.Min.X+1+x, b.area.Min.Y, tx)
 }
 
-func (b *Block) drawBorderLabel(buf Buffer) {
+// drawBorderCaption draws BorderCaption on the bottom or the right border.
+func (b *Block) drawBorderCaption(buf Buffer) {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
-	maxTxtW := b.area.Dx() - 2
-	tx := DTrimTxCls(DefaultTxBuilder.Build(b.BorderLabel, b.BorderLabelFg, b.BorderLabelBg), maxTxtW)
+	if !b.Border || b.BorderCaption == "" {
+		return
+	}
+	cs := DefaultTxBuilder.Build(b.BorderCaption, b.BorderCaptionFg, b.BorderCaptionBg)
+
+	if b.BorderCaptionEdge == AlignRight {
+		if !b.BorderRight {
+			return
+		}
+		// one cell a row down the border
+		room := b.area.Dy() - 2
+		if room <= 0 {
+			return
+		}
+		if len(cs) > room {
+			cs = append(cs[:room-1:room-1], Cell{'…', cs[room-1].Fg, cs[room-1].Bg})
+		}
+		y := labelOffset(len(cs), room, b.BorderCaptionAlign, AlignTop, AlignCenterVertical, AlignBottom, true)
+		for i, c := range cs {
+			buf.Set(b.area.Max.X-1, b.area.Min.Y+1+y+i, c)
+		}
+		return
+	}
+
+	if !b.BorderBottom {
+		return
+	}
+	room := b.area.Dx() - 2
+	cs = fitCells(cs, room)
+	x := labelOffset(cellsWidth(cs), room, b.BorderCaptionAlign, AlignLeft, AlignCenterHorizontal, AlignRight, true)
+	buf.DrawCells(b.area.Min.X+1+x, b.area.Max.Y-1, cs)
+}
 
-	for i, w := 0, 0; i < len(tx); i++ {
-		buf.Set(b.area.Min.X+1+w, b.area.Min.Y, tx[i])
-		w += tx[i].Width()
+// labelOffset returns where a label n cells long starts within room cells:
+// at the start, centred or at the end as a holds start, center or end, at
+// the end when it holds none of them and atEnd.
+func labelOffset(n, room int, a, start, center, end Align, atEnd bool) int {
+	pad := room - n
+	switch {
+	case pad <= 0 || a&start == start:
+		return 0
+	case a&center == center:
+		return pad / 2
+	case a&end == end || atEnd:
+		return pad
 	}
+	return 0
 }
 
 // Block is a base struct for all other upper level widgets,
@@ -165,6 +222,33 @@ type Block struct {
 	BorderLabel   string
 	BorderLabelFg Attribute
 	BorderLabelBg Attribute
+
+	// BorderLabelAlign places the label on the top border: AlignLeft, the
+	// default, AlignCenterHorizontal or AlignRight. A label too long for
+	// the border ends with '…'.
+	BorderLabelAlign Align
+
+	// BorderCaption is a second label drawn on the bottom border, or down
+	// the right one if BorderCaptionEdge is AlignRight, e.g. a hotkey hint
+	// like "[q](fg-bold) quit" or a live value. BorderCaptionAlign places
+	// it along its border, by default at the right end or the bottom.
+	BorderCaption      string
+	BorderCaptionFg    Attribute
+	BorderCaptionBg    Attribute
+	BorderCaptionEdge  Align
+	BorderCaptionAlign Align
+
+	// BorderStyle is the set of glyphs of the border, the zero one being
+	// BorderSingle, e.g. BorderRounded or BorderASCII.
+	BorderStyle BorderSet
+	// BorderTopFg, BorderBottomFg, BorderLeftFg and BorderRightFg colour
+	// their side instead of BorderFg unless ColorDefault. The left and
+	// right sides colour the corners.
+	BorderTopFg    Attribute
+	BorderBottomFg Attribute
+	BorderLeftFg   Attribute
+	BorderRightFg  Attribute
+
 	Display       bool
 	Bg            Attribute
 	Width         int
@@ -175,6 +259,25 @@ type Block struct {
 	PaddingRight  int
 	id            string
 	Float         Align
+
+	// Visible toggles whether the widget is drawn. A hidden widget keeps its
+	// place in the Grid unless CollapseWhenHidden is set.
+	Visible            bool
+	CollapseWhenHidden bool
+	drawn              image.Rectangle // area of the last visible Buffer
+
+	// ZIndex orders the bufferers of a Render, higher ones are drawn on top.
+	ZIndex int
+
+	// FocusBorderFg is the border colour while the widget has the keyboard
+	// focus, see FocusManager.
+	FocusBorderFg Attribute
+	focused       bool
+
+	// Keys holds the chords handled while the widget has the focus.
+	Keys *KeyMap
+
+	themed []themedAttr
 }
 
 // NewBlock returns a *Block which inherits styles from current theme.
@@ -186,15 +289,20 @@ func NewBlock() *Block {
 	b.BorderRight = true
 	b.BorderTop = true
 	b.BorderBottom = true
+	b.BorderStyle = DefaultBorderStyle
 	b.BorderBg = ThemeAttr("border.bg")
 	b.BorderFg = ThemeAttr("border.fg")
+	b.FocusBorderFg = ThemeAttr("border.focus.fg")
 	b.BorderLabelBg = ThemeAttr("label.bg")
 	b.BorderLabelFg = ThemeAttr("label.fg")
+	b.BorderCaptionBg = ThemeAttr("label.bg")
+	b.BorderCaptionFg = ThemeAttr("label.fg")
 	b.Bg = ThemeAttr("block.bg")
 	b.Width = 2
 	b.Height = 2
 	b.id = GenId()
 	b.Float = AlignNone
+	b.Visible = true
 	return &b
 }
 
@@ -202,6 +310,36 @@ func (b *Block) Id() string {
 	return b.id
 }
 
+// Focus is called by FocusManager when the widget gets the keyboard focus.
+func (b *Block) Focus() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.focused = true
+}
+
+// Blur is called by FocusManager when the widget loses the keyboard focus.
+func (b *Block) Blur() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.focused = false
+}
+
+// Focused tells if the widget has the keyboard focus.
+func (b *Block) Focused() bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.focused
+}
+
+// blocker is implemented by every widget embedding a Block.
+type blocker interface {
+	block() *Block
+}
+
+func (b *Block) block() *Block {
+	return b
+}
+
 // Align computes box model
 func (b *Block) Align() {
 	b.lock.Lock()
@@ -209,18 +347,18 @@ func (b *Block) Align() {
 	// outer
 	b.area.Min.X = 0
 	b.area.Min.Y = 0
-	b.area.Max.X = b.Width
-	b.area.Max.Y = b.Height
+	b.area.Max.X = max0(b.Width)
+	b.area.Max.Y = max0(b.Height)
 
 	// float
 	b.area = AlignArea(TermRect(), b.area, b.Float)
 	b.area = MoveArea(b.area, b.X, b.Y)
 
-	// inner
-	b.innerArea.Min.X = b.area.Min.X + b.PaddingLeft
-	b.innerArea.Min.Y = b.area.Min.Y + b.PaddingTop
-	b.innerArea.Max.X = b.area.Max.X - b.PaddingRight
-	b.innerArea.Max.Y = b.area.Max.Y - b.PaddingBottom
+	// inner, negative padding counts as none
+	b.innerArea.Min.X = b.area.Min.X + max0(b.PaddingLeft)
+	b.innerArea.Min.Y = b.area.Min.Y + max0(b.PaddingTop)
+	b.innerArea.Max.X = b.area.Max.X - max0(b.PaddingRight)
+	b.innerArea.Max.Y = b.area.Max.Y - max0(b.PaddingBottom)
 
 	if b.Border {
 		if b.BorderLeft {
@@ -236,6 +374,27 @@ func (b *Block) Align() {
 			b.innerArea.Max.Y--
 		}
 	}
+
+	// oversized padding leaves an empty inner area instead of an inverted one
+	if b.innerArea.Min.X > b.area.Max.X {
+		b.innerArea.Min.X = b.area.Max.X
+	}
+	if b.innerArea.Min.Y > b.area.Max.Y {
+		b.innerArea.Min.Y = b.area.Max.Y
+	}
+	if b.innerArea.Max.X < b.innerArea.Min.X {
+		b.innerArea.Max.X = b.innerArea.Min.X
+	}
+	if b.innerArea.Max.Y < b.innerArea.Min.Y {
+		b.innerArea.Max.Y = b.innerArea.Min.Y
+	}
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
 }
 
 // InnerBounds returns the internal bounds of the block after aligning and
@@ -251,6 +410,15 @@ func (b *Block) InnerBounds() image.Rectangle {
 // Draw background and border (if any).
 func (b *Block) Buffer() Buffer {
 	b.Align()
+	b.lock.Lock()
+	if b.drawn != b.area {
+		// the render blanks what the frame does not draw over
+		queueStaleArea(b.drawn)
+	}
+	b.drawn = b.area
+	b.lock.Unlock()
+	clearHotspots(b)
+
 	b.lock.RLock()
 	defer b.lock.RUnlock()
 
@@ -260,10 +428,32 @@ func (b *Block) Buffer() Buffer {
 
 	b.drawBorder(buf)
 	b.drawBorderLabel(buf)
+	b.drawBorderCaption(buf)
 
 	return buf
 }
 
+// drawEmptyText centers the placeholder s in the inner area, data widgets
+// use it when they have nothing to show. The caller must hold the lock.
+func (b *Block) drawEmptyText(buf Buffer, s string, fg Attribute) {
+	b.drawEmptyTextIn(buf, b.innerArea, s, fg)
+}
+
+// drawEmptyTextIn centers the placeholder s in r, a part of the inner
+// area left for the data.
+func (b *Block) drawEmptyTextIn(buf Buffer, r image.Rectangle, s string, fg Attribute) {
+	if s == "" || r.Empty() {
+		return
+	}
+	cs := fitCells(DefaultTxBuilder.Build(s, fg, b.Bg), r.Dx())
+	x := r.Min.X + (r.Dx()-cellsWidth(cs))/2
+	y := r.Min.Y + (r.Dy()-1)/2
+	for _, c := range cs {
+		buf.Set(x, y, c)
+		x += c.Width()
+	}
+}
+
 // GetHeight implements GridBufferer.
 // It returns current height of the block.
 func (b *Block) GetHeight() int {
@@ -316,3 +506,79 @@ func (b *Block) InnerY() int {
 	defer b.lock.RUnlock()
 	return b.innerArea.Min.Y
 }
+
+// staleAreas holds the areas widgets left since the last render, by moving,
+// shrinking or being hidden.
+var staleAreas struct {
+	sync.Mutex
+	rs []image.Rectangle
+}
+
+func queueStaleArea(r image.Rectangle) {
+	if r.Empty() {
+		return
+	}
+	staleAreas.Lock()
+	defer staleAreas.Unlock()
+	staleAreas.rs = append(staleAreas.rs, r)
+}
+
+func takeStaleAreas() []image.Rectangle {
+	staleAreas.Lock()
+	defer staleAreas.Unlock()
+	rs := staleAreas.rs
+	staleAreas.rs = nil
+	return rs
+}
+
+// bufferOf returns b's buffer, or an empty one if b is a hidden widget. The
+// area a hidden widget was last drawn at is queued to be cleared by the
+// next render so no ghost remains.
+func bufferOf(b Bufferer) Buffer {
+	blk, ok := b.(blocker)
+	if !ok {
+		if f, ok := b.(PrerenderedFrame); ok {
+			for _, h := range f.hits {
+				noteHit(h.k, h.w)
+			}
+		}
+		return b.Buffer()
+	}
+
+	k := blk.block()
+	k.lock.Lock()
+	if k.Visible {
+		k.lock.Unlock()
+		noteHit(k, b)
+		buf := b.Buffer()
+		drawScrollbars(k, buf)
+		return buf
+	}
+	k.lock.Unlock()
+	undraw(k)
+	return NewBuffer()
+}
+
+// undraw queues the area k was last drawn at to be cleared by the next
+// render and takes k out of the hit tests.
+func undraw(k *Block) {
+	k.lock.Lock()
+	drawn := k.drawn
+	k.drawn = image.ZR
+	k.lock.Unlock()
+	unregisterHit(k)
+
+	queueStaleArea(drawn)
+}
+
+// collapsed reports whether b is hidden and gives its space up to others.
+func collapsed(b Bufferer) bool {
+	blk, ok := b.(blocker)
+	if !ok {
+		return false
+	}
+	k := blk.block()
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return !k.Visible && k.CollapseWhenHidden
+}
@@ -18,3 +18,4 @@ const HORIZONTAL_DOWN = '┬'
 const HORIZONTAL_UP = '┴'
 const QUOTA_LEFT = '«'
 const QUOTA_RIGHT = '»'
+const CROSS = '┼'
@@ -5,6 +5,8 @@
 package termui
 
 import (
+	"fmt"
+	"image"
 	"testing"
 )
 
@@ -69,4 +71,155 @@ func TestBlockInnerBounds(t *testing.T) {
 
 	b.PaddingRight = 5
 	assert("border, 2b 3t 4l 5r padding", 15, 15, 1, 6)
+
+	b.PaddingRight = 20
+	assert("oversized right padding", 15, 15, 0, 6)
+
+	b.PaddingLeft = 30
+	b.PaddingRight = 0
+	assert("oversized left padding", 22, 15, 0, 6)
+
+	b.PaddingLeft = -1
+	b.PaddingRight = -1
+	assert("negative padding", 11, 15, 10, 6)
+}
+
+func TestBlockVisible(t *testing.T) {
+	b := NewBlock()
+	b.Width = 4
+	b.Height = 3
+
+	if buf := bufferOf(b); len(buf.CellMap) == 0 {
+		t.Fatal("visible block should draw")
+	}
+	takeStaleAreas()
+
+	b.Visible = false
+	if buf := bufferOf(b); len(buf.CellMap) != 0 {
+		t.Error("hidden block should return an empty buffer")
+	}
+	rs := takeStaleAreas()
+	if len(rs) != 1 || rs[0].Dx() != 4 || rs[0].Dy() != 3 {
+		t.Errorf("hidden block should queue its last area, got %v", rs)
+	}
+
+	bufferOf(b)
+	if rs := takeStaleAreas(); len(rs) != 0 {
+		t.Errorf("stale area should be queued once, got %v", rs)
+	}
+}
+
+func TestBlockBorderEdges(t *testing.T) {
+	for m := 0; m < 16; m++ {
+		top, bottom, left, right := m&1 != 0, m&2 != 0, m&4 != 0, m&8 != 0
+
+		b := NewBlock()
+		b.Width, b.Height = 6, 4
+		b.BorderTop, b.BorderBottom, b.BorderLeft, b.BorderRight = top, bottom, left, right
+		buf := b.Buffer()
+		name := fmt.Sprintf("top=%t bottom=%t left=%t right=%t", top, bottom, left, right)
+
+		in := b.InnerBounds()
+		want := image.Rect(0, 0, 6, 4)
+		if top {
+			want.Min.Y++
+		}
+		if bottom {
+			want.Max.Y--
+		}
+		if left {
+			want.Min.X++
+		}
+		if right {
+			want.Max.X--
+		}
+		if in != want {
+			t.Errorf("%s: expected inner area %v but got %v", name, want, in)
+		}
+
+		// expected rune of each border cell
+		at := func(x, y int) rune {
+			h := (y == 0 && top) || (y == 3 && bottom)
+			v := (x == 0 && left) || (x == 5 && right)
+			switch {
+			case h && v && y == 0 && x == 0:
+				return TOP_LEFT
+			case h && v && y == 0:
+				return TOP_RIGHT
+			case h && v && x == 0:
+				return BOTTOM_LEFT
+			case h && v:
+				return BOTTOM_RIGHT
+			case h:
+				return HORIZONTAL_LINE
+			case v:
+				return VERTICAL_LINE
+			}
+			return ' '
+		}
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 6; x++ {
+				if c := buf.At(x, y).Ch; c != at(x, y) {
+					t.Errorf("%s: expected %q at (%d,%d) but got %q", name, at(x, y), x, y, c)
+				}
+			}
+		}
+	}
+}
+
+func TestBlockBorderStyle(t *testing.T) {
+	b := NewBlock()
+	b.Width, b.Height = 4, 3
+	b.BorderLabel = "x"
+	b.BorderStyle = BorderRounded
+	b.BorderLeftFg = ColorRed
+	buf := b.Buffer()
+	rows := bufferRows(buf, image.Rect(0, 0, 4, 3))
+	if rows[0] != "╭x─╮" || rows[2] != "╰──╯" {
+		t.Errorf("expected a rounded border, got %q", rows)
+	}
+	if buf.At(0, 1).Fg != ColorRed || buf.At(3, 1).Fg != b.BorderFg || buf.At(1, 2).Fg != b.BorderFg {
+		t.Error("expected the left side red and the others in BorderFg")
+	}
+
+	// the label is not drawn without a top border
+	b.BorderTop = false
+	b.BorderStyle = BorderASCII
+	rows = bufferRows(b.Buffer(), image.Rect(0, 0, 4, 3))
+	if rows[0] != "|  |" || rows[2] != "+--+" {
+		t.Errorf("expected an ASCII border open at the top, got %q", rows)
+	}
+
+	if defaultBorderStyle("dumb") != BorderASCII || defaultBorderStyle("xterm") != BorderSingle {
+		t.Error("expected ASCII borders on dumb terminals only")
+	}
+}
+
+func TestBlockBorderCaption(t *testing.T) {
+	b := NewBlock()
+	b.Width, b.Height = 8, 4
+	b.BorderLabel = "ab"
+	b.BorderLabelAlign = AlignCenterHorizontal
+	b.BorderCaption = "[q](fg-bold) quit"
+	rows := bufferRows(b.Buffer(), image.Rect(0, 0, 8, 4))
+	if rows[0] != "┌──ab──┐" || rows[3] != "└q quit┘" {
+		t.Errorf("expected a centred label and a caption at the bottom, got %q", rows)
+	}
+
+	b.BorderLabel = "a long title"
+	b.BorderLabelAlign = AlignRight
+	b.BorderCaption = "12"
+	b.BorderCaptionAlign = AlignLeft
+	rows = bufferRows(b.Buffer(), image.Rect(0, 0, 8, 4))
+	if rows[0] != "┌a lon…┐" || rows[3] != "└12────┘" {
+		t.Errorf("expected an ellipsized label and a caption on the left, got %q", rows)
+	}
+
+	b.BorderCaptionEdge = AlignRight
+	b.BorderCaptionAlign = AlignNone
+	b.BorderCaption = "xyz"
+	rows = bufferRows(b.Buffer(), image.Rect(0, 0, 8, 4))
+	if rows[1] != "│      x" || rows[2] != "│      …" {
+		t.Errorf("expected the caption down the right border, got %q", rows)
+	}
 }
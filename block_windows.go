@@ -12,3 +12,8 @@ const HORIZONTAL_LINE = '-'
 const TOP_LEFT = '+'
 const BOTTOM_RIGHT = '+'
 const BOTTOM_LEFT = '+'
+const VERTICAL_LEFT = '+'
+const VERTICAL_RIGHT = '+'
+const HORIZONTAL_DOWN = '+'
+const HORIZONTAL_UP = '+'
+const CROSS = '+'
@@ -0,0 +1,68 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "os"
+
+// BorderSet is the glyphs a border is drawn with. The joints are used
+// where the border meets other lines, e.g. the separators of a Menu.
+type BorderSet struct {
+	Horizontal, Vertical                       rune
+	TopLeft, TopRight, BottomLeft, BottomRight rune
+
+	VerticalLeft, VerticalRight, HorizontalDown, HorizontalUp, Cross rune
+}
+
+// The border sets of Block.BorderStyle.
+var (
+	// BorderSingle is the thin line of this platform, '+', '-' and '|'
+	// on Windows.
+	BorderSingle = BorderSet{
+		HORIZONTAL_LINE, VERTICAL_LINE,
+		TOP_LEFT, TOP_RIGHT, BOTTOM_LEFT, BOTTOM_RIGHT,
+		VERTICAL_LEFT, VERTICAL_RIGHT, HORIZONTAL_DOWN, HORIZONTAL_UP, CROSS,
+	}
+	BorderRounded = BorderSet{'─', '│', '╭', '╮', '╰', '╯', '┤', '├', '┬', '┴', '┼'}
+	BorderDouble  = BorderSet{'═', '║', '╔', '╗', '╚', '╝', '╣', '╠', '╦', '╩', '╬'}
+	BorderThick   = BorderSet{'━', '┃', '┏', '┓', '┗', '┛', '┫', '┣', '┳', '┻', '╋'}
+	// BorderASCII draws on any terminal, the dumb ones included.
+	BorderASCII = BorderSet{'-', '|', '+', '+', '+', '+', '+', '+', '+', '+', '+'}
+)
+
+// DefaultBorderStyle is the BorderStyle NewBlock gives, BorderASCII if
+// $TERM is "dumb", BorderSingle otherwise.
+var DefaultBorderStyle = defaultBorderStyle(os.Getenv("TERM"))
+
+func defaultBorderStyle(term string) BorderSet {
+	if term == "dumb" {
+		return BorderASCII
+	}
+	return BorderSingle
+}
+
+// glyphs returns the glyphs of s by their arms, see boxArms. The zero
+// BorderSet is BorderSingle.
+func (s BorderSet) glyphs() *[16]rune {
+	if s == (BorderSet{}) {
+		return &boxGlyphs
+	}
+	return &[16]rune{
+		armLeft:                              s.Horizontal,
+		armRight:                             s.Horizontal,
+		armLeft | armRight:                   s.Horizontal,
+		armUp:                                s.Vertical,
+		armDown:                              s.Vertical,
+		armUp | armDown:                      s.Vertical,
+		armDown | armRight:                   s.TopLeft,
+		armDown | armLeft:                    s.TopRight,
+		armUp | armRight:                     s.BottomLeft,
+		armUp | armLeft:                      s.BottomRight,
+		armUp | armDown | armRight:           s.VerticalRight,
+		armUp | armDown | armLeft:            s.VerticalLeft,
+		armLeft | armRight | armDown:         s.HorizontalDown,
+		armLeft | armRight | armUp:           s.HorizontalUp,
+		armUp | armDown | armLeft | armRight: s.Cross,
+	}
+}
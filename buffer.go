@@ -6,7 +6,8 @@ package termui
 
 import "image"
 
-// Cell is a rune with assigned Fg and Bg
+// Cell is a rune with assigned Fg and Bg. The rune may stand for a grapheme
+// cluster, CellsToStr gives the text of cells.
 type Cell struct {
 	Ch rune
 	Fg Attribute
@@ -24,9 +25,44 @@ func (b Buffer) At(x, y int) Cell {
 	return b.CellMap[image.Pt(x, y)]
 }
 
-// Set assigns a char to (x,y)
+// Set assigns a char to (x,y). A wide char also takes (x+1,y), and one of
+// the glyphs it overwrites a half of is replaced by a space, so a glyph is
+// never split. A wide char set in the last column of the Area is too.
 func (b Buffer) Set(x, y int, c Cell) {
-	b.CellMap[image.Pt(x, y)] = c
+	p := image.Pt(x, y)
+	if c.Ch != wideCont {
+		b.unsplit(p)
+	}
+	if c.Width() == 2 {
+		q := p.Add(image.Pt(1, 0))
+		if !b.Area.Empty() && p.In(b.Area) && !q.In(b.Area) {
+			c.Ch = ' '
+		} else {
+			b.unsplit(q)
+			b.CellMap[q] = Cell{wideCont, c.Fg, c.Bg}
+		}
+	}
+	b.CellMap[p] = c
+}
+
+// unsplit blanks the other half of the wide glyph p is part of, if any.
+func (b Buffer) unsplit(p image.Point) {
+	old, ok := b.CellMap[p]
+	if !ok {
+		return
+	}
+	switch {
+	case old.Ch == wideCont:
+		l := p.Sub(image.Pt(1, 0))
+		if c, ok := b.CellMap[l]; ok && c.Width() == 2 {
+			b.CellMap[l] = Cell{' ', c.Fg, c.Bg}
+		}
+	case old.Width() == 2:
+		r := p.Add(image.Pt(1, 0))
+		if c, ok := b.CellMap[r]; ok && c.Ch == wideCont {
+			b.CellMap[r] = Cell{' ', c.Fg, c.Bg}
+		}
+	}
 }
 
 // Bounds returns the domain for which At can return non-zero color.
@@ -84,11 +120,7 @@ func NewBuffer() Buffer {
 
 // Fill fills the Buffer b with ch,fg and bg.
 func (b Buffer) Fill(ch rune, fg, bg Attribute) {
-	for x := b.Area.Min.X; x < b.Area.Max.X; x++ {
-		for y := b.Area.Min.Y; y < b.Area.Max.Y; y++ {
-			b.Set(x, y, Cell{ch, fg, bg})
-		}
-	}
+	b.FillRect(b.Area, ch, fg, bg)
 }
 
 // NewFilledBuffer returns a new Buffer filled with ch, fb and bg.
@@ -96,11 +128,6 @@ func NewFilledBuffer(x0, y0, x1, y1 int, ch rune, fg, bg Attribute) Buffer {
 	buf := NewBuffer()
 	buf.Area.Min = image.Pt(x0, y0)
 	buf.Area.Max = image.Pt(x1, y1)
-
-	for x := buf.Area.Min.X; x < buf.Area.Max.X; x++ {
-		for y := buf.Area.Min.Y; y < buf.Area.Max.Y; y++ {
-			buf.Set(x, y, Cell{ch, fg, bg})
-		}
-	}
+	buf.Fill(ch, fg, bg)
 	return buf
 }
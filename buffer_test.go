@@ -21,3 +21,53 @@ func TestBufferUnion(t *testing.T) {
 		t.Errorf("Buffer.Merge unions Area failed: should:%v, actual %v,%v", image.Rect(0, 0, 50, 0).Union(image.Rect(0, 0, 100, 100)), b1.Area, b0.Area)
 	}
 }
+
+// bufferRow returns the text of row y of buf from x0 to x1, cells never
+// drawn read as spaces and the covered half of a wide glyph skipped.
+func bufferRow(buf Buffer, y, x0, x1 int) string {
+	rs := []rune{}
+	for x := x0; x < x1; x++ {
+		switch c := buf.At(x, y); c.Ch {
+		case 0:
+			rs = append(rs, ' ')
+		case wideCont:
+		default:
+			rs = append(rs, c.Ch)
+		}
+	}
+	return string(rs)
+}
+
+// bufferRows returns the text of buf over r, a string per row.
+func bufferRows(buf Buffer, r image.Rectangle) []string {
+	rows := []string{}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		rows = append(rows, bufferRow(buf, y, r.Min.X, r.Max.X))
+	}
+	return rows
+}
+
+func TestBufferDraw(t *testing.T) {
+	buf := NewBuffer()
+	buf.DrawRect(image.Rect(0, 0, 7, 5), ColorWhite, ColorDefault)
+	buf.DrawHLine(0, 2, 7, ColorWhite, ColorDefault)
+	buf.DrawVLine(3, 0, 5, ColorWhite, ColorDefault)
+	buf.DrawLine(8, 0, 10, 2, ColorWhite, ColorDefault)
+	buf.DrawText(1, 1, "[a](fg-red)b", ColorWhite, ColorDefault)
+
+	want := []string{
+		"┌──┬──┐ ──┐",
+		"│ab│  │   │",
+		"├──┼──┤   │",
+		"│  │  │    ",
+		"└──┴──┘    ",
+	}
+	for y, s := range bufferRows(buf, image.Rect(0, 0, 11, 5)) {
+		if s != want[y] {
+			t.Errorf("row %d: expected %q, got %q", y, want[y], s)
+		}
+	}
+	if buf.At(1, 1).Fg != ColorRed || buf.At(2, 1).Fg != ColorWhite {
+		t.Error("expected the markup of the text styled")
+	}
+}
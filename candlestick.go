@@ -0,0 +1,184 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// OHLC is one period of a Candlestick chart: its open, high, low and close
+// prices, and when it started.
+type OHLC struct {
+	Open, High, Low, Close float64
+	Time                   time.Time
+}
+
+// Candlestick is an OHLC chart, a candle per period, its body spanning the
+// open and close prices in UpColor when the price closed higher and
+// DownColor otherwise, and its wick spanning the low and high prices. The
+// price axis scales to the candles shown, the latest ones fitting in the
+// widget, unless PriceAxis has fixed bounds, and the time axis labels them
+// with TimeFormat.
+/*
+  cs := termui.NewCandlestick()
+  cs.BorderLabel = "ACME"
+  cs.Data = []termui.OHLC{
+      {Open: 10, High: 12, Low: 9, Close: 11, Time: day1},
+      {Open: 11, High: 11.5, Low: 8, Close: 8.5, Time: day2},
+  }
+  cs.Width = 50
+  cs.Height = 15
+*/
+type Candlestick struct {
+	Block
+	Data       []OHLC
+	UpColor    Attribute
+	DownColor  Attribute
+	AxesColor  Attribute
+	PriceAxis  Axis   // scales and labels the prices
+	TimeFormat string // layout of the time labels, see time.Time.Format
+	CandleGap  int    // columns between two candles
+	EmptyText  string // shown centered when Data is empty
+	EmptyFg    Attribute
+}
+
+// NewCandlestick returns a new *Candlestick with current theme.
+func NewCandlestick() *Candlestick {
+	cs := &Candlestick{Block: *NewBlock()}
+	cs.themeAttr(&cs.UpColor, "candlestick.up.fg")
+	cs.themeAttr(&cs.DownColor, "candlestick.down.fg")
+	cs.themeAttr(&cs.AxesColor, "candlestick.axes.fg")
+	cs.themeAttr(&cs.PriceAxis.GridColor, "candlestick.grid.fg")
+	cs.TimeFormat = "01/02"
+	cs.CandleGap = 1
+	return cs
+}
+
+// Buffer implements Bufferer interface.
+func (cs *Candlestick) Buffer() Buffer {
+	buf := cs.Block.Buffer()
+	cs.RLock()
+	defer cs.RUnlock()
+
+	// price rows, the axis, then the time labels
+	h := cs.innerArea.Dy() - 2
+	if len(cs.Data) == 0 || h < 1 {
+		cs.drawEmptyText(buf, cs.EmptyText, cs.EmptyFg)
+		return buf
+	}
+
+	// the widest price label, found with the scale of every candle so the
+	// labels seldom get cut once the visible ones are known
+	lo, hi := cs.bounds(cs.Data)
+	_, _, ticks := cs.PriceAxis.Scale(lo, hi, h-1, 2)
+	labelW := ticksWidth(ticks)
+	x0 := cs.innerArea.Min.X + labelW + 1
+	step := 1 + cs.CandleGap
+	if step < 1 {
+		step = 1
+	}
+	n := (cs.innerArea.Max.X - x0 + cs.CandleGap) / step
+	if n <= 0 {
+		return buf
+	}
+	data := cs.Data
+	if len(data) > n {
+		data = data[len(data)-n:]
+	}
+	lo, hi = cs.bounds(data)
+	lo, hi, ticks = cs.PriceAxis.Scale(lo, hi, h-1, 2)
+
+	bottom := cs.innerArea.Min.Y + h - 1
+	row := func(v float64) int {
+		q := cs.PriceAxis.Offset(v, lo, hi, h-1) + 0.5
+		switch {
+		case !(q >= 0):
+			return bottom
+		case q > float64(h-1):
+			return cs.innerArea.Min.Y
+		}
+		return bottom - int(q)
+	}
+	cs.plotAxes(buf, x0, h, labelW, ticks, data, step)
+
+	for i, d := range data {
+		x := x0 + i*step
+		fg := cs.UpColor
+		if d.Close < d.Open {
+			fg = cs.DownColor
+		}
+		for y := row(d.High); y <= row(d.Low); y++ {
+			buf.Set(x, y, Cell{'│', fg, cs.Bg})
+		}
+		top, bot := row(math.Max(d.Open, d.Close)), row(math.Min(d.Open, d.Close))
+		body := '█'
+		if d.Open == d.Close {
+			body = '─'
+		}
+		for y := top; y <= bot; y++ {
+			buf.Set(x, y, Cell{body, fg, cs.Bg})
+		}
+	}
+	return buf
+}
+
+// bounds returns the lowest low and the highest high of data.
+func (cs *Candlestick) bounds(data []OHLC) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, d := range data {
+		lo = math.Min(lo, math.Min(d.Low, math.Min(d.Open, d.Close)))
+		hi = math.Max(hi, math.Max(d.High, math.Max(d.Open, d.Close)))
+	}
+	return lo, hi
+}
+
+// plotAxes draws the price axis left of x0, labelled at ticks with labels
+// up to labelW wide, and the time axis under its h rows, a label under the
+// candles with room for it.
+func (cs *Candlestick) plotAxes(buf Buffer, x0, h, labelW int, ticks []Tick, data []OHLC, step int) {
+	origY := cs.innerArea.Min.Y + h
+	origX := x0 - 1
+	buf.Set(origX, origY, Cell{ORIGIN, cs.AxesColor, cs.Bg})
+	for x := x0; x < cs.innerArea.Max.X; x++ {
+		buf.Set(x, origY, Cell{HDASH, cs.AxesColor, cs.Bg})
+	}
+	for y := cs.innerArea.Min.Y; y < origY; y++ {
+		buf.Set(origX, y, Cell{VDASH, cs.AxesColor, cs.Bg})
+	}
+
+	cs.drawGrid(buf, cs.PriceAxis, ticks, true, x0, cs.innerArea.Max.X, origY-1, 0)
+	for _, t := range ticks {
+		x := cs.innerArea.Min.X
+		for _, r := range trimStr2Runes(t.Label, labelW) {
+			c := Cell{r, cs.AxesColor, cs.Bg}
+			buf.Set(x, origY-1-t.Pos, c)
+			x += c.Width()
+		}
+	}
+
+	next := x0
+	for i, d := range data {
+		x := x0 + i*step
+		if x < next {
+			continue
+		}
+		s := fmt.Sprint(i)
+		if !d.Time.IsZero() {
+			s = d.Time.Format(cs.TimeFormat)
+		}
+		rs := str2runes(s)
+		if x+runesWidth(rs) > cs.innerArea.Max.X {
+			break
+		}
+		for _, r := range rs {
+			c := Cell{r, cs.AxesColor, cs.Bg}
+			buf.Set(x, origY+1, c)
+			x += c.Width()
+		}
+		next = x + 2
+	}
+}
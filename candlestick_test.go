@@ -0,0 +1,59 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandlestick(t *testing.T) {
+	day := time.Date(2016, 3, 1, 0, 0, 0, 0, time.UTC)
+	cs := NewCandlestick()
+	cs.Border = false
+	cs.Width, cs.Height = 20, 7
+	cs.Data = []OHLC{
+		{Open: 100, High: 120, Low: 90, Close: 110, Time: day},
+		{Open: 110, High: 115, Low: 80, Close: 85, Time: day.AddDate(0, 0, 1)},
+	}
+	buf := cs.Buffer()
+
+	// 5 price rows from 120 down to 80, 10 per row, labelled every 20;
+	// labels are 3 wide
+	x := 4
+	col := func(x int) (s string) {
+		for y := 0; y < 5; y++ {
+			s += string(buf.At(x, y).Ch)
+		}
+		return s
+	}
+	if got := col(x); got != "│██│ " {
+		t.Errorf("unexpected rising candle %q", got)
+	}
+	if c := buf.At(x, 1); c.Fg != ColorGreen {
+		t.Errorf("expected a rising candle green, got %v", c.Fg)
+	}
+	if got := col(x + 2); got != "│███│" {
+		t.Errorf("unexpected falling candle %q", got)
+	}
+	if c := buf.At(x+2, 1); c.Fg != ColorRed {
+		t.Errorf("expected a falling candle red, got %v", c.Fg)
+	}
+
+	labels := ""
+	for x := 4; x < 20; x++ {
+		labels += string(buf.At(x, 6).Ch)
+	}
+	// the second date would run into the first
+	if labels != "03/01           " {
+		t.Errorf("unexpected time labels %q", labels)
+	}
+	if got := string([]rune{buf.At(0, 4).Ch, buf.At(1, 4).Ch}); got != "80" {
+		t.Errorf("expected the lowest price at the bottom, got %q", got)
+	}
+	if got := string([]rune{buf.At(0, 2).Ch, buf.At(1, 2).Ch, buf.At(2, 2).Ch}); got != "100" {
+		t.Errorf("expected a tick at 100, got %q", got)
+	}
+}
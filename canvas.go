@@ -4,6 +4,12 @@
 
 package termui
 
+import (
+	"image"
+	"math"
+	"sort"
+)
+
 /*
 dots:
    ,___,
@@ -22,20 +28,27 @@ var brailleOftMap = [4][2]rune{
 	{'\u0004', '\u0020'},
 	{'\u0040', '\u0080'}}
 
-// Canvas contains drawing map: i,j -> rune
-type Canvas map[[2]int]rune
+// Canvas contains drawing map: i,j -> rune, addressed in braille dots where
+// each terminal cell holds 2x4 dots.
+type Canvas struct {
+	dots   map[[2]int]rune
+	colors map[[2]int]Attribute
+}
 
 // NewCanvas returns an empty Canvas
-func NewCanvas() Canvas {
-	return make(map[[2]int]rune)
+func NewCanvas() *Canvas {
+	return &Canvas{
+		dots:   make(map[[2]int]rune),
+		colors: make(map[[2]int]Attribute),
+	}
 }
 
 func chOft(x, y int) rune {
 	return brailleOftMap[y%4][x%2]
 }
 
-func (c Canvas) rawCh(x, y int) rune {
-	if ch, ok := c[[2]int{x, y}]; ok {
+func (c *Canvas) rawCh(x, y int) rune {
+	if ch, ok := c.dots[[2]int{x, y}]; ok {
 		return ch
 	}
 	return '\u0000' //brailleOffset
@@ -43,30 +56,223 @@ func (c Canvas) rawCh(x, y int) rune {
 
 // return coordinate in terminal
 func chPos(x, y int) (int, int) {
-	return y / 4, x / 2
+	return x / 2, y / 4
 }
 
 // Set sets a point (x,y) in the virtual coordinate
-func (c Canvas) Set(x, y int) {
+func (c *Canvas) Set(x, y int) {
 	i, j := chPos(x, y)
 	ch := c.rawCh(i, j)
 	ch |= chOft(x, y)
-	c[[2]int{i, j}] = ch
+	c.dots[[2]int{i, j}] = ch
 }
 
 // Unset removes point (x,y)
-func (c Canvas) Unset(x, y int) {
+func (c *Canvas) Unset(x, y int) {
 	i, j := chPos(x, y)
 	ch := c.rawCh(i, j)
 	ch &= ^chOft(x, y)
-	c[[2]int{i, j}] = ch
+	c.dots[[2]int{i, j}] = ch
 }
 
-// Buffer returns un-styled points
-func (c Canvas) Buffer() Buffer {
+// setColor sets point (x,y) and colors the cell containing it.
+func (c *Canvas) setColor(x, y int, color Attribute) {
+	c.Set(x, y)
+	i, j := chPos(x, y)
+	c.colors[[2]int{i, j}] = color
+}
+
+// SetPoint sets point (x,y) in the virtual coordinate and colors the cell
+// containing it, ColorDefault leaving the color to the drawing widget.
+// Points left or above the origin are clipped.
+func (c *Canvas) SetPoint(x, y int, color Attribute) {
+	if x < 0 || y < 0 {
+		return
+	}
+	c.setColor(x, y, color)
+}
+
+// Line draws a line from (x0,y0) to (x1,y1), both ends included.
+func (c *Canvas) Line(x0, y0, x1, y1 int, color Attribute) {
+	dx, dy := x1-x0, y1-y0
+	sx, sy := 1, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+	// Bresenham, err tracks both axes
+	err := dx - dy
+	for {
+		c.SetPoint(x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e := 2 * err
+		if e > -dy {
+			err -= dy
+			x0 += sx
+		}
+		if e < dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// Polyline draws lines joining points in order.
+func (c *Canvas) Polyline(points []image.Point, color Attribute) {
+	if len(points) == 1 {
+		c.SetPoint(points[0].X, points[0].Y, color)
+	}
+	for i := 1; i < len(points); i++ {
+		p, q := points[i-1], points[i]
+		c.Line(p.X, p.Y, q.X, q.Y, color)
+	}
+}
+
+// FillPolygon fills the polygon with vertices points, given in the virtual
+// coordinate, using the even-odd rule so concave and self-intersecting
+// shapes are filled as expected. Points left or above the origin are clipped.
+func (c *Canvas) FillPolygon(points []image.Point, color Attribute) {
+	if len(points) < 3 {
+		return
+	}
+
+	minY, maxY := points[0].Y, points[0].Y
+	for _, p := range points {
+		if p.Y < minY {
+			minY = p.Y
+		}
+		if p.Y > maxY {
+			maxY = p.Y
+		}
+	}
+	if minY < 0 {
+		minY = 0
+	}
+
+	xs := []float64{}
+	for y := minY; y <= maxY; y++ {
+		// sample each dot row at its center
+		sy := float64(y) + 0.5
+		xs = xs[:0]
+		for i := range points {
+			p, q := points[i], points[(i+1)%len(points)]
+			y0, y1 := float64(p.Y), float64(q.Y)
+			if (y0 <= sy && sy < y1) || (y1 <= sy && sy < y0) {
+				x := float64(p.X) + (sy-y0)*float64(q.X-p.X)/(y1-y0)
+				xs = append(xs, x)
+			}
+		}
+		sort.Float64s(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0 := int(math.Ceil(xs[i] - 0.5))
+			x1 := int(math.Floor(xs[i+1] - 0.5))
+			if x0 < 0 {
+				x0 = 0
+			}
+			for x := x0; x <= x1; x++ {
+				c.setColor(x, y, color)
+			}
+		}
+	}
+}
+
+// Buffer returns the points, styled by FillPolygon where filled.
+func (c *Canvas) Buffer() Buffer {
 	buf := NewBuffer()
-	for k, v := range c {
-		buf.Set(k[0], k[1], Cell{Ch: v + brailleBase})
+	for k, v := range c.dots {
+		buf.Set(k[0], k[1], Cell{Ch: v + brailleBase, Fg: c.colors[k]})
+	}
+	return buf
+}
+
+// BrailleCanvas is a drawing surface of braille dots, 2x4 per cell of its
+// inner area, for scatter plots, maps and other custom drawings. Dots are
+// addressed from the top left of the inner area, see DotSize, and those
+// out of it are not drawn. A cell shows a single color, the last one given
+// to its dots.
+/*
+  bc := termui.NewBrailleCanvas()
+  bc.Width, bc.Height = 22, 7
+  w, h := bc.DotSize()
+  bc.Line(0, h-1, w-1, 0, termui.ColorGreen)
+  bc.SetPoint(w/2, h/2, termui.ColorRed)
+*/
+type BrailleCanvas struct {
+	Block
+	LineColor Attribute // color of the dots drawn in ColorDefault
+	c         *Canvas
+}
+
+// NewBrailleCanvas returns a new empty *BrailleCanvas with current theme.
+func NewBrailleCanvas() *BrailleCanvas {
+	bc := &BrailleCanvas{Block: *NewBlock(), c: NewCanvas()}
+	bc.themeAttr(&bc.LineColor, "canvas.line.fg")
+	return bc
+}
+
+// DotSize returns the number of dots across and down the inner area.
+func (bc *BrailleCanvas) DotSize() (int, int) {
+	bc.Align()
+	bc.RLock()
+	defer bc.RUnlock()
+	return bc.innerArea.Dx() * 2, bc.innerArea.Dy() * 4
+}
+
+// SetPoint sets dot (x,y).
+func (bc *BrailleCanvas) SetPoint(x, y int, color Attribute) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.c.SetPoint(x, y, color)
+}
+
+// Line draws a line of dots from (x0,y0) to (x1,y1).
+func (bc *BrailleCanvas) Line(x0, y0, x1, y1 int, color Attribute) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.c.Line(x0, y0, x1, y1, color)
+}
+
+// Polyline draws lines of dots joining points in order.
+func (bc *BrailleCanvas) Polyline(points []image.Point, color Attribute) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.c.Polyline(points, color)
+}
+
+// FillPolygon fills the polygon with vertices points, see Canvas.FillPolygon.
+func (bc *BrailleCanvas) FillPolygon(points []image.Point, color Attribute) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.c.FillPolygon(points, color)
+}
+
+// Clear removes all the dots.
+func (bc *BrailleCanvas) Clear() {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.c = NewCanvas()
+}
+
+// Buffer implements Bufferer interface.
+func (bc *BrailleCanvas) Buffer() Buffer {
+	buf := bc.Block.Buffer()
+	bc.RLock()
+	defer bc.RUnlock()
+	for k, v := range bc.c.dots {
+		p := bc.innerArea.Min.Add(image.Pt(k[0], k[1]))
+		if v == 0 || !p.In(bc.innerArea) {
+			continue
+		}
+		fg := bc.c.colors[k]
+		if fg == ColorDefault {
+			fg = bc.LineColor
+		}
+		buf.Set(p.X, p.Y, Cell{v + brailleBase, fg, bc.Bg})
 	}
 	return buf
 }
@@ -2,11 +2,10 @@
 // Use of this source code is governed by a MIT license that can
 // be found in the LICENSE file.
 
-// +build ignore
-
 package termui
 
 import (
+	"image"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -55,3 +54,82 @@ func TestCanvasBuffer(t *testing.T) {
 	bufs := c.Buffer()
 	spew.Dump(bufs)
 }
+
+func TestCanvasFillPolygon(t *testing.T) {
+	full := brailleBase + 0xFF
+
+	// a 4x8 dot square covers exactly four cells, 2x2
+	c := NewCanvas()
+	c.FillPolygon([]image.Point{{0, 0}, {4, 0}, {4, 8}, {0, 8}}, ColorRed)
+	buf := c.Buffer()
+	if len(buf.CellMap) != 4 {
+		t.Fatalf("expected 4 cells but got %d", len(buf.CellMap))
+	}
+	for p, cell := range buf.CellMap {
+		if cell.Ch != full || cell.Fg != ColorRed {
+			t.Errorf("cell %v not fully filled: %q %v", p, cell.Ch, cell.Fg)
+		}
+	}
+
+	// even-odd: the inner square of a square-in-square path stays empty
+	c = NewCanvas()
+	c.FillPolygon([]image.Point{
+		{0, 0}, {12, 0}, {12, 12}, {0, 12}, {0, 0},
+		{4, 4}, {4, 8}, {8, 8}, {8, 4}, {4, 4}}, ColorBlue)
+	if c.rawCh(2, 1) != 0 {
+		t.Errorf("hole should not be filled, got %q", c.rawCh(2, 1)+brailleBase)
+	}
+	for _, x := range []int{0, 10} {
+		if c.rawCh(x/2, 1)&chOft(x, 5) == 0 {
+			t.Errorf("dot (%d,5) should be filled", x)
+		}
+	}
+
+	// filling ORs into existing dots
+	c = NewCanvas()
+	c.Set(1, 0)
+	c.FillPolygon([]image.Point{{0, 0}, {1, 0}, {1, 4}, {0, 4}}, ColorGreen)
+	if ch := c.rawCh(0, 0); ch != chOft(0, 0)|chOft(0, 1)|chOft(0, 2)|chOft(0, 3)|chOft(1, 0) {
+		t.Errorf("expected existing dot to be kept, got %b", ch)
+	}
+}
+
+func TestCanvasLine(t *testing.T) {
+	c := NewCanvas()
+	c.Line(3, 3, 0, 0, ColorRed)
+	for i := 0; i < 4; i++ {
+		if c.rawCh(i/2, 0)&chOft(i, i) == 0 {
+			t.Errorf("dot (%d,%d) should be set", i, i)
+		}
+	}
+	if c.rawCh(1, 0)&chOft(3, 0) != 0 {
+		t.Error("expected a diagonal line only")
+	}
+
+	// a polyline joins its points, clipped at the origin
+	c = NewCanvas()
+	c.Polyline([]image.Point{{-2, 0}, {1, 0}, {1, 7}}, 0)
+	want := chOft(0, 0) | chOft(1, 0) | chOft(1, 1) | chOft(1, 2) | chOft(1, 3)
+	if ch := c.rawCh(0, 0); ch != want {
+		t.Errorf("expected %b, got %b", want, ch)
+	}
+	if ch := c.rawCh(0, 1); ch != chOft(1, 4)|chOft(1, 5)|chOft(1, 6)|chOft(1, 7) {
+		t.Errorf("expected the second cell's right column, got %b", ch)
+	}
+}
+
+func TestBrailleCanvas(t *testing.T) {
+	bc := NewBrailleCanvas()
+	bc.Width, bc.Height = 4, 3
+	if w, h := bc.DotSize(); w != 4 || h != 4 {
+		t.Fatalf("expected 4x4 dots, got %dx%d", w, h)
+	}
+	bc.Line(0, 0, 9, 0, 0)
+	buf := bc.Buffer()
+	if c := buf.At(1, 1); c.Ch != brailleBase|chOft(0, 0)|chOft(1, 0) || c.Fg != bc.LineColor {
+		t.Errorf("expected the top dots in the line color, got %q %v", c.Ch, c.Fg)
+	}
+	if c := buf.At(3, 1); c.Ch != VERTICAL_LINE {
+		t.Errorf("expected dots out of the inner area clipped, got %q", c.Ch)
+	}
+}
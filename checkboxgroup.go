@@ -0,0 +1,117 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "sort"
+
+// CheckboxGroup shows Options that can be checked independently.
+/*
+  cg := termui.NewCheckboxGroup("bold", "italic", "underline")
+  cg.BorderLabel = "Style"
+  cg.Height = 5
+*/
+type CheckboxGroup struct {
+	Block
+	Options     []string
+	Cursor      int // row moved by the arrow keys
+	ItemFgColor Attribute
+	ItemBgColor Attribute
+	OnChange    func(index int, checked bool)
+	checked     map[int]bool
+}
+
+// NewCheckboxGroup returns a new *CheckboxGroup with the given options and
+// current theme, nothing is checked.
+func NewCheckboxGroup(options ...string) *CheckboxGroup {
+	cg := &CheckboxGroup{
+		Block:   *NewBlock(),
+		Options: options,
+		checked: make(map[int]bool),
+	}
+	cg.themeAttr(&cg.ItemFgColor, "list.item.fg")
+	cg.themeAttr(&cg.ItemBgColor, "list.item.bg")
+	return cg
+}
+
+// Toggle flips option i and calls OnChange.
+func (cg *CheckboxGroup) Toggle(i int) {
+	cg.Lock()
+	if i < 0 || i >= len(cg.Options) {
+		cg.Unlock()
+		return
+	}
+	on := !cg.checked[i]
+	if on {
+		cg.checked[i] = true
+	} else {
+		delete(cg.checked, i)
+	}
+	cb := cg.OnChange
+	cg.Unlock()
+
+	if cb != nil {
+		cb(i, on)
+	}
+}
+
+// Checked returns the indices of the checked options in ascending order.
+func (cg *CheckboxGroup) Checked() []int {
+	cg.RLock()
+	defer cg.RUnlock()
+	idx := []int{}
+	for i := range cg.checked {
+		if i < len(cg.Options) {
+			idx = append(idx, i)
+		}
+	}
+	sort.Ints(idx)
+	return idx
+}
+
+// FormValue implements FormValuer, the value is the []string of the
+// checked options.
+func (cg *CheckboxGroup) FormValue() interface{} {
+	idx := cg.Checked()
+	cg.RLock()
+	defer cg.RUnlock()
+	vs := make([]string, len(idx))
+	for i, j := range idx {
+		vs[i] = cg.Options[j]
+	}
+	return vs
+}
+
+// HandleKey moves the cursor with <up>/<down> and toggles the option under
+// it with <enter> or <space>. It reports whether key was consumed.
+func (cg *CheckboxGroup) HandleKey(key string) bool {
+	switch key {
+	case KeyArrowUp, KeyArrowDown:
+		cg.Lock()
+		cg.Cursor = moveCursor(cg.Cursor, key, len(cg.Options))
+		cg.Unlock()
+	case KeyEnter, KeySpace:
+		cg.Toggle(cg.Cursor)
+	default:
+		return false
+	}
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (cg *CheckboxGroup) Buffer() Buffer {
+	buf := cg.Block.Buffer()
+	cg.RLock()
+	defer cg.RUnlock()
+
+	markers := make([]string, len(cg.Options))
+	for i := range cg.Options {
+		markers[i] = checkboxOff
+		if cg.checked[i] {
+			markers[i] = checkboxOn
+		}
+	}
+	drawChoices(buf, &cg.Block, markers, cg.Options, cg.Cursor, cg.ItemFgColor, cg.ItemBgColor)
+	return buf
+}
@@ -0,0 +1,110 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Copier is a widget with text to copy, its selection, e.g. the selected
+// row of a List or the lines selected in a LogView.
+type Copier interface {
+	CopyText() string
+}
+
+// ClipboardBackend is a Backend that can set the system clipboard, e.g.
+// through the terminal.
+type ClipboardBackend interface {
+	Backend
+	SetClipboard(text string) error
+}
+
+// SetClipboard implements ClipboardBackend with an OSC 52 escape, which
+// terminals supporting it honour over ssh too.
+func (TermboxBackend) SetClipboard(text string) error {
+	_, err := io.WriteString(os.Stdout, osc52(text))
+	return err
+}
+
+// osc52 returns the escape setting the clipboard to text.
+func osc52(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+}
+
+// clipboardCmds are the commands tried in turn to set the clipboard of the
+// machine termui runs on, the text being written to their standard input.
+var clipboardCmds = func() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+	return [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"termux-clipboard-set"},
+	}
+}()
+
+// ErrNoClipboard is returned by CopyToClipboard when no clipboard could be
+// reached.
+var ErrNoClipboard = errors.New("termui: no clipboard available")
+
+// CopyToClipboard puts text on the system clipboard, through the terminal
+// when the backend is a ClipboardBackend and with the first clipboard
+// command found, e.g. pbcopy or xclip, as the terminal cannot tell whether
+// it supports OSC 52. It fails when neither way was available.
+func CopyToClipboard(text string) error {
+	renderLock.Lock()
+	cb, ok := backend.(ClipboardBackend)
+	var err error
+	if ok {
+		err = cb.SetClipboard(text)
+	}
+	renderLock.Unlock()
+
+	for _, c := range clipboardCmds {
+		if _, lerr := exec.LookPath(c[0]); lerr != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if cerr := cmd.Run(); cerr == nil {
+			return nil
+		} else if !ok || err != nil {
+			err = cerr
+		}
+		break
+	}
+	if !ok && err == nil {
+		return ErrNoClipboard
+	}
+	return err
+}
+
+// CopyFocused copies the text of the focused widget of DefaultFocus if it
+// is a Copier with some, see CopyToClipboard.
+/*
+  ui.DefaultKeyMap.Bind("C-y", func() { ui.CopyFocused() })
+*/
+func CopyFocused() error {
+	c, ok := DefaultFocus.Focused().(Copier)
+	if !ok {
+		return nil
+	}
+	s := c.CopyText()
+	if s == "" {
+		return nil
+	}
+	return CopyToClipboard(s)
+}
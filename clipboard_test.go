@@ -0,0 +1,57 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+// clipBackend is a fakeBackend with a clipboard.
+type clipBackend struct {
+	*fakeBackend
+	clip string
+}
+
+func (b *clipBackend) SetClipboard(text string) error {
+	b.clip = text
+	return nil
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	oldCmds := clipboardCmds
+	defer func() { clipboardCmds = oldCmds }()
+	clipboardCmds = nil
+	fb, done := useFakeBackend(10, 3)
+	defer done()
+
+	if err := CopyToClipboard("x"); err != ErrNoClipboard {
+		t.Errorf("expected no clipboard, got %v", err)
+	}
+
+	cb := &clipBackend{fakeBackend: fb}
+	backend = cb
+	old := DefaultFocus
+	defer func() { DefaultFocus = old }()
+	DefaultFocus = NewFocusManager()
+	l := NewList()
+	l.Items = []string{"[error](fg-red) one", "two"}
+	l.Selectable = true
+	DefaultFocus.Add(l)
+	DefaultFocus.Focus(l)
+	if err := CopyFocused(); err != nil || cb.clip != "error one" {
+		t.Errorf("expected the selected row copied, got %q, %v", cb.clip, err)
+	}
+
+	if s := osc52("hi"); s != "\x1b]52;c;aGk=\a" {
+		t.Errorf("unexpected OSC 52 escape %q", s)
+	}
+}
+
+func TestTableCopyText(t *testing.T) {
+	tb := NewTable()
+	tb.Rows = [][]string{{"a", "1"}, {"b", "2"}}
+	tb.SelectedRow = 1
+	if s := tb.CopyText(); s != "b\t2" {
+		t.Errorf("expected the selected row, got %q", s)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "time"
+
+// Clock displays the current time and re-renders itself on every
+// /timer/1s event from its first draw on, until Stop.
+/*
+  c := termui.NewClock()
+  c.Layout = "15:04:05"
+  c.BigDigits = true
+  c.Width = 34
+  c.Height = 7
+*/
+type Clock struct {
+	Block
+	Layout      string         // time.Format layout
+	Location    *time.Location // defaults to time.Local
+	BigDigits   bool           // draw digits as 3x5 block glyphs
+	TextFgColor Attribute
+	TextBgColor Attribute
+	ticking     string // path of the timer events re-rendering it, "" for none
+	stopped     bool
+}
+
+// NewClock returns a new *Clock with current theme.
+func NewClock() *Clock {
+	c := &Clock{
+		Block:  *NewBlock(),
+		Layout: "15:04:05",
+	}
+	c.themeAttr(&c.TextFgColor, "clock.text.fg")
+	c.themeAttr(&c.TextBgColor, "clock.text.bg")
+	c.Width = 10
+	c.Height = 3
+	return c
+}
+
+// Start has c re-rendered every second, which its first draw does unless
+// Stop was called.
+func (c *Clock) Start() {
+	c.Lock()
+	defer c.Unlock()
+	c.stopped = false
+	c.start()
+}
+
+func (c *Clock) start() {
+	if c.ticking == "" {
+		c.ticking = c.startAnim(time.Second, func() {}, c)
+	}
+}
+
+// Stop stops re-rendering c, e.g. before dropping it, until Start.
+func (c *Clock) Stop() {
+	c.Lock()
+	defer c.Unlock()
+	c.stopped = true
+	c.stopAnim(c.ticking)
+	c.ticking = ""
+}
+
+// Running tells if c is re-rendered every second.
+func (c *Clock) Running() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.ticking != ""
+}
+
+// Text returns the current time formatted with Layout.
+func (c *Clock) Text() string {
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return now().In(loc).Format(c.Layout)
+}
+
+// 3x5 glyphs, ':' is a single column wide
+var bigDigits = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {"██ ", " █ ", " █ ", " █ ", "███"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// Buffer implements Bufferer interface.
+func (c *Clock) Buffer() Buffer {
+	c.Lock()
+	if !c.stopped {
+		c.start()
+	}
+	c.Unlock()
+	buf := c.Block.Buffer()
+	c.RLock()
+	defer c.RUnlock()
+
+	s := c.Text()
+	x0, y0 := c.innerArea.Min.X, c.innerArea.Min.Y
+	set := func(x, y int, cell Cell) {
+		if x < c.innerArea.Max.X && y < c.innerArea.Max.Y {
+			buf.Set(x, y, cell)
+		}
+	}
+
+	if !c.BigDigits {
+		cs := fitCells(TextCells(s, c.TextFgColor, c.TextBgColor), c.innerArea.Dx())
+		for i, x := 0, x0; i < len(cs); i++ {
+			set(x, y0, cs[i])
+			x += cs[i].Width()
+		}
+		return buf
+	}
+
+	x := x0
+	for _, r := range s {
+		g, ok := bigDigits[r]
+		if !ok {
+			// glyph with no big form, e.g. a space or "PM"
+			set(x, y0+2, Cell{r, c.TextFgColor, c.TextBgColor})
+			x += charWidth(r) + 1
+			continue
+		}
+		w := 0
+		for dy, row := range g {
+			rs := []rune(row)
+			w = len(rs)
+			for dx, ch := range rs {
+				set(x+dx, y0+dy, Cell{ch, c.TextFgColor, c.TextBgColor})
+			}
+		}
+		x += w + 1
+	}
+	return buf
+}
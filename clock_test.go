@@ -0,0 +1,65 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockBuffer(t *testing.T) {
+	c := NewClock()
+	c.Layout = "Jan"
+	c.Location = time.UTC
+	c.Width = 10
+
+	want := time.Now().UTC().Format("Jan")
+	buf := c.Buffer()
+	for i, r := range want {
+		if ch := buf.At(1+i, 1).Ch; ch != r {
+			t.Errorf("expected %q at %d but got %q", r, i, ch)
+		}
+	}
+
+	if _, ok := DefaultWgtMgr[c.Id()].Handlers["/timer/1s"]; !ok || !c.Running() {
+		t.Error("clock should re-render itself on /timer/1s once drawn")
+	}
+	c.Stop()
+	c.Buffer()
+	if _, ok := DefaultWgtMgr[c.Id()]; ok || c.Running() {
+		t.Error("expected a stopped clock to release the timer")
+	}
+	c.Start()
+	if !c.Running() {
+		t.Error("expected Start to tick again")
+	}
+	c.Stop()
+}
+
+func TestClockNotTickingUndrawn(t *testing.T) {
+	c := NewClock()
+	if _, ok := DefaultWgtMgr[c.Id()]; ok || c.Running() {
+		t.Error("expected no timer before the first draw")
+	}
+}
+
+func TestClockBigDigits(t *testing.T) {
+	c := NewClock()
+	c.Layout = "15"
+	c.BigDigits = true
+	c.Border = false
+	c.Width = 10
+	c.Height = 5
+
+	buf := c.Buffer()
+	// glyphs are 3 columns wide plus a column of spacing
+	s := c.Text()
+	for i, r := range s {
+		g := bigDigits[r]
+		if ch := buf.At(4*i, 0).Ch; ch != []rune(g[0])[0] {
+			t.Errorf("glyph %q drawn wrong: %q", r, ch)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"math"
+	"strconv"
+)
+
+// The colour part of an Attribute is the 256-colour palette index plus one,
+// 0 being the terminal's default colour, or with attrTrueColor set a 24-bit
+// RGB value in bits 32 to 55.
+const (
+	attrTrueColor Attribute = 1 << 56
+	attrColorMask           = 0x1FF | attrTrueColor | 0xFFFFFF<<32
+)
+
+// ColorRGB24 returns the 24-bit colour r, g, b. It is drawn as is on
+// truecolor terminals and as the nearest palette colour on others, see
+// ColorDepth.
+func ColorRGB24(r, g, b uint8) Attribute {
+	return attrTrueColor | Attribute(r)<<48 | Attribute(g)<<40 | Attribute(b)<<32
+}
+
+// hexColor parses "#rrggbb" or "#rgb" into a 24-bit colour.
+func hexColor(s string) (Attribute, bool) {
+	if len(s) == 4 && s[0] == '#' {
+		s = string([]byte{'#', s[1], s[1], s[2], s[2], s[3], s[3]})
+	}
+	if len(s) != 7 || s[0] != '#' {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return ColorRGB24(uint8(n>>16), uint8(n>>8), uint8(n)), true
+}
+
+// rgb is a colour in 24-bit RGB space.
+type rgb struct {
+	r, g, b float64
+}
+
+// the 16 system colours as rendered by xterm.
+var systemColors = [16]rgb{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]float64{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the RGB value of the 256-colour palette entry n.
+func paletteRGB(n int) rgb {
+	switch {
+	case n < 16:
+		return systemColors[n]
+	case n < 232:
+		n -= 16
+		return rgb{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	default:
+		v := float64(8 + 10*(n-232))
+		return rgb{v, v, v}
+	}
+}
+
+// attrRGB reports the RGB value of a's colour, ok is false for ColorDefault.
+func attrRGB(a Attribute) (c rgb, ok bool) {
+	if a&attrTrueColor != 0 {
+		return rgb{float64(a >> 48 & 0xFF), float64(a >> 40 & 0xFF), float64(a >> 32 & 0xFF)}, true
+	}
+	n := int(a & 0x1FF)
+	if n == 0 || n > 256 {
+		return rgb{}, false
+	}
+	return paletteRGB(n - 1), true
+}
+
+func (c rgb) dist(o rgb) float64 {
+	dr, dg, db := c.r-o.r, c.g-o.g, c.b-o.b
+	return dr*dr + dg*dg + db*db
+}
+
+// nearestColor returns the palette entry among the first n closest to c.
+// The cube and greyscale ramp win ties over the 16 system colours, which
+// terminals are likely to have redefined.
+func nearestColor(c rgb, n int) Attribute {
+	best, min := 0, math.Inf(1)
+	try := func(i int) {
+		if d := c.dist(paletteRGB(i)); d < min {
+			best, min = i, d
+		}
+	}
+	for i := 16; i < n; i++ {
+		try(i)
+	}
+	for i := 0; i < n && i < 16; i++ {
+		try(i)
+	}
+	return Attribute(best + 1)
+}
+
+func clamp01(t float64) float64 {
+	return math.Max(0, math.Min(1, t))
+}
+
+// Blend linearly interpolates between colours a and b in RGB space, t is
+// clamped to [0,1]. The result is a 24-bit colour if a or b is one, the
+// nearest 256-colour palette entry otherwise, and carries the text style
+// of a. Blending with ColorDefault, which has no
+// known RGB value, switches from a to b at t = 0.5.
+func Blend(a, b Attribute, t float64) Attribute {
+	t = clamp01(t)
+	style := a &^ attrColorMask
+
+	ca, oka := attrRGB(a)
+	cb, okb := attrRGB(b)
+	if !oka || !okb {
+		if t < 0.5 {
+			return a
+		}
+		return b&attrColorMask | style
+	}
+
+	c := rgb{
+		ca.r + (cb.r-ca.r)*t,
+		ca.g + (cb.g-ca.g)*t,
+		ca.b + (cb.b-ca.b)*t,
+	}
+	if (a|b)&attrTrueColor != 0 {
+		return ColorRGB24(uint8(c.r+0.5), uint8(c.g+0.5), uint8(c.b+0.5)) | style
+	}
+	return nearestColor(c, 256) | style
+}
+
+// Gradient returns the colour at t, clamped to [0,1], along evenly spaced
+// colour stops.
+func Gradient(stops []Attribute, t float64) Attribute {
+	switch len(stops) {
+	case 0:
+		return ColorDefault
+	case 1:
+		return stops[0]
+	}
+
+	pos := clamp01(t) * float64(len(stops)-1)
+	i := int(pos)
+	if i >= len(stops)-1 {
+		return stops[len(stops)-1]
+	}
+	return Blend(stops[i], stops[i+1], pos-float64(i))
+}
+
+// Threshold maps the values below Below to Color, see ThresholdColor.
+type Threshold struct {
+	Below float64
+	Color Attribute
+}
+
+// ThresholdColor returns the colour of the first of ts v is below, def if
+// none. List the thresholds in increasing order.
+/*
+  ts := []termui.Threshold{{70, termui.ColorGreen}, {90, termui.ColorYellow}}
+  termui.ThresholdColor(ts, 95, termui.ColorRed) // ColorRed
+*/
+func ThresholdColor(ts []Threshold, v float64, def Attribute) Attribute {
+	for _, t := range ts {
+		if v < t.Below {
+			return t.Color
+		}
+	}
+	return def
+}
@@ -0,0 +1,80 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestBlend(t *testing.T) {
+	a, b := ColorRGB(5, 0, 0), ColorRGB(0, 0, 5)
+
+	if c := Blend(a, b, -1); c != a {
+		t.Errorf("t<0 should clamp to a, got %v", c)
+	}
+	if c := Blend(a, b, 2); c != b {
+		t.Errorf("t>1 should clamp to b, got %v", c)
+	}
+	if c := Blend(a, b, 0.5); c != ColorRGB(2, 0, 2) {
+		t.Errorf("expected midpoint %v but got %v", ColorRGB(2, 0, 2), c)
+	}
+	if c := Blend(ColorRed|AttrBold, ColorRed, 0.3); c != ColorRed|AttrBold {
+		t.Errorf("style of a should be kept, got %v", c)
+	}
+	if c := Blend(ColorDefault, ColorBlue, 0.7); c != ColorBlue {
+		t.Errorf("blend with default should switch to b, got %v", c)
+	}
+}
+
+func TestGradient(t *testing.T) {
+	stops := []Attribute{ColorRGB(0, 5, 0), ColorRGB(5, 5, 0), ColorRGB(5, 0, 0)}
+
+	if c := Gradient(stops, 0); c != stops[0] {
+		t.Errorf("expected first stop but got %v", c)
+	}
+	if c := Gradient(stops, 0.5); c != stops[1] {
+		t.Errorf("expected middle stop but got %v", c)
+	}
+	if c := Gradient(stops, 1); c != stops[2] {
+		t.Errorf("expected last stop but got %v", c)
+	}
+	if c := Gradient(nil, 0.5); c != ColorDefault {
+		t.Errorf("expected default for no stops but got %v", c)
+	}
+}
+
+func TestColorRGB24(t *testing.T) {
+	defer ForceColorDepth(0)
+
+	c := ColorRGB24(255, 135, 0)
+	if a, ok := hexColor("#ff8700"); !ok || a != c {
+		t.Errorf("hexColor(#ff8700) = %v, %v", a, ok)
+	}
+	if a := StringToAttribute("#ff8700, bold"); a != c|AttrBold {
+		t.Errorf("themes should accept hex colours, got %v", a)
+	}
+	mtb := MarkdownTxBuilder{}
+	if fg, _ := mtb.readAttr("fg-#f80"); fg != ColorRGB24(255, 136, 0) {
+		t.Errorf("markup should accept hex colours, got %v", fg)
+	}
+
+	ForceColorDepth(TrueColorDepth)
+	if a := quantizeAttr(c|AttrUnderline, ColorDepth()); a != c|AttrUnderline {
+		t.Errorf("truecolor terminals should get the colour as is, got %v", a)
+	}
+	// 208 is the palette's orange (255, 135, 0)
+	if a := quantizeAttr(c, 256); a != 209 {
+		t.Errorf("expected palette orange on 256 colours, got %v", a)
+	}
+	if a := toTmAttr(c | AttrBold); Attribute(a) != 209|AttrBold {
+		t.Errorf("termbox should get the palette colour, got %v", a)
+	}
+	if a := quantizeAttr(c, 8); a != ColorYellow && a != ColorRed {
+		t.Errorf("expected a basic colour on 8 colours, got %v", a)
+	}
+
+	b := Blend(ColorRGB24(0, 0, 0), ColorRGB24(200, 100, 50), 0.5)
+	if b != ColorRGB24(100, 50, 25) {
+		t.Errorf("blending 24-bit colours should stay 24-bit, got %v", b)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"os"
+	"strings"
+)
+
+// TrueColorDepth is the number of colours of a 24-bit terminal.
+const TrueColorDepth = 1 << 24
+
+var colorDepth = detectColorDepth(os.Getenv("TERM"), os.Getenv("COLORTERM"))
+
+// detectColorDepth guesses the number of colours from $TERM and $COLORTERM.
+func detectColorDepth(term, colorterm string) int {
+	switch strings.ToLower(colorterm) {
+	case "truecolor", "24bit":
+		return TrueColorDepth
+	}
+
+	switch {
+	case term == "" || term == "dumb":
+		return 1
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct"):
+		return TrueColorDepth
+	case strings.Contains(term, "256color"):
+		return 256
+	case strings.Contains(term, "16color"):
+		return 16
+	case strings.HasPrefix(term, "vt1"), strings.HasPrefix(term, "vt2"):
+		return 1
+	}
+	return 8
+}
+
+// ColorDepth returns the number of colours the terminal supports: 1, 8, 16,
+// 256 or TrueColorDepth. Attributes are downgraded to it when drawn.
+func ColorDepth() int {
+	return colorDepth
+}
+
+// ForceColorDepth overrides the detected colour depth, n <= 0 restores
+// the detection from the environment.
+func ForceColorDepth(n int) {
+	if n <= 0 {
+		n = detectColorDepth(os.Getenv("TERM"), os.Getenv("COLORTERM"))
+	}
+	colorDepth = n
+}
+
+// quantizeAttr maps the colour of a to the closest one available within
+// depth colours, keeping its text style.
+func quantizeAttr(a Attribute, depth int) Attribute {
+	n := int(a & 0x1FF)
+	tc := a&attrTrueColor != 0
+	if (n == 0 && !tc) || depth >= TrueColorDepth || (depth >= 256 && !tc) {
+		return a
+	}
+
+	style := a &^ attrColorMask
+	if depth < 8 {
+		return style
+	}
+	if tc {
+		c, _ := attrRGB(a)
+		if depth > 256 {
+			depth = 256
+		}
+		return nearestColor(c, depth) | style
+	}
+
+	idx := n - 1
+	max := 8
+	if depth >= 16 {
+		max = 16
+	}
+	switch {
+	case idx < max:
+		return a
+	case idx < 16:
+		// bright system colours fall back to their normal variant
+		return Attribute(idx-8+1) | style
+	}
+	return nearestColor(paletteRGB(idx), max) | style
+}
@@ -0,0 +1,52 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestDetectColorDepth(t *testing.T) {
+	tbl := []struct {
+		term, colorterm string
+		depth           int
+	}{
+		{"dumb", "", 1},
+		{"", "", 1},
+		{"xterm", "", 8},
+		{"rxvt-16color", "", 16},
+		{"screen-256color", "", 256},
+		{"xterm-256color", "truecolor", TrueColorDepth},
+		{"vt100", "", 1},
+	}
+	for _, v := range tbl {
+		if d := detectColorDepth(v.term, v.colorterm); d != v.depth {
+			t.Errorf("TERM=%q COLORTERM=%q: expected %d but got %d", v.term, v.colorterm, v.depth, d)
+		}
+	}
+}
+
+func TestQuantizeAttr(t *testing.T) {
+	defer ForceColorDepth(0)
+
+	ForceColorDepth(256)
+	if a := toTmAttr(ColorRGB(5, 0, 0)); Attribute(a) != ColorRGB(5, 0, 0) {
+		t.Errorf("256 colours should pass through, got %v", a)
+	}
+
+	ForceColorDepth(8)
+	if a := toTmAttr(ColorRGB(5, 0, 0) | AttrBold); Attribute(a) != ColorRed|AttrBold {
+		t.Errorf("expected red on 8 colours but got %v", a)
+	}
+	if a := toTmAttr(Attribute(10)); Attribute(a) != ColorRed {
+		t.Errorf("bright red should become red but got %v", a)
+	}
+	if a := toTmAttr(ColorBlue); Attribute(a) != ColorBlue {
+		t.Errorf("basic colours should pass through, got %v", a)
+	}
+
+	ForceColorDepth(1)
+	if a := toTmAttr(ColorGreen | AttrUnderline); Attribute(a) != AttrUnderline {
+		t.Errorf("monochrome should keep only the style, got %v", a)
+	}
+}
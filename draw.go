@@ -0,0 +1,211 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "image"
+
+// boxArms are the arms of the box drawing glyphs, from the cell centre to
+// its up, down, left and right edges.
+const (
+	armUp = 1 << iota
+	armDown
+	armLeft
+	armRight
+)
+
+var boxArms = map[rune]int{
+	'─': armLeft | armRight, '━': armLeft | armRight, '═': armLeft | armRight,
+	'│': armUp | armDown, '┃': armUp | armDown, '║': armUp | armDown,
+	'┌': armDown | armRight, '╭': armDown | armRight, '╔': armDown | armRight,
+	'┐': armDown | armLeft, '╮': armDown | armLeft, '╗': armDown | armLeft,
+	'└': armUp | armRight, '╰': armUp | armRight, '╚': armUp | armRight,
+	'┘': armUp | armLeft, '╯': armUp | armLeft, '╝': armUp | armLeft,
+	'├': armUp | armDown | armRight, '╠': armUp | armDown | armRight,
+	'┤': armUp | armDown | armLeft, '╣': armUp | armDown | armLeft,
+	'┬': armLeft | armRight | armDown, '╦': armLeft | armRight | armDown,
+	'┴': armLeft | armRight | armUp, '╩': armLeft | armRight | armUp,
+	'┼': armUp | armDown | armLeft | armRight, '╬': armUp | armDown | armLeft | armRight,
+	'┏': armDown | armRight, '┓': armDown | armLeft, '┗': armUp | armRight, '┛': armUp | armLeft,
+	'┣': armUp | armDown | armRight, '┫': armUp | armDown | armLeft,
+	'┳': armLeft | armRight | armDown, '┻': armLeft | armRight | armUp,
+	'╋': armUp | armDown | armLeft | armRight,
+	'-': armLeft | armRight, '|': armUp | armDown, '+': armUp | armDown | armLeft | armRight,
+}
+
+// boxGlyphs are the line glyphs of this platform by their arms.
+var boxGlyphs = [16]rune{
+	armLeft:                              HORIZONTAL_LINE,
+	armRight:                             HORIZONTAL_LINE,
+	armLeft | armRight:                   HORIZONTAL_LINE,
+	armUp:                                VERTICAL_LINE,
+	armDown:                              VERTICAL_LINE,
+	armUp | armDown:                      VERTICAL_LINE,
+	armDown | armRight:                   TOP_LEFT,
+	armDown | armLeft:                    TOP_RIGHT,
+	armUp | armRight:                     BOTTOM_LEFT,
+	armUp | armLeft:                      BOTTOM_RIGHT,
+	armUp | armDown | armRight:           VERTICAL_RIGHT,
+	armUp | armDown | armLeft:            VERTICAL_LEFT,
+	armLeft | armRight | armDown:         HORIZONTAL_DOWN,
+	armLeft | armRight | armUp:           HORIZONTAL_UP,
+	armUp | armDown | armLeft | armRight: CROSS,
+}
+
+// armsOf returns the arms of the box drawing glyph ch, 0 if it is not one.
+func armsOf(ch rune) int {
+	if a, ok := boxArms[ch]; ok {
+		return a
+	}
+	a := 0
+	for m, g := range boxGlyphs {
+		if g == ch && ch != 0 {
+			a |= m
+		}
+	}
+	return a
+}
+
+// armsAt returns the arms of the line glyph at (x,y). The end of a line
+// looks like a straight line, so an arm counts only if the neighbour on its
+// side reaches back, unless no neighbour does.
+func (b Buffer) armsAt(x, y int) int {
+	a := armsOf(b.At(x, y).Ch)
+	if a == 0 {
+		return 0
+	}
+	linked := 0
+	if armsOf(b.At(x, y-1).Ch)&armDown != 0 {
+		linked |= armUp
+	}
+	if armsOf(b.At(x, y+1).Ch)&armUp != 0 {
+		linked |= armDown
+	}
+	if armsOf(b.At(x-1, y).Ch)&armRight != 0 {
+		linked |= armLeft
+	}
+	if armsOf(b.At(x+1, y).Ch)&armLeft != 0 {
+		linked |= armRight
+	}
+	if a&linked != 0 {
+		return a & linked
+	}
+	return a
+}
+
+// joinLine sets the glyph of g with arms at (x,y), joined to the line glyph
+// already there if any.
+func (b Buffer) joinLine(g *[16]rune, x, y, arms int, fg, bg Attribute) {
+	arms |= b.armsAt(x, y)
+	b.Set(x, y, Cell{g[arms], fg, bg})
+}
+
+// DrawHLine draws a horizontal line of n cells from (x,y) to its right. It
+// joins the lines already drawn it crosses or ends on, e.g. a line ending
+// on a vertical one makes a '┤'.
+func (b Buffer) DrawHLine(x, y, n int, fg, bg Attribute) {
+	b.drawHLine(&boxGlyphs, x, y, n, fg, bg)
+}
+
+func (b Buffer) drawHLine(g *[16]rune, x, y, n int, fg, bg Attribute) {
+	for i := 0; i < n; i++ {
+		a := armLeft | armRight
+		switch {
+		case i == 0:
+			a = armRight
+		case i == n-1:
+			a = armLeft
+		}
+		b.joinLine(g, x+i, y, a, fg, bg)
+	}
+}
+
+// DrawVLine draws a vertical line of n cells from (x,y) downward, joined to
+// the lines already drawn like DrawHLine.
+func (b Buffer) DrawVLine(x, y, n int, fg, bg Attribute) {
+	b.drawVLine(&boxGlyphs, x, y, n, fg, bg)
+}
+
+func (b Buffer) drawVLine(g *[16]rune, x, y, n int, fg, bg Attribute) {
+	for i := 0; i < n; i++ {
+		a := armUp | armDown
+		switch {
+		case i == 0:
+			a = armDown
+		case i == n-1:
+			a = armUp
+		}
+		b.joinLine(g, x, y+i, a, fg, bg)
+	}
+}
+
+// DrawLine draws a line from (x0,y0) to (x1,y1), both included. Unless they
+// share a row or a column, it goes along row y0 then column x1, turning
+// with a corner.
+func (b Buffer) DrawLine(x0, y0, x1, y1 int, fg, bg Attribute) {
+	xa, xb, ya, yb := x0, x1, y0, y1
+	if xa > xb {
+		xa, xb = xb, xa
+	}
+	if ya > yb {
+		ya, yb = yb, ya
+	}
+	if y0 != y1 || x0 == x1 {
+		b.DrawVLine(x1, ya, yb-ya+1, fg, bg)
+	}
+	if x0 != x1 {
+		b.DrawHLine(xa, y0, xb-xa+1, fg, bg)
+	}
+}
+
+// DrawRect draws the outline of r with box drawing lines, joined to the
+// lines already drawn.
+func (b Buffer) DrawRect(r image.Rectangle, fg, bg Attribute) {
+	b.DrawRectStyle(r, BorderSingle, fg, bg)
+}
+
+// DrawRectStyle draws the outline of r with the glyphs of s, joined to the
+// lines already drawn.
+func (b Buffer) DrawRectStyle(r image.Rectangle, s BorderSet, fg, bg Attribute) {
+	r = r.Canon()
+	if r.Empty() {
+		return
+	}
+	g := s.glyphs()
+	b.drawHLine(g, r.Min.X, r.Min.Y, r.Dx(), fg, bg)
+	b.drawVLine(g, r.Min.X, r.Min.Y, r.Dy(), fg, bg)
+	if r.Dy() > 1 {
+		b.drawHLine(g, r.Min.X, r.Max.Y-1, r.Dx(), fg, bg)
+	}
+	if r.Dx() > 1 {
+		b.drawVLine(g, r.Max.X-1, r.Min.Y, r.Dy(), fg, bg)
+	}
+}
+
+// FillRect sets every cell of r to ch, fg and bg.
+func (b Buffer) FillRect(r image.Rectangle, ch rune, fg, bg Attribute) {
+	r = r.Canon()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			b.Set(x, y, Cell{ch, fg, bg})
+		}
+	}
+}
+
+// DrawCells sets cs from (x,y) to its right, each one taking its width. It
+// returns the columns drawn.
+func (b Buffer) DrawCells(x, y int, cs []Cell) int {
+	w := 0
+	for _, c := range cs {
+		b.Set(x+w, y, c)
+		w += c.Width()
+	}
+	return w
+}
+
+// DrawText draws s from (x,y) in fg and bg, styling parts of it as its
+// markup says, see DefaultTxBuilder. It returns the columns drawn.
+func (b Buffer) DrawText(x, y int, s string, fg, bg Attribute) int {
+	return b.DrawCells(x, y, DefaultTxBuilder.Build(s, fg, bg))
+}
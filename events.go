@@ -68,7 +68,7 @@ func evtKbd(e termbox.Event) EvtKbd {
 		}
 	}
 
-	ek.KeyStr = pre + mod + k
+	ek.KeyStr = normalizeKeyStr(pre + mod + k)
 	return ek
 }
 
@@ -80,7 +80,7 @@ func crtTermboxEvt(e termbox.Event) Event {
 		termbox.EventError:     "error",
 		termbox.EventInterrupt: "interrupt",
 	}
-	ne := Event{From: "/sys", Time: time.Now().Unix()}
+	ne := Event{From: "/sys", Time: now().Unix()}
 	typ := e.Type
 	ne.Type = systypemap[typ]
 
@@ -103,7 +103,9 @@ func crtTermboxEvt(e termbox.Event) Event {
 		m := EvtMouse{}
 		m.X = e.MouseX
 		m.Y = e.MouseY
-		ne.Path = "/sys/mouse"
+		m.Press = mouseKeys[e.Key]
+		m = termboxDrags.track(m)
+		ne.Path = MousePath(m)
 		ne.Data = m
 	}
 	return ne
@@ -114,25 +116,53 @@ type EvtWnd struct {
 	Height int
 }
 
+// EvtMouse is a mouse event. Press names the button, "MouseRelease"
+// ends a press and a drag is a run of presses at moving positions, the
+// ones after the first having Drag set. See MousePath for the event paths.
 type EvtMouse struct {
 	X     int
 	Y     int
 	Press string
+	Drag  bool
 }
 
+var mouseKeys = map[termbox.Key]string{
+	termbox.MouseLeft:      "MouseLeft",
+	termbox.MouseMiddle:    "MouseMiddle",
+	termbox.MouseRight:     "MouseRight",
+	termbox.MouseRelease:   "MouseRelease",
+	termbox.MouseWheelUp:   "MouseWheelUp",
+	termbox.MouseWheelDown: "MouseWheelDown",
+}
+
+// termboxDrags tells drags from presses in the events polled from termbox.
+var termboxDrags dragTracker
+
 type EvtErr error
 
-func hookTermboxEvt() {
+// hookBackendEvt sends the events polled from b to the channels of
+// NewSysEvtCh until quit is closed, it then closes polled.
+func hookBackendEvt(b Backend, quit, polled chan struct{}) {
+	defer close(polled)
 	for {
-		e := termbox.PollEvent()
+		e := b.PollEvent()
+		select {
+		case <-quit:
+			return
+		default:
+		}
 
 		func() {
 			sysEvtChsLock.Lock()
 			defer sysEvtChsLock.Unlock()
 			for _, c := range sysEvtChs {
-				c <- crtTermboxEvt(e)
+				select {
+				case c <- e:
+				case <-quit:
+					return
+				}
 				/*			go func(ch chan Event) {
-								ch <- crtTermboxEvt(e)
+								ch <- e
 							}(c)
 				*/
 			}
@@ -158,6 +188,8 @@ type EvtStream struct {
 	sigStopLoop chan Event
 	Handlers    map[string]func(Event)
 	hook        func(Event)
+	done        chan struct{} // closed by stop
+	stopOnce    sync.Once
 }
 
 func NewEvtStream() *EvtStream {
@@ -166,9 +198,16 @@ func NewEvtStream() *EvtStream {
 		stream:      make(chan Event),
 		Handlers:    make(map[string]func(Event)),
 		sigStopLoop: make(chan Event),
+		done:        make(chan struct{}),
 	}
 }
 
+// stop stops reading the merged channels, Loop returns once the events
+// being forwarded are handled.
+func (es *EvtStream) stop() {
+	es.stopOnce.Do(func() { close(es.done) })
+}
+
 func (es *EvtStream) Init() {
 	es.Merge("internal", es.sigStopLoop)
 	go func() {
@@ -203,11 +242,23 @@ func (es *EvtStream) Merge(name string, ec chan Event) {
 	es.srcMap[name] = ec
 
 	go func(a chan Event) {
-		for n := range a {
-			n.From = name
-			es.stream <- n
+		defer es.wg.Done()
+		for {
+			select {
+			case n, ok := <-a:
+				if !ok {
+					return
+				}
+				n.From = name
+				select {
+				case es.stream <- n:
+				case <-es.done:
+					return
+				}
+			case <-es.done:
+				return
+			}
 		}
-		es.wg.Done()
 	}(ec)
 }
 
@@ -238,25 +289,48 @@ func (es *EvtStream) match(path string) string {
 }
 
 func (es *EvtStream) Hook(f func(Event)) {
+	es.Lock()
+	defer es.Unlock()
 	es.hook = f
 }
 
+// Loop dispatches the events of es until StopLoop is called or every merged
+// channel is closed. The handlers run one at a time and may call Handle,
+// Merge or Every themselves.
 func (es *EvtStream) Loop() {
 	for e := range es.stream {
-		func() {
-			es.Lock()
-			defer es.Unlock()
-			switch e.Path {
-			case "/sig/stoploop":
-				return
-			}
-			if pattern := es.match(e.Path); pattern != "" {
-				es.Handlers[pattern](e)
-			}
-			if es.hook != nil {
-				es.hook(e)
-			}
-		}()
+		switch e.Path {
+		case "/sig/stoploop":
+			return
+		case "/sig/idle":
+			close(e.Data.(chan struct{}))
+			continue
+		case "/sig/call":
+			e.Data.(func())()
+			continue
+		}
+
+		es.RLock()
+		var h func(Event)
+		if pattern := es.match(e.Path); pattern != "" {
+			h = es.Handlers[pattern]
+		}
+		hook := es.hook
+		es.RUnlock()
+		if h != nil {
+			h(e)
+		}
+		if hook != nil {
+			hook(e)
+		}
+	}
+}
+
+// call has Loop run f between two events, it is dropped once es stopped.
+func (es *EvtStream) call(f func()) {
+	select {
+	case es.sigStopLoop <- Event{Path: "/sig/call", Data: f}:
+	case <-es.done:
 	}
 }
 
@@ -265,7 +339,10 @@ func (es *EvtStream) StopLoop() {
 		e := Event{
 			Path: "/sig/stoploop",
 		}
-		es.sigStopLoop <- e
+		select {
+		case es.sigStopLoop <- e:
+		case <-es.done:
+		}
 	}()
 }
 
@@ -291,24 +368,64 @@ type EvtTimer struct {
 }
 
 func NewTimerCh(du time.Duration) chan Event {
+	return newTimerCh(du, nil)
+}
+
+// newTimerCh is NewTimerCh with a timer stopped when quit is closed.
+func newTimerCh(du time.Duration, quit chan struct{}) chan Event {
+	return timerCh(du, "/timer/"+du.String(), 0, quit, nil)
+}
+
+// timerCh returns a channel of timer events on path every du, stopped
+// when quit or stop is closed, or after limit events unless it is 0. The
+// channel is closed once the timer stopped but for quit.
+func timerCh(du time.Duration, path string, limit uint64, quit, stop chan struct{}) chan Event {
 	t := make(chan Event)
 
+	tk := DefaultTimeSource.NewTicker(du)
 	go func(a chan Event) {
+		defer tk.Stop()
 		n := uint64(0)
-		for {
+		for limit == 0 || n < limit {
+			var tm time.Time
+			var ok bool
+			select {
+			case tm, ok = <-tk.C():
+				if !ok {
+					return
+				}
+			case <-quit:
+				return
+			case <-stop:
+				close(a)
+				return
+			}
+			// a tick racing Stop is dropped
+			select {
+			case <-stop:
+				close(a)
+				return
+			default:
+			}
 			n++
-			time.Sleep(du)
 			e := Event{}
 			e.Type = "timer"
-			e.Path = "/timer/" + du.String()
-			e.Time = time.Now().Unix()
+			e.Path = path
+			e.Time = tm.Unix()
 			e.Data = EvtTimer{
 				Duration: du,
 				Count:    n,
 			}
-			t <- e
-
+			select {
+			case t <- e:
+			case <-quit:
+				return
+			case <-stop:
+				close(a)
+				return
+			}
 		}
+		close(a)
 	}(t)
 	return t
 }
@@ -322,6 +439,104 @@ func SendCustomEvt(path string, data interface{}) {
 	e := Event{}
 	e.Path = path
 	e.Data = data
-	e.Time = time.Now().Unix()
+	e.Time = now().Unix()
+	usrEvtCh <- e
+}
+
+// customEvts tells if DefaultEvtStream reads the events of SendCustomEvt,
+// it is set by Init and cleared by Close.
+var (
+	customEvts     bool
+	customEvtsLock sync.Mutex
+)
+
+func setCustomEvts(on bool) {
+	customEvtsLock.Lock()
+	defer customEvtsLock.Unlock()
+	customEvts = on
+}
+
+// emitEvt sends a custom event for a widget without waiting for Loop, which
+// may be running the caller. It is dropped before Init and after Close,
+// and once the stream stopped.
+func emitEvt(path string, data interface{}) {
+	customEvtsLock.Lock()
+	on := customEvts
+	customEvtsLock.Unlock()
+	if !on {
+		return
+	}
+	e := Event{Path: path, Data: data, Time: now().Unix()}
+	done := DefaultEvtStream.done
+	go func() {
+		select {
+		case usrEvtCh <- e:
+		case <-done:
+		}
+	}()
+}
+
+// SendEvent injects e into DefaultEvtStream through the same source as
+// SendCustomEvt, it reaches the handlers and widget hooks exactly like an
+// event polled from the terminal. Events are dispatched asynchronously by
+// Loop, use WaitIdle to wait for them to be handled.
+func SendEvent(e Event) {
+	if e.Time == 0 {
+		e.Time = now().Unix()
+	}
 	usrEvtCh <- e
 }
+
+// SendKey injects a key press, s is a key as it appears in /sys/kbd paths,
+// e.g. "q", "C-c" or KeyEnter, see NormalizeKey.
+func SendKey(s string) {
+	s = normalizeKeyStr(s)
+	SendEvent(Event{
+		Type: "keyboard",
+		Path: "/sys/kbd/" + s,
+		Data: EvtKbd{KeyStr: s},
+	})
+}
+
+// sentDrags tells the drags among the presses of SendMouse.
+var sentDrags struct {
+	sync.Mutex
+	dragTracker
+}
+
+// mouseEvent returns the event of press at terminal cell (x, y), see
+// EvtMouse.
+func mouseEvent(x, y int, press string) Event {
+	sentDrags.Lock()
+	m := sentDrags.track(EvtMouse{X: x, Y: y, Press: press})
+	sentDrags.Unlock()
+	return Event{
+		Type: "mouse",
+		Path: MousePath(m),
+		Data: m,
+	}
+}
+
+// SendMouse injects a mouse event at terminal cell (x, y), press being
+// "MouseLeft", "MouseMiddle", "MouseRight", "MouseRelease", "MouseWheelUp"
+// or "MouseWheelDown". A press moved while the button is held is a drag.
+func SendMouse(x, y int, press string) {
+	SendEvent(mouseEvent(x, y, press))
+}
+
+// SendResize injects a terminal resize to w columns and h rows.
+func SendResize(w, h int) {
+	SendEvent(Event{
+		Type: "window",
+		Path: "/sys/wnd/resize",
+		Data: EvtWnd{Width: w, Height: h},
+	})
+}
+
+// WaitIdle blocks until Loop has handled every event injected before it
+// was called. Events from other sources may still be pending.
+func WaitIdle() {
+	done := make(chan struct{})
+	usrEvtCh <- Event{Path: "/sig/idle", Data: done}
+	<-done
+}
@@ -35,3 +35,61 @@ func TestMatchScore(t *testing.T) {
 func TestCrtEvt(t *testing.T) {
 
 }
+
+func TestSendEvent(t *testing.T) {
+	es := NewEvtStream()
+	es.Init()
+	es.Merge("custom", usrEvtCh)
+
+	var keys []string
+	var wnd EvtWnd
+	es.Handle("/sys/kbd", func(e Event) {
+		keys = append(keys, e.Data.(EvtKbd).KeyStr)
+	})
+	es.Handle("/sys/wnd/resize", func(e Event) {
+		wnd = e.Data.(EvtWnd)
+	})
+	looped := make(chan struct{})
+	go func() {
+		es.Loop()
+		close(looped)
+	}()
+	// stop the stream so it does not take the events of a later run from
+	// usrEvtCh
+	defer func() {
+		es.StopLoop()
+		<-looped
+		es.stop()
+		es.wg.Wait()
+	}()
+
+	SendKey("q")
+	SendKey("C-c")
+	SendResize(80, 24)
+	WaitIdle()
+
+	if len(keys) != 2 || keys[0] != "q" || keys[1] != "C-c" {
+		t.Errorf("expected keys [q C-c] but got %v", keys)
+	}
+	if wnd.Width != 80 || wnd.Height != 24 {
+		t.Errorf("expected resize to 80x24 but got %+v", wnd)
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	cases := []struct {
+		e    Event
+		want string
+	}{
+		{Event{Path: "/sys/kbd/q", Data: EvtKbd{KeyStr: "q"}}, "q"},
+		{Event{Path: "/sys/kbd/C-8", Data: EvtKbd{KeyStr: "C-8"}}, KeyBackspace},
+		{Event{Path: "/sys/kbd/<return>"}, KeyEnter},
+		{Event{Path: "/sys/kbd/C-c"}, KeyCtrlC},
+		{Event{Path: "/timer/1s"}, ""},
+	}
+	for _, c := range cases {
+		if k := NormalizeKey(c.e); k != c.want {
+			t.Errorf("%s: expected %q but got %q", c.e.Path, c.want, k)
+		}
+	}
+}
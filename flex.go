@@ -0,0 +1,193 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sync"
+)
+
+// FlexDirection is the axis a Flex lays its items along.
+type FlexDirection int
+
+// All supported directions.
+const (
+	FlexRow    FlexDirection = iota // items side by side, left to right
+	FlexColumn                      // items stacked, top to bottom
+)
+
+// FlexItem is a widget of a Flex and the room it takes along the axis:
+// Size cells, plus a share of the space left proportional to Grow.
+type FlexItem struct {
+	Widget Bufferer // a widget embedding a Block, a *Grid or a nested *Flex
+	Size   int
+	Grow   int
+}
+
+// FlexFixed returns an item always size cells long.
+func FlexFixed(size int, b Bufferer) FlexItem {
+	return FlexItem{Widget: b, Size: size}
+}
+
+// FlexGrow returns an item taking grow shares of the free space.
+func FlexGrow(grow int, b Bufferer) FlexItem {
+	return FlexItem{Widget: b, Grow: grow}
+}
+
+// Flex lays its Items out in a row or a column of its area, every item
+// filling the other axis. Flexes nest, and one filling the terminal with
+// Fill follows its size when it is resized.
+/*
+  side := ui.NewFlex(ui.FlexColumn, ui.FlexGrow(1, list), ui.FlexFixed(3, gauge))
+  root := ui.NewFlex(ui.FlexRow, ui.FlexFixed(30, side), ui.FlexGrow(1, chart))
+  root.Fill()
+  ui.Render(root)
+*/
+type Flex struct {
+	sync.RWMutex
+	Direction FlexDirection
+	Items     []FlexItem
+	Gap       int // cells between two items
+	X         int
+	Y         int
+	Width     int
+	Height    int
+}
+
+// NewFlex returns a *Flex of items along dir.
+func NewFlex(dir FlexDirection, items ...FlexItem) *Flex {
+	return &Flex{Direction: dir, Items: items}
+}
+
+// Add appends items.
+func (f *Flex) Add(items ...FlexItem) {
+	f.Lock()
+	defer f.Unlock()
+	f.Items = append(f.Items, items...)
+}
+
+// sizes returns the length of each item along an axis n cells long. Hidden
+// items that collapse take no room.
+func (f *Flex) sizes(n int) []int {
+	ls := make([]int, len(f.Items))
+	shown, grow := 0, 0
+	for _, it := range f.Items {
+		if collapsed(it.Widget) {
+			continue
+		}
+		if shown > 0 {
+			n -= f.Gap
+		}
+		shown++
+		n -= it.Size
+		grow += it.Grow
+	}
+
+	free := n
+	if free < 0 {
+		free = 0
+	}
+	given, seen := 0, 0
+	for i, it := range f.Items {
+		if collapsed(it.Widget) {
+			continue
+		}
+		ls[i] = it.Size
+		if it.Grow > 0 {
+			seen += it.Grow
+			// round the running total so the last grower takes the rest
+			share := free*seen/grow - given
+			ls[i] += share
+			given += share
+		}
+	}
+	return ls
+}
+
+// Align computes the position and size of every item, recursively.
+func (f *Flex) Align() {
+	f.RLock()
+	defer f.RUnlock()
+
+	n := f.Width
+	if f.Direction == FlexColumn {
+		n = f.Height
+	}
+	pos := 0
+	for i, l := range f.sizes(n) {
+		it := f.Items[i]
+		if collapsed(it.Widget) {
+			continue
+		}
+		r := image.Rect(f.X+pos, f.Y, f.X+pos+l, f.Y+f.Height)
+		if f.Direction == FlexColumn {
+			r = image.Rect(f.X, f.Y+pos, f.X+f.Width, f.Y+pos+l)
+		}
+		placeIn(it.Widget, r)
+		pos += l + f.Gap
+	}
+}
+
+// placeIn fits b in r, when it is a Grid, a Flex or has a Block.
+func placeIn(b Bufferer, r image.Rectangle) {
+	switch w := b.(type) {
+	case *Grid:
+		w.X, w.Y, w.Width = r.Min.X, r.Min.Y, r.Dx()
+		w.Align()
+	case *Flex:
+		w.Lock()
+		w.X, w.Y, w.Width, w.Height = r.Min.X, r.Min.Y, r.Dx(), r.Dy()
+		w.Unlock()
+		w.Align()
+	case blocker:
+		k := w.block()
+		k.Lock()
+		k.X, k.Y, k.Width, k.Height = r.Min.X, r.Min.Y, r.Dx(), r.Dy()
+		k.Unlock()
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (f *Flex) Buffer() Buffer {
+	f.RLock()
+	defer f.RUnlock()
+	buf := NewBuffer()
+	for _, it := range f.Items {
+		buf.Merge(bufferOf(it.Widget))
+	}
+	return buf
+}
+
+// filled holds the flexes sized to the terminal by Fill.
+var filled = struct {
+	sync.Mutex
+	m map[*Flex]bool
+}{m: make(map[*Flex]bool)}
+
+// Fill sizes f to the terminal, or the viewport, and lays it out again
+// whenever it is resized.
+func (f *Flex) Fill() {
+	filled.Lock()
+	filled.m[f] = true
+	filled.Unlock()
+	placeIn(f, TermRect())
+}
+
+// Unfill stops following the terminal size.
+func (f *Flex) Unfill() {
+	filled.Lock()
+	defer filled.Unlock()
+	delete(filled.m, f)
+}
+
+// refillFlexes re-applies Fill after the terminal size changed.
+func refillFlexes() {
+	filled.Lock()
+	defer filled.Unlock()
+	r := TermRect()
+	for f := range filled.m {
+		placeIn(f, r)
+	}
+}
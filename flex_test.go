@@ -0,0 +1,64 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestFlexLayout(t *testing.T) {
+	a, b, c, d := NewPar("a"), NewPar("b"), NewPar("c"), NewPar("d")
+	side := NewFlex(FlexColumn, FlexFixed(3, a), FlexGrow(1, b))
+	root := NewFlex(FlexRow, FlexFixed(10, side), FlexGrow(1, c), FlexGrow(2, d))
+	root.Gap = 1
+	root.Width, root.Height = 41, 12
+	root.Align()
+
+	cases := []struct {
+		p    *Par
+		want image.Rectangle
+	}{
+		{a, image.Rect(0, 0, 10, 3)},
+		{b, image.Rect(0, 3, 10, 12)},
+		// 29 cells left after the gaps, shared 1:2
+		{c, image.Rect(11, 0, 20, 12)},
+		{d, image.Rect(21, 0, 41, 12)},
+	}
+	for _, tc := range cases {
+		got := image.Rect(tc.p.X, tc.p.Y, tc.p.X+tc.p.Width, tc.p.Y+tc.p.Height)
+		if got != tc.want {
+			t.Errorf("%q: got %v, want %v", tc.p.Text, got, tc.want)
+		}
+	}
+
+	c.Visible = false
+	c.CollapseWhenHidden = true
+	root.Align()
+	if d.X != 11 || d.Width != 30 {
+		t.Errorf("expected a collapsed item to give its room up, got x=%d w=%d", d.X, d.Width)
+	}
+	if buf := root.Buffer(); buf.At(1, 1).Ch != 'a' || buf.At(12, 1).Ch != 'd' {
+		t.Error("expected the items drawn")
+	}
+}
+
+func TestFlexFill(t *testing.T) {
+	_, done := useFakeBackend(30, 8)
+	defer done()
+
+	p := NewPar("x")
+	f := NewFlex(FlexRow, FlexGrow(1, p))
+	f.Fill()
+	defer f.Unfill()
+	if p.Width != 30 || p.Height != 8 {
+		t.Fatalf("expected the terminal filled, got %dx%d", p.Width, p.Height)
+	}
+	termWidth, termHeight = 40, 10
+	refillFlexes()
+	if p.Width != 40 || p.Height != 10 {
+		t.Errorf("expected a resize to be followed, got %dx%d", p.Width, p.Height)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"sync"
+)
+
+// Focusable is a widget taking keyboard input, HandleKey reports whether
+// it used the key.
+type Focusable interface {
+	Bufferer
+	HandleKey(key string) bool
+}
+
+// focusHooks is implemented by every widget embedding a Block.
+type focusHooks interface {
+	Focus()
+	Blur()
+}
+
+// FocusManager keeps a chain of widgets of which one at most has the
+// keyboard focus. Keys are routed to it alone and <tab> and Shift-Tab
+// move the focus along the chain. Widgets of the chain are re-rendered when
+// they gain or lose the focus, a Block draws its border with FocusBorderFg
+// while focused.
+/*
+  ui.DefaultFocus.Add(list, radios, checks)
+  ui.DefaultFocus.Focus(list)
+*/
+type FocusManager struct {
+	sync.Mutex
+	chain   []Focusable
+	current int // index in chain, -1 when nothing is focused
+
+	// OnChange, when set, is called after the focus moved, prev or next
+	// being nil when nothing was or is focused.
+	OnChange func(prev, next Focusable)
+}
+
+// NewFocusManager returns an empty *FocusManager.
+func NewFocusManager() *FocusManager {
+	return &FocusManager{current: -1}
+}
+
+// DefaultFocus is the FocusManager WgtHandlersHook routes keys with.
+var DefaultFocus = NewFocusManager()
+
+// Add appends ws to the focus chain. The first widget added gets the
+// focus.
+func (fm *FocusManager) Add(ws ...Focusable) {
+	fm.Lock()
+	fm.chain = append(fm.chain, ws...)
+	first := fm.current < 0 && len(fm.chain) > 0
+	fm.Unlock()
+
+	if first {
+		fm.focusAt(0)
+	}
+}
+
+// Remove takes w out of the focus chain, the focus moves to the next
+// widget if w had it.
+func (fm *FocusManager) Remove(w Focusable) {
+	fm.Lock()
+	i := fm.index(w)
+	if i < 0 {
+		fm.Unlock()
+		return
+	}
+	had := i == fm.current
+	fm.chain = append(fm.chain[:i:i], fm.chain[i+1:]...)
+	if i < fm.current {
+		fm.current--
+	}
+	next := -1
+	if had {
+		fm.current = -1
+		if len(fm.chain) > 0 {
+			next = i % len(fm.chain)
+		}
+	}
+	fm.Unlock()
+
+	if had {
+		blur(w)
+		Render(w)
+		if next >= 0 {
+			fm.focusAt(next)
+		} else if fm.OnChange != nil {
+			fm.OnChange(w, nil)
+		}
+	}
+}
+
+func (fm *FocusManager) index(w Focusable) int {
+	for i, v := range fm.chain {
+		if v == w {
+			return i
+		}
+	}
+	return -1
+}
+
+func (fm *FocusManager) contains(w Focusable) bool {
+	fm.Lock()
+	defer fm.Unlock()
+	return fm.index(w) >= 0
+}
+
+// Focused returns the widget having the focus, or nil.
+func (fm *FocusManager) Focused() Focusable {
+	fm.Lock()
+	defer fm.Unlock()
+	if fm.current < 0 {
+		return nil
+	}
+	return fm.chain[fm.current]
+}
+
+// Focus gives the focus to w, which must be in the chain.
+func (fm *FocusManager) Focus(w Focusable) {
+	fm.Lock()
+	i := fm.index(w)
+	fm.Unlock()
+	if i >= 0 {
+		fm.focusAt(i)
+	}
+}
+
+// Blur removes the focus from the chain altogether.
+func (fm *FocusManager) Blur() {
+	fm.focusAt(-1)
+}
+
+// Next moves the focus to the next widget of the chain, wrapping around.
+func (fm *FocusManager) Next() {
+	fm.step(1)
+}
+
+// Prev moves the focus to the previous widget of the chain, wrapping around.
+func (fm *FocusManager) Prev() {
+	fm.step(-1)
+}
+
+func (fm *FocusManager) step(d int) {
+	fm.Lock()
+	n, i := len(fm.chain), fm.current
+	fm.Unlock()
+	if n == 0 {
+		return
+	}
+	if i < 0 && d < 0 {
+		i = 0
+	}
+	fm.focusAt(((i+d)%n + n) % n)
+}
+
+func (fm *FocusManager) focusAt(i int) {
+	fm.Lock()
+	var prev, next Focusable
+	if fm.current >= 0 {
+		prev = fm.chain[fm.current]
+	}
+	if i >= len(fm.chain) {
+		i = -1
+	}
+	if i >= 0 {
+		next = fm.chain[i]
+	}
+	fm.current = i
+	cb := fm.OnChange
+	fm.Unlock()
+
+	if prev == next {
+		return
+	}
+	rs := []Bufferer{}
+	if prev != nil {
+		blur(prev)
+		rs = append(rs, prev)
+	}
+	if next != nil {
+		if f, ok := next.(focusHooks); ok {
+			f.Focus()
+		}
+		rs = append(rs, next)
+	}
+	Render(rs...)
+	if cb != nil {
+		cb(prev, next)
+	}
+}
+
+// focusMover is a widget keeping a focus of its own, e.g. a Form, which
+// FocusManager moves first on <tab> and Shift-Tab.
+type focusMover interface {
+	moveFocus(d int, wrap bool) bool
+}
+
+func blur(w Focusable) {
+	if f, ok := w.(focusHooks); ok {
+		f.Blur()
+	}
+}
+
+// HandleKey moves the focus on <tab> and Shift-Tab, within the focused
+// widget first if it keeps a focus of its own, and passes any other key to
+// the focused widget. It reports whether key was used.
+func (fm *FocusManager) HandleKey(key string) bool {
+	if key == KeyTab || key == KeyBacktab {
+		d := 1
+		if key == KeyBacktab {
+			d = -1
+		}
+		if m, ok := fm.Focused().(focusMover); ok && m.moveFocus(d, false) {
+			return true
+		}
+	}
+	switch key {
+	case KeyTab:
+		fm.Next()
+		return true
+	case KeyBacktab:
+		fm.Prev()
+		return true
+	}
+	if w := fm.Focused(); w != nil {
+		return w.HandleKey(key)
+	}
+	return false
+}
+
+// route passes the keyboard event e to fm.HandleKey unless the chain is
+// empty. It returns the ids of the widgets of the chain not having the
+// focus, whose own keyboard handlers are then bypassed.
+func (fm *FocusManager) route(e Event) map[string]bool {
+	if !strings.HasPrefix(e.Path, "/sys/kbd/") {
+		return nil
+	}
+	fm.Lock()
+	skip := make(map[string]bool)
+	for i, w := range fm.chain {
+		if b, ok := w.(blocker); ok && i != fm.current {
+			skip[b.block().Id()] = true
+		}
+	}
+	n := len(fm.chain)
+	fm.Unlock()
+	if n == 0 {
+		return nil
+	}
+
+	fm.HandleKey(NormalizeKey(e))
+	return skip
+}
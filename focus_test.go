@@ -0,0 +1,91 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestFocusManager(t *testing.T) {
+	fm := NewFocusManager()
+	l := NewList()
+	l.Items = []string{"a", "b"}
+	l.MultiSelect = true
+	rg := NewRadioGroup("x", "y")
+	cg := NewCheckboxGroup("p", "q")
+
+	changes := 0
+	fm.OnChange = func(prev, next Focusable) { changes++ }
+	fm.Add(l, rg, cg)
+	if fm.Focused() != l || !l.Focused() || changes != 1 {
+		t.Fatalf("the first widget added should get the focus")
+	}
+
+	fm.HandleKey(KeyTab)
+	fm.HandleKey(KeyTab)
+	if fm.Focused() != cg || l.Focused() || rg.Focused() || !cg.Focused() {
+		t.Errorf("tab should move the focus along the chain, got %T", fm.Focused())
+	}
+	fm.HandleKey(KeyTab)
+	if fm.Focused() != l {
+		t.Errorf("tab should wrap around, got %T", fm.Focused())
+	}
+	fm.HandleKey(KeyBacktab)
+	if fm.Focused() != cg {
+		t.Errorf("shift-tab should move back, got %T", fm.Focused())
+	}
+
+	if !fm.HandleKey(KeySpace) || len(cg.Checked()) != 1 {
+		t.Error("keys should go to the focused widget")
+	}
+
+	fm.Remove(cg)
+	if fm.Focused() != l || cg.Focused() {
+		t.Errorf("removing the focused widget should focus the next one, got %T", fm.Focused())
+	}
+	fm.Blur()
+	if fm.Focused() != nil || l.Focused() {
+		t.Error("Blur should leave nothing focused")
+	}
+}
+
+func TestFocusBorder(t *testing.T) {
+	p := NewPar("")
+	p.Width, p.Height = 4, 3
+	p.BorderFg, p.FocusBorderFg = ColorWhite, ColorCyan
+
+	if c := p.Buffer().At(0, 0); c.Fg != ColorWhite {
+		t.Errorf("expected the normal border, got %+v", c)
+	}
+	p.Focus()
+	if c := p.Buffer().At(1, 0); c.Fg != ColorCyan {
+		t.Errorf("expected the focus border, got %+v", c)
+	}
+}
+
+func TestFocusRouting(t *testing.T) {
+	old := DefaultFocus
+	defer func() { DefaultFocus = old }()
+	DefaultFocus = NewFocusManager()
+
+	wm := NewWgtMgr()
+	a, b := NewRadioGroup("x", "y"), NewRadioGroup("x", "y")
+	wm.AddWgt(a)
+	wm.AddWgt(b)
+	hits := map[*RadioGroup]int{}
+	wm.AddWgtHandler(a.Id(), "/sys/kbd", func(Event) { hits[a]++ })
+	wm.AddWgtHandler(b.Id(), "/sys/kbd", func(Event) { hits[b]++ })
+	hook := wm.WgtHandlersHook()
+
+	key := Event{Path: "/sys/kbd/<down>", Data: EvtKbd{KeyStr: "<down>"}}
+	hook(key)
+	if hits[a] != 1 || hits[b] != 1 {
+		t.Errorf("without a focus chain every widget should get keys, got %v", hits)
+	}
+
+	DefaultFocus.Add(a, b)
+	hook(key)
+	if hits[a] != 2 || hits[b] != 1 || a.Cursor != 1 || b.Cursor != 0 {
+		t.Errorf("only the focused widget should get keys, got %v", hits)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"errors"
+	"image"
+)
+
+// FormValuer is a widget with a value for a Form, e.g. the Text of a
+// TextInput, the chosen option of a RadioGroup or a Select, or the checked
+// options of a CheckboxGroup.
+type FormValuer interface {
+	FormValue() interface{}
+}
+
+// FormField is a labelled widget of a Form. Its value, see FormValuer, is
+// checked when the field loses the focus and when the form is submitted,
+// the error then being shown under it until it is valid.
+type FormField struct {
+	Name     string // key of the value in the submitted values
+	Label    string
+	Widget   Focusable
+	Required bool                      // the value must not be empty
+	Validate func(v interface{}) error // checks a non empty value, nil if valid
+	err      error
+}
+
+// Err returns the error of the last check of the field, nil if it passed.
+func (ff *FormField) Err() error {
+	return ff.err
+}
+
+// ErrRequired is the error of a Required field left empty.
+var ErrRequired = errors.New("required")
+
+// value returns the value of the widget of ff, nil if it has none.
+func (ff *FormField) value() interface{} {
+	if v, ok := ff.Widget.(FormValuer); ok {
+		return v.FormValue()
+	}
+	return nil
+}
+
+// check runs the checks of ff and records their error.
+func (ff *FormField) check() error {
+	v := ff.value()
+	empty := false
+	switch v := v.(type) {
+	case nil:
+		empty = true
+	case string:
+		empty = v == ""
+	case []string:
+		empty = len(v) == 0
+	}
+	switch {
+	case empty && ff.Required:
+		ff.err = ErrRequired
+	case empty || ff.Validate == nil:
+		ff.err = nil
+	default:
+		ff.err = ff.Validate(v)
+	}
+	return ff.err
+}
+
+// EvtForm is the data of the "/form/submit" event sent after a Form was
+// submitted with valid values.
+type EvtForm struct {
+	Id     string // of the Form
+	Values map[string]interface{}
+}
+
+// Form lays out labelled Fields one under the other, the labels in a
+// column on the left, followed by a submit button. It keeps a focus of its
+// own: <tab> and Shift-Tab move it along the fields and the button, <enter>
+// goes from a TextInput to the next field, or submits on the button, and
+// the other keys go to the focused field. Within DefaultFocus, <tab> leaves
+// the form past its first or last field. Submitting checks every field and
+// focuses the first invalid one, otherwise calls OnSubmit and sends a
+// "/form/submit" event.
+/*
+  name := termui.NewTextInput()
+  f := termui.NewForm(
+      &termui.FormField{Name: "name", Label: "Name", Widget: name, Required: true},
+      &termui.FormField{Name: "size", Label: "Size", Widget: termui.NewSelect("S", "M", "L")})
+  f.Width, f.Height = 40, 12
+  f.OnSubmit = func(vs map[string]interface{}) { ... }
+  termui.DefaultFocus.Add(f)
+*/
+type Form struct {
+	Block
+	Fields      []*FormField
+	SubmitLabel string
+	LabelFg     Attribute
+	ErrorFg     Attribute
+	ButtonFg    Attribute
+	ButtonBg    Attribute
+	OnSubmit    func(values map[string]interface{})
+	current     int // index of the focused field, len(Fields) for the button
+	button      image.Rectangle
+}
+
+// NewForm returns a new *Form of fields with current theme, the first one
+// to be focused.
+func NewForm(fields ...*FormField) *Form {
+	f := &Form{Block: *NewBlock(), SubmitLabel: "Submit"}
+	f.themeAttr(&f.LabelFg, "form.label.fg")
+	f.themeAttr(&f.ErrorFg, "form.error.fg")
+	f.themeAttr(&f.ButtonFg, "form.button.fg")
+	f.themeAttr(&f.ButtonBg, "form.button.bg")
+	f.Add(fields...)
+	return f
+}
+
+// Add appends fields to the form.
+func (f *Form) Add(fields ...*FormField) {
+	f.Lock()
+	defer f.Unlock()
+	for _, ff := range fields {
+		if t, ok := ff.Widget.(*TextInput); ok {
+			t.Lock()
+			t.inForm = true
+			t.Unlock()
+		}
+	}
+	f.Fields = append(f.Fields, fields...)
+}
+
+// Field returns the field called name, nil if there is none.
+func (f *Form) Field(name string) *FormField {
+	f.RLock()
+	defer f.RUnlock()
+	for _, ff := range f.Fields {
+		if ff.Name == name {
+			return ff
+		}
+	}
+	return nil
+}
+
+// Values returns the values of the fields by name.
+func (f *Form) Values() map[string]interface{} {
+	f.RLock()
+	defer f.RUnlock()
+	vs := make(map[string]interface{}, len(f.Fields))
+	for _, ff := range f.Fields {
+		vs[ff.Name] = ff.value()
+	}
+	return vs
+}
+
+// Validate checks every field and reports whether they all passed, the
+// first invalid one getting the focus otherwise.
+func (f *Form) Validate() bool {
+	f.Lock()
+	first := -1
+	for i, ff := range f.Fields {
+		if ff.check() != nil && first < 0 {
+			first = i
+		}
+	}
+	f.Unlock()
+	if first >= 0 {
+		f.focusAt(first)
+	}
+	return first < 0
+}
+
+// Submit validates the form, then calls OnSubmit and sends a
+// "/form/submit" event with the values. It reports whether the form was
+// valid.
+func (f *Form) Submit() bool {
+	if !f.Validate() {
+		return false
+	}
+	vs := f.Values()
+	f.RLock()
+	cb := f.OnSubmit
+	f.RUnlock()
+	if cb != nil {
+		cb(vs)
+	}
+	emitEvt("/form/submit", EvtForm{Id: f.Id(), Values: vs})
+	return true
+}
+
+// widgetAt returns the widget of field i, nil for the button. The caller
+// must hold the lock.
+func (f *Form) widgetAt(i int) Focusable {
+	if i >= 0 && i < len(f.Fields) {
+		return f.Fields[i].Widget
+	}
+	return nil
+}
+
+// focusAt moves the focus of the form to field i, or to the button for
+// len(Fields), checking the field left.
+func (f *Form) focusAt(i int) {
+	f.Lock()
+	if i < 0 || i > len(f.Fields) || i == f.current {
+		f.Unlock()
+		return
+	}
+	prev, next := f.widgetAt(f.current), f.widgetAt(i)
+	var left *FormField
+	if f.current < len(f.Fields) {
+		left = f.Fields[f.current]
+	}
+	f.current = i
+	focused := f.focused
+	f.Unlock()
+
+	if prev != nil {
+		blur(prev)
+	}
+	if left != nil {
+		left.check()
+	}
+	if next != nil && focused {
+		if h, ok := next.(focusHooks); ok {
+			h.Focus()
+		}
+	}
+}
+
+// moveFocus moves the focus of the form by d along the fields and the
+// button, wrapping around if wrap is set. It reports whether it moved.
+func (f *Form) moveFocus(d int, wrap bool) bool {
+	f.RLock()
+	i, n := f.current+d, len(f.Fields)+1
+	f.RUnlock()
+	if i < 0 || i >= n {
+		if !wrap {
+			return false
+		}
+		i = (i%n + n) % n
+	}
+	f.focusAt(i)
+	return true
+}
+
+// Focus is called by FocusManager when the form gets the keyboard focus,
+// which goes to its focused field.
+func (f *Form) Focus() {
+	f.Block.Focus()
+	f.RLock()
+	w := f.widgetAt(f.current)
+	f.RUnlock()
+	if h, ok := w.(focusHooks); ok {
+		h.Focus()
+	}
+}
+
+// Blur is called by FocusManager when the form loses the keyboard focus.
+func (f *Form) Blur() {
+	f.Block.Blur()
+	f.RLock()
+	w := f.widgetAt(f.current)
+	f.RUnlock()
+	if w != nil {
+		blur(w)
+	}
+}
+
+// HandleKey moves the focus with <tab> and Shift-Tab and from a TextInput
+// with <enter>, submits the form with <enter> on the button and passes any
+// other key to the focused field. It reports whether key was used.
+func (f *Form) HandleKey(key string) bool {
+	switch key {
+	case KeyTab:
+		return f.moveFocus(1, true)
+	case KeyBacktab:
+		return f.moveFocus(-1, true)
+	}
+	f.RLock()
+	w := f.widgetAt(f.current)
+	f.RUnlock()
+	if w == nil {
+		if key == KeyEnter || key == KeySpace {
+			f.Submit()
+			return true
+		}
+		return false
+	}
+	if _, ok := w.(*TextInput); ok && key == KeyEnter {
+		return f.moveFocus(1, false)
+	}
+	return w.HandleKey(key)
+}
+
+// HandleMouse focuses the field under a left click, the field getting the
+// click itself if it handles the mouse, and submits the form on a click on
+// the button. It returns true if the form changed.
+func (f *Form) HandleMouse(m EvtMouse) bool {
+	if m.Press != "MouseLeft" || m.Drag {
+		return false
+	}
+	p := image.Pt(m.X, m.Y).Sub(viewportOrigin())
+	f.RLock()
+	button := f.button
+	target := -1
+	for i, ff := range f.Fields {
+		if b, ok := ff.Widget.(blocker); ok && p.In(b.block().Bounds().Sub(viewportOrigin())) {
+			target = i
+		}
+	}
+	f.RUnlock()
+	switch {
+	case p.In(button):
+		f.focusAt(len(f.Fields))
+		f.Submit()
+	case target >= 0:
+		f.focusAt(target)
+	default:
+		return false
+	}
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (f *Form) Buffer() Buffer {
+	buf := f.Block.Buffer()
+	f.Lock()
+	defer f.Unlock()
+	r := f.innerArea
+	if r.Empty() {
+		return buf
+	}
+
+	lw := 0
+	for _, ff := range f.Fields {
+		if w := strWidth(ff.Label); w > lw {
+			lw = w
+		}
+	}
+	if lw > 0 {
+		lw++
+	}
+	x := r.Min.X + lw
+	put := func(x, y int, cs []Cell) {
+		if y < r.Min.Y || y >= r.Max.Y {
+			return
+		}
+		for _, c := range fitCells(cs, r.Max.X-x) {
+			buf.Set(x, y, c)
+			x += c.Width()
+		}
+	}
+
+	y := r.Min.Y
+	for _, ff := range f.Fields {
+		h := 1
+		if b, ok := ff.Widget.(blocker); ok {
+			h = b.block().GetHeight()
+		}
+		placeIn(ff.Widget, image.Rect(x, y, r.Max.X, y+h))
+		// the label faces the text of a bordered widget
+		ly := y
+		if h >= 3 {
+			ly++
+		}
+		put(r.Min.X, ly, TextCells(ff.Label, f.LabelFg, f.Bg))
+		wb := bufferOf(ff.Widget)
+		for p, c := range wb.CellMap {
+			if p.In(r) && p.In(wb.Area) {
+				buf.Set(p.X, p.Y, c)
+			}
+		}
+		y += h
+		if ff.err != nil {
+			put(x, y, TextCells(ff.err.Error(), f.ErrorFg, f.Bg))
+			y++
+		}
+	}
+
+	label := " " + f.SubmitLabel + " "
+	fg, bg := f.ButtonFg, f.ButtonBg
+	if f.current == len(f.Fields) && f.focused {
+		fg |= AttrReverse
+	}
+	put(x, y, TextCells("["+label+"]", fg, bg))
+	f.button = image.Rect(x, y, x+strWidth(label)+2, y+1)
+	return buf
+}
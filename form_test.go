@@ -0,0 +1,124 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForm(t *testing.T) {
+	_, done := useFakeBackend(40, 20)
+	defer done()
+
+	name := NewTextInput()
+	size := NewSelect("S", "M", "L")
+	age := NewTextInput()
+	f := NewForm(
+		&FormField{Name: "name", Label: "Name", Widget: name, Required: true},
+		&FormField{Name: "size", Label: "Size", Widget: size},
+		&FormField{Name: "age", Label: "Age", Widget: age, Validate: func(v interface{}) error {
+			if v.(string) != "42" {
+				return errors.New("not 42")
+			}
+			return nil
+		}})
+	f.Width, f.Height = 40, 16
+	var got map[string]interface{}
+	f.OnSubmit = func(vs map[string]interface{}) { got = vs }
+
+	f.Focus()
+	if !name.Focused() {
+		t.Fatal("expected the first field focused")
+	}
+	if f.Submit() || got != nil || f.Field("name").Err() != ErrRequired {
+		t.Fatal("expected the empty required field to block the submit")
+	}
+
+	buf := f.Buffer()
+	if c := buf.At(1, 2); c.Ch != 'N' || c.Fg != f.LabelFg {
+		t.Errorf("expected the label facing the text, got %q", c.Ch)
+	}
+	if c := buf.At(6, 4); c.Ch != 'r' || c.Fg != f.ErrorFg {
+		t.Errorf("expected the error under the field, got %q", c.Ch)
+	}
+
+	name.SetText("bob")
+	if !f.HandleKey(KeyEnter) || !size.Focused() || name.Focused() || f.Field("name").Err() != nil {
+		t.Fatal("expected <enter> to move on and check the field left")
+	}
+	f.HandleKey(KeyArrowRight)
+	f.HandleKey(KeyTab)
+	age.SetText("41")
+	f.HandleKey(KeyTab)
+	if !f.HandleKey(KeyEnter) || got != nil || !age.Focused() {
+		t.Fatal("expected the invalid field focused on submit")
+	}
+	age.SetText("42")
+	f.HandleKey(KeyBacktab)
+	f.HandleKey(KeyTab)
+	f.HandleKey(KeyTab)
+	if !f.HandleKey(KeySpace) || got["name"] != "bob" || got["size"] != "M" || got["age"] != "42" {
+		t.Errorf("expected the values submitted, got %v", got)
+	}
+}
+
+func TestFormFocusChain(t *testing.T) {
+	a, b := NewTextInput(), NewTextInput()
+	other := NewTextInput()
+	f := NewForm(&FormField{Name: "a", Widget: a}, &FormField{Name: "b", Widget: b})
+	fm := NewFocusManager()
+	fm.Add(f, other)
+	if !f.Focused() || !a.Focused() {
+		t.Fatal("expected the form and its first field focused")
+	}
+	fm.HandleKey(KeyTab)
+	fm.HandleKey(KeyTab)
+	if fm.Focused() != f || !f.Focused() || a.Focused() || b.Focused() {
+		t.Fatal("expected tab to stay within the form up to its button")
+	}
+	fm.HandleKey(KeyTab)
+	if fm.Focused() != other || f.Focused() {
+		t.Error("expected tab to leave the form past its button")
+	}
+	if a.showCursor() {
+		t.Error("expected no cursor in a field of an unfocused form")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	_, done := useFakeBackend(40, 20)
+	defer done()
+
+	s := NewSelect("low", "normal", "high")
+	s.X, s.Y, s.Width = 2, 1, 12
+	changed := -1
+	s.OnChange = func(i int) { changed = i }
+
+	if s.HandleKey(KeyArrowLeft); s.Selected != 0 || changed != -1 {
+		t.Error("expected no option before the first")
+	}
+	s.HandleKey(KeyArrowRight)
+	if s.Value() != "normal" || changed != 1 {
+		t.Errorf("expected normal chosen, got %q", s.Value())
+	}
+	buf := s.Buffer()
+	if buf.At(3, 2).Ch != 'n' || buf.At(12, 2).Ch != '▾' {
+		t.Error("expected the value and the arrow in the border")
+	}
+
+	s.HandleKey(KeyEnter)
+	if !s.menu.IsOpen() || s.menu.levels[0].r.Min.Y != 3 {
+		t.Fatal("expected the options listed under the select")
+	}
+	if !s.HandleKey(KeyArrowDown) || !s.HandleKey(KeyEnter) || s.menu.IsOpen() || s.Value() != "normal" {
+		t.Errorf("expected the list to take the keys, got %q", s.Value())
+	}
+	s.HandleKey(KeyEnter)
+	s.Blur()
+	if s.menu.IsOpen() || len(Overlays()) != 0 {
+		t.Error("expected the list closed with the focus")
+	}
+}
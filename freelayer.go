@@ -0,0 +1,53 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "sync"
+
+// FreeLayer holds widgets placed at their own X and Y outside of any grid
+// layout. It draws them in the order they were added, stably sorted by
+// ZIndex. A Grid draws its FreeLayer on top of its rows, see Grid.AddFree.
+// Use AlignTo or Center to keep a free widget anchored across resizes.
+type FreeLayer struct {
+	sync.RWMutex
+	Items []Bufferer
+}
+
+// NewFreeLayer returns a *FreeLayer with the given widgets.
+func NewFreeLayer(bs ...Bufferer) *FreeLayer {
+	return &FreeLayer{Items: bs}
+}
+
+// Add appends widgets to the layer.
+func (fl *FreeLayer) Add(bs ...Bufferer) {
+	fl.Lock()
+	defer fl.Unlock()
+	fl.Items = append(fl.Items, bs...)
+}
+
+// Remove takes b out of the layer. The next render clears where b was
+// drawn, and b no longer gets the clicks there.
+func (fl *FreeLayer) Remove(b Bufferer) {
+	fl.Lock()
+	removed := false
+	for i, v := range fl.Items {
+		if v == b {
+			fl.Items = append(fl.Items[:i:i], fl.Items[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	fl.Unlock()
+	if blk, ok := b.(blocker); ok && removed {
+		undraw(blk.block())
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (fl *FreeLayer) Buffer() Buffer {
+	fl.RLock()
+	defer fl.RUnlock()
+	return composeBufferers(fl.Items)
+}
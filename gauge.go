@@ -7,9 +7,13 @@ package termui
 import (
 	"strconv"
 	"strings"
+	"time"
 )
 
-// Gauge is a progress bar like widget.
+// Gauge is a progress bar like widget. Label is a template in which
+// {{percent}}, {{current}} and {{total}} are replaced by Percent and the
+// values given to SetProgress. The bar is filled in BarColor, in the colour
+// of BarThresholds for Percent, or along the colours of BarGradient.
 // A simple example:
 /*
   g := termui.NewGauge()
@@ -19,6 +23,11 @@ import (
   g.BorderLabel = "Slim Gauge"
   g.BarColor = termui.ColorRed
   g.PercentColor = termui.ColorBlue
+
+  g.Label = "{{percent}}% ({{current}}/{{total}} MB)"
+  g.SetProgress(512, 2048)
+  g.BarThresholds = []termui.Threshold{{70, termui.ColorGreen}, {90, termui.ColorYellow}}
+  g.BarColor = termui.ColorRed // from 90%
 */
 
 const ColorUndef Attribute = Attribute(^uint16(0))
@@ -31,37 +40,174 @@ type Gauge struct {
 	PercentColorHighlighted Attribute
 	Label                   string
 	LabelAlign              Align
+	Current, Total          float64     // shown as {{current}} and {{total}}, see SetProgress
+	BarThresholds           []Threshold // the bar colour by Percent, BarColor above them
+	BarGradient             []Attribute // colour stops from the left end of the bar to the right one
+
+	// Indeterminate ignores Percent and moves a segment of SegmentWidth
+	// cells (a quarter of the bar when zero) across the bar on every timer
+	// tick, bouncing at the ends or wrapping around when WrapSegment is set.
+	// The /timer/1s handler stepping a Gauge of NewGauge is added by its
+	// first draw with Indeterminate set, and removed by the first one
+	// without.
+	Indeterminate      bool
+	IndeterminateLabel string
+	SegmentWidth       int
+	WrapSegment        bool
+	segPos             int
+	segDir             int
+	tick               bool   // stepped on /timer/1s while Indeterminate
+	ticking            string // path of the timer events stepping it, "" for none
 }
 
 // NewGauge return a new gauge with current theme.
 func NewGauge() *Gauge {
+	g := newGauge()
+	g.tick = true
+	return g
+}
+
+// syncTimer has g stepped on the /timer/1s events while it is
+// Indeterminate only.
+func (g *Gauge) syncTimer() {
+	g.Lock()
+	defer g.Unlock()
+	switch {
+	case !g.tick:
+	case g.Indeterminate && g.ticking == "":
+		g.ticking = g.startAnim(time.Second, g.Step, g)
+	case !g.Indeterminate && g.ticking != "":
+		g.stopAnim(g.ticking)
+		g.ticking = ""
+	}
+}
+
+// newGauge returns a gauge that is not stepped by the /timer/1s events.
+func newGauge() *Gauge {
 	g := &Gauge{
 		Block:                   *NewBlock(),
-		PercentColor:            ThemeAttr("gauge.percent.fg"),
-		BarColor:                ThemeAttr("gauge.bar.bg"),
 		Label:                   "{{percent}}%",
 		LabelAlign:              AlignCenter,
 		PercentColorHighlighted: ColorUndef,
+		IndeterminateLabel:      "Working…",
+		segDir:                  1,
 	}
+	g.themeAttr(&g.PercentColor, "gauge.percent.fg")
+	g.themeAttr(&g.BarColor, "gauge.bar.bg")
 
 	g.Width = 12
 	g.Height = 5
 	return g
 }
 
+// SetProgress sets Current and Total, and Percent from them.
+func (g *Gauge) SetProgress(current, total float64) {
+	g.Lock()
+	defer g.Unlock()
+	g.Current, g.Total = current, total
+	g.Percent = 0
+	if total > 0 {
+		g.Percent = int(current * 100 / total)
+	}
+}
+
+// label returns Label with its fields replaced.
+func (g *Gauge) label() string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return strings.NewReplacer(
+		"{{percent}}", strconv.Itoa(g.Percent),
+		"{{current}}", f(g.Current),
+		"{{total}}", f(g.Total),
+	).Replace(g.Label)
+}
+
+// barColor returns the colour of column j of the bar.
+func (g *Gauge) barColor(j int) Attribute {
+	if len(g.BarGradient) > 0 {
+		t := 0.0
+		if n := g.innerArea.Dx(); n > 1 {
+			t = float64(j) / float64(n-1)
+		}
+		return Gradient(g.BarGradient, t)
+	}
+	return ThresholdColor(g.BarThresholds, float64(g.Percent), g.BarColor)
+}
+
+func (g *Gauge) segWidth() int {
+	w := g.SegmentWidth
+	if w <= 0 {
+		w = g.innerArea.Dx() / 4
+	}
+	if w < 1 {
+		w = 1
+	}
+	if w > g.innerArea.Dx() {
+		w = g.innerArea.Dx()
+	}
+	return w
+}
+
+// Step moves the indeterminate segment by one cell.
+func (g *Gauge) Step() {
+	g.Lock()
+	defer g.Unlock()
+
+	n := g.innerArea.Dx()
+	if n <= 0 {
+		return
+	}
+	if g.WrapSegment {
+		g.segPos = (g.segPos + 1) % n
+		return
+	}
+
+	max := n - g.segWidth()
+	g.segPos += g.segDir
+	if g.segPos >= max {
+		g.segPos = max
+		g.segDir = -1
+	} else if g.segPos <= 0 {
+		g.segPos = 0
+		g.segDir = 1
+	}
+}
+
+// inSegment tells if column j of the bar is covered by the indeterminate
+// segment.
+func (g *Gauge) inSegment(j int) bool {
+	n := g.innerArea.Dx()
+	if n <= 0 {
+		return false
+	}
+	if g.WrapSegment {
+		return ((j-g.segPos)%n+n)%n < g.segWidth()
+	}
+	return j >= g.segPos && j < g.segPos+g.segWidth()
+}
+
 // Buffer implements Bufferer interface.
 func (g *Gauge) Buffer() Buffer {
+	g.syncTimer()
 	buf := g.Block.Buffer()
 	g.RLock()
 	defer g.RUnlock()
 
 	// plot bar
 	w := g.Percent * g.innerArea.Dx() / 100
+	filled := func(j int) bool { return j < w }
+	s := g.label()
+	if g.Indeterminate {
+		filled = g.inSegment
+		s = g.IndeterminateLabel
+	}
 	for i := 0; i < g.innerArea.Dy(); i++ {
-		for j := 0; j < w; j++ {
+		for j := 0; j < g.innerArea.Dx(); j++ {
+			if !filled(j) {
+				continue
+			}
 			c := Cell{}
 			c.Ch = ' '
-			c.Bg = g.BarColor
+			c.Bg = g.barColor(j)
 			if c.Bg == ColorDefault {
 				c.Bg |= AttrReverse
 			}
@@ -70,7 +216,6 @@ func (g *Gauge) Buffer() Buffer {
 	}
 
 	// plot percentage
-	s := strings.Replace(g.Label, "{{percent}}", strconv.Itoa(g.Percent), -1)
 	pry := g.innerArea.Min.Y + g.innerArea.Dy()/2
 	rs := str2runes(s)
 	var pos int
@@ -86,14 +231,15 @@ func (g *Gauge) Buffer() Buffer {
 	}
 	pos += g.innerArea.Min.X
 
-	for i, v := range rs {
+	for i, j := 0, 0; j < len(rs); j++ {
+		v := rs[j]
 		c := Cell{
 			Ch: v,
 			Fg: g.PercentColor,
 		}
 
-		if w+g.innerArea.Min.X > pos+i {
-			c.Bg = g.BarColor
+		if col := 1 + pos - g.innerArea.Min.X + i; filled(col) {
+			c.Bg = g.barColor(col)
 			if c.Bg == ColorDefault {
 				c.Bg |= AttrReverse
 			}
@@ -106,6 +252,7 @@ func (g *Gauge) Buffer() Buffer {
 		}
 
 		buf.Set(1+pos+i, pry, c)
+		i += c.Width()
 	}
 	return buf
 }
@@ -0,0 +1,137 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+// gaugeBar returns the bar's middle row, '#' marking filled cells.
+func gaugeBar(g *Gauge) string {
+	buf := g.Buffer()
+	s := ""
+	y := g.innerArea.Min.Y
+	for x := g.innerArea.Min.X; x < g.innerArea.Max.X; x++ {
+		if buf.At(x, y).Bg == g.BarColor {
+			s += "#"
+		} else {
+			s += "."
+		}
+	}
+	return s
+}
+
+func TestGaugeIndeterminate(t *testing.T) {
+	g := NewGauge()
+	g.Width = 8
+	g.Height = 3
+	g.BarColor = ColorRed
+	g.Percent = 100
+	g.Indeterminate = true
+	g.SegmentWidth = 2
+	g.IndeterminateLabel = ""
+
+	want := []string{"##....", ".##...", "..##..", "...##.", "....##", "...##.", "..##.."}
+	for i, w := range want {
+		if i > 0 {
+			g.Step()
+		}
+		if s := gaugeBar(g); s != w {
+			t.Errorf("bounce step %d: expected %s but got %s", i, w, s)
+		}
+	}
+
+	g.WrapSegment = true
+	g.segPos = 4
+	g.Step()
+	if s := gaugeBar(g); s != "#....#" {
+		t.Errorf("wrap: expected #....# but got %s", s)
+	}
+}
+
+func TestGaugeLabelHighlight(t *testing.T) {
+	g := NewGauge()
+	g.Width = 12
+	g.Height = 3
+	g.BarColor = ColorRed
+	g.Percent = 50
+	g.Label = "xxxx"
+
+	// the label sits on columns 4-7, the bar covers columns 0-4
+	if s := gaugeBar(g); s != "#####....." {
+		t.Errorf("expected the label highlighted over the bar only, got %s", s)
+	}
+}
+
+func TestGaugeLabelAndColors(t *testing.T) {
+	g := NewGauge()
+	g.Border = false
+	g.Width, g.Height = 20, 1
+	g.Label = "{{percent}}% ({{current}}/{{total}})"
+	g.LabelAlign = AlignLeft
+	g.SetProgress(1.5, 2)
+	if g.Percent != 75 || g.label() != "75% (1.5/2)" {
+		t.Errorf("unexpected label %q at %d%%", g.label(), g.Percent)
+	}
+
+	g.BarColor = ColorRed
+	g.BarThresholds = []Threshold{{70, ColorGreen}, {90, ColorYellow}}
+	if c := g.Buffer().At(0, 0); c.Bg != ColorYellow {
+		t.Errorf("expected the 90%% colour at 75%%, got %v", c.Bg)
+	}
+	g.Percent = 95
+	if c := g.Buffer().At(0, 0); c.Bg != ColorRed {
+		t.Errorf("expected BarColor above the thresholds, got %v", c.Bg)
+	}
+
+	g.Percent = 100
+	g.BarGradient = []Attribute{ColorRGB24(0, 0, 0), ColorRGB24(190, 0, 0)}
+	buf := g.Buffer()
+	if buf.At(0, 0).Bg != ColorRGB24(0, 0, 0) || buf.At(19, 0).Bg != ColorRGB24(190, 0, 0) ||
+		buf.At(10, 0).Bg != ColorRGB24(100, 0, 0) {
+		t.Errorf("expected a gradient, got %v %v %v", buf.At(0, 0).Bg, buf.At(10, 0).Bg, buf.At(19, 0).Bg)
+	}
+}
+
+func TestGaugeTimer(t *testing.T) {
+	g := NewGauge()
+	g.Width, g.Height = 8, 3
+	g.Buffer()
+	if _, ok := DefaultWgtMgr[g.Id()]; ok {
+		t.Fatal("expected a determinate gauge not to handle the timer")
+	}
+
+	g.Indeterminate = true
+	g.Buffer()
+	if _, ok := DefaultWgtMgr[g.Id()].Handlers["/timer/1s"]; !ok {
+		t.Fatal("expected an indeterminate gauge stepped on /timer/1s")
+	}
+	g.Indeterminate = false
+	g.Buffer()
+	if _, ok := DefaultWgtMgr[g.Id()]; ok {
+		t.Error("expected the gauge forgotten once determinate again")
+	}
+}
+
+func TestGaugeTimerConcurrent(t *testing.T) {
+	hook := DefaultWgtMgr.WgtHandlersHook()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			g := NewGauge()
+			g.Indeterminate = true
+			g.Buffer()
+			g.Indeterminate = false
+			g.Buffer()
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			hook(Event{Path: "/timer/1s"})
+		}
+	}
+}
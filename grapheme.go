@@ -0,0 +1,164 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"sync"
+	"unicode"
+
+	rw "github.com/mattn/go-runewidth"
+)
+
+// A Cell holds a single rune, so a grapheme cluster, a rune followed by
+// combining marks such as "é" or a joined emoji sequence, is stored
+// as a rune standing for it, see clusterRune. The runes come from the
+// Supplementary Private Use Area-B, which text is not expected to use.
+const (
+	clusterFirst rune = 0x100000
+	clusterLast  rune = 0x10fffd
+	// wideCont fills the cells a wide glyph covers right of its own, see
+	// Buffer.Set.
+	wideCont rune = 0x10fffe
+)
+
+// clusters interns the grapheme clusters met in text.
+var clusters = struct {
+	sync.RWMutex
+	byRune map[rune][]rune
+	byText map[string]rune
+	next   rune
+}{
+	byRune: make(map[rune][]rune),
+	byText: make(map[string]rune),
+	next:   clusterFirst,
+}
+
+// clusterRune returns the rune standing for the cluster rs. Once the
+// private runes are all used, new clusters are reduced to their first rune.
+func clusterRune(rs []rune) rune {
+	if len(rs) == 1 {
+		return rs[0]
+	}
+	s := string(rs)
+	clusters.RLock()
+	r, ok := clusters.byText[s]
+	clusters.RUnlock()
+	if ok {
+		return r
+	}
+
+	clusters.Lock()
+	defer clusters.Unlock()
+	if r, ok := clusters.byText[s]; ok {
+		return r
+	}
+	if clusters.next > clusterLast {
+		return rs[0]
+	}
+	r = clusters.next
+	clusters.next++
+	clusters.byRune[r] = append([]rune(nil), rs...)
+	clusters.byText[s] = r
+	return r
+}
+
+// clusterOf returns the runes ch stands for, ch itself if it is no cluster.
+func clusterOf(ch rune) []rune {
+	if ch < clusterFirst || ch > clusterLast {
+		return []rune{ch}
+	}
+	clusters.RLock()
+	defer clusters.RUnlock()
+	if rs, ok := clusters.byRune[ch]; ok {
+		return rs
+	}
+	return []rune{ch}
+}
+
+// runesToStr returns the text of rs, expanding its clusters.
+func runesToStr(rs []rune) string {
+	out := make([]rune, 0, len(rs))
+	for _, r := range rs {
+		if r != wideCont {
+			out = append(out, clusterOf(r)...)
+		}
+	}
+	return string(out)
+}
+
+// extends tells if r, following prev, belongs to the cluster of prev: a
+// combining mark, a variation selector, an emoji modifier, a zero width
+// joiner or the rune it joins.
+func extends(prev, r rune) bool {
+	switch {
+	case prev == '\u200d':
+		return true
+	case r == '\u200d', unicode.In(r, unicode.Mn, unicode.Me):
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // skin tones
+		return true
+	}
+	return false
+}
+
+// clusterRunes folds the grapheme clusters of rs into single runes, see
+// clusterRune.
+func clusterRunes(rs []rune) []rune {
+	out := make([]rune, 0, len(rs))
+	for i := 0; i < len(rs); {
+		j := i + 1
+		for j < len(rs) && rs[i] != '\n' && extends(rs[j-1], rs[j]) {
+			j++
+		}
+		out = append(out, clusterRune(rs[i:j]))
+		i = j
+	}
+	return out
+}
+
+// joinCells folds the marks of cs into the cells they follow, as
+// clusterRunes does for runes, the first cell of a cluster giving its
+// colours.
+func joinCells(cs []Cell) []Cell {
+	out := make([]Cell, 0, len(cs))
+	for i := 0; i < len(cs); {
+		rs := []rune{cs[i].Ch}
+		j := i + 1
+		for ; j < len(cs) && cs[i].Ch != '\n' && extends(cs[j-1].Ch, cs[j].Ch); j++ {
+			rs = append(rs, cs[j].Ch)
+		}
+		c := cs[i]
+		c.Ch = clusterRune(rs)
+		out = append(out, c)
+		i = j
+	}
+	return out
+}
+
+// runeWidth is the number of cells ch takes, that of its first rune for a
+// cluster and 0 for the cells a wide glyph covers.
+func runeWidth(ch rune) int {
+	switch {
+	case ch == wideCont:
+		return 0
+	case ch >= clusterFirst && ch <= clusterLast:
+		return rw.RuneWidth(clusterOf(ch)[0])
+	}
+	return rw.RuneWidth(ch)
+}
+
+// truncateRunes trims rs to w cells, ending it with dot if it had to go.
+func truncateRunes(rs []rune, w int) []rune {
+	if runesWidth(rs) <= w {
+		return rs
+	}
+	w -= strWidth(dot)
+	out := []rune{}
+	for n, i := 0, 0; i < len(rs) && n+runeWidth(rs[i]) <= w; i++ {
+		n += runeWidth(rs[i])
+		out = append(out, rs[i])
+	}
+	return append(out, []rune(dot)...)
+}
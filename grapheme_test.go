@@ -0,0 +1,92 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestClusterRunes(t *testing.T) {
+	s := "e\u0301te\u0301 a\u0323\u0300"
+	rs := str2runes(s)
+	if len(rs) != 5 {
+		t.Fatalf("expected 5 clusters, got %d", len(rs))
+	}
+	if rs[0] < clusterFirst || charWidth(rs[0]) != 1 || rs[1] != 't' {
+		t.Errorf("expected e and its accent in one cell, got %q", rs)
+	}
+	if got := CellsToStr(TextCells(s, 0, 0)); got != s {
+		t.Errorf("expected the text back, got %q", got)
+	}
+	if r := str2runes("e\u0301"); r[0] != rs[0] {
+		t.Error("expected a cluster to keep its rune")
+	}
+	if got := TrimStrIfAppropriate("e\u0301e\u0301e\u0301", 2); got != "e\u0301…" {
+		t.Errorf("expected the accent kept with its letter, got %q", got)
+	}
+	if cs := ParseANSI("\x1b[31me\x1b[0m\u0301", 0, 0); len(cs) != 1 || cs[0].Fg != ColorRed {
+		t.Errorf("expected one red cell, got %+v", cs)
+	}
+}
+
+func TestBufferWide(t *testing.T) {
+	buf := NewBuffer()
+	buf.Set(0, 0, Cell{'世', 0, 0})
+	if c := buf.At(1, 0); c.Ch != wideCont || c.Width() != 0 {
+		t.Errorf("expected the right half covered, got %+v", c)
+	}
+
+	// overwriting either half blanks the other
+	buf.Set(1, 0, Cell{'a', 0, 0})
+	if c := buf.At(0, 0); c.Ch != ' ' {
+		t.Errorf("expected the split glyph blanked, got %q", c.Ch)
+	}
+	buf.Set(2, 0, Cell{'界', 0, 0})
+	buf.Set(2, 0, Cell{'b', 0, 0})
+	if c := buf.At(3, 0); c.Ch != ' ' {
+		t.Errorf("expected the right half blanked, got %q", c.Ch)
+	}
+
+	// a glyph never spills out of the area
+	buf = NewBuffer()
+	buf.SetArea(image.Rect(0, 0, 2, 1))
+	buf.Set(1, 0, Cell{'世', 0, 0})
+	if c := buf.At(1, 0); c.Ch != ' ' || len(buf.CellMap) != 1 {
+		t.Errorf("expected a space in the last column, got %q", c.Ch)
+	}
+}
+
+func TestRenderWide(t *testing.T) {
+	fb, done := useFakeBackend(10, 3)
+	defer done()
+
+	p := NewPar("世界x")
+	p.Border = false
+	p.Width, p.Height = 5, 1
+	q := NewPar("y")
+	q.Border = false
+	q.X, q.Width, q.Height = 3, 1, 1
+	q.ZIndex = 1
+	Render(p, q)
+
+	if fb.cells[image.Pt(0, 0)].Ch != '世' || fb.cells[image.Pt(2, 0)].Ch != ' ' {
+		t.Errorf("expected the half-covered glyph blanked, got %q %q",
+			fb.cells[image.Pt(0, 0)].Ch, fb.cells[image.Pt(2, 0)].Ch)
+	}
+	if _, ok := fb.cells[image.Pt(1, 0)]; ok {
+		t.Error("expected the right half of a glyph never written")
+	}
+	if fb.cells[image.Pt(3, 0)].Ch != 'y' || fb.cells[image.Pt(4, 0)].Ch != 'x' {
+		t.Error("expected the widgets around the glyphs in place")
+	}
+
+	// a narrow glyph over a wide one uncovers its right half
+	p.Text = "ab"
+	Render(p)
+	if fb.cells[image.Pt(1, 0)].Ch != 'b' {
+		t.Errorf("expected b, got %q", fb.cells[image.Pt(1, 0)].Ch)
+	}
+}
@@ -23,6 +23,110 @@ type Row struct {
 	Height int
 	Span   int
 	Offset int
+
+	// HeightPercent, on a row of the Grid, makes its widgets as high as
+	// this percentage of the grid height. HeightRatio instead gives them
+	// a share, proportional to it, of the height left by the other rows.
+	HeightPercent int
+	HeightRatio   int
+
+	// Breakpoints change Span and Offset, or hide the col, from some
+	// widths of the Grid on, see At and HiddenAt. The cols of a row
+	// whose spans pass 12 go on under the previous ones.
+	Breakpoints []Breakpoint
+	bp          *Breakpoint // the one applying since the last Align, nil for none
+}
+
+// Breakpoint sets the Span and Offset of a col, or hides it, when the Grid
+// is at least MinWidth wide, until the next Breakpoint of a larger
+// MinWidth.
+type Breakpoint struct {
+	MinWidth int
+	Span     int
+	Offset   int
+	Hidden   bool // the col takes no space and is not drawn
+}
+
+// At adds a Breakpoint giving r span and offset from a grid width of
+// minWidth on, and returns r.
+/*
+  // the full width on small terminals, a third from 80 columns on, a
+  // quarter from 160 on
+  ui.NewCol(12, 0, cpu).At(80, 4, 0).At(160, 3, 0)
+*/
+func (r *Row) At(minWidth, span, offset int) *Row {
+	r.Breakpoints = append(r.Breakpoints, Breakpoint{MinWidth: minWidth, Span: span, Offset: offset})
+	return r
+}
+
+// HiddenAt adds a Breakpoint hiding r from a grid width of minWidth on,
+// and returns r. HiddenAt(0).At(80, 6, 0) shows r from 80 columns on only.
+func (r *Row) HiddenAt(minWidth int) *Row {
+	r.Breakpoints = append(r.Breakpoints, Breakpoint{MinWidth: minWidth, Hidden: true})
+	return r
+}
+
+// applyBreakpoints picks the Breakpoints of r and its cols for a grid w
+// wide.
+func (r *Row) applyBreakpoints(w int) {
+	r.bp = nil
+	for i, b := range r.Breakpoints {
+		if b.MinWidth <= w && (r.bp == nil || b.MinWidth >= r.bp.MinWidth) {
+			r.bp = &r.Breakpoints[i]
+		}
+	}
+	for _, c := range r.Cols {
+		c.applyBreakpoints(w)
+	}
+}
+
+// responsive tells if r or one of its cols has Breakpoints.
+func (r *Row) responsive() bool {
+	if len(r.Breakpoints) > 0 {
+		return true
+	}
+	for _, c := range r.Cols {
+		if c.responsive() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Row) hidden() bool {
+	return r.bp != nil && r.bp.Hidden
+}
+
+// span returns the Span of r at the current Breakpoint.
+func (r *Row) span() int {
+	switch {
+	case r.hidden():
+		return 0
+	case r.bp != nil:
+		return r.bp.Span
+	}
+	return r.Span
+}
+
+// offset returns the Offset of r at the current Breakpoint.
+func (r *Row) offset() int {
+	switch {
+	case r.hidden():
+		return 0
+	case r.bp != nil:
+		return r.bp.Offset
+	}
+	return r.Offset
+}
+
+// undraw clears where the widgets of r were drawn.
+func (r *Row) undraw() {
+	if b, ok := r.Widget.(blocker); ok {
+		undraw(b.block())
+	}
+	for _, c := range r.Cols {
+		c.undraw()
+	}
 }
 
 // calculate and set the underlying layout tree's x, y, height and width.
@@ -42,76 +146,103 @@ func (r *Row) isRenderableLeaf() bool {
 	return r.isLeaf() && r.Widget != nil
 }
 
+// lines splits the cols of r into the lines they take, a col going under
+// the previous ones when their spans and offsets would pass 12.
+func (r *Row) lines() [][]*Row {
+	var ls [][]*Row
+	acc := 0
+	for _, c := range r.Cols {
+		n := c.span() + c.offset()
+		if len(ls) == 0 || (acc > 0 && acc+n > 12) {
+			ls = append(ls, nil)
+			acc = 0
+		}
+		ls[len(ls)-1] = append(ls[len(ls)-1], c)
+		acc += n
+	}
+	return ls
+}
+
 // assign widgets' (and their parent rows') width recursively.
 func (r *Row) assignWidth(w int) {
 	r.SetWidth(w)
 
-	accW := 0                            // acc span and offset
-	calcW := make([]int, len(r.Cols))    // calculated width
-	calcOftX := make([]int, len(r.Cols)) // computated start position of x
+	for _, l := range r.lines() {
+		accW := 0                       // acc span and offset
+		calcW := make([]int, len(l))    // calculated width
+		calcOftX := make([]int, len(l)) // computated start position of x
 
-	for i, c := range r.Cols {
-		accW += c.Span + c.Offset
-		cw := int(float64(c.Span*r.Width) / 12.0)
+		for i, c := range l {
+			accW += c.span() + c.offset()
+			cw := int(float64(c.span()*r.Width) / 12.0)
 
-		if i >= 1 {
-			calcOftX[i] = calcOftX[i-1] +
-				calcW[i-1] +
-				int(float64(r.Cols[i-1].Offset*r.Width)/12.0)
-		}
+			if i >= 1 {
+				calcOftX[i] = calcOftX[i-1] +
+					calcW[i-1] +
+					int(float64(l[i-1].offset()*r.Width)/12.0)
+			}
 
-		// use up the space if it is the last col
-		if i == len(r.Cols)-1 && accW == 12 {
-			cw = r.Width - calcOftX[i]
+			// use up the space if it is the last col
+			if i == len(l)-1 && accW == 12 {
+				cw = r.Width - calcOftX[i]
+			}
+			calcW[i] = cw
+			c.assignWidth(cw)
 		}
-		calcW[i] = cw
-		r.Cols[i].assignWidth(cw)
 	}
 }
 
 // bottom up calc and set rows' (and their widgets') height,
 // return r's total height.
 func (r *Row) solveHeight() int {
+	if r.hidden() {
+		r.Height = 0
+		return 0
+	}
 	if r.isRenderableLeaf() {
-		r.Height = r.Widget.GetHeight()
-		return r.Widget.GetHeight()
+		r.Height = r.widgetHeight()
+		return r.Height
 	}
 
-	maxh := 0
+	h := 0
 	if !r.isLeaf() {
-		for _, c := range r.Cols {
-			nh := c.solveHeight()
-			// when embed rows in Cols, row widgets stack up
-			if r.Widget != nil {
-				nh += r.Widget.GetHeight()
-			}
-			if nh > maxh {
-				maxh = nh
+		// when embed rows in Cols, row widgets stack up
+		if r.Widget != nil {
+			h = r.widgetHeight()
+		}
+		for _, l := range r.lines() {
+			lh := 0
+			for _, c := range l {
+				if nh := c.solveHeight(); nh > lh {
+					lh = nh
+				}
 			}
+			h += lh
 		}
 	}
 
-	r.Height = maxh
-	return maxh
+	r.Height = h
+	return h
 }
 
 // recursively assign x position for r tree.
 func (r *Row) assignX(x int) {
 	r.SetX(x)
 
-	if !r.isLeaf() {
+	for _, l := range r.lines() {
 		acc := 0
-		for i, c := range r.Cols {
-			if c.Offset != 0 {
-				acc += int(float64(c.Offset*r.Width) / 12.0)
+		for _, c := range l {
+			if c.offset() != 0 {
+				acc += int(float64(c.offset()*r.Width) / 12.0)
 			}
-			r.Cols[i].assignX(x + acc)
+			c.assignX(x + acc)
 			acc += c.Width
 		}
 	}
 }
 
-// recursively assign y position to r.
+// recursively assign y position to r, the lines of its cols one under the
+// other.
 func (r *Row) assignY(y int) {
 	r.SetY(y)
 
@@ -119,14 +250,98 @@ func (r *Row) assignY(y int) {
 		return
 	}
 
-	for i := range r.Cols {
-		acc := 0
-		if r.Widget != nil {
-			acc = r.Widget.GetHeight()
+	if r.Widget != nil {
+		y += r.widgetHeight()
+	}
+	for _, l := range r.lines() {
+		lh := 0
+		for _, c := range l {
+			c.assignY(y)
+			if c.Height > lh {
+				lh = c.Height
+			}
+		}
+		y += lh
+	}
+}
+
+// stackDepth returns the number of widgets stacked up in r's tallest col.
+func (r *Row) stackDepth() int {
+	if r.hidden() {
+		return 0
+	}
+	d := 0
+	for _, l := range r.lines() {
+		d += lineDepth(l)
+	}
+	if r.Widget != nil && !collapsed(r.Widget) {
+		d++
+	}
+	return d
+}
+
+// lineDepth returns the stackDepth of the tallest col of line l.
+func lineDepth(l []*Row) int {
+	d := 0
+	for _, c := range l {
+		if n := c.stackDepth(); n > d {
+			d = n
+		}
+	}
+	return d
+}
+
+// assignHeight makes r h rows high, sharing h between the widgets stacked
+// up in each col.
+func (r *Row) assignHeight(h int) {
+	if r.hidden() {
+		return
+	}
+	if r.Widget != nil && !collapsed(r.Widget) {
+		wh := h
+		if n := r.stackDepth(); n > 1 {
+			wh = h / n
+		}
+		if b, ok := r.Widget.(blocker); ok {
+			k := b.block()
+			k.Lock()
+			k.Height = wh
+			k.Unlock()
+		}
+		h -= wh
+	}
+
+	// the lines share h by their depth
+	ls := r.lines()
+	total := 0
+	for _, l := range ls {
+		total += lineDepth(l)
+	}
+	given, seen := 0, 0
+	for _, l := range ls {
+		lh := h
+		if total > 0 && len(ls) > 1 {
+			seen += lineDepth(l)
+			lh = h*seen/total - given
+			given += lh
+		}
+		for _, c := range l {
+			c.assignHeight(lh)
 		}
-		r.Cols[i].assignY(y + acc)
 	}
+}
+
+// relative tells if r's height follows the grid's.
+func (r *Row) relative() bool {
+	return r.HeightPercent > 0 || r.HeightRatio > 0
+}
 
+// widgetHeight returns the height r's widget takes in the layout.
+func (r *Row) widgetHeight() int {
+	if collapsed(r.Widget) {
+		return 0
+	}
+	return r.Widget.GetHeight()
 }
 
 // GetHeight implements GridBufferer interface.
@@ -162,14 +377,18 @@ func (r *Row) SetWidth(w int) {
 // recursively merge all widgets buffer
 func (r *Row) Buffer() Buffer {
 	merged := NewBuffer()
+	if r.hidden() {
+		r.undraw()
+		return merged
+	}
 
 	if r.isRenderableLeaf() {
-		return r.Widget.Buffer()
+		return bufferOf(r.Widget)
 	}
 
 	// for those are not leaves but have a renderable widget
 	if r.Widget != nil {
-		merged.Merge(r.Widget.Buffer())
+		merged.Merge(bufferOf(r.Widget))
 	}
 
 	// collect buffer from children
@@ -209,6 +428,11 @@ type Grid struct {
 	X       int
 	Y       int
 	BgColor Attribute
+	Free    *FreeLayer // drawn on top of Rows, see AddFree
+
+	// Height is shared by the rows with a HeightPercent or HeightRatio, 0
+	// standing for the viewport's height.
+	Height int
 }
 
 // NewGrid returns *Grid with given rows.
@@ -221,6 +445,15 @@ func (g *Grid) AddRows(rs ...*Row) {
 	g.Rows = append(g.Rows, rs...)
 }
 
+// AddFree places widgets outside of the rows, at their own X and Y. They are
+// drawn after, thus over, the rows.
+func (g *Grid) AddFree(bs ...Bufferer) {
+	if g.Free == nil {
+		g.Free = NewFreeLayer()
+	}
+	g.Free.Add(bs...)
+}
+
 // NewRow creates a new row out of given columns.
 func NewRow(cols ...*Row) *Row {
 	rs := &Row{Span: 12, Cols: cols}
@@ -254,8 +487,13 @@ func NewCol(span, offset int, widgets ...GridBufferer) *Row {
 	return r
 }
 
-// Align calculate each rows' layout.
+// Align calculate each rows' layout, following the Breakpoints for the
+// grid's Width.
 func (g *Grid) Align() {
+	for _, r := range g.Rows {
+		r.applyBreakpoints(g.Width)
+	}
+	g.assignHeights()
 	h := 0
 	for _, r := range g.Rows {
 		r.SetWidth(g.Width)
@@ -266,6 +504,68 @@ func (g *Grid) Align() {
 	}
 }
 
+// assignHeights sizes the rows with a HeightPercent, then shares what the
+// other rows leave between the rows with a HeightRatio.
+func (g *Grid) assignHeights() {
+	if !g.relative() {
+		return
+	}
+	total := g.Height
+	if total <= 0 {
+		total = TermRect().Dy()
+	}
+	left, ratios := total, 0
+	for _, r := range g.Rows {
+		switch {
+		case r.HeightPercent > 0:
+			h := total * r.HeightPercent / 100
+			r.assignHeight(h)
+			left -= h
+		case r.HeightRatio > 0:
+			ratios += r.HeightRatio
+		default:
+			left -= r.solveHeight()
+		}
+	}
+	if ratios == 0 {
+		return
+	}
+	if left < 0 {
+		left = 0
+	}
+	given, seen := 0, 0
+	for _, r := range g.Rows {
+		if r.HeightPercent > 0 || r.HeightRatio <= 0 {
+			continue
+		}
+		// round the running total so the last row takes the rest
+		seen += r.HeightRatio
+		h := left*seen/ratios - given
+		r.assignHeight(h)
+		given += h
+	}
+}
+
+// relative tells if some row's height follows the grid's.
+func (g *Grid) relative() bool {
+	for _, r := range g.Rows {
+		if r.relative() {
+			return true
+		}
+	}
+	return false
+}
+
+// responsive tells if some row or col has Breakpoints.
+func (g *Grid) responsive() bool {
+	for _, r := range g.Rows {
+		if r.responsive() {
+			return true
+		}
+	}
+	return false
+}
+
 // Buffer implments Bufferer interface.
 func (g Grid) Buffer() Buffer {
 	buf := NewBuffer()
@@ -273,6 +573,9 @@ func (g Grid) Buffer() Buffer {
 	for _, r := range g.Rows {
 		buf.Merge(r.Buffer())
 	}
+	if g.Free != nil {
+		buf.Merge(g.Free.Buffer())
+	}
 	return buf
 }
 
@@ -5,6 +5,7 @@
 package termui
 
 import (
+	"image"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -78,3 +79,204 @@ func TestAssignXY(t *testing.T) {
 		t.Error("assignXY fails")
 	}
 }
+
+func TestGridCollapseWhenHidden(t *testing.T) {
+	w0, w1 := NewBlock(), NewBlock()
+	w0.Height, w1.Height = 5, 3
+
+	g := NewGrid(NewRow(NewCol(12, 0, w0, w1)))
+	g.Width = 20
+	g.Align()
+	if w1.Y != 5 {
+		t.Fatalf("expected w1 at y=5, got %d", w1.Y)
+	}
+
+	w0.Visible = false
+	g.Align()
+	if w1.Y != 5 {
+		t.Errorf("hidden widget should keep its slot, w1 at y=%d", w1.Y)
+	}
+
+	w0.CollapseWhenHidden = true
+	g.Align()
+	if w1.Y != 0 {
+		t.Errorf("collapsed widget should release its slot, w1 at y=%d", w1.Y)
+	}
+}
+
+func TestGridAddFree(t *testing.T) {
+	w := NewPar("grid")
+	w.Height = 3
+	g := NewGrid(NewRow(NewCol(12, 0, w)))
+	g.Width = 20
+	g.Align()
+
+	tip := NewPar("tip")
+	tip.Border = false
+	tip.X, tip.Y = 2, 1
+	tip.Width, tip.Height = 3, 1
+	g.AddFree(tip)
+
+	buf := g.Buffer()
+	if c := buf.At(2, 1); c.Ch != 't' {
+		t.Errorf("free widget should be drawn over the rows, got %q", c.Ch)
+	}
+	if c := buf.At(6, 1); c.Ch != ' ' {
+		t.Errorf("the rows should show around the free widget, got %q", c.Ch)
+	}
+
+	g.Free.Remove(tip)
+	if c := g.Buffer().At(2, 1); c.Ch != 'r' {
+		t.Errorf("removed free widget should not be drawn, got %q", c.Ch)
+	}
+}
+
+func TestWidgetAt(t *testing.T) {
+	_, done := useFakeBackend(40, 8)
+	defer done()
+	defer resetHits()
+	resetHits()
+
+	l, p0, p1 := NewList(), NewPar("a"), NewPar("b")
+	l.Height, p0.Height, p1.Height = 4, 4, 2
+	g := NewGrid(NewRow(NewCol(6, 0, l), NewCol(6, 0, p0, p1)))
+	g.Width = 20
+	g.Align()
+
+	want := map[*Block]image.Rectangle{
+		&l.Block:  image.Rect(0, 0, 10, 4),
+		&p0.Block: image.Rect(10, 0, 20, 4),
+		&p1.Block: image.Rect(10, 4, 20, 6),
+	}
+	for b, r := range want {
+		if got := b.Bounds(); got != r {
+			t.Errorf("Bounds() = %v, want %v", got, r)
+		}
+	}
+
+	if WidgetAt(1, 1) != nil {
+		t.Error("widgets not drawn yet should not be found")
+	}
+	// a frame composed off screen is not hit until it is rendered
+	pre := Prerender(g)
+	if WidgetAt(1, 1) != nil {
+		t.Error("prerendered widgets should not be found")
+	}
+	Render(pre)
+	if WidgetAt(1, 1) != l {
+		t.Error("expected the prerendered widgets found once rendered")
+	}
+	f := NewPar("float")
+	f.X, f.Y, f.Width, f.Height = 8, 2, 5, 3
+	f.ZIndex = 1
+	Render(f, g)
+
+	for _, c := range []struct {
+		x, y int
+		w    Bufferer
+	}{
+		{1, 1, l},
+		{15, 1, p0},
+		{15, 5, p1},
+		{9, 2, f},
+		{11, 3, f},
+		{30, 1, nil},
+	} {
+		if got := WidgetAt(c.x, c.y); got != c.w {
+			t.Errorf("WidgetAt(%d, %d) = %T %p, want %p", c.x, c.y, got, got, c.w)
+		}
+	}
+
+	f.Visible = false
+	if got := WidgetAt(9, 2); got != l {
+		t.Errorf("a hidden widget should not be hit, got %T", got)
+	}
+}
+
+func TestGridRelativeHeights(t *testing.T) {
+	header, a, b, c0, c1 := NewPar("h"), NewPar("a"), NewPar("b"), NewPar("c0"), NewPar("c1")
+	header.Height = 3
+	g := NewGrid(
+		NewRow(NewCol(12, 0, header)),
+		NewRow(NewCol(6, 0, a), NewCol(6, 0, c0, c1)),
+		NewRow(NewCol(12, 0, b)))
+	g.Rows[1].HeightRatio = 2
+	g.Rows[2].HeightRatio = 1
+	g.Width, g.Height = 40, 30
+	g.Align()
+
+	// 27 rows left by the header, shared 2:1
+	if a.Height != 18 || b.Height != 9 || b.Y != 21 {
+		t.Errorf("ratios: got a=%d b=%d at y=%d", a.Height, b.Height, b.Y)
+	}
+	if c0.Height != 9 || c1.Height != 9 || c1.Y != 12 {
+		t.Errorf("expected stacked widgets to share the height, got %d %d at y=%d", c0.Height, c1.Height, c1.Y)
+	}
+
+	g.Rows[0].HeightPercent = 50
+	g.Height = 20
+	g.Align()
+	if header.Height != 10 || a.Height != 6 || b.Height != 4 {
+		t.Errorf("percent: got header=%d a=%d b=%d", header.Height, a.Height, b.Height)
+	}
+}
+
+func TestGridBreakpoints(t *testing.T) {
+	a, b, c := NewBlock(), NewBlock(), NewBlock()
+	a.Height, b.Height, c.Height = 2, 2, 3
+	g := NewGrid(
+		NewRow(
+			NewCol(12, 0, a).At(80, 6, 0).At(160, 4, 0),
+			NewCol(12, 0, b).At(80, 6, 0).At(160, 4, 0),
+			NewCol(4, 0, c).HiddenAt(0).At(160, 4, 0)))
+
+	cases := []struct {
+		width, aw, bx, h int
+		cShown           bool
+	}{
+		{60, 60, 0, 4, false},
+		{100, 50, 50, 2, false},
+		{180, 60, 60, 3, true},
+	}
+	for _, tc := range cases {
+		g.Width = tc.width
+		g.Align()
+		if a.Width != tc.aw || b.X != tc.bx || g.Rows[0].Height != tc.h {
+			t.Errorf("width %d: got a %d wide, b at %d, a row %d high", tc.width, a.Width, b.X, g.Rows[0].Height)
+		}
+		_, drawn := g.Buffer().CellMap[image.Pt(c.X, c.Y)]
+		if drawn != tc.cShown {
+			t.Errorf("width %d: expected c drawn %v", tc.width, tc.cShown)
+		}
+	}
+	if !g.responsive() {
+		t.Error("expected the grid responsive")
+	}
+}
+
+func TestFreeLayerRemove(t *testing.T) {
+	fb, done := useFakeBackend(20, 8)
+	defer done()
+	defer resetHits()
+
+	g := NewGrid()
+	g.Width = 20
+	tip := NewPar("tip")
+	tip.Border = false
+	tip.X, tip.Y = 2, 5
+	tip.Width, tip.Height = 3, 1
+	g.AddFree(tip)
+	Render(g)
+	if fb.cells[image.Pt(2, 5)].Ch != 't' || WidgetAt(2, 5) != tip {
+		t.Fatal("expected the free widget drawn and hit")
+	}
+
+	g.Free.Remove(tip)
+	Render(g)
+	if c := fb.cells[image.Pt(2, 5)].Ch; c == 't' {
+		t.Error("expected the removed widget cleared from the screen")
+	}
+	if WidgetAt(2, 5) == tip {
+		t.Error("expected the removed widget out of the hit tests")
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"strings"
+	"sync"
+)
+
+// HeadlessBackend is a Backend drawing into memory, for testing an
+// application without a terminal. Events are injected with SendKey,
+// SendMouse and SendEvent, a resize with Resize, and what a real terminal
+// would show is read back with Screen, Line or Find.
+/*
+  hb := termui.NewHeadlessBackend(80, 24)
+  termui.InitBackend(hb)
+  defer termui.Close()
+  go termui.Loop()
+
+  termui.SendKey("j")
+  termui.WaitIdle()
+  if !strings.Contains(hb.Line(3), "> second item") {
+      t.Error("expected the second item selected")
+  }
+*/
+type HeadlessBackend struct {
+	mu     sync.Mutex
+	w, h   int
+	back   map[image.Point]Cell
+	front  map[image.Point]Cell
+	events chan Event
+	wake   chan struct{} // Interrupt
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewHeadlessBackend returns a HeadlessBackend of w columns and h rows.
+func NewHeadlessBackend(w, h int) *HeadlessBackend {
+	return &HeadlessBackend{
+		w:      w,
+		h:      h,
+		back:   make(map[image.Point]Cell),
+		front:  make(map[image.Point]Cell),
+		events: make(chan Event),
+		wake:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+}
+
+// Init implements Backend.
+func (hb *HeadlessBackend) Init() error { return nil }
+
+// Close implements Backend, PollEvent then blocks until Interrupt.
+func (hb *HeadlessBackend) Close() {
+	hb.once.Do(func() { close(hb.closed) })
+}
+
+// Size implements Backend.
+func (hb *HeadlessBackend) Size() (int, int) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	return hb.w, hb.h
+}
+
+// SetCell implements Backend.
+func (hb *HeadlessBackend) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	hb.back[image.Pt(x, y)] = Cell{ch, fg, bg}
+}
+
+// SetCombiningCell implements CombiningBackend.
+func (hb *HeadlessBackend) SetCombiningCell(x, y int, ch rune, comb []rune, fg, bg Attribute) {
+	hb.SetCell(x, y, clusterRune(append([]rune{ch}, comb...)), fg, bg)
+}
+
+// Flush implements Backend, making the cells set so far visible.
+func (hb *HeadlessBackend) Flush() error {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	for p, c := range hb.back {
+		hb.front[p] = c
+	}
+	return nil
+}
+
+// PollEvent implements Backend, returning the events of Resize.
+func (hb *HeadlessBackend) PollEvent() Event {
+	select {
+	case e := <-hb.events:
+		return e
+	case <-hb.wake:
+		return Event{Type: "interrupt", From: "/sys", Time: now().Unix()}
+	}
+}
+
+// Interrupt implements InterruptibleBackend.
+func (hb *HeadlessBackend) Interrupt() {
+	select {
+	case hb.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Resize changes the size of the terminal and sends the resize event a
+// real one would. It blocks until the event is polled.
+func (hb *HeadlessBackend) Resize(w, h int) {
+	hb.mu.Lock()
+	hb.w, hb.h = w, h
+	for p := range hb.front {
+		if p.X >= w || p.Y >= h {
+			delete(hb.front, p)
+			delete(hb.back, p)
+		}
+	}
+	hb.mu.Unlock()
+	hb.events <- Event{
+		Type: "window",
+		From: "/sys",
+		Path: "/sys/wnd/resize",
+		Data: EvtWnd{Width: w, Height: h},
+		Time: now().Unix(),
+	}
+}
+
+// Screen returns a copy of what the terminal shows, cells never drawn being
+// blank.
+func (hb *HeadlessBackend) Screen() Buffer {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	buf := NewBuffer()
+	buf.SetArea(image.Rect(0, 0, hb.w, hb.h))
+	for p, c := range hb.front {
+		buf.CellMap[p] = c
+	}
+	return buf
+}
+
+// Cell returns the cell shown at (x, y).
+func (hb *HeadlessBackend) Cell(x, y int) Cell {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	if c, ok := hb.front[image.Pt(x, y)]; ok {
+		return c
+	}
+	return Cell{' ', ColorDefault, ColorDefault}
+}
+
+// Line returns the text of row y, a wide glyph taking a single rune.
+func (hb *HeadlessBackend) Line(y int) string {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+	var sb strings.Builder
+	for x := 0; x < hb.w; x++ {
+		c, ok := hb.front[image.Pt(x, y)]
+		if !ok {
+			sb.WriteRune(' ')
+			continue
+		}
+		sb.WriteString(runesToStr([]rune{c.Ch}))
+		if charWidth(c.Ch) == 2 {
+			x++
+		}
+	}
+	return sb.String()
+}
+
+// Text returns the rows shown, separated by '\n'.
+func (hb *HeadlessBackend) Text() string {
+	_, h := hb.Size()
+	ls := make([]string, h)
+	for y := range ls {
+		ls[y] = hb.Line(y)
+	}
+	return strings.Join(ls, "\n")
+}
+
+// Find returns the position of the first cell showing s, looking row by
+// row, and whether there is one. s must not span rows.
+func (hb *HeadlessBackend) Find(s string) (image.Point, bool) {
+	_, h := hb.Size()
+	for y := 0; y < h; y++ {
+		l := hb.Line(y)
+		i := strings.Index(l, s)
+		if i < 0 {
+			continue
+		}
+		// back to a column, past the wide glyphs before it
+		x := 0
+		for _, r := range l[:i] {
+			x += charWidth(r)
+		}
+		return image.Pt(x, y), true
+	}
+	return image.Point{}, false
+}
@@ -0,0 +1,49 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestHeadlessBackend(t *testing.T) {
+	hb := NewHeadlessBackend(12, 4)
+	old, ow, oh, os := backend, termWidth, termHeight, screen
+	defer func() { backend, termWidth, termHeight, screen = old, ow, oh, os }()
+	backend, termWidth, termHeight, screen = hb, 12, 4, newScreenState()
+
+	p := NewPar("世界 e\u0301!")
+	p.Width, p.Height = 10, 3
+	Render(p)
+
+	if got := hb.Line(1); got != "│世界 e\u0301! │  " {
+		t.Errorf("unexpected line %q", got)
+	}
+	if pt, ok := hb.Find("!"); !ok || pt != image.Pt(7, 1) {
+		t.Errorf("expected ! at (7,1), got %v %v", pt, ok)
+	}
+	if c := hb.Cell(0, 0); c.Ch != TOP_LEFT || c.Fg != p.BorderFg {
+		t.Errorf("expected the top left corner, got %+v", c)
+	}
+
+	go hb.PollEvent()
+	hb.Resize(6, 2)
+	if w, h := hb.Size(); w != 6 || h != 2 || hb.Screen().At(8, 1).Ch != 0 {
+		t.Error("expected the screen cut to 6x2")
+	}
+}
+
+func TestMouseEvent(t *testing.T) {
+	if e := mouseEvent(1, 1, "MouseLeft"); e.Path != "/sys/mouse/left" {
+		t.Errorf("expected a press, got %s", e.Path)
+	}
+	if e := mouseEvent(2, 1, "MouseLeft"); e.Path != "/sys/mouse/drag/left" || !e.Data.(EvtMouse).Drag {
+		t.Errorf("expected a drag, got %s", e.Path)
+	}
+	if e := mouseEvent(2, 1, "MouseRelease"); e.Path != "/sys/mouse/release" {
+		t.Errorf("expected a release, got %s", e.Path)
+	}
+}
@@ -15,7 +15,7 @@ import rw "github.com/mattn/go-runewidth"
 /* ---------------Port from termbox-go --------------------- */
 
 // Attribute is printable cell's color and style.
-type Attribute uint16
+type Attribute uint64
 
 // 8 basic clolrs
 const (
@@ -48,11 +48,17 @@ var (
 /* ----------------------- End ----------------------------- */
 
 func toTmAttr(x Attribute) tm.Attribute {
-	return tm.Attribute(x)
+	// termbox has no truecolor mode
+	depth := colorDepth
+	if depth > 256 {
+		depth = 256
+	}
+	return tm.Attribute(quantizeAttr(x, depth))
 }
 
+// str2runes returns the runes of s, a grapheme cluster taking one.
 func str2runes(s string) []rune {
-	return []rune(s)
+	return clusterRunes([]rune(s))
 }
 
 // Here for backwards-compatibility.
@@ -62,17 +68,12 @@ func trimStr2Runes(s string, w int) []rune {
 
 // TrimStr2Runes trims string to w[-1 rune], appends …, and returns the runes
 // of that string if string is grather then n. If string is small then w,
-// return the runes.
+// return the runes. A grapheme cluster is kept or dropped whole.
 func TrimStr2Runes(s string, w int) []rune {
 	if w <= 0 {
 		return []rune{}
 	}
-
-	sw := rw.StringWidth(s)
-	if sw > w {
-		return []rune(rw.Truncate(s, w, dot))
-	}
-	return str2runes(s)
+	return truncateRunes(str2runes(s), w)
 }
 
 // TrimStrIfAppropriate trim string to "s[:-1] + …"
@@ -82,9 +83,8 @@ func TrimStrIfAppropriate(s string, w int) string {
 		return ""
 	}
 
-	sw := rw.StringWidth(s)
-	if sw > w {
-		return rw.Truncate(s, w, dot)
+	if rw.StringWidth(s) > w {
+		return runesToStr(truncateRunes(str2runes(s), w))
 	}
 
 	return s
@@ -95,7 +95,7 @@ func strWidth(s string) int {
 }
 
 func charWidth(ch rune) int {
-	return rw.RuneWidth(ch)
+	return runeWidth(ch)
 }
 
 var whiteSpaceRegex = regexp.MustCompile(`\s`)
@@ -146,6 +146,9 @@ func StringToAttribute(text string) Attribute {
 
 		case "reverse":
 			match = AttrReverse
+
+		default:
+			match, _ = hexColor(theAttribute)
 		}
 
 		result |= match
@@ -213,10 +216,11 @@ func DTrimTxCls(cs []Cell, w int) []Cell {
 	return rt
 }
 
+// CellsToStr returns the text of cs.
 func CellsToStr(cs []Cell) string {
-	str := ""
-	for _, c := range cs {
-		str += string(c.Ch)
+	rs := make([]rune, len(cs))
+	for i, c := range cs {
+		rs[i] = c.Ch
 	}
-	return str
+	return runesToStr(rs)
 }
@@ -0,0 +1,122 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sync"
+)
+
+// Bounds returns the rectangle b occupies on the terminal once laid out,
+// i.e. its area offset by the viewport.
+func (b *Block) Bounds() image.Rectangle {
+	b.Align()
+	b.lock.RLock()
+	r := b.area
+	b.lock.RUnlock()
+	return r.Add(viewportOrigin())
+}
+
+func viewportOrigin() image.Point {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	return viewport.Min
+}
+
+type hit struct {
+	w   Bufferer
+	seq uint64 // draw order, later is on top
+}
+
+// hits registers the widgets with a Block that have been drawn on the
+// terminal, whether on their own or inside a Grid or FreeLayer, so WidgetAt
+// can find them. It is emptied whenever the screen is wiped, by a clear or
+// a resize.
+var hits = struct {
+	sync.Mutex
+	ws    map[*Block]hit
+	seq   uint64
+	drawn *[]drawnHit // collects the widgets drawn under collectHits
+}{ws: make(map[*Block]hit)}
+
+// drawnHit is a widget drawn into a frame, registered once the frame has
+// reached the terminal.
+type drawnHit struct {
+	k *Block
+	w Bufferer
+}
+
+// noteHit records w as drawn into the frame being composed, if any.
+func noteHit(k *Block, w Bufferer) {
+	hits.Lock()
+	defer hits.Unlock()
+	if hits.drawn != nil {
+		*hits.drawn = append(*hits.drawn, drawnHit{k, w})
+	}
+}
+
+// registerHits registers the widgets of a flushed frame, in drawing order.
+func registerHits(hs []drawnHit) {
+	hits.Lock()
+	defer hits.Unlock()
+	for _, h := range hs {
+		hits.seq++
+		hits.ws[h.k] = hit{h.w, hits.seq}
+	}
+}
+
+func unregisterHit(k *Block) {
+	hits.Lock()
+	defer hits.Unlock()
+	delete(hits.ws, k)
+	clearHotspots(k)
+}
+
+func resetHits() {
+	hits.Lock()
+	defer hits.Unlock()
+	hits.ws = make(map[*Block]hit)
+
+	hotspots.Lock()
+	hotspots.m = make(map[*Block][]Hotspot)
+	hotspots.Unlock()
+}
+
+// WidgetAt returns the topmost drawn widget covering the terminal cell
+// (x, y), or nil. The highest ZIndex wins, then the widget drawn last.
+// Hidden widgets are skipped.
+/*
+  ui.Handle("/sys/mouse", func(e ui.Event) {
+      m := e.Data.(ui.EvtMouse)
+      if ui.WidgetAt(m.X, m.Y) == list {
+          // ...
+      }
+  })
+*/
+func WidgetAt(x, y int) Bufferer {
+	hits.Lock()
+	ws := make(map[*Block]hit, len(hits.ws))
+	for k, h := range hits.ws {
+		ws[k] = h
+	}
+	hits.Unlock()
+
+	p := image.Pt(x, y)
+	var top Bufferer
+	var topZ int
+	var topSeq uint64
+	for k, h := range ws {
+		k.lock.RLock()
+		visible, z := k.Visible, k.ZIndex
+		k.lock.RUnlock()
+		if !visible || !p.In(k.Bounds()) {
+			continue
+		}
+		if top == nil || z > topZ || (z == topZ && h.seq > topSeq) {
+			top, topZ, topSeq = h.w, z, h.seq
+		}
+	}
+	return top
+}
@@ -0,0 +1,71 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sync"
+)
+
+// Hotspot is a clickable region of a widget. OnClick gets the position of
+// the click relative to Area.Min.
+type Hotspot struct {
+	Area    image.Rectangle // in the same coordinates as the widget's Buffer
+	OnClick func(x, y int)
+}
+
+// hotspots holds the regions declared by each widget during its last
+// Buffer, Block.Buffer starting afresh.
+var hotspots = struct {
+	sync.Mutex
+	m map[*Block][]Hotspot
+}{m: make(map[*Block][]Hotspot)}
+
+// AddHotspot declares r as clickable, calling fn on a left click inside it.
+// It is meant to be called from a widget's Buffer method, after
+// Block.Buffer, which drops the hotspots of the previous draw. Later
+// hotspots take precedence over earlier overlapping ones.
+func (b *Block) AddHotspot(r image.Rectangle, fn func(x, y int)) {
+	hotspots.Lock()
+	defer hotspots.Unlock()
+	hotspots.m[b] = append(hotspots.m[b], Hotspot{r, fn})
+}
+
+// Hotspots returns the hotspots declared by b's last draw.
+func (b *Block) Hotspots() []Hotspot {
+	hotspots.Lock()
+	defer hotspots.Unlock()
+	return append([]Hotspot(nil), hotspots.m[b]...)
+}
+
+func clearHotspots(b *Block) {
+	hotspots.Lock()
+	defer hotspots.Unlock()
+	delete(hotspots.m, b)
+}
+
+// dispatchHotspot calls the hotspot under a left click of e, not a drag,
+// on the topmost widget there, see WidgetAt. It reports whether one was
+// found.
+func dispatchHotspot(e Event) bool {
+	m, ok := e.Data.(EvtMouse)
+	if !ok || m.Press != "MouseLeft" || m.Drag {
+		return false
+	}
+	w, ok := WidgetAt(m.X, m.Y).(blocker)
+	if !ok {
+		return false
+	}
+	p := image.Pt(m.X, m.Y).Sub(viewportOrigin())
+	hs := w.block().Hotspots()
+	for i := len(hs) - 1; i >= 0; i-- {
+		if h := hs[i]; p.In(h.Area) && h.OnClick != nil {
+			q := p.Sub(h.Area.Min)
+			h.OnClick(q.X, q.Y)
+			return true
+		}
+	}
+	return false
+}
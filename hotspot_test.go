@@ -0,0 +1,76 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func click(x, y int) Event {
+	return Event{Path: "/sys/mouse", Data: EvtMouse{X: x, Y: y, Press: "MouseLeft"}}
+}
+
+func TestListHotspots(t *testing.T) {
+	_, done := useFakeBackend(20, 6)
+	defer done()
+	defer resetHits()
+
+	l := NewList()
+	l.Width, l.Height = 10, 4
+	l.Items = []string{"a", "b", "c", "d"}
+	l.ScrollTop = 1
+	got := -1
+	l.OnClick = func(i int) { got = i }
+	Render(l)
+
+	for y, want := range map[int]int{1: 1, 2: 2} {
+		got = -1
+		if !dispatchHotspot(click(3, y)) || got != want {
+			t.Errorf("click on row %d: got item %d, want %d", y, got, want)
+		}
+	}
+	if got = -1; dispatchHotspot(click(3, 0)) || got != -1 {
+		t.Error("a click on the border should not hit a row")
+	}
+
+	l.Overflow = "wrap"
+	l.Items = []string{"abcdefghijkl", "m"}
+	l.ScrollTop = 0
+	l.Height = 5
+	Render(l)
+	// the first item wraps over two rows
+	for y, want := range map[int]int{1: 0, 2: 0, 3: 1} {
+		got = -1
+		dispatchHotspot(click(3, y))
+		if got != want {
+			t.Errorf("wrap: click on row %d: got item %d, want %d", y, got, want)
+		}
+	}
+}
+
+func TestBarChartHotspots(t *testing.T) {
+	_, done := useFakeBackend(20, 6)
+	defer done()
+	defer resetHits()
+
+	bc := NewBarChart()
+	bc.Width, bc.Height = 14, 6
+	bc.Data = []int{1, 2, 3}
+	bc.DataLabels = []string{"a", "b", "c"}
+	got := -1
+	bc.OnClick = func(i int) { got = i }
+	Render(bc)
+
+	// bars are 3 wide with a gap of 1, from x=1
+	hook := DefaultWgtMgr.WgtHandlersHook()
+	for x, want := range map[int]int{1: 0, 3: 0, 5: 1, 9: 2} {
+		got = -1
+		hook(click(x, 2))
+		if got != want {
+			t.Errorf("click at x=%d: got bar %d, want %d", x, got, want)
+		}
+	}
+	if got = -1; dispatchHotspot(click(4, 2)) {
+		t.Errorf("a click in a gap should not hit a bar, got %d", got)
+	}
+}
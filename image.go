@@ -0,0 +1,261 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"sync"
+)
+
+// Image displays an image.Image scaled into its inner area, two pixels per
+// cell drawn as a "▀" half block, the upper pixel in the foreground and the
+// lower one in the background. Transparent pixels show the Block's Bg.
+// With Sixel set, terminals reached through a SixelBackend draw the image
+// in sixel graphics over the half blocks, at CellPixels pixels per cell.
+/*
+  f, _ := os.Open("logo.png")
+  img, _, _ := image.Decode(f)
+  im := termui.NewImage(img)
+  im.Width, im.Height = 40, 20
+*/
+type Image struct {
+	Block
+	Img        image.Image
+	Stretch    bool        // fill the inner area instead of keeping the aspect ratio, centered
+	Sixel      bool        // draw with sixel graphics where supported
+	CellPixels image.Point // size of a cell in pixels, for sixel output
+}
+
+// NewImage returns a new *Image of img.
+func NewImage(img image.Image) *Image {
+	return &Image{
+		Block:      *NewBlock(),
+		Img:        img,
+		CellPixels: image.Pt(10, 20),
+	}
+}
+
+// fitImage returns the cells of r an image of bounds src takes, at sx x sy
+// pixels per cell, centered and kept to its aspect ratio unless stretched.
+func fitImage(src image.Rectangle, r image.Rectangle, sx, sy int, stretch bool) image.Rectangle {
+	if stretch || src.Empty() || r.Empty() {
+		return r
+	}
+	// compare in pixels: the image width over height against the area's
+	w, h := r.Dx()*sx, r.Dy()*sy
+	iw, ih := src.Dx(), src.Dy()
+	if w*ih > h*iw {
+		w = h * iw / ih
+	} else {
+		h = w * ih / iw
+	}
+	cw, ch := (w+sx-1)/sx, (h+sy-1)/sy
+	if cw < 1 {
+		cw = 1
+	}
+	if ch < 1 {
+		ch = 1
+	}
+	min := r.Min.Add(image.Pt((r.Dx()-cw)/2, (r.Dy()-ch)/2))
+	return image.Rectangle{min, min.Add(image.Pt(cw, ch))}
+}
+
+// sampleImage returns the colour of pixel (x,y) of img scaled to w x h
+// pixels, averaging the source pixels it covers, and whether it is opaque.
+func sampleImage(img image.Image, w, h, x, y int) (r, g, b uint8, opaque bool) {
+	src := img.Bounds()
+	x0 := src.Min.X + x*src.Dx()/w
+	x1 := src.Min.X + (x+1)*src.Dx()/w
+	y0 := src.Min.Y + y*src.Dy()/h
+	y1 := src.Min.Y + (y+1)*src.Dy()/h
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+
+	var sr, sg, sb, sa, n uint64
+	for j := y0; j < y1; j++ {
+		for i := x0; i < x1; i++ {
+			cr, cg, cb, ca := img.At(i, j).RGBA()
+			sr, sg, sb, sa = sr+uint64(cr), sg+uint64(cg), sb+uint64(cb), sa+uint64(ca)
+			n++
+		}
+	}
+	if sa/n < 0x8000 {
+		return 0, 0, 0, false
+	}
+	// un-premultiply
+	return uint8(sr * 0xff / sa), uint8(sg * 0xff / sa), uint8(sb * 0xff / sa), true
+}
+
+// Buffer implements Bufferer interface.
+func (im *Image) Buffer() Buffer {
+	buf := im.Block.Buffer()
+	im.RLock()
+	defer im.RUnlock()
+	if im.Img == nil || im.Img.Bounds().Empty() {
+		return buf
+	}
+
+	r := fitImage(im.Img.Bounds(), im.innerArea, 1, 2, im.Stretch)
+	w, h := r.Dx(), r.Dy()*2
+	pixel := func(x, y int) (Attribute, bool) {
+		cr, cg, cb, ok := sampleImage(im.Img, w, h, x, y)
+		return ColorRGB24(cr, cg, cb), ok
+	}
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < w; x++ {
+			top, topOk := pixel(x, 2*y)
+			bot, botOk := pixel(x, 2*y+1)
+			c := Cell{'▀', top, bot}
+			switch {
+			case !topOk && !botOk:
+				c = Cell{' ', ColorDefault, im.Bg}
+			case !topOk:
+				c = Cell{'▄', bot, im.Bg}
+			case !botOk:
+				c.Bg = im.Bg
+			}
+			buf.Set(r.Min.X+x, r.Min.Y+y, c)
+		}
+	}
+
+	if im.Sixel {
+		img, px := im.Img, im.CellPixels
+		sr := fitImage(img.Bounds(), im.innerArea, px.X, px.Y, im.Stretch)
+		sixels.Lock()
+		sixels.l = append(sixels.l, sixelAt{sr, func() []byte {
+			return EncodeSixel(img, sr.Dx()*px.X, sr.Dy()*px.Y)
+		}})
+		sixels.Unlock()
+	}
+	return buf
+}
+
+// SixelBackend is a Backend that can draw sixel graphics, data being the
+// whole escape sequence, with the cursor moved to cell (x, y) first.
+type SixelBackend interface {
+	Backend
+	DrawSixel(x, y int, data []byte)
+}
+
+// sixelAt is a sixel image to draw over the cells of r, in widget
+// coordinates. It is encoded only if drawn.
+type sixelAt struct {
+	r    image.Rectangle
+	data func() []byte
+}
+
+// sixels are the sixel images of the frame being drawn.
+var sixels struct {
+	sync.Mutex
+	l []sixelAt
+}
+
+// takeSixels returns the sixel images queued since the last call.
+func takeSixels() []sixelAt {
+	sixels.Lock()
+	defer sixels.Unlock()
+	l := sixels.l
+	sixels.l = nil
+	return l
+}
+
+// drawSixels draws the sixel images of the frame whose cells were written
+// over, cs being the cells written, after the frame was flushed.
+func drawSixels(vp image.Rectangle, pending []sixelAt, cs []cellAt) {
+	sb, ok := backend.(SixelBackend)
+	if !ok {
+		return
+	}
+	for _, s := range pending {
+		r := s.r.Add(vp.Min)
+		for _, c := range cs {
+			if c.p.In(r) {
+				sb.DrawSixel(r.Min.X, r.Min.Y, s.data())
+				break
+			}
+		}
+	}
+}
+
+// EncodeSixel returns img scaled to w x h pixels as a sixel escape
+// sequence, in the colours of the 6x6x6 cube of the 256-colour palette.
+// Transparent pixels are left undrawn.
+func EncodeSixel(img image.Image, w, h int) []byte {
+	var out bytes.Buffer
+	if w <= 0 || h <= 0 || img.Bounds().Empty() {
+		return nil
+	}
+	// P2=1: pixels of colour 0 stay transparent
+	fmt.Fprintf(&out, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i+1, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	// cube index + 1 of every pixel, 0 when transparent
+	px := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, ok := sampleImage(img, w, h, x, y)
+			if ok {
+				px[y*w+x] = 1 + (int(r)+25)/51*36 + (int(g)+25)/51*6 + (int(b)+25)/51
+			}
+		}
+	}
+
+	row := make([]byte, w)
+	for band := 0; band < h; band += 6 {
+		used := map[int]bool{}
+		order := []int{}
+		for y := band; y < band+6 && y < h; y++ {
+			for x := 0; x < w; x++ {
+				if c := px[y*w+x]; c != 0 && !used[c] {
+					used[c] = true
+					order = append(order, c)
+				}
+			}
+		}
+		for i, c := range order {
+			for x := 0; x < w; x++ {
+				bits := byte(0)
+				for k := 0; k < 6 && band+k < h; k++ {
+					if px[(band+k)*w+x] == c {
+						bits |= 1 << uint(k)
+					}
+				}
+				row[x] = '?' + bits
+			}
+			fmt.Fprintf(&out, "#%d", c)
+			writeSixelRun(&out, row)
+			if i < len(order)-1 {
+				out.WriteByte('$')
+			}
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\")
+	return out.Bytes()
+}
+
+// writeSixelRun writes row, compressing repeats of a sixel.
+func writeSixelRun(out *bytes.Buffer, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, row[i])
+		} else {
+			out.Write(row[i:j])
+		}
+		i = j
+	}
+}
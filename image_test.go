@@ -0,0 +1,81 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// sixelBackend is a fakeBackend recording the sixel images drawn.
+type sixelBackend struct {
+	*fakeBackend
+	at []image.Point
+}
+
+func (b *sixelBackend) DrawSixel(x, y int, data []byte) {
+	b.at = append(b.at, image.Pt(x, y))
+}
+
+func TestImageHalfBlocks(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 4))
+	for x := 0; x < 2; x++ {
+		img.Set(x, 0, red)
+		img.Set(x, 1, blue)
+		img.Set(x, 3, blue) // row 2 stays transparent
+	}
+	im := NewImage(img)
+	im.Border = false
+	im.Width, im.Height = 2, 2
+	buf := im.Buffer()
+
+	if c := buf.At(0, 0); c.Ch != '▀' || c.Fg != ColorRGB24(255, 0, 0) || c.Bg != ColorRGB24(0, 0, 255) {
+		t.Errorf("expected red over blue, got %+v", c)
+	}
+	if c := buf.At(1, 1); c.Ch != '▄' || c.Fg != ColorRGB24(0, 0, 255) || c.Bg != im.Bg {
+		t.Errorf("expected blue under a transparent pixel, got %+v", c)
+	}
+}
+
+func TestImageFit(t *testing.T) {
+	// a square image in a 10x3 area takes 6 pixels, 6 cells across
+	r := fitImage(image.Rect(0, 0, 50, 50), image.Rect(0, 0, 10, 3), 1, 2, false)
+	if r != image.Rect(2, 0, 8, 3) {
+		t.Errorf("expected a centered square, got %v", r)
+	}
+	if r := fitImage(image.Rect(0, 0, 50, 50), image.Rect(0, 0, 10, 3), 1, 2, true); r.Dx() != 10 {
+		t.Errorf("expected the image stretched, got %v", r)
+	}
+}
+
+func TestImageSixel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 6))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{255, 255, 255, 255})
+	}
+	data := EncodeSixel(img, 4, 6)
+	// white is cube entry 215, only the top row of the band set
+	if !bytes.HasPrefix(data, []byte("\x1bP0;1;0q\"1;1;4;6")) ||
+		!bytes.HasSuffix(data, []byte("#216!4@-\x1b\\")) {
+		t.Errorf("unexpected sixel data %q", data)
+	}
+
+	fb, done := useFakeBackend(10, 5)
+	defer done()
+	sb := &sixelBackend{fakeBackend: fb}
+	backend = sb
+
+	im := NewImage(img)
+	im.Sixel = true
+	im.X, im.Width, im.Height = 2, 6, 4
+	Render(im)
+	Render(im)
+	if len(sb.at) != 1 || sb.at[0] != image.Pt(3, 1) {
+		t.Errorf("expected the image drawn once, got %v", sb.at)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyMap binds chords, runs of keys separated by spaces such as "g g" or
+// "C-x C-c", to functions. Keys are named as in /sys/kbd paths, see
+// NormalizeKey. The keys of a chord must follow each other within Timeout.
+// DefaultKeyMap holds the global bindings, a widget's Block.Keys the ones
+// active while it has the focus, see FocusManager. Keys taken by a map, a
+// chord prefix included, do not reach the widgets' handlers.
+/*
+  ui.DefaultKeyMap.Bind("C-x C-c", ui.StopLoop)
+  list.Keys = ui.NewKeyMap()
+  list.Keys.Bind("g g", func() { list.ScrollTop = 0 })
+*/
+type KeyMap struct {
+	sync.Mutex
+	Timeout  time.Duration
+	bindings map[string]func()
+	pending  []string
+	last     time.Time
+}
+
+// NewKeyMap returns an empty *KeyMap with a one second Timeout.
+func NewKeyMap() *KeyMap {
+	return &KeyMap{
+		Timeout:  time.Second,
+		bindings: make(map[string]func()),
+	}
+}
+
+// DefaultKeyMap holds the global bindings.
+var DefaultKeyMap = NewKeyMap()
+
+// parseChord returns the canonical keys of chord.
+func parseChord(chord string) []string {
+	ks := strings.Fields(chord)
+	for i, k := range ks {
+		ks[i] = normalizeKeyStr(k)
+	}
+	return ks
+}
+
+// isPrefix tells if a starts b, a key sequence being a prefix of itself.
+func isPrefix(a, b []string) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Bind runs fn when chord is typed. It fails if chord is empty, already
+// bound, or starts or is started by a bound chord, which would make one of
+// them unreachable.
+func (km *KeyMap) Bind(chord string, fn func()) error {
+	ks := parseChord(chord)
+	if len(ks) == 0 {
+		return fmt.Errorf("termui: empty chord %q", chord)
+	}
+	km.Lock()
+	defer km.Unlock()
+	for c := range km.bindings {
+		bs := strings.Split(c, " ")
+		if isPrefix(ks, bs) || isPrefix(bs, ks) {
+			return fmt.Errorf("termui: chord %q conflicts with %q", chord, c)
+		}
+	}
+	km.bindings[strings.Join(ks, " ")] = fn
+	return nil
+}
+
+// Unbind removes the binding of chord.
+func (km *KeyMap) Unbind(chord string) {
+	km.Lock()
+	defer km.Unlock()
+	delete(km.bindings, strings.Join(parseChord(chord), " "))
+}
+
+// Bindings returns the bound chords, sorted.
+func (km *KeyMap) Bindings() []string {
+	km.Lock()
+	defer km.Unlock()
+	cs := make([]string, 0, len(km.bindings))
+	for c := range km.bindings {
+		cs = append(cs, c)
+	}
+	sort.Strings(cs)
+	return cs
+}
+
+// Pending returns the keys typed so far of an unfinished chord, e.g. to
+// show them in a status bar.
+func (km *KeyMap) Pending() string {
+	km.Lock()
+	defer km.Unlock()
+	if km.expired() {
+		return ""
+	}
+	return strings.Join(km.pending, " ")
+}
+
+// expired tells if the pending keys timed out. The caller must hold the
+// lock.
+func (km *KeyMap) expired() bool {
+	return km.Timeout > 0 && now().Sub(km.last) > km.Timeout
+}
+
+// lookup tells if ks is bound, returning its function, or starts a chord.
+// The caller must hold the lock.
+func (km *KeyMap) lookup(ks []string) (fn func(), prefix bool) {
+	if fn, ok := km.bindings[strings.Join(ks, " ")]; ok {
+		return fn, false
+	}
+	for c := range km.bindings {
+		if isPrefix(ks, strings.Split(c, " ")) {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// HandleKey feeds key to the chords, running the function of the one it
+// completes. A key breaking a chord starts a new one. It reports whether
+// key was consumed, i.e. it completed or continued a chord.
+func (km *KeyMap) HandleKey(key string) bool {
+	key = normalizeKeyStr(key)
+	km.Lock()
+	if len(km.pending) > 0 && km.expired() {
+		km.pending = nil
+	}
+	km.last = now()
+
+	ks := append(km.pending, key)
+	fn, prefix := km.lookup(ks)
+	if fn == nil && !prefix && len(ks) > 1 {
+		ks = []string{key}
+		fn, prefix = km.lookup(ks)
+	}
+	km.pending = nil
+	if prefix {
+		km.pending = ks
+	}
+	km.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+	return fn != nil || prefix
+}
+
+// routeKeyMaps feeds the keyboard event e to the focused widget's KeyMap,
+// then to DefaultKeyMap. It reports whether one of them took the key.
+func routeKeyMaps(e Event) bool {
+	if !strings.HasPrefix(e.Path, "/sys/kbd/") {
+		return false
+	}
+	key := NormalizeKey(e)
+	if w, ok := DefaultFocus.Focused().(blocker); ok {
+		k := w.block()
+		k.RLock()
+		km := k.Keys
+		k.RUnlock()
+		if km != nil && km.HandleKey(key) {
+			return true
+		}
+	}
+	return DefaultKeyMap.HandleKey(key)
+}
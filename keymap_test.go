@@ -0,0 +1,111 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyMapChords(t *testing.T) {
+	ft := NewFakeTimeSource(time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC))
+	old := DefaultTimeSource
+	DefaultTimeSource = ft
+	defer func() { DefaultTimeSource = old }()
+
+	km := NewKeyMap()
+	got := []string{}
+	bind := func(chord string) {
+		if err := km.Bind(chord, func() { got = append(got, chord) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bind("g g")
+	bind("C-x C-c")
+	bind("q")
+
+	for _, k := range []string{"g", "g", "C-x", "C-c", "x", "q"} {
+		km.HandleKey(k)
+	}
+	if len(got) != 3 || got[0] != "g g" || got[1] != "C-x C-c" || got[2] != "q" {
+		t.Errorf("got %v", got)
+	}
+
+	// a key breaking a chord starts a new one
+	got = nil
+	km.HandleKey("g")
+	if km.Pending() != "g" {
+		t.Errorf("expected g pending, got %q", km.Pending())
+	}
+	if !km.HandleKey("q") || len(got) != 1 || got[0] != "q" {
+		t.Errorf("expected q to run, got %v", got)
+	}
+
+	// keys too far apart do not make a chord
+	got = nil
+	km.HandleKey("g")
+	ft.Advance(2 * time.Second)
+	if km.Pending() != "" {
+		t.Error("expected the chord to time out")
+	}
+	km.HandleKey("g")
+	if len(got) != 0 || km.Pending() != "g" {
+		t.Errorf("expected a fresh chord, got %v %q", got, km.Pending())
+	}
+	if km.HandleKey("z") {
+		t.Error("expected an unbound key not to be consumed")
+	}
+}
+
+func TestKeyMapConflicts(t *testing.T) {
+	km := NewKeyMap()
+	km.Bind("C-x C-c", func() {})
+	for _, c := range []string{"C-x", "C-x C-c", "C-x C-c C-d", " "} {
+		if err := km.Bind(c, func() {}); err == nil {
+			t.Errorf("expected %q to be rejected", c)
+		}
+	}
+	if err := km.Bind("C-x C-s", func() {}); err != nil {
+		t.Error(err)
+	}
+	km.Unbind("C-x  C-c")
+	if bs := km.Bindings(); len(bs) != 1 || bs[0] != "C-x C-s" {
+		t.Errorf("got %v", bs)
+	}
+}
+
+func TestKeyMapRouting(t *testing.T) {
+	l := NewList()
+	l.Keys = NewKeyMap()
+	local, global, direct := 0, 0, 0
+	l.Keys.Bind("d d", func() { local++ })
+	DefaultKeyMap.Bind("Q", func() { global++ })
+	defer DefaultKeyMap.Unbind("Q")
+	l.Handle("/sys/kbd", func(Event) { direct++ })
+	defer DefaultWgtMgr.RmWgt(l)
+
+	fm := DefaultFocus
+	DefaultFocus = NewFocusManager()
+	defer func() { DefaultFocus = fm }()
+	ti := NewTextInput()
+	DefaultFocus.Add(l, ti)
+
+	hook := DefaultWgtMgr.WgtHandlersHook()
+	key := func(k string) { hook(Event{Path: "/sys/kbd/" + k, Data: EvtKbd{KeyStr: k}}) }
+	key("Q")
+	key("d")
+	key("d")
+	if global != 1 || local != 1 || direct != 0 {
+		t.Errorf("got global=%d local=%d direct=%d", global, local, direct)
+	}
+
+	// the list's chords are off once it lost the focus
+	key(KeyTab)
+	key("d")
+	key("d")
+	if local != 1 || ti.Text != "dd" {
+		t.Errorf("expected the keys typed in the input, got local=%d %q", local, ti.Text)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "strings"
+
+// Canonical key names, as they appear after "/sys/kbd/" in event paths and
+// in EvtKbd.KeyStr. Printable keys are the character itself ("q", "+"),
+// control keys are "C-" followed by the lower case letter and Alt adds
+// "M-", e.g. "C-x", "M-x" or "C-M-x". Use them with Handle:
+/*
+  ui.Handle("/sys/kbd/"+ui.KeyEnter, func(ui.Event) {})
+*/
+const (
+	KeyEnter      = "<enter>"
+	KeyEsc        = "<escape>"
+	KeyTab        = "<tab>"
+	KeyBacktab    = "S-<tab>" // Shift-Tab, from backends that report it
+	KeySpace      = "<space>"
+	KeyBackspace  = "<backspace>"
+	KeyInsert     = "<insert>"
+	KeyDelete     = "<delete>"
+	KeyHome       = "<home>"
+	KeyEnd        = "<end>"
+	KeyPgUp       = "<previous>"
+	KeyPgDn       = "<next>"
+	KeyArrowUp    = "<up>"
+	KeyArrowDown  = "<down>"
+	KeyArrowLeft  = "<left>"
+	KeyArrowRight = "<right>"
+	KeyCtrlLeft   = "C-<left>"  // from backends that report it
+	KeyCtrlRight  = "C-<right>" // from backends that report it
+	KeyF1         = "<f1>"
+	KeyF2         = "<f2>"
+	KeyF3         = "<f3>"
+	KeyF4         = "<f4>"
+	KeyF5         = "<f5>"
+	KeyF6         = "<f6>"
+	KeyF7         = "<f7>"
+	KeyF8         = "<f8>"
+	KeyF9         = "<f9>"
+	KeyF10        = "<f10>"
+	KeyF11        = "<f11>"
+	KeyF12        = "<f12>"
+	KeyCtrlC      = "C-c"
+	KeyCtrlD      = "C-d"
+	KeyCtrlSpace  = "C-<space>"
+)
+
+// keyAliases maps names some terminals or callers produce to the canonical
+// ones. Most terminals send DEL (C-8) for backspace.
+var keyAliases = map[string]string{
+	"C-8":       KeyBackspace,
+	"<return>":  KeyEnter,
+	"<esc>":     KeyEsc,
+	"<pgup>":    KeyPgUp,
+	"<pgdown>":  KeyPgDn,
+	"<pgdn>":    KeyPgDn,
+	"<bs>":      KeyBackspace,
+	"<del>":     KeyDelete,
+	"<ins>":     KeyInsert,
+	"<backtab>": KeyBacktab,
+}
+
+func normalizeKeyStr(s string) string {
+	if k, ok := keyAliases[s]; ok {
+		return k
+	}
+	return s
+}
+
+// NormalizeKey returns the canonical name of the key of a keyboard event,
+// or "" if e is not one.
+func NormalizeKey(e Event) string {
+	if kbd, ok := e.Data.(EvtKbd); ok {
+		return normalizeKeyStr(kbd.KeyStr)
+	}
+	if strings.HasPrefix(e.Path, "/sys/kbd/") {
+		return normalizeKeyStr(strings.TrimPrefix(e.Path, "/sys/kbd/"))
+	}
+	return ""
+}
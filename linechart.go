@@ -6,37 +6,21 @@ package termui
 
 import (
 	"fmt"
+	"image"
 	"math"
 )
 
-// only 16 possible combinations, why bother
-var braillePatterns = map[[2]int]rune{
-	[2]int{0, 0}: '⣀',
-	[2]int{0, 1}: '⡠',
-	[2]int{0, 2}: '⡐',
-	[2]int{0, 3}: '⡈',
-
-	[2]int{1, 0}: '⢄',
-	[2]int{1, 1}: '⠤',
-	[2]int{1, 2}: '⠔',
-	[2]int{1, 3}: '⠌',
-
-	[2]int{2, 0}: '⢂',
-	[2]int{2, 1}: '⠢',
-	[2]int{2, 2}: '⠒',
-	[2]int{2, 3}: '⠊',
-
-	[2]int{3, 0}: '⢁',
-	[2]int{3, 1}: '⠡',
-	[2]int{3, 2}: '⠑',
-	[2]int{3, 3}: '⠉',
-}
-
+// the braille dots of the left and right columns of a cell, bottom to top
 var lSingleBraille = [4]rune{'\u2840', '⠄', '⠂', '⠁'}
 var rSingleBraille = [4]rune{'\u2880', '⠠', '⠐', '⠈'}
 
 // LineChart has two modes: braille(default) and dot. Using braille gives 2x capicity as dot mode,
 // because one braille char can represent two data points.
+// YAxis scales the values and labels their axis, see Axis.
+// Several named series can be plotted at once through Series, each in its
+// own colour and mode, a legend naming them in the top right corner. The
+// braille dots of series sharing a cell are all drawn, in the colour of the
+// last one.
 /*
   lc := termui.NewLineChart()
   lc.BorderLabel = "braille-mode Line Chart"
@@ -46,6 +30,13 @@ var rSingleBraille = [4]rune{'\u2880', '⠠', '⠐', '⠈'}
   lc.AxesColor = termui.ColorWhite
   lc.LineColor = termui.ColorGreen | termui.AttrBold
   // termui.Render(lc)...
+  lc.YAxis.Format = termui.FormatSI
+  lc.YAxis.Grid = true
+
+  lc.Series = []termui.LineSeries{
+      {Label: "rx", Data: rx, Color: termui.ColorGreen},
+      {Label: "tx", Data: tx, Color: termui.ColorRed, Mode: "dot"},
+  }
 */
 type LineChart struct {
 	Block
@@ -54,8 +45,8 @@ type LineChart struct {
 	Mode          string   // braille | dot
 	DotStyle      rune
 	LineColor     Attribute
-	scale         float64 // data span per cell on y-axis
 	AxesColor     Attribute
+	YAxis         Axis // scales and labels the values
 	drawingX      int
 	drawingY      int
 	axisYHeight   int
@@ -64,20 +55,60 @@ type LineChart struct {
 	axisXLabelGap int
 	topValue      float64
 	bottomValue   float64
+	axisMin       float64 // bounds of the y-axis, see Axis.Scale
+	axisMax       float64
 	labelX        [][]rune
-	labelY        [][]rune
+	ticksY        []Tick
 	labelYSpace   int
 	maxY          float64
 	minY          float64
+	EmptyText     string // shown centered when Data is empty
+	EmptyFg       Attribute
+	Series        []LineSeries // plotted instead of Data when set
+	ShowLegend    bool         // name the labelled series, true by default
+}
+
+// LineSeries is a named data series of a LineChart.
+type LineSeries struct {
+	Label    string
+	Data     []float64
+	Color    Attribute // ColorDefault for the chart's LineColor
+	Mode     string    // braille | dot, "" for the chart's Mode
+	DotStyle rune      // 0 for the chart's DotStyle
+}
+
+// series returns the series to plot, Data being the only one unless
+// Series is set, with the chart's defaults filled in.
+func (lc *LineChart) series() []LineSeries {
+	ss := lc.Series
+	if len(ss) == 0 {
+		ss = []LineSeries{{Data: lc.Data}}
+	}
+	out := make([]LineSeries, len(ss))
+	for i, s := range ss {
+		if s.Color == ColorDefault {
+			s.Color = lc.LineColor
+		}
+		if s.Mode == "" {
+			s.Mode = lc.Mode
+		}
+		if s.DotStyle == 0 {
+			s.DotStyle = lc.DotStyle
+		}
+		out[i] = s
+	}
+	return out
 }
 
 // NewLineChart returns a new LineChart with current theme.
 func NewLineChart() *LineChart {
 	lc := &LineChart{Block: *NewBlock()}
-	lc.AxesColor = ThemeAttr("linechart.axes.fg")
-	lc.LineColor = ThemeAttr("linechart.line.fg")
+	lc.themeAttr(&lc.AxesColor, "linechart.axes.fg")
+	lc.themeAttr(&lc.LineColor, "linechart.line.fg")
+	lc.themeAttr(&lc.YAxis.GridColor, "linechart.grid.fg")
 	lc.Mode = "braille"
 	lc.DotStyle = '•'
+	lc.ShowLegend = true
 	lc.axisXLabelGap = 2
 	lc.axisYLabelGap = 1
 	lc.bottomValue = math.Inf(1)
@@ -87,64 +118,93 @@ func NewLineChart() *LineChart {
 
 // one cell contains two data points
 // so the capicity is 2x as dot-mode
-func (lc *LineChart) renderBraille() Buffer {
-	buf := NewBuffer()
-
+// The series are all plotted in cells, the dots of braille series sharing
+// a cell being ORed, in the colour of the last one.
+func (lc *LineChart) renderBraille(s LineSeries, cells map[image.Point]Cell) {
 	// return: b -> which cell should the point be in
 	//         m -> in the cell, divided into 4 equal height levels, which subcell?
 	getPos := func(d float64) (b, m int) {
-		cnt4 := int((d-lc.bottomValue)/(lc.scale/4) + 0.5)
+		q := lc.yOffset(d)*4 + 0.5
+		if !(q >= 0) {
+			return -1, 0
+		}
+		cnt4 := int(q)
 		b = cnt4 / 4
 		m = cnt4 % 4
 		return
 	}
-	// plot points
-	for i := 0; 2*i+1 < len(lc.Data) && i < lc.axisXWidth; i++ {
-		b0, m0 := getPos(lc.Data[2*i])
-		b1, m1 := getPos(lc.Data[2*i+1])
-
-		if b0 == b1 {
-			c := Cell{
-				Ch: braillePatterns[[2]int{m0, m1}],
-				Bg: lc.Bg,
-				Fg: lc.LineColor,
-			}
-			y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b0
-			x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
-			buf.Set(x, y, c)
-		} else {
-			c0 := Cell{Ch: lSingleBraille[m0],
-				Fg: lc.LineColor,
-				Bg: lc.Bg}
-			x0 := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
-			y0 := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b0
-			buf.Set(x0, y0, c0)
-
-			c1 := Cell{Ch: rSingleBraille[m1],
-				Fg: lc.LineColor,
-				Bg: lc.Bg}
-			x1 := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
-			y1 := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b1
-			buf.Set(x1, y1, c1)
+	set := func(x, b int, dot rune) {
+		if b < 0 || b >= lc.axisYHeight {
+			return
 		}
-
+		p := image.Pt(x, lc.innerArea.Min.Y+lc.innerArea.Dy()-3-b)
+		c, ok := cells[p]
+		if !ok || c.Ch < brailleBase || c.Ch > brailleBase+0xff {
+			c.Ch = brailleBase
+		}
+		cells[p] = Cell{c.Ch | dot, s.Color, lc.Bg}
+	}
+	// plot points
+	for i := 0; 2*i+1 < len(s.Data) && i < lc.axisXWidth; i++ {
+		b0, m0 := getPos(s.Data[2*i])
+		b1, m1 := getPos(s.Data[2*i+1])
+		x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
+		set(x, b0, lSingleBraille[m0])
+		set(x, b1, rSingleBraille[m1])
 	}
-	return buf
 }
 
-func (lc *LineChart) renderDot() Buffer {
-	buf := NewBuffer()
-	for i := 0; i < len(lc.Data) && i < lc.axisXWidth; i++ {
+func (lc *LineChart) renderDot(s LineSeries, cells map[image.Point]Cell) {
+	for i := 0; i < len(s.Data) && i < lc.axisXWidth; i++ {
 		c := Cell{
-			Ch: lc.DotStyle,
-			Fg: lc.LineColor,
+			Ch: s.DotStyle,
+			Fg: s.Color,
 			Bg: lc.Bg,
 		}
+		q := lc.yOffset(s.Data[i]) + 0.5
+		if !(q >= 0) || int(q) >= lc.axisYHeight {
+			continue
+		}
+		b := int(q)
 		x := lc.innerArea.Min.X + lc.labelYSpace + 1 + i
-		y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - int((lc.Data[i]-lc.bottomValue)/lc.scale+0.5)
-		buf.Set(x, y, c)
+		y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 3 - b
+		cells[image.Pt(x, y)] = c
 	}
+}
 
+// renderLegend draws the labels of ss right aligned in the top right
+// corner, each after a mark of its series.
+func (lc *LineChart) renderLegend(ss []LineSeries) Buffer {
+	buf := NewBuffer()
+	w := 0
+	for _, s := range ss {
+		if n := strWidth(s.Label); n > w {
+			w = n
+		}
+	}
+	y := lc.innerArea.Min.Y
+	for _, s := range ss {
+		if s.Label == "" || y >= lc.innerArea.Max.Y-2 {
+			continue
+		}
+		mark := s.DotStyle
+		if s.Mode != "dot" {
+			mark = '⠒'
+		}
+		x := lc.innerArea.Max.X - w - 2
+		buf.Set(x, y, Cell{mark, s.Color, lc.Bg})
+		buf.Set(x+1, y, Cell{' ', lc.AxesColor, lc.Bg})
+		for x += 2; x < lc.innerArea.Max.X; x++ {
+			buf.Set(x, y, Cell{' ', lc.AxesColor, lc.Bg})
+		}
+		x = lc.innerArea.Max.X - w
+		for _, r := range str2runes(s.Label) {
+			c := Cell{r, lc.AxesColor, lc.Bg}
+			buf.Set(x, y, c)
+			x += c.Width()
+		}
+		y++
+	}
 	return buf
 }
 
@@ -179,65 +239,53 @@ func (lc *LineChart) calcLabelX() {
 	}
 }
 
-func shortenFloatVal(x float64) string {
-	s := fmt.Sprintf("%.2f", x)
-	if len(s)-3 > 3 {
-		s = fmt.Sprintf("%.2e", x)
-	}
-
-	if x < 0 {
-		s = fmt.Sprintf("%.2f", x)
-	}
-	return s
+// yOffset returns the rows from the bottom of the plot where v goes.
+func (lc *LineChart) yOffset(v float64) float64 {
+	return lc.YAxis.Offset(v, lc.axisMin, lc.axisMax, lc.axisYHeight-1)
 }
 
 func (lc *LineChart) calcLabelY() {
-	span := lc.topValue - lc.bottomValue
-	lc.scale = span / float64(lc.axisYHeight)
-
-	n := (1 + lc.axisYHeight) / (lc.axisYLabelGap + 1)
-	lc.labelY = make([][]rune, n)
-	maxLen := 0
-	for i := 0; i < n; i++ {
-		s := str2runes(shortenFloatVal(lc.bottomValue + float64(i)*span/float64(n)))
-		if len(s) > maxLen {
-			maxLen = len(s)
-		}
-		lc.labelY[i] = s
-	}
-
-	lc.labelYSpace = maxLen
+	lc.axisMin, lc.axisMax, lc.ticksY = lc.YAxis.Scale(lc.bottomValue, lc.topValue, lc.axisYHeight-1, lc.axisYLabelGap+1)
+	lc.labelYSpace = ticksWidth(lc.ticksY)
 }
 
-func (lc *LineChart) calcLayout() {
+func (lc *LineChart) calcLayout(ss []LineSeries) {
 	// set datalabels if it is not provided
 	if lc.DataLabels == nil || len(lc.DataLabels) == 0 {
-		lc.DataLabels = make([]string, len(lc.Data))
-		for i := range lc.Data {
+		n := 0
+		for _, s := range ss {
+			if len(s.Data) > n {
+				n = len(s.Data)
+			}
+		}
+		lc.DataLabels = make([]string, n)
+		for i := range lc.DataLabels {
 			lc.DataLabels[i] = fmt.Sprint(i)
 		}
 	}
 
 	// lazy increase, to avoid y shaking frequently
 	// update bound Y when drawing is gonna overflow
-	lc.minY = lc.Data[0]
-	lc.maxY = lc.Data[0]
-
-	// valid visible range
-	vrange := lc.innerArea.Dx()
-	if lc.Mode == "braille" {
-		vrange = 2 * lc.innerArea.Dx()
-	}
-	if vrange > len(lc.Data) {
-		vrange = len(lc.Data)
-	}
-
-	for _, v := range lc.Data[:vrange] {
-		if v > lc.maxY {
-			lc.maxY = v
+	lc.minY = math.Inf(1)
+	lc.maxY = math.Inf(-1)
+
+	for _, s := range ss {
+		// valid visible range
+		vrange := lc.innerArea.Dx()
+		if s.Mode == "braille" {
+			vrange = 2 * lc.innerArea.Dx()
 		}
-		if v < lc.minY {
-			lc.minY = v
+		if vrange > len(s.Data) {
+			vrange = len(s.Data)
+		}
+
+		for _, v := range s.Data[:vrange] {
+			if v > lc.maxY {
+				lc.maxY = v
+			}
+			if v < lc.minY {
+				lc.minY = v
+			}
 		}
 	}
 
@@ -280,29 +328,32 @@ func (lc *LineChart) plotAxes() Buffer {
 	// x label
 	oft := 0
 	for _, rs := range lc.labelX {
-		if oft+len(rs) > lc.axisXWidth {
+		w := runesWidth(rs)
+		if oft+w > lc.axisXWidth {
 			break
 		}
-		for j, r := range rs {
+		x := origX + oft
+		for _, r := range rs {
 			c := Cell{
 				Ch: r,
 				Fg: lc.AxesColor,
 				Bg: lc.Bg,
 			}
-			x := origX + oft + j
 			y := lc.innerArea.Min.Y + lc.innerArea.Dy() - 1
 			buf.Set(x, y, c)
+			x += c.Width()
 		}
-		oft += len(rs) + lc.axisXLabelGap
+		oft += w + lc.axisXLabelGap
 	}
 
 	// y labels
-	for i, rs := range lc.labelY {
-		for j, r := range rs {
-			buf.Set(
-				lc.innerArea.Min.X+j,
-				origY-i*(lc.axisYLabelGap+1),
-				Cell{Ch: r, Fg: lc.AxesColor, Bg: lc.Bg})
+	lc.drawGrid(buf, lc.YAxis, lc.ticksY, true, origX+1, origX+lc.axisXWidth, origY-1, 0)
+	for _, t := range lc.ticksY {
+		x := lc.innerArea.Min.X
+		for _, r := range str2runes(t.Label) {
+			c := Cell{Ch: r, Fg: lc.AxesColor, Bg: lc.Bg}
+			buf.Set(x, origY-1-t.Pos, c)
+			x += c.Width()
 		}
 	}
 
@@ -315,16 +366,31 @@ func (lc *LineChart) Buffer() Buffer {
 	lc.RLock()
 	defer lc.RUnlock()
 
-	if lc.Data == nil || len(lc.Data) == 0 {
+	ss := lc.series()
+	empty := true
+	for _, s := range ss {
+		empty = empty && len(s.Data) == 0
+	}
+	if empty {
+		lc.drawEmptyText(buf, lc.EmptyText, lc.EmptyFg)
 		return buf
 	}
-	lc.calcLayout()
+	lc.calcLayout(ss)
 	buf.Merge(lc.plotAxes())
 
-	if lc.Mode == "dot" {
-		buf.Merge(lc.renderDot())
-	} else {
-		buf.Merge(lc.renderBraille())
+	cells := map[image.Point]Cell{}
+	for _, s := range ss {
+		if s.Mode == "dot" {
+			lc.renderDot(s, cells)
+		} else {
+			lc.renderBraille(s, cells)
+		}
+	}
+	for p, c := range cells {
+		buf.Set(p.X, p.Y, c)
+	}
+	if lc.ShowLegend {
+		buf.Merge(lc.renderLegend(ss))
 	}
 
 	return buf
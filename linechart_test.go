@@ -0,0 +1,41 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineChartSeries(t *testing.T) {
+	lc := NewLineChart()
+	lc.Width, lc.Height = 20, 8
+	lc.Series = []LineSeries{
+		{Label: "up", Data: []float64{0, 10}, Color: ColorRed},
+		{Label: "down", Data: []float64{10, 0}, Color: ColorBlue},
+	}
+	buf := lc.Buffer()
+
+	// the low points of both series share a cell
+	x := lc.innerArea.Min.X + lc.labelYSpace + 1
+	shared := 0
+	for y := lc.innerArea.Min.Y; y < lc.innerArea.Max.Y; y++ {
+		c := buf.At(x, y)
+		bits := c.Ch - brailleBase
+		if c.Ch >= brailleBase && bits&0x47 != 0 && bits&0xb8 != 0 {
+			shared++
+			if c.Fg != ColorBlue {
+				t.Errorf("expected the shared cell in the last colour, got %v", c.Fg)
+			}
+		}
+	}
+	if shared != 2 {
+		t.Errorf("expected both series in 2 cells, got %d", shared)
+	}
+
+	if got := bufferRow(buf, lc.innerArea.Min.Y+1, lc.innerArea.Min.X, lc.innerArea.Max.X); !strings.HasSuffix(got, "⠒ down") {
+		t.Errorf("expected the legend, got %q", got)
+	}
+}
@@ -4,7 +4,10 @@
 
 package termui
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 // List displays []string as its items,
 // it has a Overflow option (default is "hidden"), when set to "hidden",
@@ -35,56 +38,472 @@ type List struct {
 	Overflow    string
 	ItemFgColor Attribute
 	ItemBgColor Attribute
+	MultiSelect bool                           // render a checkbox left of each item
+	Selectable  bool                           // highlight the selected row and move it with the keys
+	SelectedRow int                            // the current row, <space> toggles it in MultiSelect mode
+	OnSelect    func(index int)                // called after the keys moved the selected row
+	OnToggle    func(index int, selected bool) // called after an item's checkbox changes
+	EmptyText   string                         // shown centered when there are no items
+	EmptyFg     Attribute
+	ScrollTop   int // index of the first visible item
+	MaxItems    int // Append and Prepend trim the list to it when > 0
+
+	// RowStyle, when set, gives the colours of item index, selected telling
+	// if it is the current row. A zero attribute keeps the default colour.
+	RowStyle func(index int, selected bool) (fg, bg Attribute)
+
+	// OnClick, when set, is called with the index of an item clicked with
+	// the left mouse button.
+	OnClick func(index int)
+
+	// Wrap breaks the items in Overflow "wrap" mode: at the last cell that
+	// fits (WrapChar, default) or at spaces (WrapWord), WrapWordHyphenate
+	// ending the cut words with '-'.
+	Wrap              string
+	WrapWordHyphenate bool
+
+	// ItemAlign places the text of each item as AlignLeft (default),
+	// AlignCenter, AlignRight or AlignJustify, see Par.TextAlign.
+	ItemAlign Align
+	checked   map[int]bool
 }
 
 // NewList returns a new *List with current theme.
 func NewList() *List {
 	l := &List{Block: *NewBlock()}
 	l.Overflow = "hidden"
-	l.ItemFgColor = ThemeAttr("list.item.fg")
-	l.ItemBgColor = ThemeAttr("list.item.bg")
+	l.themeAttr(&l.ItemFgColor, "list.item.fg")
+	l.themeAttr(&l.ItemBgColor, "list.item.bg")
+	l.checked = make(map[int]bool)
 	return l
 }
 
+const (
+	checkboxOn  = "[x] "
+	checkboxOff = "[ ] "
+)
+
+// Toggle flips the checkbox of item i.
+func (l *List) Toggle(i int) {
+	l.Lock()
+	if i < 0 || i >= len(l.Items) {
+		l.Unlock()
+		return
+	}
+	sel := !l.checked[i]
+	if sel {
+		l.checked[i] = true
+	} else {
+		delete(l.checked, i)
+	}
+	cb := l.OnToggle
+	l.Unlock()
+
+	if cb != nil {
+		cb(i, sel)
+	}
+}
+
+// Selected returns the indices of the checked items in ascending order.
+func (l *List) Selected() []int {
+	l.RLock()
+	defer l.RUnlock()
+	idx := []int{}
+	for i := range l.checked {
+		if i < len(l.Items) {
+			idx = append(idx, i)
+		}
+	}
+	sort.Ints(idx)
+	return idx
+}
+
+// SelectAll checks every item.
+func (l *List) SelectAll() {
+	l.Lock()
+	defer l.Unlock()
+	for i := range l.Items {
+		l.checked[i] = true
+	}
+}
+
+// ClearSelection unchecks every item.
+func (l *List) ClearSelection() {
+	l.Lock()
+	defer l.Unlock()
+	l.checked = make(map[int]bool)
+}
+
+// CopyText implements Copier, returning the checked items, one per line,
+// or else the selected one, without their markup.
+func (l *List) CopyText() string {
+	idx := l.Selected()
+	l.RLock()
+	defer l.RUnlock()
+	if len(idx) == 0 && (l.Selectable || l.MultiSelect) && l.SelectedRow < len(l.Items) {
+		idx = []int{l.SelectedRow}
+	}
+	ss := make([]string, len(idx))
+	for i, n := range idx {
+		ss[i] = PlainText(l.Items[n])
+	}
+	return strings.Join(ss, "\n")
+}
+
+// shiftChecked moves the checked marks by d, dropping those falling outside
+// [0, n).
+func (l *List) shiftChecked(d, n int) {
+	m := make(map[int]bool, len(l.checked))
+	for i := range l.checked {
+		if j := i + d; j >= 0 && j < n {
+			m[j] = true
+		}
+	}
+	l.checked = m
+}
+
+// ScrollState implements Scrollable, counting items.
+func (l *List) ScrollState(vertical bool) (total, shown, offset int) {
+	l.RLock()
+	defer l.RUnlock()
+	if !vertical {
+		return 0, 0, 0
+	}
+	return len(l.Items), l.innerArea.Dy(), l.ScrollTop
+}
+
+// SetScrollOffset implements Scrollable, showing the items from offset.
+func (l *List) SetScrollOffset(vertical bool, offset int) {
+	l.Lock()
+	defer l.Unlock()
+	if vertical {
+		l.ScrollTop = clampInt(offset, 0, max0(len(l.Items)-l.innerArea.Dy()))
+	}
+}
+
+// atBottom tells if the last item is visible.
+func (l *List) atBottom() bool {
+	return l.ScrollTop+l.innerArea.Dy() >= len(l.Items)
+}
+
+// Append adds items at the end of the list. If the last item was visible
+// the list scrolls to keep following the bottom. With MaxItems set, the
+// oldest items are dropped from the top.
+func (l *List) Append(items ...string) {
+	l.Lock()
+	defer l.Unlock()
+
+	follow := l.innerArea.Dy() > 0 && l.atBottom()
+	l.Items = append(l.Items, items...)
+
+	if n := len(l.Items) - l.MaxItems; l.MaxItems > 0 && n > 0 {
+		l.Items = append(l.Items[:0:0], l.Items[n:]...)
+		l.shiftChecked(-n, len(l.Items))
+		l.SelectedRow -= n
+		l.ScrollTop -= n
+	}
+	if follow && len(l.Items) > l.innerArea.Dy() {
+		l.ScrollTop = len(l.Items) - l.innerArea.Dy()
+	}
+	l.clampRows()
+}
+
+// Prepend adds items at the top of the list, the selected row and the
+// visible items stay the same. With MaxItems set, items are dropped from
+// the bottom.
+func (l *List) Prepend(items ...string) {
+	l.Lock()
+	defer l.Unlock()
+
+	n := len(items)
+	l.Items = append(append(make([]string, 0, n+len(l.Items)), items...), l.Items...)
+	if l.MaxItems > 0 && len(l.Items) > l.MaxItems {
+		l.Items = l.Items[:l.MaxItems]
+	}
+	l.shiftChecked(n, len(l.Items))
+	l.SelectedRow += n
+	l.ScrollTop += n
+	l.clampRows()
+}
+
+func (l *List) clampRows() {
+	if l.SelectedRow >= len(l.Items) {
+		l.SelectedRow = len(l.Items) - 1
+	}
+	if l.SelectedRow < 0 {
+		l.SelectedRow = 0
+	}
+	if l.ScrollTop >= len(l.Items) {
+		l.ScrollTop = len(l.Items) - 1
+	}
+	if l.ScrollTop < 0 {
+		l.ScrollTop = 0
+	}
+}
+
+// ListState is the scroll and selection state of a List, see List.State.
+type ListState struct {
+	ScrollTop   int   `json:"scroll_top"`
+	SelectedRow int   `json:"selected_row"`
+	Checked     []int `json:"checked,omitempty"`
+}
+
+// State returns a snapshot of l's scroll position and selection, it can be
+// saved, e.g. as JSON, and given back to RestoreState.
+func (l *List) State() ListState {
+	st := ListState{
+		Checked: l.Selected(),
+	}
+	l.RLock()
+	defer l.RUnlock()
+	st.ScrollTop = l.ScrollTop
+	st.SelectedRow = l.SelectedRow
+	return st
+}
+
+// RestoreState restores a snapshot taken by State, clamped to the current
+// items.
+func (l *List) RestoreState(st ListState) {
+	l.Lock()
+	defer l.Unlock()
+	l.ScrollTop = st.ScrollTop
+	l.SelectedRow = st.SelectedRow
+	l.checked = make(map[int]bool)
+	for _, i := range st.Checked {
+		if i >= 0 && i < len(l.Items) {
+			l.checked[i] = true
+		}
+	}
+	l.clampRows()
+}
+
+// HandleKey moves the current row with <up>/<down>, <previous>/<next> by
+// a page and <home>/<end>, scrolling to keep it visible, when Selectable
+// or MultiSelect is enabled. In MultiSelect mode <space> toggles it. It
+// reports whether key was consumed.
+func (l *List) HandleKey(key string) bool {
+	if !l.MultiSelect && !l.Selectable {
+		return false
+	}
+	if key == KeySpace && l.MultiSelect {
+		l.Toggle(l.SelectedRow)
+		return true
+	}
+
+	l.Lock()
+	page := l.innerArea.Dy()
+	if page < 1 {
+		page = 1
+	}
+	row := l.SelectedRow
+	switch key {
+	case KeyArrowUp:
+		row--
+	case KeyArrowDown:
+		row++
+	case KeyPgUp:
+		row -= page
+	case KeyPgDn:
+		row += page
+	case KeyHome:
+		row = 0
+	case KeyEnd:
+		row = len(l.Items) - 1
+	default:
+		l.Unlock()
+		return false
+	}
+	if row >= len(l.Items) {
+		row = len(l.Items) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	moved := row != l.SelectedRow
+	l.SelectedRow = row
+	l.scrollToSelected()
+	cb := l.OnSelect
+	l.Unlock()
+
+	if moved && cb != nil {
+		cb(row)
+	}
+	return true
+}
+
+// scrollToSelected moves ScrollTop so the selected row is visible, each
+// item counting as one row.
+func (l *List) scrollToSelected() {
+	h := l.innerArea.Dy()
+	if h < 1 {
+		return
+	}
+	if l.SelectedRow < l.ScrollTop {
+		l.ScrollTop = l.SelectedRow
+	}
+	if l.SelectedRow >= l.ScrollTop+h {
+		l.ScrollTop = l.SelectedRow - h + 1
+	}
+}
+
+// highlighted tells if item i is drawn as the selected row.
+func (l *List) highlighted(i int) bool {
+	return l.Selectable && i == l.SelectedRow
+}
+
+// highlight reverses cs if item i is the selected row.
+func (l *List) highlight(i int, cs []Cell) []Cell {
+	if l.highlighted(i) {
+		for j := range cs {
+			cs[j].Fg |= AttrReverse
+		}
+	}
+	return cs
+}
+
+// rowStyle returns the colours of item i.
+func (l *List) rowStyle(i int) (fg, bg Attribute) {
+	fg, bg = l.ItemFgColor, l.ItemBgColor
+	if l.RowStyle == nil {
+		return
+	}
+	f, b := l.RowStyle(i, (l.MultiSelect || l.Selectable) && i == l.SelectedRow)
+	if f != 0 {
+		fg = f
+	}
+	if b != 0 {
+		bg = b
+	}
+	return
+}
+
+// checkbox returns the checkbox cells for item i, the current row is reversed.
+func (l *List) checkbox(i int) []Cell {
+	s := checkboxOff
+	if l.checked[i] {
+		s = checkboxOn
+	}
+	fg, bg := l.rowStyle(i)
+	cs := TextCells(s, fg, bg)
+	if i == l.SelectedRow {
+		for j := 0; j < len(cs)-1; j++ {
+			cs[j].Fg |= AttrReverse
+		}
+	}
+	return cs
+}
+
+// addRowHotspot makes the inner rows [y0, y1) a hotspot for item i.
+func (l *List) addRowHotspot(i, y0, y1 int) {
+	if l.OnClick == nil {
+		return
+	}
+	if y1 > l.innerArea.Dy() {
+		y1 = l.innerArea.Dy()
+	}
+	if y0 >= y1 {
+		return
+	}
+	r := l.innerArea
+	r.Min.Y, r.Max.Y = r.Min.Y+y0, r.Min.Y+y1
+	cb := l.OnClick
+	l.AddHotspot(r, func(int, int) { cb(i) })
+}
+
+// drawItemLine draws the checkbox cells box then line, placed as ItemAlign
+// says, at the inner row y. It returns the inner column after the last one
+// drawn.
+func (l *List) drawItemLine(buf Buffer, y int, box, line []Cell, end bool) int {
+	x := 0
+	for _, c := range box {
+		buf.Set(l.innerArea.Min.X+x, l.innerArea.Min.Y+y, c)
+		x += c.Width()
+	}
+	off, cs := alignLine(line, l.innerArea.Dx()-x, l.ItemAlign, end)
+	x += off
+	for _, c := range cs {
+		buf.Set(l.innerArea.Min.X+x, l.innerArea.Min.Y+y, c)
+		x += c.Width()
+	}
+	return x
+}
+
+// blankCells returns w spaces.
+func blankCells(w int, fg, bg Attribute) []Cell {
+	cs := make([]Cell, w)
+	for i := range cs {
+		cs[i] = Cell{' ', fg, bg}
+	}
+	return cs
+}
+
 // Buffer implements Bufferer interface.
 func (l *List) Buffer() Buffer {
 	buf := l.Block.Buffer()
 	l.RLock()
 	defer l.RUnlock()
+	if len(l.Items) == 0 {
+		l.drawEmptyText(buf, l.EmptyText, l.EmptyFg)
+		return buf
+	}
+
+	top := l.ScrollTop
+	if top >= len(l.Items) {
+		top = len(l.Items) - 1
+	}
+	if top < 0 {
+		top = 0
+	}
+	w := l.innerArea.Dx()
 	switch l.Overflow {
 	case "wrap":
-		cs := DefaultTxBuilder.Build(strings.Join(l.Items, "\n"), l.ItemFgColor, l.ItemBgColor)
-		i, j, k := 0, 0, 0
-		for i < l.innerArea.Dy() && k < len(cs) {
-			w := cs[k].Width()
-			if cs[k].Ch == '\n' || j+w > l.innerArea.Dx() {
-				i++
-				j = 0
-				if cs[k].Ch == '\n' {
-					k++
+		mode := l.Wrap
+		if mode == "" || mode == WrapNone {
+			mode = WrapChar
+		}
+		y, item := 0, top
+		for ; item < len(l.Items) && y < l.innerArea.Dy(); item++ {
+			fg, bg := l.rowStyle(item)
+			var box []Cell
+			if l.MultiSelect {
+				box = l.checkbox(item)
+			}
+			cs := l.highlight(item, DefaultTxBuilder.Build(l.Items[item], fg, bg))
+			ls, ends := wrapLines(cs, w-cellsWidth(box), mode, l.WrapWordHyphenate)
+			first := y
+			for k := 0; k < len(ls) && y < l.innerArea.Dy(); k++ {
+				if k == 0 {
+					l.drawItemLine(buf, y, box, ls[k], ends[k])
+				} else {
+					l.drawItemLine(buf, y, blankCells(cellsWidth(box), fg, bg), ls[k], ends[k])
 				}
-				continue
+				y++
 			}
-			buf.Set(l.innerArea.Min.X+j, l.innerArea.Min.Y+i, cs[k])
-
-			k++
-			j++
+			l.addRowHotspot(item, first, y)
 		}
+		l.drawScrollIndicators(buf, top > 0, item < len(l.Items), false, false)
 
 	case "hidden":
-		trimItems := l.Items
+		trimItems := l.Items[top:]
 		if len(trimItems) > l.innerArea.Dy() {
 			trimItems = trimItems[:l.innerArea.Dy()]
 		}
 		for i, v := range trimItems {
-			cs := DTrimTxCls(DefaultTxBuilder.Build(v, l.ItemFgColor, l.ItemBgColor), l.innerArea.Dx())
-			j := 0
-			for _, vv := range cs {
-				w := vv.Width()
-				buf.Set(l.innerArea.Min.X+j, l.innerArea.Min.Y+i, vv)
-				j += w
+			fg, bg := l.rowStyle(top + i)
+			cs := l.highlight(top+i, DefaultTxBuilder.Build(v, fg, bg))
+			var box []Cell
+			if l.MultiSelect {
+				box = fitCells(l.checkbox(top+i), w)
+			}
+			cs = DTrimTxCls(cs, w-cellsWidth(box))
+			l.addRowHotspot(top+i, i, i+1)
+			j := l.drawItemLine(buf, i, box, cs, true)
+			// the highlight spans the whole row
+			for ; l.highlighted(top+i) && j < w; j++ {
+				buf.Set(l.innerArea.Min.X+j, l.innerArea.Min.Y+i, Cell{' ', fg | AttrReverse, bg})
 			}
 		}
+		l.drawScrollIndicators(buf, top > 0, top+len(trimItems) < len(l.Items), false, false)
 	}
 	return buf
 }
@@ -0,0 +1,278 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestListMultiSelect(t *testing.T) {
+	l := NewList()
+	l.Items = []string{"a", "b", "c"}
+	l.MultiSelect = true
+	l.Width = 10
+	l.Height = 5
+
+	toggled := map[int]bool{}
+	l.OnToggle = func(i int, sel bool) {
+		toggled[i] = sel
+	}
+
+	l.HandleKey("<down>")
+	l.HandleKey("<space>")
+	l.Toggle(2)
+	if s := l.Selected(); !reflect.DeepEqual(s, []int{1, 2}) {
+		t.Errorf("expected [1 2] but got %v", s)
+	}
+	if !toggled[1] || !toggled[2] {
+		t.Errorf("OnToggle not called: %v", toggled)
+	}
+
+	buf := l.Buffer()
+	if c := buf.At(l.innerArea.Min.X+1, l.innerArea.Min.Y+1); c.Ch != 'x' {
+		t.Errorf("expected checked box on row 1 but got %q", c.Ch)
+	}
+	if c := buf.At(l.innerArea.Min.X+4, l.innerArea.Min.Y); c.Ch != 'a' {
+		t.Errorf("expected item text after checkbox but got %q", c.Ch)
+	}
+
+	l.Toggle(1)
+	if toggled[1] {
+		t.Error("OnToggle should report deselection")
+	}
+
+	l.SelectAll()
+	if s := l.Selected(); len(s) != 3 {
+		t.Errorf("SelectAll failed: %v", s)
+	}
+	l.ClearSelection()
+	if s := l.Selected(); len(s) != 0 {
+		t.Errorf("ClearSelection failed: %v", s)
+	}
+}
+
+func TestListEmptyText(t *testing.T) {
+	l := NewList()
+	l.Width = 12
+	l.Height = 5
+	l.EmptyText = "No data"
+
+	buf := l.Buffer()
+	if row := bufferRow(buf, 2, 1, 11); row != " No data  " {
+		t.Errorf("expected centered placeholder but got %q", row)
+	}
+
+	l.Items = []string{"a"}
+	if c := l.Buffer().At(2, 2); c.Ch != ' ' {
+		t.Error("placeholder should go away once items are set")
+	}
+}
+
+func TestListAppendPrepend(t *testing.T) {
+	l := NewList()
+	l.Width = 10
+	l.Height = 5 // 3 visible rows
+	l.Items = []string{"0", "1"}
+	l.Buffer()
+
+	l.Append("2", "3", "4")
+	if l.ScrollTop != 2 {
+		t.Errorf("Append at the bottom should follow it, ScrollTop is %d", l.ScrollTop)
+	}
+	if c := l.Buffer().At(1, 1); c.Ch != '2' {
+		t.Errorf("expected item 2 on the first row but got %q", c.Ch)
+	}
+
+	l.ScrollTop = 0
+	l.Append("5")
+	if l.ScrollTop != 0 {
+		t.Errorf("Append should not scroll when the bottom is not shown, ScrollTop is %d", l.ScrollTop)
+	}
+
+	l.SelectedRow = 1
+	l.Toggle(1)
+	l.Prepend("a", "b")
+	if l.SelectedRow != 3 || l.ScrollTop != 2 || !reflect.DeepEqual(l.Selected(), []int{3}) {
+		t.Errorf("Prepend should keep the view: row %d top %d selected %v",
+			l.SelectedRow, l.ScrollTop, l.Selected())
+	}
+
+	l.MaxItems = 4
+	l.Append("6")
+	if !reflect.DeepEqual(l.Items, []string{"3", "4", "5", "6"}) {
+		t.Errorf("MaxItems should drop the oldest items, got %v", l.Items)
+	}
+	if l.SelectedRow != 0 || len(l.Selected()) != 0 {
+		t.Errorf("trimmed items should leave the selection, row %d selected %v",
+			l.SelectedRow, l.Selected())
+	}
+}
+
+func TestListRowStyle(t *testing.T) {
+	l := NewList()
+	l.Width = 10
+	l.Height = 5
+	l.Items = []string{"ok", "error", "ok"}
+	l.MultiSelect = true
+	l.SelectedRow = 2
+	l.RowStyle = func(i int, selected bool) (Attribute, Attribute) {
+		if selected {
+			return 0, ColorBlue
+		}
+		if l.Items[i] == "error" {
+			return ColorRed, 0
+		}
+		return 0, 0
+	}
+
+	buf := l.Buffer()
+	if c := buf.At(5, 1); c.Fg != l.ItemFgColor || c.Bg != l.ItemBgColor {
+		t.Errorf("zero attributes should keep the defaults, got %+v", c)
+	}
+	if c := buf.At(5, 2); c.Fg != ColorRed || c.Bg != l.ItemBgColor {
+		t.Errorf("expected a red error row, got %+v", c)
+	}
+	if c := buf.At(5, 3); c.Bg != ColorBlue {
+		t.Errorf("expected the selected row styled, got %+v", c)
+	}
+}
+
+func TestListState(t *testing.T) {
+	l := NewList()
+	l.Items = []string{"a", "b", "c", "d"}
+	l.ScrollTop = 1
+	l.SelectedRow = 3
+	l.Toggle(2)
+	l.Toggle(3)
+
+	data, err := json.Marshal(l.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st ListState
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewList()
+	r.Items = []string{"a", "b", "c"}
+	r.RestoreState(st)
+	if r.ScrollTop != 1 || r.SelectedRow != 2 || !reflect.DeepEqual(r.Selected(), []int{2}) {
+		t.Errorf("expected the state clamped to 3 items, got top %d row %d checked %v",
+			r.ScrollTop, r.SelectedRow, r.Selected())
+	}
+}
+
+func TestListScrollIndicators(t *testing.T) {
+	l := NewList()
+	l.Width = 6
+	l.Height = 4
+	l.Items = []string{"a", "b", "c", "d"}
+
+	marks := func() (rune, rune) {
+		buf := l.Buffer()
+		return buf.At(4, 0).Ch, buf.At(4, 3).Ch
+	}
+	if up, down := marks(); up == ScrollIndicators.Up || down != ScrollIndicators.Down {
+		t.Errorf("at the top expected only the down mark, got %q %q", up, down)
+	}
+	l.ScrollTop = 2
+	if up, down := marks(); up != ScrollIndicators.Up || down == ScrollIndicators.Down {
+		t.Errorf("at the bottom expected only the up mark, got %q %q", up, down)
+	}
+
+	old := ScrollIndicators
+	defer func() { ScrollIndicators = old }()
+	ScrollIndicators.Up = '^'
+	ScrollIndicators.Fg = ColorRed
+	l.ScrollTop = 1
+	buf := l.Buffer()
+	if c := buf.At(4, 0); c.Ch != '^' || c.Fg != ColorRed {
+		t.Errorf("expected a custom up mark, got %+v", c)
+	}
+	if c := buf.At(4, 3); c.Ch != old.Down || c.Fg != ColorRed {
+		t.Errorf("expected the down mark, got %+v", c)
+	}
+}
+
+func TestListSelectable(t *testing.T) {
+	l := NewList()
+	l.Width = 6
+	l.Height = 5
+	l.Selectable = true
+	l.Items = []string{"a", "b", "c", "d", "e", "f", "g"}
+	l.Buffer()
+
+	picked := []int{}
+	l.OnSelect = func(i int) { picked = append(picked, i) }
+
+	if l.HandleKey(KeyArrowUp) != true || len(picked) != 0 {
+		t.Errorf("<up> at the top should be consumed without selecting, got %v", picked)
+	}
+	for i := 0; i < 3; i++ {
+		l.HandleKey(KeyArrowDown)
+	}
+	if l.SelectedRow != 3 || l.ScrollTop != 1 {
+		t.Errorf("expected row 3 scrolled to 1, got %d %d", l.SelectedRow, l.ScrollTop)
+	}
+
+	buf := l.Buffer()
+	if c := buf.At(1, 3); c.Ch != 'd' || c.Fg&AttrReverse == 0 {
+		t.Errorf("expected the selected row reversed, got %+v", c)
+	}
+	if c := buf.At(4, 3); c.Fg&AttrReverse == 0 {
+		t.Errorf("expected the highlight to span the row, got %+v", c)
+	}
+	if c := buf.At(1, 2); c.Fg&AttrReverse != 0 {
+		t.Errorf("expected other rows plain, got %+v", c)
+	}
+
+	l.HandleKey(KeyEnd)
+	if l.SelectedRow != 6 || l.ScrollTop != 4 {
+		t.Errorf("expected the last row scrolled to 4, got %d %d", l.SelectedRow, l.ScrollTop)
+	}
+	l.HandleKey(KeyPgUp)
+	if l.SelectedRow != 3 || l.ScrollTop != 3 {
+		t.Errorf("expected a page up to row 3, got %d %d", l.SelectedRow, l.ScrollTop)
+	}
+	l.HandleKey(KeyHome)
+	if l.SelectedRow != 0 || l.ScrollTop != 0 {
+		t.Errorf("expected the first row, got %d %d", l.SelectedRow, l.ScrollTop)
+	}
+	if want := []int{1, 2, 3, 6, 3, 0}; !reflect.DeepEqual(picked, want) {
+		t.Errorf("expected OnSelect calls %v, got %v", want, picked)
+	}
+	if l.HandleKey(KeySpace) {
+		t.Error("<space> should only toggle in MultiSelect mode")
+	}
+}
+
+func TestListItemAlign(t *testing.T) {
+	l := NewList()
+	l.Items = []string{"ab", "one two three"}
+	l.Width, l.Height = 10, 5
+	l.ItemAlign = AlignRight
+	buf := l.Buffer()
+	if c := buf.At(8, 1); c.Ch != 'b' {
+		t.Errorf("expected the item right aligned, got %q", c.Ch)
+	}
+
+	l.Overflow = "wrap"
+	l.Wrap = WrapWord
+	l.ItemAlign = AlignCenter
+	buf = l.Buffer()
+	row := func(y int) string {
+		s := ""
+		for x := 1; x < 9; x++ {
+			s += string(buf.At(x, y).Ch)
+		}
+		return s
+	}
+	if row(2) != "one two " || row(3) != " three  " {
+		t.Errorf("expected the item broken at spaces and centered, got %q %q", row(2), row(3))
+	}
+}
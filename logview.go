@@ -0,0 +1,610 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LogView shows the last MaxLines lines of a log, ANSI colours included,
+// following its tail as lines come unless scrolled up. <up>/<down>,
+// <previous>/<next>, <home>/<end> and the mouse wheel scroll it, <end>
+// following the tail again. "/" starts an incremental search, the matches
+// being highlighted and n/N moving to the next and previous one; Search
+// does the same from code, e.g. for a separate TextInput. "v" selects the
+// current match or the last line shown, <up>/<down> then extending the
+// selection and "y" copying it to the clipboard, see CopyToClipboard.
+/*
+  lv := termui.NewLogView()
+  lv.BorderLabel = "syslog"
+  lv.Width, lv.Height = 80, 20
+  f, _ := os.Open("/var/log/syslog")
+  go lv.ReadFrom(f)
+*/
+type LogView struct {
+	Block
+	MaxLines       int  // lines kept, the oldest ones being dropped
+	Wrap           bool // break long lines instead of cutting them
+	Follow         bool // keep the last line in view as lines are added
+	TextFg         Attribute
+	MatchFg        Attribute
+	CurrentMatchFg Attribute
+	WheelStep      int // lines scrolled by a wheel notch
+
+	ring    []string
+	start   int    // index in ring of the oldest line
+	n       int    // lines held
+	partial string // of an unterminated line given to Write
+	top     int    // first line shown when not following
+
+	query     string
+	matches   []int // lines matching query, in order
+	cur       int   // index in matches of the current one, -1 if none
+	searching bool  // the query is being typed
+
+	selecting      bool
+	selFrom, selTo int // ends of the selected lines, selTo moving with the keys
+}
+
+// NewLogView returns a new *LogView with current theme.
+func NewLogView() *LogView {
+	lv := &LogView{
+		Block:     *NewBlock(),
+		MaxLines:  1000,
+		Follow:    true,
+		WheelStep: 3,
+		cur:       -1,
+	}
+	lv.themeAttr(&lv.TextFg, "logview.fg")
+	lv.themeAttr(&lv.MatchFg, "logview.match.fg", AttrReverse)
+	lv.themeAttr(&lv.CurrentMatchFg, "logview.match.current.fg", AttrReverse)
+	lv.Handle("/sys/mouse", func(e Event) {
+		m, ok := e.Data.(EvtMouse)
+		if ok && lv.HandleMouse(m) {
+			Render(lv)
+		}
+	})
+	return lv
+}
+
+// line returns line i, 0 being the oldest held.
+func (lv *LogView) line(i int) string {
+	return lv.ring[(lv.start+i)%len(lv.ring)]
+}
+
+// Lines returns the lines held, oldest first.
+func (lv *LogView) Lines() []string {
+	lv.RLock()
+	defer lv.RUnlock()
+	ls := make([]string, lv.n)
+	for i := range ls {
+		ls[i] = lv.line(i)
+	}
+	return ls
+}
+
+// Append adds lines at the end of the log.
+func (lv *LogView) Append(lines ...string) {
+	lv.Lock()
+	defer lv.Unlock()
+	lv.appendLines(lines)
+}
+
+func (lv *LogView) appendLines(lines []string) {
+	max := lv.MaxLines
+	if max <= 0 {
+		max = 1
+	}
+	if len(lv.ring) != max {
+		// MaxLines changed, keep the latest lines in a new ring
+		ring := make([]string, max)
+		n := lv.n
+		if n > max {
+			n = max
+		}
+		for i := 0; i < n; i++ {
+			ring[i] = lv.line(lv.n - n + i)
+		}
+		lv.drop(lv.n - n)
+		lv.ring, lv.start, lv.n = ring, 0, n
+	}
+
+	for _, l := range lines {
+		if lv.n < len(lv.ring) {
+			lv.ring[(lv.start+lv.n)%len(lv.ring)] = l
+			lv.n++
+		} else {
+			lv.ring[lv.start] = l
+			lv.start = (lv.start + 1) % len(lv.ring)
+			lv.drop(1)
+		}
+		if lv.query != "" && lv.matchesIn(l) != nil {
+			lv.matches = append(lv.matches, lv.n-1)
+		}
+	}
+}
+
+// drop shifts the positions kept by k lines dropped from the top.
+func (lv *LogView) drop(k int) {
+	if k <= 0 {
+		return
+	}
+	lv.top -= k
+	if lv.top < 0 {
+		lv.top = 0
+	}
+	ms := lv.matches[:0]
+	for _, m := range lv.matches {
+		if m >= k {
+			ms = append(ms, m-k)
+		}
+	}
+	lv.cur -= len(lv.matches) - len(ms)
+	if lv.cur < 0 && len(ms) > 0 {
+		lv.cur = 0
+	}
+	lv.matches = ms
+	lv.selFrom, lv.selTo = lv.selFrom-k, lv.selTo-k
+	if lv.selFrom < 0 {
+		lv.selFrom = 0
+	}
+	if lv.selTo < 0 {
+		lv.selTo = 0
+	}
+}
+
+// Write implements io.Writer, adding the lines of p. A line is added once
+// its '\n' is written.
+func (lv *LogView) Write(p []byte) (int, error) {
+	lv.Lock()
+	defer lv.Unlock()
+	s := lv.partial + string(p)
+	ls := strings.Split(s, "\n")
+	lv.partial = ls[len(ls)-1]
+	for i := range ls[:len(ls)-1] {
+		ls[i] = strings.TrimSuffix(ls[i], "\r")
+	}
+	lv.appendLines(ls[:len(ls)-1])
+	return len(p), nil
+}
+
+// ReadFrom implements io.ReaderFrom, adding the lines read from r until
+// EOF or an error and rendering lv after each of them.
+func (lv *LogView) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		n += int64(len(sc.Bytes())) + 1
+		lv.Append(strings.TrimSuffix(sc.Text(), "\r"))
+		Render(lv)
+	}
+	return n, sc.Err()
+}
+
+// ReadChan adds the lines received from ch until it is closed, rendering
+// lv after each of them.
+func (lv *LogView) ReadChan(ch <-chan string) {
+	for l := range ch {
+		lv.Append(l)
+		Render(lv)
+	}
+}
+
+// queryRunes returns the query folded like the text, lowercased unless it
+// has capitals: a lowercase query ignores case.
+func (lv *LogView) queryRunes() (q []rune, fold bool) {
+	fold = strings.ToLower(lv.query) == lv.query
+	return str2runes(lv.query), fold
+}
+
+// matchesIn returns the start and end cells of the matches of the query in
+// line l, nil if there are none.
+func (lv *LogView) matchesIn(l string) [][2]int {
+	q, fold := lv.queryRunes()
+	if len(q) == 0 {
+		return nil
+	}
+	cs := ParseANSI(l, 0, 0)
+	eq := func(a, b rune) bool {
+		if fold {
+			return unicode.ToLower(a) == b
+		}
+		return a == b
+	}
+	var ms [][2]int
+	for i := 0; i+len(q) <= len(cs); i++ {
+		j := 0
+		for j < len(q) && eq(cs[i+j].Ch, q[j]) {
+			j++
+		}
+		if j == len(q) {
+			ms = append(ms, [2]int{i, i + j})
+			i += j - 1
+		}
+	}
+	return ms
+}
+
+// Search highlights the matches of query, ignoring case if it is all
+// lowercase, and scrolls to the first one from the top of the view, wrapping around. An
+// empty query clears the search. It reports the number of matching lines.
+func (lv *LogView) Search(query string) int {
+	lv.Lock()
+	defer lv.Unlock()
+	return lv.search(query)
+}
+
+func (lv *LogView) search(query string) int {
+	lv.query = query
+	lv.matches = nil
+	lv.cur = -1
+	if query == "" {
+		return 0
+	}
+	for i := 0; i < lv.n; i++ {
+		if lv.matchesIn(lv.line(i)) != nil {
+			lv.matches = append(lv.matches, i)
+		}
+	}
+	from := lv.viewTop()
+	for i, m := range lv.matches {
+		if m >= from {
+			lv.cur = i
+			break
+		}
+	}
+	if lv.cur < 0 && len(lv.matches) > 0 {
+		lv.cur = 0
+	}
+	lv.showMatch()
+	return len(lv.matches)
+}
+
+// NextMatch moves to the match d matches away, wrapping around.
+func (lv *LogView) NextMatch(d int) {
+	lv.Lock()
+	defer lv.Unlock()
+	if len(lv.matches) == 0 {
+		return
+	}
+	n := len(lv.matches)
+	lv.cur = ((lv.cur+d)%n + n) % n
+	lv.showMatch()
+}
+
+// showMatch scrolls the current match into view.
+func (lv *LogView) showMatch() {
+	if lv.cur < 0 {
+		return
+	}
+	m := lv.matches[lv.cur]
+	top := lv.viewTop()
+	if m >= top && m < top+lv.rows() && !lv.Wrap {
+		return
+	}
+	lv.Follow = false
+	lv.top = m - lv.rows()/2
+	lv.clampTop()
+}
+
+// showLine scrolls line i into view.
+func (lv *LogView) showLine(i int) {
+	top := lv.viewTop()
+	switch {
+	case i < top:
+		lv.top = i
+	case i >= top+lv.rows():
+		lv.top = i - lv.rows() + 1
+	default:
+		return
+	}
+	lv.Follow = false
+	lv.clampTop()
+}
+
+// lastShown returns the last line shown, ignoring wrapping.
+func (lv *LogView) lastShown() int {
+	i := lv.viewTop() + lv.rows() - 1
+	if lv.Follow || i >= lv.n {
+		i = lv.n - 1
+	}
+	return i
+}
+
+// selected tells if line i is selected.
+func (lv *LogView) selected(i int) bool {
+	from, to := lv.selFrom, lv.selTo
+	if from > to {
+		from, to = to, from
+	}
+	return lv.selecting && i >= from && i <= to
+}
+
+// CopyText implements Copier, returning the selected lines, or else the
+// line of the current match, without their escapes.
+func (lv *LogView) CopyText() string {
+	lv.RLock()
+	defer lv.RUnlock()
+	var ls []string
+	for i := 0; i < lv.n; i++ {
+		if lv.selected(i) || !lv.selecting && lv.cur >= 0 && lv.matches[lv.cur] == i {
+			ls = append(ls, CellsToStr(ParseANSI(lv.line(i), 0, 0)))
+		}
+	}
+	return strings.Join(ls, "\n")
+}
+
+// rows is the number of rows the lines take.
+func (lv *LogView) rows() int {
+	h := lv.innerArea.Dy()
+	if lv.searching {
+		h--
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// height returns the rows line l takes.
+func (lv *LogView) height(l string) int {
+	w := lv.innerArea.Dx()
+	if !lv.Wrap || w <= 0 {
+		return 1
+	}
+	n := cellsWidth(ParseANSI(l, 0, 0))
+	if n == 0 {
+		return 1
+	}
+	return (n + w - 1) / w
+}
+
+// tailTop returns the first line shown when following the tail.
+func (lv *LogView) tailTop() int {
+	h := lv.rows()
+	i := lv.n
+	for i > 0 && h >= lv.height(lv.line(i-1)) {
+		h -= lv.height(lv.line(i - 1))
+		i--
+	}
+	if i == lv.n && i > 0 {
+		// a last line higher than the view shows its start
+		i--
+	}
+	return i
+}
+
+// viewTop returns the first line shown.
+func (lv *LogView) viewTop() int {
+	if lv.Follow {
+		return lv.tailTop()
+	}
+	return lv.top
+}
+
+func (lv *LogView) clampTop() {
+	if t := lv.tailTop(); lv.top >= t {
+		lv.top = t
+		lv.Follow = true
+	}
+	if lv.top < 0 {
+		lv.top = 0
+	}
+}
+
+// ScrollState implements Scrollable, counting lines.
+func (lv *LogView) ScrollState(vertical bool) (total, shown, offset int) {
+	lv.RLock()
+	defer lv.RUnlock()
+	if !vertical {
+		return 0, 0, 0
+	}
+	return lv.n, lv.rows(), lv.viewTop()
+}
+
+// SetScrollOffset implements Scrollable, showing the lines from offset and
+// following the tail from the bottom on.
+func (lv *LogView) SetScrollOffset(vertical bool, offset int) {
+	lv.Lock()
+	defer lv.Unlock()
+	if vertical {
+		lv.top = offset
+		lv.Follow = false
+		lv.clampTop()
+	}
+}
+
+// scroll moves the view by d lines, following the tail once at the bottom.
+func (lv *LogView) scroll(d int) bool {
+	lv.Lock()
+	defer lv.Unlock()
+	top, follow := lv.viewTop(), lv.Follow
+	lv.top = top + d
+	lv.Follow = false
+	lv.clampTop()
+	return lv.viewTop() != top || lv.Follow != follow
+}
+
+// HandleMouse scrolls lv with the mouse wheel and reports whether it moved.
+func (lv *LogView) HandleMouse(m EvtMouse) bool {
+	switch m.Press {
+	case "MouseWheelUp":
+		return lv.scroll(-lv.WheelStep)
+	case "MouseWheelDown":
+		return lv.scroll(lv.WheelStep)
+	}
+	return false
+}
+
+// HandleKey scrolls lv and drives the search, see LogView. It reports
+// whether key was consumed.
+func (lv *LogView) HandleKey(key string) bool {
+	lv.Lock()
+	searching, page := lv.searching, lv.rows()
+	lv.Unlock()
+
+	if searching {
+		lv.Lock()
+		defer lv.Unlock()
+		switch key {
+		case KeyEnter:
+			lv.searching = false
+		case KeyEsc:
+			lv.searching = false
+			lv.search("")
+		case KeyBackspace:
+			if q := []rune(lv.query); len(q) > 0 {
+				lv.search(string(q[:len(q)-1]))
+			}
+		case KeySpace:
+			lv.search(lv.query + " ")
+		default:
+			if utf8.RuneCountInString(key) != 1 {
+				return false
+			}
+			lv.search(lv.query + key)
+		}
+		return true
+	}
+
+	lv.Lock()
+	selecting := lv.selecting
+	if selecting {
+		switch key {
+		case KeyArrowUp, KeyArrowDown:
+			if key == KeyArrowUp && lv.selTo > 0 {
+				lv.selTo--
+			} else if key == KeyArrowDown && lv.selTo < lv.n-1 {
+				lv.selTo++
+			}
+			lv.showLine(lv.selTo)
+			lv.Unlock()
+			return true
+		case "y", KeyEnter:
+			lv.Unlock()
+			CopyToClipboard(lv.CopyText())
+			lv.Lock()
+			lv.selecting = false
+			lv.Unlock()
+			return true
+		case "v", KeyEsc:
+			lv.selecting = false
+			lv.Unlock()
+			return true
+		}
+	} else if key == "v" && lv.n > 0 {
+		lv.selecting = true
+		lv.selTo = lv.lastShown()
+		if lv.cur >= 0 {
+			lv.selTo = lv.matches[lv.cur]
+		}
+		lv.selFrom = lv.selTo
+		lv.Unlock()
+		return true
+	}
+	lv.Unlock()
+
+	switch key {
+	case KeyArrowUp:
+		lv.scroll(-1)
+	case KeyArrowDown:
+		lv.scroll(1)
+	case KeyPgUp:
+		lv.scroll(-page)
+	case KeyPgDn:
+		lv.scroll(page)
+	case KeyHome:
+		lv.scroll(-lv.n)
+	case KeyEnd:
+		lv.scroll(lv.n)
+	case "/":
+		lv.Lock()
+		lv.searching = true
+		lv.search("")
+		lv.Unlock()
+	case "n":
+		lv.NextMatch(1)
+	case "N":
+		lv.NextMatch(-1)
+	default:
+		return false
+	}
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (lv *LogView) Buffer() Buffer {
+	buf := lv.Block.Buffer()
+	lv.RLock()
+	defer lv.RUnlock()
+
+	in := lv.innerArea
+	h, w := lv.rows(), in.Dx()
+	top := lv.viewTop()
+	cur := -1
+	if lv.cur >= 0 {
+		cur = lv.matches[lv.cur]
+	}
+
+	y, i := 0, top
+	for ; i < lv.n && y < h; i++ {
+		l := lv.line(i)
+		cs := ParseANSI(l, lv.TextFg, lv.Bg)
+		for _, m := range lv.matchesIn(l) {
+			fg := lv.MatchFg
+			if i == cur {
+				fg = lv.CurrentMatchFg
+			}
+			for k := m[0]; k < m[1]; k++ {
+				cs[k].Fg = fg
+			}
+		}
+		if !lv.Wrap && cellsWidth(cs) > w {
+			cs = DTrimTxCls(cs, w)
+		}
+		sel := lv.selected(i)
+		x := 0
+		for _, c := range cs {
+			if x+c.Width() > w {
+				x, y = 0, y+1
+				if y >= h {
+					break
+				}
+			}
+			if sel {
+				c.Fg |= AttrReverse
+			}
+			buf.Set(in.Min.X+x, in.Min.Y+y, c)
+			x += c.Width()
+		}
+		for ; sel && x < w && y < h; x++ {
+			buf.Set(in.Min.X+x, in.Min.Y+y, Cell{' ', lv.TextFg | AttrReverse, lv.Bg})
+		}
+		y++
+	}
+	lv.drawScrollIndicators(buf, top > 0, i < lv.n || y > h, false, false)
+
+	if lv.searching {
+		status := "/" + lv.query
+		if lv.query != "" {
+			status += fmt.Sprintf("  (%d/%d)", lv.cur+1, len(lv.matches))
+		}
+		cs := ParseANSI(status, lv.TextFg, lv.Bg)
+		if cellsWidth(cs) > w {
+			cs = DTrimTxCls(cs, w)
+		}
+		x := 0
+		for _, c := range cs {
+			buf.Set(in.Min.X+x, in.Max.Y-1, c)
+			x += c.Width()
+		}
+	}
+	return buf
+}
@@ -0,0 +1,129 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// logViewRows draws lv and returns its inner rows, trailing spaces
+// removed.
+func logViewRows(lv *LogView) []string {
+	rows := bufferRows(lv.Buffer(), lv.innerArea)
+	for i := range rows {
+		rows[i] = strings.TrimRight(rows[i], " ")
+	}
+	return rows
+}
+
+func TestLogViewFollow(t *testing.T) {
+	lv := NewLogView()
+	lv.Border = false
+	lv.MaxLines = 5
+	lv.Width, lv.Height = 10, 3
+	lv.Align()
+	for i := 0; i < 8; i++ {
+		fmt.Fprintf(lv, "line %d\n", i)
+	}
+	fmt.Fprint(lv, "part")
+	if ls := lv.Lines(); len(ls) != 5 || ls[0] != "line 3" || ls[4] != "line 7" {
+		t.Fatalf("expected the last 5 lines kept, got %q", ls)
+	}
+	if rows := logViewRows(lv); rows[2] != "line 7" {
+		t.Errorf("expected the tail shown, got %q", rows)
+	}
+
+	// scrolling up stops following
+	lv.HandleKey(KeyArrowUp)
+	lv.Append("line 8")
+	if lv.Follow || logViewRows(lv)[0] != "line 4" {
+		t.Errorf("expected the view to stay put, got %q", logViewRows(lv))
+	}
+	lv.HandleKey(KeyEnd)
+	if !lv.Follow || logViewRows(lv)[2] != "line 8" {
+		t.Errorf("expected <end> to follow the tail, got %q", logViewRows(lv))
+	}
+}
+
+func TestLogViewWrap(t *testing.T) {
+	lv := NewLogView()
+	lv.Border = false
+	lv.Width, lv.Height = 4, 3
+	lv.Align()
+	lv.Append("a", "bcdefg")
+	if rows := logViewRows(lv); rows[0] != "a" || rows[1] != "bcd…" {
+		t.Errorf("expected long lines cut, got %q", rows)
+	}
+	lv.Wrap = true
+	if rows := logViewRows(lv); rows[1] != "bcde" || rows[2] != "fg" {
+		t.Errorf("expected long lines wrapped, got %q", rows)
+	}
+}
+
+func TestLogViewSearch(t *testing.T) {
+	lv := NewLogView()
+	lv.Border = false
+	lv.Width, lv.Height = 10, 2
+	lv.Align()
+	lv.Append("Error one", "ok", "error two", "ok", "ok")
+
+	for _, k := range []string{"/", "e", "r", "r", KeyEnter} {
+		if !lv.HandleKey(k) {
+			t.Fatalf("expected %q consumed", k)
+		}
+	}
+	if len(lv.matches) != 2 || lv.cur != 0 {
+		t.Fatalf("expected 2 matches ignoring case, got %v", lv.matches)
+	}
+	if rows := logViewRows(lv); !strings.HasPrefix(rows[0], "Error one") {
+		t.Errorf("expected the first match shown, got %q", rows)
+	}
+	buf := lv.Buffer()
+	if buf.At(0, 0).Fg != lv.CurrentMatchFg || buf.At(3, 0).Fg != lv.TextFg {
+		t.Error("expected the current match highlighted")
+	}
+
+	lv.HandleKey("n")
+	if rows := logViewRows(lv); !strings.HasPrefix(rows[1], "error two") {
+		t.Errorf("expected n to center the second match, got %q", rows)
+	}
+	lv.HandleKey("N")
+	if lv.cur != 0 {
+		t.Errorf("expected N to go back, got %d", lv.cur)
+	}
+
+	// capitals make the search case sensitive
+	if n := lv.Search("Err"); n != 1 {
+		t.Errorf("expected a case sensitive search, got %d matches", n)
+	}
+}
+
+func TestLogViewSelection(t *testing.T) {
+	lv := NewLogView()
+	lv.Border = false
+	lv.Width, lv.Height = 10, 3
+	lv.Align()
+	lv.Append("one", "\x1b[31mtwo\x1b[0m", "three", "four")
+
+	lv.HandleKey("v")
+	lv.HandleKey(KeyArrowUp)
+	lv.HandleKey(KeyArrowUp)
+	if s := lv.CopyText(); s != "two\nthree\nfour" {
+		t.Errorf("expected the selected lines, got %q", s)
+	}
+	if buf := lv.Buffer(); buf.At(5, 0).Fg&AttrReverse == 0 || buf.At(0, 0).Ch != 't' {
+		t.Error("expected the selected rows highlighted")
+	}
+	lv.HandleKey(KeyArrowUp)
+	if rows := logViewRows(lv); rows[0] != "one" {
+		t.Errorf("expected the selection kept in view, got %q", rows)
+	}
+	lv.HandleKey(KeyEsc)
+	if s := lv.CopyText(); s != "" {
+		t.Errorf("expected nothing to copy, got %q", s)
+	}
+}
@@ -0,0 +1,628 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"strings"
+	"unicode/utf8"
+)
+
+// MenuItem is an entry of a menu: a command, a submenu when it has Items,
+// or a separator when its Label is "-". An "&" in Label marks the next
+// letter as the accelerator of the item, underlined and choosing it when
+// typed; the first letter is the accelerator otherwise.
+type MenuItem struct {
+	Label    string
+	Shortcut string // shown on the right, e.g. "C-s", it is not bound
+	Items    []MenuItem
+	Disabled bool
+	OnSelect func()
+}
+
+// EvtMenu is the data of the "/menu/select" event sent after an item of a
+// ContextMenu or a MenuBar was chosen.
+type EvtMenu struct {
+	Id    string // of the ContextMenu or MenuBar
+	Path  []int  // index of the item in every menu level, the top one first
+	Label string // without its accelerator mark
+}
+
+// menuLabel returns the text of label without its accelerator mark, the
+// index of the accelerator among the cells of the text, -1 if there is
+// none, and its key.
+func menuLabel(label string) (text string, at int, key string) {
+	i := strings.IndexByte(label, '&')
+	if i < 0 || i == len(label)-1 {
+		r, _ := utf8.DecodeRuneInString(label)
+		return label, -1, strings.ToLower(string(r))
+	}
+	text = label[:i] + label[i+1:]
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return text, len(str2runes(text[:i])), strings.ToLower(string(r))
+}
+
+func (it MenuItem) separator() bool {
+	return it.Label == "-"
+}
+
+// usable tells if it can be selected.
+func (it MenuItem) usable() bool {
+	return !it.separator() && !it.Disabled
+}
+
+// menuLevel is an open menu, r being the panel it is drawn in.
+type menuLevel struct {
+	items []MenuItem
+	sel   int
+	r     image.Rectangle
+}
+
+// menuSize returns the size of the panel of items, borders included.
+func menuSize(items []MenuItem) image.Point {
+	lw, kw, arrow := 0, 0, 0
+	for _, it := range items {
+		text, _, _ := menuLabel(it.Label)
+		if w := strWidth(text); w > lw {
+			lw = w
+		}
+		if w := strWidth(it.Shortcut); w > kw {
+			kw = w
+		}
+		if len(it.Items) > 0 {
+			arrow = 2
+		}
+	}
+	if kw > 0 {
+		kw += 2
+	}
+	return image.Pt(lw+kw+arrow+4, len(items)+2)
+}
+
+// nextUsable returns the first usable item from i on going by d, wrapping
+// around, or -1.
+func nextUsable(items []MenuItem, i, d int) int {
+	n := len(items)
+	for k := 0; k < n; k++ {
+		j := ((i+d*k)%n + n) % n
+		if items[j].usable() {
+			return j
+		}
+	}
+	return -1
+}
+
+// ContextMenu is a popup menu opened at a given position as an overlay,
+// its submenus opening next to it. The arrow keys move through it and
+// open or close submenus, <enter> or an accelerator chooses an item and
+// <escape> closes it; a click chooses an item, a click elsewhere closes
+// it. Choosing an item closes the menu, calls its OnSelect and sends a
+// "/menu/select" event. It does not listen to events on its own: feed it
+// the keys and the mouse events while it is open.
+/*
+  cm := ui.NewContextMenu(
+      ui.MenuItem{Label: "&Copy", Shortcut: "C-c"},
+      ui.MenuItem{Label: "-"},
+      ui.MenuItem{Label: "&Sort", Items: []ui.MenuItem{{Label: "By &name"}, {Label: "By &size"}}})
+  ui.Handle("/sys/mouse/right", func(e ui.Event) {
+      m := e.Data.(ui.EvtMouse)
+      cm.Open(m.X, m.Y)
+  })
+  ui.Handle("/sys/kbd", func(e ui.Event) {
+      if cm.IsOpen() && cm.HandleKey(ui.NormalizeKey(e)) {
+          ui.Render()
+      }
+  })
+*/
+type ContextMenu struct {
+	Block
+	Items      []MenuItem
+	TextFg     Attribute
+	SelectedFg Attribute
+	SelectedBg Attribute
+	DisabledFg Attribute
+	levels     []menuLevel // the open menus, Items first
+	owner      string      // Id of the events, the MenuBar's for its menus
+	prefix     []int       // Path of the events before the levels
+}
+
+// NewContextMenu returns a new closed *ContextMenu of items with current
+// theme.
+func NewContextMenu(items ...MenuItem) *ContextMenu {
+	cm := &ContextMenu{Block: *NewBlock(), Items: items}
+	// the panels draw their own borders
+	cm.Border = false
+	cm.themeAttr(&cm.TextFg, "menu.fg")
+	cm.themeAttr(&cm.SelectedFg, "menu.selected.fg")
+	cm.themeAttr(&cm.SelectedBg, "menu.selected.bg")
+	cm.themeAttr(&cm.DisabledFg, "menu.disabled.fg")
+	return cm
+}
+
+// Open shows the menu with its top left corner at terminal cell (x, y),
+// moved so it fits on the screen, on top of the overlays.
+func (cm *ContextMenu) Open(x, y int) {
+	o := viewportOrigin()
+	cm.Lock()
+	show := cm.levels == nil
+	items := cm.Items
+	cm.levels = []menuLevel{{
+		items: items,
+		sel:   nextUsable(items, 0, 1),
+		r:     fitMenu(image.Rectangle{Min: image.Pt(x, y).Sub(o), Max: image.Pt(x, y).Sub(o).Add(menuSize(items))}),
+	}}
+	cm.setBounds()
+	cm.Unlock()
+	if show {
+		PushOverlay(cm)
+	}
+}
+
+// Close hides the menu.
+func (cm *ContextMenu) Close() {
+	cm.Lock()
+	open := cm.levels != nil
+	cm.levels = nil
+	cm.Unlock()
+	if open {
+		RemoveOverlay(cm)
+	}
+}
+
+// IsOpen tells if the menu is shown.
+func (cm *ContextMenu) IsOpen() bool {
+	cm.RLock()
+	defer cm.RUnlock()
+	return cm.levels != nil
+}
+
+// fitMenu moves r within the viewport where possible.
+func fitMenu(r image.Rectangle) image.Rectangle {
+	vp := Viewport()
+	if vp.Empty() {
+		return r
+	}
+	d := image.Pt(0, 0)
+	if r.Max.X > vp.Dx() {
+		d.X = vp.Dx() - r.Max.X
+	}
+	if r.Max.Y > vp.Dy() {
+		d.Y = vp.Dy() - r.Max.Y
+	}
+	if r.Min.X+d.X < 0 {
+		d.X = -r.Min.X
+	}
+	if r.Min.Y+d.Y < 0 {
+		d.Y = -r.Min.Y
+	}
+	return r.Add(d)
+}
+
+// setBounds sizes the Block over all the open panels, so the clicks on
+// them reach the menu. The caller must hold the lock.
+func (cm *ContextMenu) setBounds() {
+	var u image.Rectangle
+	for _, l := range cm.levels {
+		u = u.Union(l.r)
+	}
+	cm.X, cm.Y, cm.Width, cm.Height = u.Min.X, u.Min.Y, u.Dx(), u.Dy()
+}
+
+// openSub opens the submenu of the selected item of the top level. The
+// caller must hold the lock.
+func (cm *ContextMenu) openSub() bool {
+	l := cm.levels[len(cm.levels)-1]
+	if l.sel < 0 || len(l.items[l.sel].Items) == 0 || !l.items[l.sel].usable() {
+		return false
+	}
+	items := l.items[l.sel].Items
+	size := menuSize(items)
+	r := image.Rectangle{Min: image.Pt(l.r.Max.X, l.r.Min.Y+l.sel), Max: image.Pt(l.r.Max.X, l.r.Min.Y+l.sel).Add(size)}
+	if vp := Viewport(); !vp.Empty() && r.Max.X > vp.Dx() {
+		r = r.Add(image.Pt(l.r.Min.X-size.X-r.Min.X, 0))
+	}
+	cm.levels = append(cm.levels, menuLevel{items: items, sel: nextUsable(items, 0, 1), r: fitMenu(r)})
+	cm.setBounds()
+	return true
+}
+
+// choose opens the submenu of item i of the top level or chooses it, then
+// returns what to do once the lock is released.
+func (cm *ContextMenu) choose(i int) func() {
+	l := &cm.levels[len(cm.levels)-1]
+	if i < 0 || i >= len(l.items) || !l.items[i].usable() {
+		return nil
+	}
+	l.sel = i
+	if cm.openSub() {
+		return func() {}
+	}
+
+	it := l.items[i]
+	text, _, _ := menuLabel(it.Label)
+	e := EvtMenu{Id: cm.owner, Path: append([]int(nil), cm.prefix...), Label: text}
+	if e.Id == "" {
+		e.Id = cm.Id()
+	}
+	for _, l := range cm.levels {
+		e.Path = append(e.Path, l.sel)
+	}
+	return func() {
+		cm.Close()
+		if it.OnSelect != nil {
+			it.OnSelect()
+		}
+		emitEvt("/menu/select", e)
+	}
+}
+
+// HandleKey moves the selection with <up> and <down>, opens a submenu with
+// <right> or <enter>, closes it with <left> or <escape>, <escape> closing
+// the menu at its top level, and chooses an item with <enter> or its
+// accelerator. It reports whether key was consumed.
+func (cm *ContextMenu) HandleKey(key string) bool {
+	cm.Lock()
+	if cm.levels == nil {
+		cm.Unlock()
+		return false
+	}
+	l := &cm.levels[len(cm.levels)-1]
+	var after func()
+	switch key {
+	case KeyArrowUp, KeyArrowDown:
+		d := 1
+		if key == KeyArrowUp {
+			d = -1
+		}
+		if j := nextUsable(l.items, l.sel+d, d); j >= 0 {
+			l.sel = j
+		}
+	case KeyArrowRight:
+		if !cm.openSub() {
+			cm.Unlock()
+			return false
+		}
+	case KeyArrowLeft, KeyEsc:
+		if len(cm.levels) > 1 {
+			cm.levels = cm.levels[:len(cm.levels)-1]
+			cm.setBounds()
+		} else if key == KeyEsc {
+			after = cm.Close
+		} else {
+			cm.Unlock()
+			return false
+		}
+	case KeyEnter, KeySpace:
+		after = cm.choose(l.sel)
+	default:
+		i := -1
+		for j, it := range l.items {
+			if _, _, k := menuLabel(it.Label); k == key && it.usable() {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			cm.Unlock()
+			return false
+		}
+		after = cm.choose(i)
+	}
+	cm.Unlock()
+	if after != nil {
+		after()
+	}
+	return true
+}
+
+// HandleMouse chooses the item under a left click and closes the menu on a
+// click outside of it. It returns true if the menu changed.
+func (cm *ContextMenu) HandleMouse(m EvtMouse) bool {
+	if m.Drag || !isButton(m.Press) {
+		return false
+	}
+	p := image.Pt(m.X, m.Y).Sub(viewportOrigin())
+	cm.Lock()
+	if cm.levels == nil {
+		cm.Unlock()
+		return false
+	}
+	after := cm.Close
+	for k := len(cm.levels) - 1; k >= 0; k-- {
+		r := cm.levels[k].r
+		if !p.In(r) {
+			continue
+		}
+		cm.levels = cm.levels[:k+1]
+		cm.setBounds()
+		after = func() {}
+		if m.Press == "MouseLeft" {
+			if f := cm.choose(p.Y - r.Min.Y - 1); f != nil {
+				after = f
+			}
+		}
+		break
+	}
+	cm.Unlock()
+	after()
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (cm *ContextMenu) Buffer() Buffer {
+	buf := cm.Block.Buffer()
+	cm.RLock()
+	defer cm.RUnlock()
+	// the Block spans the panels, only they are drawn
+	on := func(p image.Point) bool {
+		for _, l := range cm.levels {
+			if p.In(l.r) {
+				return true
+			}
+		}
+		return false
+	}
+	for p := range buf.CellMap {
+		if !on(p) {
+			delete(buf.CellMap, p)
+		}
+	}
+	for _, l := range cm.levels {
+		cm.drawLevel(buf, l)
+	}
+	return buf
+}
+
+// drawLevel draws the panel of l. The caller must hold the lock.
+func (cm *ContextMenu) drawLevel(buf Buffer, l menuLevel) {
+	r := l.r
+	fg, bg := cm.BorderFg, cm.Bg
+	buf.FillRect(r, ' ', ColorDefault, bg)
+	buf.DrawRect(r, fg, bg)
+	x0, y0, x1 := r.Min.X, r.Min.Y, r.Max.X-1
+
+	for i, it := range l.items {
+		y := y0 + 1 + i
+		if it.separator() {
+			// joins the sides into ├ and ┤
+			buf.DrawHLine(x0, y, r.Dx(), fg, bg)
+			continue
+		}
+		ifg, ibg := cm.TextFg, bg
+		switch {
+		case it.Disabled:
+			ifg = cm.DisabledFg
+		case i == l.sel:
+			ifg, ibg = cm.SelectedFg, cm.SelectedBg
+		}
+		for x := x0 + 1; x < x1; x++ {
+			buf.Set(x, y, Cell{' ', ifg, ibg})
+		}
+		text, at, _ := menuLabel(it.Label)
+		x := x0 + 2
+		for k, c := range TextCells(text, ifg, ibg) {
+			if k == at && !it.Disabled {
+				c.Fg |= AttrUnderline
+			}
+			buf.Set(x, y, c)
+			x += c.Width()
+		}
+		end := x1 - 1
+		if len(it.Items) > 0 {
+			buf.Set(end-1, y, Cell{'▸', ifg, ibg})
+			end -= 2
+		}
+		x = end - strWidth(it.Shortcut)
+		for _, c := range TextCells(it.Shortcut, ifg, ibg) {
+			buf.Set(x, y, c)
+			x += c.Width()
+		}
+	}
+}
+
+// MenuBar is a row of menus, each opening a dropdown ContextMenu under its
+// title. Alt and the accelerator of a menu, or <f10> for the first one,
+// open it; while one is open, <left> and <right> move to the neighbouring
+// menus and the other keys go to its dropdown. A click on a title opens or
+// closes its menu. The "/menu/select" events of the dropdowns carry the Id
+// of the bar and the index of the menu first in their Path. Like
+// ContextMenu, it must be fed the keys and the mouse events.
+/*
+  mb := ui.NewMenuBar(
+      ui.MenuItem{Label: "&File", Items: []ui.MenuItem{{Label: "&Open", Shortcut: "C-o"}, {Label: "&Quit"}}},
+      ui.MenuItem{Label: "&Help", Items: []ui.MenuItem{{Label: "&About"}}})
+  mb.Width = ui.TermWidth()
+  ui.Handle("/sys/kbd", func(e ui.Event) {
+      if mb.HandleKey(ui.NormalizeKey(e)) {
+          ui.Render(mb)
+      }
+  })
+*/
+type MenuBar struct {
+	Block
+	Menus    []MenuItem
+	TextFg   Attribute
+	ActiveFg Attribute
+	ActiveBg Attribute
+	active   int // index of the menu open, -1 if none
+	drop     *ContextMenu
+}
+
+// NewMenuBar returns a new *MenuBar of menus with current theme, one row
+// high.
+func NewMenuBar(menus ...MenuItem) *MenuBar {
+	mb := &MenuBar{Block: *NewBlock(), Menus: menus, active: -1}
+	mb.Border = false
+	mb.Height = 1
+	mb.drop = NewContextMenu()
+	mb.drop.owner = mb.Id()
+	mb.themeAttr(&mb.TextFg, "menubar.fg")
+	mb.themeAttr(&mb.ActiveFg, "menu.selected.fg")
+	mb.themeAttr(&mb.ActiveBg, "menu.selected.bg")
+	return mb
+}
+
+// titleXs returns the column of the title of every menu, relative to the
+// inner area, and the column past the last one.
+func (mb *MenuBar) titleXs() []int {
+	xs := make([]int, len(mb.Menus)+1)
+	for i, m := range mb.Menus {
+		text, _, _ := menuLabel(m.Label)
+		xs[i+1] = xs[i] + strWidth(text) + 2
+	}
+	return xs
+}
+
+// Open opens menu i, closing the one open.
+func (mb *MenuBar) Open(i int) {
+	mb.Align()
+	mb.Lock()
+	if i < 0 || i >= len(mb.Menus) || !mb.Menus[i].usable() {
+		mb.Unlock()
+		return
+	}
+	mb.active = i
+	p := mb.innerArea.Min.Add(image.Pt(mb.titleXs()[i], 1)).Add(viewportOrigin())
+	items := mb.Menus[i].Items
+	mb.Unlock()
+
+	mb.drop.Close()
+	mb.drop.Lock()
+	mb.drop.Items = items
+	mb.drop.prefix = []int{i}
+	mb.drop.Unlock()
+	mb.drop.Open(p.X, p.Y)
+}
+
+// Close closes the menu open, if any.
+func (mb *MenuBar) Close() {
+	mb.Lock()
+	mb.active = -1
+	mb.Unlock()
+	mb.drop.Close()
+}
+
+// Active returns the index of the menu open, -1 if none.
+func (mb *MenuBar) Active() int {
+	mb.RLock()
+	defer mb.RUnlock()
+	if !mb.drop.IsOpen() {
+		return -1
+	}
+	return mb.active
+}
+
+// step opens the usable menu next to the active one going by d.
+func (mb *MenuBar) step(d int) {
+	mb.RLock()
+	i := nextUsable(mb.Menus, mb.active+d, d)
+	mb.RUnlock()
+	mb.Open(i)
+}
+
+// HandleKey opens a menu with Alt and its accelerator or <f10>, and while
+// one is open passes key to its dropdown, <left> and <right> moving to the
+// neighbouring menus when the dropdown leaves them. It reports whether key
+// was consumed.
+func (mb *MenuBar) HandleKey(key string) bool {
+	mb.RLock()
+	menus := mb.Menus
+	mb.RUnlock()
+	if strings.HasPrefix(key, "M-") {
+		for i, m := range menus {
+			if _, _, k := menuLabel(m.Label); k == key[2:] && m.usable() {
+				mb.Open(i)
+				return true
+			}
+		}
+	}
+	if mb.Active() < 0 {
+		if key == KeyF10 {
+			mb.step(1)
+			return true
+		}
+		return false
+	}
+
+	if mb.drop.HandleKey(key) {
+		return true
+	}
+	switch key {
+	case KeyArrowLeft:
+		mb.step(-1)
+	case KeyArrowRight:
+		mb.step(1)
+	case KeyF10:
+		mb.Close()
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleMouse opens or closes the menu of a title clicked and otherwise
+// passes m to the dropdown open. It returns true if the bar or its menus
+// changed.
+func (mb *MenuBar) HandleMouse(m EvtMouse) bool {
+	if m.Drag || !isButton(m.Press) {
+		return false
+	}
+	r := mb.Bounds()
+	if image.Pt(m.X, m.Y).In(r) {
+		mb.RLock()
+		xs := mb.titleXs()
+		mb.RUnlock()
+		for i := 0; i+1 < len(xs); i++ {
+			if x := m.X - r.Min.X; x >= xs[i] && x < xs[i+1] {
+				if mb.Active() == i {
+					mb.Close()
+				} else {
+					mb.Open(i)
+				}
+				return true
+			}
+		}
+	}
+	if mb.Active() < 0 {
+		return false
+	}
+	return mb.drop.HandleMouse(m)
+}
+
+// Buffer implements Bufferer interface.
+func (mb *MenuBar) Buffer() Buffer {
+	active := mb.Active()
+	buf := mb.Block.Buffer()
+	mb.RLock()
+	defer mb.RUnlock()
+	r := mb.innerArea
+	if r.Empty() {
+		return buf
+	}
+
+	xs := mb.titleXs()
+	for i, m := range mb.Menus {
+		fg, bg := mb.TextFg, mb.Bg
+		if i == active {
+			fg, bg = mb.ActiveFg, mb.ActiveBg
+		}
+		if m.Disabled {
+			fg = mb.drop.DisabledFg
+		}
+		text, at, _ := menuLabel(m.Label)
+		x := r.Min.X + xs[i]
+		for k, c := range TextCells(" "+text+" ", fg, bg) {
+			if k == at+1 && at >= 0 && !m.Disabled {
+				c.Fg |= AttrUnderline
+			}
+			if x+c.Width() > r.Max.X {
+				break
+			}
+			buf.Set(x, r.Min.Y, c)
+			x += c.Width()
+		}
+	}
+	return buf
+}
@@ -0,0 +1,117 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestMenuLabel(t *testing.T) {
+	cases := []struct {
+		label, text string
+		at          int
+		key         string
+	}{
+		{"&Open", "Open", 0, "o"},
+		{"Save &As", "Save As", 5, "a"},
+		{"Quit", "Quit", -1, "q"},
+	}
+	for _, c := range cases {
+		text, at, key := menuLabel(c.label)
+		if text != c.text || at != c.at || key != c.key {
+			t.Errorf("%q: expected %q %d %q, got %q %d %q", c.label, c.text, c.at, c.key, text, at, key)
+		}
+	}
+}
+
+func TestContextMenu(t *testing.T) {
+	_, done := useFakeBackend(40, 20)
+	defer done()
+
+	var chosen []string
+	item := func(label string) MenuItem {
+		return MenuItem{Label: label, OnSelect: func() { chosen = append(chosen, label) }}
+	}
+	cm := NewContextMenu(
+		item("&Copy"),
+		MenuItem{Label: "-"},
+		MenuItem{Label: "&Sort", Items: []MenuItem{item("By &name"), item("By &size")}},
+		MenuItem{Label: "&Paste", Disabled: true})
+	cm.Open(5, 3)
+	if !cm.IsOpen() || len(Overlays()) != 1 {
+		t.Fatal("expected the menu open as an overlay")
+	}
+	buf := cm.Buffer()
+	if buf.At(5, 3).Ch != TOP_LEFT || buf.At(7, 4).Ch != 'C' || buf.At(7, 4).Fg&AttrUnderline == 0 {
+		t.Error("expected the panel drawn at (5,3) with the accelerator underlined")
+	}
+
+	// <down> skips the separator, <right> opens the submenu
+	cm.HandleKey(KeyArrowDown)
+	if !cm.HandleKey(KeyArrowRight) || len(cm.levels) != 2 || cm.levels[1].r.Min.Y != 5 {
+		t.Fatalf("expected the submenu open next to Sort, got %+v", cm.levels)
+	}
+	cm.HandleKey(KeyArrowDown)
+	cm.HandleKey(KeyEnter)
+	if cm.IsOpen() || len(Overlays()) != 0 || len(chosen) != 1 || chosen[0] != "By &size" {
+		t.Fatalf("expected By size chosen and the menu closed, got %v", chosen)
+	}
+
+	cm.Open(5, 3)
+	if cm.HandleKey("p") || !cm.HandleKey("c") || cm.IsOpen() || chosen[1] != "&Copy" {
+		t.Errorf("expected Copy chosen by its accelerator, got %v", chosen)
+	}
+
+	cm.Open(5, 3)
+	if !cm.HandleMouse(EvtMouse{X: 7, Y: 6, Press: "MouseLeft"}) || len(cm.levels) != 2 {
+		t.Error("expected a click on Sort to open its submenu")
+	}
+	if !cm.HandleMouse(EvtMouse{X: 30, Y: 15, Press: "MouseLeft"}) || cm.IsOpen() {
+		t.Error("expected a click outside to close the menu")
+	}
+
+	// a menu opened at the edge is moved on screen
+	cm.Open(38, 19)
+	if r := cm.levels[0].r; r.Max.X != 40 || r.Max.Y != 20 {
+		t.Errorf("expected the menu moved within the screen, got %v", r)
+	}
+	cm.Close()
+}
+
+func TestMenuBar(t *testing.T) {
+	_, done := useFakeBackend(40, 20)
+	defer done()
+
+	quit := false
+	mb := NewMenuBar(
+		MenuItem{Label: "&File", Items: []MenuItem{{Label: "&Open"}, {Label: "&Quit", OnSelect: func() { quit = true }}}},
+		MenuItem{Label: "&Edit", Items: []MenuItem{{Label: "&Undo"}}})
+	mb.Width = 40
+
+	if mb.HandleKey("q") || !mb.HandleKey("M-e") || mb.Active() != 1 {
+		t.Fatal("expected Alt-e to open Edit")
+	}
+	if buf := mb.Buffer(); buf.At(7, 0).Ch != 'E' || buf.At(7, 0).Bg != mb.ActiveBg || buf.At(1, 0).Fg&AttrUnderline == 0 {
+		t.Error("expected the Edit title active and the File accelerator underlined")
+	}
+	if r := mb.drop.levels[0].r; r.Min.X != 6 || r.Min.Y != 1 {
+		t.Errorf("expected the dropdown under Edit, got %v", r)
+	}
+
+	mb.HandleKey(KeyArrowRight)
+	if mb.Active() != 0 {
+		t.Errorf("expected <right> to wrap to File, got %d", mb.Active())
+	}
+	if !mb.HandleKey("q") || !quit || mb.Active() != -1 || len(Overlays()) != 0 {
+		t.Error("expected Quit chosen and the menu closed")
+	}
+
+	mb.HandleMouse(EvtMouse{X: 8, Y: 0, Press: "MouseLeft"})
+	if mb.Active() != 1 {
+		t.Error("expected a click on Edit to open it")
+	}
+	mb.HandleMouse(EvtMouse{X: 8, Y: 0, Press: "MouseLeft"})
+	if mb.Active() != -1 || len(Overlays()) != 0 {
+		t.Error("expected a second click to close it")
+	}
+}
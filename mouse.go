@@ -0,0 +1,101 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"sync"
+)
+
+// mouseNames are the path elements of the mouse buttons, see MousePath.
+var mouseNames = map[string]string{
+	"MouseLeft":      "left",
+	"MouseMiddle":    "middle",
+	"MouseRight":     "right",
+	"MouseRelease":   "release",
+	"MouseWheelUp":   "wheel/up",
+	"MouseWheelDown": "wheel/down",
+}
+
+// MousePath returns the event path of m: "/sys/mouse/" followed by "left",
+// "middle" or "right" for a press, "drag/left" and so on while moving with
+// a button held, "release", "wheel/up" or "wheel/down". Handlers of
+// "/sys/mouse" get them all.
+func MousePath(m EvtMouse) string {
+	n, ok := mouseNames[m.Press]
+	if !ok {
+		return "/sys/mouse"
+	}
+	if m.Drag {
+		return "/sys/mouse/drag/" + n
+	}
+	return "/sys/mouse/" + n
+}
+
+// isMouseEvt tells if e is a mouse event.
+func isMouseEvt(e Event) bool {
+	_, ok := e.Data.(EvtMouse)
+	return ok && strings.HasPrefix(e.Path, "/sys/mouse")
+}
+
+// dragTracker marks the presses following one of the same button at
+// another position, without a release in between, as a drag. Terminals
+// report a drag as such a run of presses.
+type dragTracker struct {
+	down string // button held, "" for none
+	x, y int
+}
+
+func (t *dragTracker) track(m EvtMouse) EvtMouse {
+	switch {
+	case isButton(m.Press):
+		m.Drag = t.down == m.Press && (m.X != t.x || m.Y != t.y)
+		t.down, t.x, t.y = m.Press, m.X, m.Y
+	case m.Press == "MouseRelease":
+		t.down = ""
+	}
+	return m
+}
+
+// mouseCapture is the widget that got the last press: it keeps getting
+// the mouse events, wherever they happen, until the button is released, so
+// a drag is not lost when the pointer leaves it.
+var mouseCapture struct {
+	sync.Mutex
+	id string
+}
+
+// mouseTarget returns the id of the widget a mouse event goes to: the one
+// holding the capture, else the topmost one under the pointer, see
+// WidgetAt. It is "" if there is none.
+func mouseTarget(m EvtMouse) string {
+	mouseCapture.Lock()
+	defer mouseCapture.Unlock()
+
+	// a new press starts afresh, should a release have been missed
+	id := mouseCapture.id
+	if id == "" || (isButton(m.Press) && !m.Drag) {
+		id = ""
+		if w, ok := WidgetAt(m.X, m.Y).(blocker); ok {
+			id = w.block().Id()
+		}
+	}
+	switch {
+	case isButton(m.Press):
+		mouseCapture.id = id
+	case m.Press == "MouseRelease":
+		mouseCapture.id = ""
+	}
+	return id
+}
+
+// isButton tells if press is a button being held.
+func isButton(press string) bool {
+	switch press {
+	case "MouseLeft", "MouseMiddle", "MouseRight":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestMousePath(t *testing.T) {
+	var dt dragTracker
+	cases := []struct {
+		m    EvtMouse
+		want string
+	}{
+		{EvtMouse{X: 1, Y: 1, Press: "MouseLeft"}, "/sys/mouse/left"},
+		{EvtMouse{X: 1, Y: 1, Press: "MouseLeft"}, "/sys/mouse/left"},
+		{EvtMouse{X: 2, Y: 1, Press: "MouseLeft"}, "/sys/mouse/drag/left"},
+		{EvtMouse{X: 2, Y: 1, Press: "MouseRelease"}, "/sys/mouse/release"},
+		{EvtMouse{X: 3, Y: 1, Press: "MouseLeft"}, "/sys/mouse/left"},
+		{EvtMouse{X: 3, Y: 1, Press: "MouseWheelDown"}, "/sys/mouse/wheel/down"},
+		{EvtMouse{X: 3, Y: 1}, "/sys/mouse"},
+	}
+	for i, tc := range cases {
+		if got := MousePath(dt.track(tc.m)); got != tc.want {
+			t.Errorf("%d: got %q, want %q", i, got, tc.want)
+		}
+	}
+}
+
+func TestMouseRouting(t *testing.T) {
+	_, done := useFakeBackend(20, 5)
+	defer done()
+	defer resetHits()
+	// other tests may leave a press unreleased
+	mouseCapture.id = ""
+
+	a, b := NewPar("a"), NewPar("b")
+	a.Width, a.Height = 5, 3
+	b.X, b.Width, b.Height = 5, 5, 3
+	Render(a, b)
+
+	got := []string{}
+	a.Handle("/sys/mouse", func(e Event) { got = append(got, "a "+e.Path) })
+	b.Handle("/sys/mouse", func(e Event) { got = append(got, "b "+e.Path) })
+	defer DefaultWgtMgr.RmWgt(a)
+	defer DefaultWgtMgr.RmWgt(b)
+
+	hook := DefaultWgtMgr.WgtHandlersHook()
+	var dt dragTracker
+	send := func(x, y int, press string) {
+		m := dt.track(EvtMouse{X: x, Y: y, Press: press})
+		hook(Event{Path: MousePath(m), Data: m})
+	}
+	send(7, 1, "MouseWheelUp")
+	// a drag from a over b stays with a until the release
+	send(1, 1, "MouseLeft")
+	send(7, 1, "MouseLeft")
+	send(7, 1, "MouseRelease")
+	send(7, 1, "MouseLeft")
+
+	want := []string{
+		"b /sys/mouse/wheel/up",
+		"a /sys/mouse/left",
+		"a /sys/mouse/drag/left",
+		"a /sys/mouse/release",
+		"b /sys/mouse/left",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+	send(7, 1, "MouseRelease")
+}
@@ -0,0 +1,140 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "image"
+
+// overlays is the stack of layers drawn above every frame, the last one on
+// top. It is guarded by renderLock.
+var overlays []Bufferer
+
+// under mirrors what the renders drew below the overlays, so a popped
+// overlay uncovers it without the caller rendering it again. It is in
+// widget coordinates and guarded by renderLock.
+var under = NewBuffer()
+
+// PushOverlay draws b above everything rendered so far and keeps it there,
+// over the widgets of later renders, until PopOverlay. It suits modal
+// dialogs, dropdowns and tooltips.
+/*
+  dlg := ui.NewPar("Quit? (y/n)")
+  dlg.Width, dlg.Height = 20, 3
+  dlg.Float = ui.AlignCenter
+  ui.PushOverlay(dlg)
+  // ...
+  ui.PopOverlay()
+*/
+func PushOverlay(b Bufferer) {
+	renderLock.Lock()
+	overlays = append(overlays, b)
+	if batchDepth > 0 {
+		// drawn with the batch
+		batchCount++
+		renderLock.Unlock()
+		return
+	}
+	hook := RenderHook
+	stats := drawFrame(nil, hook != nil)
+	renderLock.Unlock()
+
+	if hook != nil {
+		hook(stats)
+	}
+}
+
+// PopOverlay removes the top overlay and redraws what it covered. It
+// returns the overlay, or nil if there is none.
+func PopOverlay() Bufferer {
+	renderLock.Lock()
+	if len(overlays) == 0 {
+		renderLock.Unlock()
+		return nil
+	}
+	b := overlays[len(overlays)-1]
+	dropOverlay(len(overlays) - 1)
+	return b
+}
+
+// RemoveOverlay takes b off the overlay stack wherever it is and redraws
+// what it covered. It reports whether b was an overlay.
+func RemoveOverlay(b Bufferer) bool {
+	renderLock.Lock()
+	for i := len(overlays) - 1; i >= 0; i-- {
+		if overlays[i] == b {
+			dropOverlay(i)
+			return true
+		}
+	}
+	renderLock.Unlock()
+	return false
+}
+
+// dropOverlay removes overlay i and redraws what it covered. The caller
+// must hold renderLock, which dropOverlay releases.
+func dropOverlay(i int) {
+	b := overlays[i]
+	overlays = append(overlays[:i:i], overlays[i+1:]...)
+	if blk, ok := b.(blocker); ok {
+		unregisterHit(blk.block())
+	}
+	restorePending = true
+	if batchDepth > 0 {
+		batchCount++
+		renderLock.Unlock()
+		return
+	}
+	hook := RenderHook
+	stats := drawFrame(nil, hook != nil)
+	renderLock.Unlock()
+
+	if hook != nil {
+		hook(stats)
+	}
+}
+
+// Overlays returns the overlay stack, bottom first.
+func Overlays() []Bufferer {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	return append([]Bufferer(nil), overlays...)
+}
+
+// restorePending asks the next frame to blank what neither the renders nor
+// the remaining overlays draw, i.e. where a popped overlay was.
+var restorePending bool
+
+// updateUnder records base, drawn by a render, below the overlays. The
+// cells of stale it does not draw are forgotten, as is everything else
+// with wipe.
+func updateUnder(base Buffer, stale []image.Rectangle, wipe bool) {
+	if wipe {
+		under = NewBuffer()
+	}
+	for _, r := range stale {
+		for p := range under.CellMap {
+			if _, ok := base.CellMap[p]; p.In(r) && !ok {
+				delete(under.CellMap, p)
+			}
+		}
+	}
+	for p, c := range base.CellMap {
+		under.CellMap[p] = c
+	}
+	under.SetArea(under.Area.Union(base.Area))
+}
+
+// overlayFrame returns what is under the overlays, base included, with the
+// overlays on top in stack order, or base alone when there is no overlay
+// to draw or uncover. The caller must hold renderLock.
+func overlayFrame(base Buffer) Buffer {
+	if len(overlays) == 0 && !restorePending {
+		return base
+	}
+	bufs := []Buffer{under}
+	for _, b := range overlays {
+		bufs = append(bufs, bufferOf(b))
+	}
+	return compose(bufs...)
+}
@@ -0,0 +1,63 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	fb, done := useFakeBackend(20, 6)
+	defer done()
+	old := under
+	under = NewBuffer()
+	defer func() { under = old }()
+	defer resetHits()
+
+	p := NewPar("abcdefgh")
+	p.Width, p.Height = 10, 4
+	Render(p)
+
+	dlg := NewPar("ok")
+	dlg.X, dlg.Y = 6, 1
+	dlg.Width, dlg.Height = 8, 3
+	PushOverlay(dlg)
+	if c := fb.cells[image.Pt(7, 2)]; c.Ch != 'o' {
+		t.Fatalf("expected the overlay drawn, got %q", c.Ch)
+	}
+	if n := len(Overlays()); n != 1 {
+		t.Errorf("expected one overlay, got %d", n)
+	}
+
+	// a render below the overlay does not draw over it
+	p.Text = "ABCDEFGH"
+	Render(p)
+	if c := fb.cells[image.Pt(7, 2)]; c.Ch != 'o' {
+		t.Errorf("expected the overlay kept on top, got %q", c.Ch)
+	}
+	if c := fb.cells[image.Pt(1, 1)]; c.Ch != 'A' {
+		t.Errorf("expected the render drawn below, got %q", c.Ch)
+	}
+	if WidgetAt(7, 2) != dlg {
+		t.Error("expected the overlay to be hit first")
+	}
+
+	if PopOverlay() != dlg {
+		t.Error("expected the popped overlay back")
+	}
+	if c := fb.cells[image.Pt(7, 1)]; c.Ch != 'G' {
+		t.Errorf("expected the covered text restored, got %q", c.Ch)
+	}
+	if c := fb.cells[image.Pt(12, 2)]; c.Ch != ' ' {
+		t.Errorf("expected the uncovered cells blanked, got %q", c.Ch)
+	}
+	if w := WidgetAt(7, 2); w == dlg {
+		t.Error("expected the popped overlay not to be hit")
+	}
+	if PopOverlay() != nil {
+		t.Error("expected an empty stack")
+	}
+}
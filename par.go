@@ -4,6 +4,13 @@
 
 package termui
 
+// Overflow behaviors of Par when the text is taller than the widget.
+const (
+	OverflowClip     = "clip"     // lines below the widget are not shown
+	OverflowEllipsis = "ellipsis" // the last visible line ends with …
+	OverflowScroll   = "scroll"   // lines from ScrollTop are shown, with a scrollbar
+)
+
 // Par displays a paragraph.
 /*
   par := termui.NewPar("Simple Text")
@@ -16,28 +23,126 @@ type Par struct {
 	Text        string
 	TextFgColor Attribute
 	TextBgColor Attribute
-	WrapLength  int // words wrap limit. Note it may not work properly with multi-width char
+	WrapLength  int    // words wrap limit. Note it may not work properly with multi-width char
+	Overflow    string // OverflowEllipsis (default), OverflowClip or OverflowScroll
+	ScrollTop   int    // first visible line in OverflowScroll mode
+
+	// WrapWordHyphenate ends a line with '-' when a word wider than the
+	// line has to be cut, it only applies with word wrap (WrapLength != 0
+	// or Wrap WrapWord).
+	WrapWordHyphenate bool
+
+	// Wrap, when set, replaces the breaking of WrapLength: WrapWord breaks
+	// the lines at spaces, WrapChar at the last cell that fits and WrapNone
+	// cuts them with …, at WrapLength if > 0 or the inner width.
+	Wrap string
+
+	// TextAlign places each line as AlignLeft (default), AlignCenter,
+	// AlignRight or AlignJustify, which fills the lines broken by Wrap.
+	TextAlign Align
+
+	// ANSI reads Text as terminal output coloured with ANSI escape
+	// sequences instead of markup, see ParseANSI.
+	ANSI bool
 }
 
 // NewPar returns a new *Par with given text as its content.
 func NewPar(s string) *Par {
-	return &Par{
-		Block:       *NewBlock(),
-		Text:        s,
-		TextFgColor: ThemeAttr("par.text.fg"),
-		TextBgColor: ThemeAttr("par.text.bg"),
-		WrapLength:  0,
+	p := &Par{
+		Block:      *NewBlock(),
+		Text:       s,
+		WrapLength: 0,
+		Overflow:   OverflowEllipsis,
 	}
+	p.themeAttr(&p.TextFgColor, "par.text.fg")
+	p.themeAttr(&p.TextBgColor, "par.text.bg")
+	return p
 }
 
-// Buffer implements Bufferer interface.
-func (p *Par) Buffer() Buffer {
-	buf := p.Block.Buffer()
-	p.RLock()
-	defer p.RUnlock()
+// breakLines splits cs at '\n' and wherever a line would get wider than w.
+// With hyphenate, words wider than w are cut with a trailing '-'.
+func breakLines(cs []Cell, w int, hyphenate bool) [][]Cell {
+	lines := [][]Cell{}
+	if w <= 0 {
+		return lines
+	}
+
+	line := []Cell{}
+	x := 0
+	for n := 0; n < len(cs); {
+		cw := cs[n].Width()
+		if cs[n].Ch == '\n' || (x+cw > w && x > 0) {
+			if hyphenate && w > 1 && cs[n].Ch != '\n' && wordWidth(cs, n-len(line)+wordStart(line)) > w {
+				// give back cells until the hyphen fits, keeping one at least
+				for len(line) > 1 && x+1 > w && !isSpace(line[len(line)-1]) {
+					x -= line[len(line)-1].Width()
+					line = line[:len(line)-1]
+					n--
+				}
+				last := line[len(line)-1]
+				if x+1 <= w && !isSpace(last) && !isSpace(cs[n]) {
+					line = append(line, Cell{Ch: '-', Fg: last.Fg, Bg: last.Bg})
+				}
+			}
+			lines = append(lines, line)
+			line = []Cell{}
+			x = 0
+			if cs[n].Ch == '\n' {
+				n++
+			}
+			continue
+		}
+		line = append(line, cs[n])
+		x += cw
+		n++
+	}
+	return append(lines, line)
+}
+
+func isSpace(c Cell) bool {
+	return c.Ch == ' ' || c.Ch == '\n'
+}
+
+// wordStart returns the index in line where its last word begins.
+func wordStart(line []Cell) int {
+	i := len(line)
+	for i > 0 && !isSpace(line[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordWidth returns the display width of the word starting at cs[i].
+func wordWidth(cs []Cell, i int) int {
+	w := 0
+	for ; i < len(cs) && !isSpace(cs[i]); i++ {
+		w += cs[i].Width()
+	}
+	return w
+}
+
+// lines returns the text broken into display lines of width w.
+func (p *Par) lines(w int) [][]Cell {
+	ls, _ := p.layout(w)
+	return ls
+}
+
+// layout returns the text broken into display lines of width w and, for
+// each one, whether it ends a paragraph.
+func (p *Par) layout(w int) ([][]Cell, []bool) {
+	var cs []Cell
+	if p.ANSI {
+		cs = ParseANSI(p.Text, p.TextFgColor, p.TextBgColor)
+	} else {
+		cs = DefaultTxBuilder.Build(p.Text, p.TextFgColor, p.TextBgColor)
+	}
 
-	fg, bg := p.TextFgColor, p.TextBgColor
-	cs := DefaultTxBuilder.Build(p.Text, fg, bg)
+	if p.Wrap != "" {
+		if p.WrapLength > 0 && p.WrapLength < w {
+			w = p.WrapLength
+		}
+		return wrapLines(cs, w, p.Wrap, p.WrapWordHyphenate)
+	}
 
 	// wrap if WrapLength set
 	if p.WrapLength < 0 {
@@ -45,31 +150,138 @@ func (p *Par) Buffer() Buffer {
 	} else if p.WrapLength > 0 {
 		cs = wrapTx(cs, p.WrapLength)
 	}
+	ls := breakLines(cs, w, p.WrapWordHyphenate && p.WrapLength != 0)
+	ends := make([]bool, len(ls))
+	for i := range ends {
+		ends[i] = true
+	}
+	return ls, ends
+}
 
-	y, x, n := 0, 0, 0
-	for y < p.innerArea.Dy() && n < len(cs) {
-		w := cs[n].Width()
-		if cs[n].Ch == '\n' || x+w > p.innerArea.Dx() {
-			y++
-			x = 0 // set x = 0
-			if cs[n].Ch == '\n' {
-				n++
-			}
+// WrappedLines returns the text as it is broken into display lines for an
+// inner width of width cells, following WrapLength and WrapWordHyphenate.
+func (p *Par) WrappedLines(width int) []string {
+	p.RLock()
+	defer p.RUnlock()
+	ls := p.lines(width)
+	ss := make([]string, len(ls))
+	for i, l := range ls {
+		ss[i] = CellsToStr(l)
+	}
+	return ss
+}
+
+// LineCount returns the number of display lines for an inner width of width
+// cells, see WrappedLines.
+func (p *Par) LineCount(width int) int {
+	p.RLock()
+	defer p.RUnlock()
+	return len(p.lines(width))
+}
+
+// CopyText implements Copier, returning Text without its markup.
+func (p *Par) CopyText() string {
+	p.RLock()
+	defer p.RUnlock()
+	return PlainText(p.Text)
+}
+
+// scrollLines returns the display lines in OverflowScroll mode, see layout,
+// and whether they need a scrollbar, which takes the rightmost inner column.
+func (p *Par) scrollLines() ([][]Cell, []bool, bool) {
+	ls, ends := p.layout(p.innerArea.Dx())
+	if len(ls) <= p.innerArea.Dy() {
+		return ls, ends, false
+	}
+	ls, ends = p.layout(p.innerArea.Dx() - 1)
+	return ls, ends, true
+}
 
-			if y >= p.innerArea.Dy() {
-				buf.Set(p.innerArea.Min.X+p.innerArea.Dx()-1,
-					p.innerArea.Min.Y+p.innerArea.Dy()-1,
-					Cell{Ch: '…', Fg: p.TextFgColor, Bg: p.TextBgColor})
-				break
+func (p *Par) maxScrollTop() int {
+	ls, _, _ := p.scrollLines()
+	if n := len(ls) - p.innerArea.Dy(); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// ScrollUp scrolls the text up by one line in OverflowScroll mode.
+func (p *Par) ScrollUp() {
+	p.Lock()
+	defer p.Unlock()
+	if p.ScrollTop > 0 {
+		p.ScrollTop--
+	}
+}
+
+// ScrollDown scrolls the text down by one line in OverflowScroll mode.
+func (p *Par) ScrollDown() {
+	p.Align()
+	p.Lock()
+	defer p.Unlock()
+	if p.ScrollTop < p.maxScrollTop() {
+		p.ScrollTop++
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (p *Par) Buffer() Buffer {
+	buf := p.Block.Buffer()
+	p.RLock()
+	defer p.RUnlock()
+
+	h, w := p.innerArea.Dy(), p.innerArea.Dx()
+	var ls [][]Cell
+	var ends []bool
+	var up, down bool // lines hidden above and below
+	switch p.Overflow {
+	case OverflowScroll:
+		var bar bool
+		ls, ends, bar = p.scrollLines()
+		top := p.ScrollTop
+		if max := len(ls) - h; top > max {
+			top = max
+		}
+		if top < 0 {
+			top = 0
+		}
+		if bar {
+			drawScrollbar(buf, p.innerArea.Max.X-1, p.innerArea.Min.Y, h,
+				len(ls), top, p.TextFgColor, p.TextBgColor)
+			w--
+		}
+		up, down = top > 0, top+h < len(ls)
+		if bar {
+			// without the border the marks would cover the scrollbar
+			up = up && p.Border && p.BorderTop
+			down = down && p.Border && p.BorderBottom
+		}
+		ls, ends = ls[top:], ends[top:]
+
+	case OverflowEllipsis:
+		ls, ends = p.layout(w)
+		if len(ls) > h && h > 0 {
+			last := ls[h-1]
+			for len(last) > 0 && cellsWidth(last)+charWidth('…') > w {
+				last = last[:len(last)-1]
 			}
-			continue
+			ls[h-1] = append(last, Cell{Ch: '…', Fg: p.TextFgColor, Bg: p.TextBgColor})
+			ends[h-1] = true
 		}
 
-		buf.Set(p.innerArea.Min.X+x, p.innerArea.Min.Y+y, cs[n])
+	default:
+		ls, ends = p.layout(w)
+		down = len(ls) > h
+	}
 
-		n++
-		x += w
+	for y := 0; y < h && y < len(ls); y++ {
+		x, line := alignLine(ls[y], w, p.TextAlign, ends[y])
+		for _, c := range line {
+			buf.Set(p.innerArea.Min.X+x, p.innerArea.Min.Y+y, c)
+			x += c.Width()
+		}
 	}
+	p.drawScrollIndicators(buf, up, down, false, false)
 
 	return buf
 }
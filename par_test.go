@@ -4,7 +4,10 @@
 
 package termui
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestPar_NoBorderBackground(t *testing.T) {
 	par := NewPar("a")
@@ -22,3 +25,165 @@ func TestPar_NoBorderBackground(t *testing.T) {
 		}
 	}
 }
+
+func TestPar_OverflowEllipsis(t *testing.T) {
+	par := NewPar("abc\nde你好\nfgh")
+	par.Border = false
+	par.Width = 5
+	par.Height = 2
+
+	buf := par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 1, 0, 5), " "); s != "de你…" {
+		t.Errorf("expected wide-rune aware ellipsis but got %q", s)
+	}
+
+	// without the border the mark of the hidden line takes the last cell
+	par.Overflow = OverflowClip
+	buf = par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 1, 0, 5), " "); s != "de你"+string(ScrollIndicators.Down) {
+		t.Errorf("expected clipped line but got %q", s)
+	}
+}
+
+func TestPar_OverflowScroll(t *testing.T) {
+	par := NewPar("0\n1\n2\n3\n4")
+	par.Border = false
+	par.Overflow = OverflowScroll
+	par.Width = 3
+	par.Height = 2
+
+	for i := 0; i < 10; i++ {
+		par.ScrollDown()
+	}
+	if par.ScrollTop != 3 {
+		t.Errorf("ScrollTop should stop at 3 but is %d", par.ScrollTop)
+	}
+	par.ScrollUp()
+
+	buf := par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 0, 0, 1), " "); s != "2" {
+		t.Errorf("expected line 2 on top but got %q", s)
+	}
+	if c := buf.At(2, 0); c.Ch != '░' && c.Ch != '█' {
+		t.Errorf("expected a scrollbar in the last column but got %q", c.Ch)
+	}
+}
+
+func TestPar_WrapWordHyphenate(t *testing.T) {
+	par := NewPar("see abcdefghij ok")
+	par.Border = false
+	par.Width = 6
+	par.Height = 4
+	par.WrapLength = 6
+	par.WrapWordHyphenate = true
+
+	buf := par.Buffer()
+	want := []string{"see", "abcde-", "fghij", "ok"}
+	for y, w := range want {
+		if s := strings.TrimRight(bufferRow(buf, y, 0, 6), " "); s != w {
+			t.Errorf("line %d: expected %q but got %q", y, w, s)
+		}
+	}
+
+	par.Text = "你好你好"
+	par.Width = 5
+	par.WrapLength = 5
+	buf = par.Buffer()
+	want = []string{"你好-", "你好"}
+	for y, w := range want {
+		if s := strings.TrimRight(bufferRow(buf, y, 0, 5), " "); s != w {
+			t.Errorf("wide line %d: expected %q but got %q", y, w, s)
+		}
+	}
+}
+
+func TestPar_WrappedLines(t *testing.T) {
+	par := NewPar("ab [cd](fg-red)\n你好你")
+	ls := par.WrappedLines(4)
+	want := []string{"ab c", "d", "你好", "你"}
+	if strings.Join(ls, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q but got %q", want, ls)
+	}
+	if n := par.LineCount(4); n != len(want) {
+		t.Errorf("expected %d lines but got %d", len(want), n)
+	}
+	if n := par.LineCount(0); n != 0 {
+		t.Errorf("expected no line for zero width, got %d", n)
+	}
+}
+
+func TestPar_TextAlign(t *testing.T) {
+	par := NewPar("aa bb cc dddddddddd\nend")
+	par.Border = false
+	par.Width = 8
+	par.Height = 4
+	par.Wrap = WrapWord
+	par.TextAlign = AlignJustify
+
+	buf := par.Buffer()
+	want := []string{"aa bb cc", "dddddddd", "dd", "end"}
+	for y, w := range want {
+		if s := strings.TrimRight(bufferRow(buf, y, 0, 8), " "); s != w {
+			t.Errorf("justify line %d: expected %q but got %q", y, w, s)
+		}
+	}
+
+	par.Text = "aa bb ccc"
+	buf = par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 0, 0, 8), " "); s != "aa    bb" {
+		t.Errorf("expected the gap widened but got %q", s)
+	}
+
+	par.TextAlign = AlignRight
+	buf = par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 0, 0, 8), " "); s != "   aa bb" || strings.TrimRight(bufferRow(buf, 1, 0, 8), " ") != "     ccc" {
+		t.Errorf("expected right aligned lines but got %q", s)
+	}
+	par.TextAlign = AlignCenter
+	buf = par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 1, 0, 8), " "); s != "  ccc" {
+		t.Errorf("expected a centered line but got %q", s)
+	}
+
+	par.Wrap = WrapNone
+	par.TextAlign = AlignLeft
+	par.Text = "abcdefghijk"
+	buf = par.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 0, 0, 8), " "); s != "abcdefg…" || strings.TrimRight(bufferRow(buf, 1, 0, 8), " ") != "" {
+		t.Errorf("expected the line cut with an ellipsis but got %q", s)
+	}
+}
+
+func TestPar_ScrollIndicators(t *testing.T) {
+	par := NewPar("0\n1\n2\n3")
+	par.Width = 5
+	par.Height = 4
+	marks := func() (rune, rune) {
+		buf := par.Buffer()
+		return buf.At(3, 0).Ch, buf.At(3, 3).Ch
+	}
+
+	// clipped lines are only ever below
+	par.Overflow = OverflowClip
+	if up, down := marks(); up == ScrollIndicators.Up || down != ScrollIndicators.Down {
+		t.Errorf("clip: expected only the down mark, got %q %q", up, down)
+	}
+
+	par.Overflow = OverflowScroll
+	if up, down := marks(); up == ScrollIndicators.Up || down != ScrollIndicators.Down {
+		t.Errorf("at the top expected only the down mark, got %q %q", up, down)
+	}
+	par.ScrollTop = 1
+	if up, down := marks(); up != ScrollIndicators.Up || down != ScrollIndicators.Down {
+		t.Errorf("in the middle expected both marks, got %q %q", up, down)
+	}
+	par.ScrollTop = 2
+	if up, down := marks(); up != ScrollIndicators.Up || down == ScrollIndicators.Down {
+		t.Errorf("at the bottom expected only the up mark, got %q %q", up, down)
+	}
+
+	par.Text = "0\n1"
+	if up, down := marks(); up == ScrollIndicators.Up || down == ScrollIndicators.Down {
+		t.Errorf("expected no marks when all lines fit, got %q %q", up, down)
+	}
+}
@@ -4,7 +4,10 @@
 
 package termui
 
-import "image"
+import (
+	"image"
+	"sync"
+)
 
 // Align is the position of the gauge's label.
 type Align uint
@@ -18,7 +21,8 @@ const (
 	AlignTop
 	AlignCenterVertical
 	AlignCenterHorizontal
-	AlignCenter = AlignCenterVertical | AlignCenterHorizontal
+	AlignJustify // text lines widened to the full width, see Par.TextAlign
+	AlignCenter  = AlignCenterVertical | AlignCenterHorizontal
 )
 
 func AlignArea(parent, child image.Rectangle, a Align) image.Rectangle {
@@ -73,6 +77,117 @@ func MoveArea(a image.Rectangle, dx, dy int) image.Rectangle {
 var termWidth int
 var termHeight int
 
+// TermRect returns the area widgets are laid out in: the terminal, or the
+// viewport set by SetViewport, with its top left corner at (0,0).
 func TermRect() image.Rectangle {
-	return image.Rect(0, 0, termWidth, termHeight)
+	vp := viewportRect()
+	return image.Rect(0, 0, vp.Dx(), vp.Dy())
+}
+
+// HAlign is a horizontal alignment used by AlignTo and AlignToArea.
+type HAlign int
+
+// All supported horizontal alignments.
+const (
+	HAlignLeft HAlign = iota
+	HAlignCenter
+	HAlignRight
+)
+
+// VAlign is a vertical alignment used by AlignTo and AlignToArea.
+type VAlign int
+
+// All supported vertical alignments.
+const (
+	VAlignTop VAlign = iota
+	VAlignMiddle
+	VAlignBottom
+)
+
+type anchor struct {
+	h HAlign
+	v VAlign
+}
+
+// widgets aligned to the terminal, re-aligned on resize
+var anchors = struct {
+	sync.Mutex
+	m map[*Block]anchor
+}{m: make(map[*Block]anchor)}
+
+func alignBlock(b *Block, r image.Rectangle, h HAlign, v VAlign) {
+	b.Lock()
+	defer b.Unlock()
+
+	switch h {
+	case HAlignLeft:
+		b.X = r.Min.X
+	case HAlignCenter:
+		b.X = r.Min.X + (r.Dx()-b.Width)/2
+	case HAlignRight:
+		b.X = r.Max.X - b.Width
+	}
+
+	switch v {
+	case VAlignTop:
+		b.Y = r.Min.Y
+	case VAlignMiddle:
+		b.Y = r.Min.Y + (r.Dy()-b.Height)/2
+	case VAlignBottom:
+		b.Y = r.Max.Y - b.Height
+	}
+}
+
+// AlignToArea sets the X and Y of widget b, which must embed a Block, so
+// that it is aligned within r according to its Width and Height.
+func AlignToArea(b Bufferer, r image.Rectangle, h HAlign, v VAlign) {
+	blk, ok := b.(blocker)
+	if !ok {
+		return
+	}
+	Unanchor(b)
+	alignBlock(blk.block(), r, h, v)
+}
+
+// AlignTo aligns widget b, which must embed a Block, within the terminal
+// and keeps it aligned when the terminal is resized, until Unanchor or
+// AlignToArea is called for it.
+func AlignTo(b Bufferer, h HAlign, v VAlign) {
+	blk, ok := b.(blocker)
+	if !ok {
+		return
+	}
+	anchors.Lock()
+	anchors.m[blk.block()] = anchor{h, v}
+	anchors.Unlock()
+
+	alignBlock(blk.block(), TermRect(), h, v)
+}
+
+// Center centers widget b on the terminal, see AlignTo.
+func Center(b Bufferer) {
+	AlignTo(b, HAlignCenter, VAlignMiddle)
+}
+
+// Unanchor stops re-aligning b, aligned by AlignTo or Center, when the
+// terminal is resized; it stays where it is. Call it before dropping a
+// widget that was aligned, so it can be collected.
+func Unanchor(b Bufferer) {
+	blk, ok := b.(blocker)
+	if !ok {
+		return
+	}
+	anchors.Lock()
+	delete(anchors.m, blk.block())
+	anchors.Unlock()
+}
+
+// realignAnchors re-applies AlignTo after the terminal size changed.
+func realignAnchors() {
+	anchors.Lock()
+	defer anchors.Unlock()
+	r := TermRect()
+	for b, a := range anchors.m {
+		alignBlock(b, r, a.h, a.v)
+	}
 }
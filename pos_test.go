@@ -36,3 +36,62 @@ func TestMoveArea(t *testing.T) {
 		t.Error("MoveArea failed")
 	}
 }
+
+func TestViewport(t *testing.T) {
+	defer SetViewport(image.Rectangle{})
+	termWidth, termHeight = 80, 24
+
+	if r := TermRect(); r != image.Rect(0, 0, 80, 24) {
+		t.Errorf("expected the whole terminal but got %v", r)
+	}
+
+	SetViewport(image.Rect(40, 24, 10, 4))
+	if r := viewportRect(); r != image.Rect(10, 4, 40, 24) {
+		t.Errorf("expected canonical viewport but got %v", r)
+	}
+	if r := TermRect(); r != image.Rect(0, 0, 30, 20) {
+		t.Errorf("expected layout area of the viewport size but got %v", r)
+	}
+}
+
+func TestAlignTo(t *testing.T) {
+	termWidth, termHeight = 80, 24
+
+	b := NewBlock()
+	b.Width = 20
+	b.Height = 10
+
+	Center(b)
+	if b.X != 30 || b.Y != 7 {
+		t.Errorf("Center failed: %d,%d", b.X, b.Y)
+	}
+
+	termWidth, termHeight = 100, 30
+	realignAnchors()
+	if b.X != 40 || b.Y != 10 {
+		t.Errorf("Center not re-applied on resize: %d,%d", b.X, b.Y)
+	}
+
+	AlignToArea(b, image.Rect(10, 10, 50, 50), HAlignRight, VAlignBottom)
+	if b.X != 30 || b.Y != 40 {
+		t.Errorf("AlignToArea failed: %d,%d", b.X, b.Y)
+	}
+	realignAnchors()
+	if b.X != 30 || b.Y != 40 {
+		t.Error("AlignToArea should drop the terminal anchor")
+	}
+
+	Center(b)
+	Unanchor(b)
+	termWidth, termHeight = 80, 24
+	realignAnchors()
+	if b.X != 40 || b.Y != 10 {
+		t.Errorf("Unanchor should keep b in place: %d,%d", b.X, b.Y)
+	}
+	anchors.Lock()
+	_, ok := anchors.m[b]
+	anchors.Unlock()
+	if ok {
+		t.Error("Unanchor should forget b")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "time"
+
+// ProgressBar is an indeterminate Gauge whose segment moves every Interval
+// while it runs, see Start, rather than on the /timer/1s events. Unset
+// Indeterminate to show Percent once the progress is known.
+/*
+  pb := termui.NewProgressBar()
+  pb.IndeterminateLabel = "Connecting…"
+  pb.Width = 40
+  pb.Start()
+*/
+type ProgressBar struct {
+	Gauge
+	Interval time.Duration
+	ticking  string // path of the timer events animating it, "" when stopped
+}
+
+// NewProgressBar returns a new *ProgressBar with current theme.
+func NewProgressBar() *ProgressBar {
+	pb := &ProgressBar{
+		Gauge:    *newGauge(),
+		Interval: 50 * time.Millisecond,
+	}
+	pb.Indeterminate = true
+	pb.Height = 3
+	return pb
+}
+
+// Start animates pb until Stop.
+func (pb *ProgressBar) Start() {
+	pb.Lock()
+	defer pb.Unlock()
+	if pb.ticking == "" {
+		pb.ticking = pb.startAnim(pb.Interval, pb.Step, pb)
+	}
+}
+
+// Stop stops the animation started by Start.
+func (pb *ProgressBar) Stop() {
+	pb.Lock()
+	defer pb.Unlock()
+	pb.stopAnim(pb.ticking)
+	pb.ticking = ""
+}
+
+// Running tells if pb is animated.
+func (pb *ProgressBar) Running() bool {
+	pb.RLock()
+	defer pb.RUnlock()
+	return pb.ticking != ""
+}
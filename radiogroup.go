@@ -0,0 +1,152 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+// RadioGroup lets the user pick one of its Options.
+/*
+  rg := termui.NewRadioGroup("small", "medium", "large")
+  rg.BorderLabel = "Size"
+  rg.Height = 5
+  rg.OnChange = func(i int) { ... }
+  termui.Handle("/sys/kbd", func(e termui.Event) {
+      if rg.HandleKey(termui.NormalizeKey(e)) {
+          termui.Render(rg)
+      }
+  })
+*/
+type RadioGroup struct {
+	Block
+	Options     []string
+	Selected    int // index of the chosen option, -1 for none
+	Cursor      int // row moved by the arrow keys
+	ItemFgColor Attribute
+	ItemBgColor Attribute
+	OnChange    func(index int)
+}
+
+const (
+	radioOn  = "(•) "
+	radioOff = "( ) "
+)
+
+// NewRadioGroup returns a new *RadioGroup with the given options and
+// current theme, no option is selected.
+func NewRadioGroup(options ...string) *RadioGroup {
+	rg := &RadioGroup{
+		Block:    *NewBlock(),
+		Options:  options,
+		Selected: -1,
+	}
+	rg.themeAttr(&rg.ItemFgColor, "list.item.fg")
+	rg.themeAttr(&rg.ItemBgColor, "list.item.bg")
+	return rg
+}
+
+// Select chooses option i and calls OnChange if it changed.
+func (rg *RadioGroup) Select(i int) {
+	rg.Lock()
+	if i < 0 || i >= len(rg.Options) || i == rg.Selected {
+		rg.Unlock()
+		return
+	}
+	rg.Selected = i
+	cb := rg.OnChange
+	rg.Unlock()
+
+	if cb != nil {
+		cb(i)
+	}
+}
+
+// FormValue implements FormValuer, the value is the selected option, ""
+// if none.
+func (rg *RadioGroup) FormValue() interface{} {
+	rg.RLock()
+	defer rg.RUnlock()
+	if rg.Selected < 0 || rg.Selected >= len(rg.Options) {
+		return ""
+	}
+	return rg.Options[rg.Selected]
+}
+
+// HandleKey moves the cursor with <up>/<down> and selects the option under
+// it with <enter> or <space>. It reports whether key was consumed.
+func (rg *RadioGroup) HandleKey(key string) bool {
+	switch key {
+	case KeyArrowUp, KeyArrowDown:
+		rg.Lock()
+		rg.Cursor = moveCursor(rg.Cursor, key, len(rg.Options))
+		rg.Unlock()
+	case KeyEnter, KeySpace:
+		rg.Select(rg.Cursor)
+	default:
+		return false
+	}
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (rg *RadioGroup) Buffer() Buffer {
+	buf := rg.Block.Buffer()
+	rg.RLock()
+	defer rg.RUnlock()
+
+	markers := make([]string, len(rg.Options))
+	for i := range rg.Options {
+		markers[i] = radioOff
+		if i == rg.Selected {
+			markers[i] = radioOn
+		}
+	}
+	drawChoices(buf, &rg.Block, markers, rg.Options, rg.Cursor, rg.ItemFgColor, rg.ItemBgColor)
+	return buf
+}
+
+// moveCursor returns cursor moved by an arrow key within n rows.
+func moveCursor(cursor int, key string, n int) int {
+	switch key {
+	case KeyArrowUp:
+		cursor--
+	case KeyArrowDown:
+		cursor++
+	}
+	if cursor >= n {
+		cursor = n - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
+
+// drawChoices draws one marker and label per row in b's inner area,
+// scrolling to keep the cursor row, whose marker is reversed, visible. The
+// caller must hold b's lock.
+func drawChoices(buf Buffer, b *Block, markers, labels []string, cursor int, fg, bg Attribute) {
+	h := b.innerArea.Dy()
+	if h <= 0 {
+		return
+	}
+	top := 0
+	if cursor >= h {
+		top = cursor - h + 1
+	}
+
+	for y := 0; y < h && top+y < len(labels); y++ {
+		i := top + y
+		mk := TextCells(markers[i], fg, bg)
+		if i == cursor {
+			for j := 0; j < len(mk)-1; j++ {
+				mk[j].Fg |= AttrReverse
+			}
+		}
+		cs := fitCells(append(mk, DefaultTxBuilder.Build(labels[i], fg, bg)...), b.innerArea.Dx())
+		x := b.innerArea.Min.X
+		for _, c := range cs {
+			buf.Set(x, b.innerArea.Min.Y+y, c)
+			x += c.Width()
+		}
+	}
+}
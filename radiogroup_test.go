@@ -0,0 +1,61 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRadioGroup(t *testing.T) {
+	rg := NewRadioGroup("a", "你好", "c")
+	rg.Width = 12
+	rg.Height = 4 // two visible rows
+	changed := -1
+	rg.OnChange = func(i int) { changed = i }
+
+	rg.HandleKey(KeyArrowDown)
+	rg.HandleKey(KeyEnter)
+	if rg.Selected != 1 || changed != 1 {
+		t.Errorf("expected option 1 chosen, got %d (OnChange %d)", rg.Selected, changed)
+	}
+
+	buf := rg.Buffer()
+	if s := strings.TrimRight(bufferRow(buf, 2, 1, 11), " "); s != "(•) 你好" {
+		t.Errorf("expected the wide label after the marker, got %q", s)
+	}
+
+	rg.HandleKey(KeyArrowDown)
+	rg.HandleKey(KeyArrowDown)
+	if rg.Cursor != 2 {
+		t.Errorf("cursor should stop on the last option, got %d", rg.Cursor)
+	}
+	if s := strings.TrimRight(bufferRow(rg.Buffer(), 2, 1, 11), " "); s != "( ) c" {
+		t.Errorf("the cursor row should be scrolled into view, got %q", s)
+	}
+}
+
+func TestCheckboxGroup(t *testing.T) {
+	cg := NewCheckboxGroup("a", "b", "c")
+	cg.Width = 10
+	cg.Height = 5
+
+	cg.HandleKey(KeySpace)
+	cg.HandleKey(KeyArrowDown)
+	cg.HandleKey(KeyArrowDown)
+	cg.HandleKey(KeyEnter)
+	if c := cg.Checked(); !reflect.DeepEqual(c, []int{0, 2}) {
+		t.Errorf("expected [0 2] checked but got %v", c)
+	}
+	if s := strings.TrimRight(bufferRow(cg.Buffer(), 1, 1, 9), " "); s != "[x] a" {
+		t.Errorf("expected a checked box, got %q", s)
+	}
+
+	cg.Toggle(0)
+	if c := cg.Checked(); !reflect.DeepEqual(c, []int{2}) {
+		t.Errorf("expected [2] checked but got %v", c)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+)
+
+// Frame is a screen flushed while a Recorder ran.
+type Frame struct {
+	Time time.Duration // since the recording started
+	Snapshot
+}
+
+// Recorder captures every frame flushed to the terminal, with its time,
+// from StartRecording to Stop. The session can then be written as an
+// asciinema cast, or replayed on the screen.
+/*
+  rec := termui.StartRecording()
+  termui.Loop()
+  rec.Stop()
+  f, _ := os.Create("session.cast")
+  rec.WriteCast(f)
+  f.Close()
+*/
+type Recorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	frames []Frame
+}
+
+// recorders are the running Recorders, guarded by renderLock.
+var recorders []*Recorder
+
+// StartRecording returns a new Recorder capturing the frames from now on,
+// the screen as it is being its first frame.
+func StartRecording() *Recorder {
+	r := &Recorder{start: now()}
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	recorders = append(recorders, r)
+	if vp := viewportRect(); !vp.Empty() {
+		r.add(screenFrame(vp))
+	}
+	return r
+}
+
+// Stop ends the recording.
+func (r *Recorder) Stop() {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	for i, o := range recorders {
+		if o == r {
+			recorders = append(recorders[:i], recorders[i+1:]...)
+			return
+		}
+	}
+}
+
+// Frames returns the frames captured so far.
+func (r *Recorder) Frames() []Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Frame{}, r.frames...)
+}
+
+func (r *Recorder) add(s Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.frames = append(r.frames, Frame{Time: now().Sub(r.start), Snapshot: s})
+}
+
+// screenFrame returns what vp of the screen shows, every cell set. The
+// caller must hold renderLock.
+func screenFrame(vp image.Rectangle) Snapshot {
+	buf := NewBuffer()
+	buf.SetArea(image.Rect(0, 0, vp.Dx(), vp.Dy()))
+	blank := Cell{' ', ColorDefault, ColorDefault}
+	for y := vp.Min.Y; y < vp.Max.Y; y++ {
+		for x := vp.Min.X; x < vp.Max.X; x++ {
+			c, ok := screen.cells[image.Pt(x, y)]
+			if !ok {
+				c = blank
+			}
+			buf.CellMap[image.Pt(x-vp.Min.X, y-vp.Min.Y)] = c
+		}
+	}
+	return Snapshot{buf}
+}
+
+// recordFrame gives the frame just flushed on vp to the running Recorders.
+// The caller must hold renderLock.
+func recordFrame(vp image.Rectangle) {
+	if len(recorders) == 0 {
+		return
+	}
+	s := screenFrame(vp)
+	for _, r := range recorders {
+		r.add(s)
+	}
+}
+
+// WriteCast writes the frames as an asciinema v2 cast, whose player shows
+// the session as it was, see https://docs.asciinema.org/manual/asciicast/v2/.
+func (r *Recorder) WriteCast(w io.Writer) error {
+	frames := r.Frames()
+	bw := bufio.NewWriter(w)
+	width, height := 0, 0
+	if len(frames) > 0 {
+		width, height = frames[0].Area.Dx(), frames[0].Area.Dy()
+	}
+	hdr, err := json.Marshal(map[string]interface{}{
+		"version":   2,
+		"width":     width,
+		"height":    height,
+		"timestamp": r.start.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	bw.Write(hdr)
+	bw.WriteByte('\n')
+
+	event := func(t time.Duration, kind, data string) {
+		e, _ := json.Marshal([]interface{}{t.Seconds(), kind, data})
+		bw.Write(e)
+		bw.WriteByte('\n')
+	}
+	var prev map[image.Point]Cell
+	for i, f := range frames {
+		var out bytes.Buffer
+		if i == 0 || f.Area != frames[i-1].Area {
+			if i > 0 {
+				event(f.Time, "r", fmt.Sprintf("%dx%d", f.Area.Dx(), f.Area.Dy()))
+			}
+			out.WriteString("\x1b[?25l\x1b[0m\x1b[2J")
+			prev = nil
+		}
+		ps := []image.Point{}
+		for p, c := range f.CellMap {
+			if o, ok := prev[p]; c.Ch != wideCont && (!ok || o != c) {
+				ps = append(ps, p)
+			}
+		}
+		prev = f.CellMap
+		if len(ps) == 0 && out.Len() == 0 {
+			continue
+		}
+		sortPoints(ps)
+		writeANSICells(&out, ps, f.CellMap)
+		event(f.Time, "o", out.String())
+	}
+	return bw.Flush()
+}
+
+// snapshotBufferer draws a Snapshot.
+type snapshotBufferer struct {
+	Snapshot
+}
+
+func (s snapshotBufferer) Buffer() Buffer {
+	return s.Snapshot.Buffer
+}
+
+// Replay draws the frames on the screen again with their timing, speed
+// times faster, until the last one or ctx is done. It returns ctx.Err()
+// then, nil otherwise.
+func (r *Recorder) Replay(ctx context.Context, speed float64) error {
+	if speed <= 0 {
+		speed = 1
+	}
+	var last time.Duration
+	for _, f := range r.Frames() {
+		if d := time.Duration(float64(f.Time-last) / speed); d > 0 {
+			t := time.NewTimer(d)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			}
+		}
+		last = f.Time
+		Render(snapshotBufferer{f.Snapshot})
+	}
+	return nil
+}
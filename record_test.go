@@ -0,0 +1,73 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	fb, done := useFakeBackend(6, 3)
+	defer done()
+	old := DefaultTimeSource
+	defer func() { DefaultTimeSource = old }()
+	ft := NewFakeTimeSource(time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC))
+	DefaultTimeSource = ft
+
+	p := NewPar("ab")
+	p.Width, p.Height = 6, 3
+	rec := StartRecording()
+	ft.Advance(time.Second)
+	Render(p)
+	ft.Advance(500 * time.Millisecond)
+	p.Text = "ac"
+	Render(p)
+	rec.Stop()
+	Render(p)
+
+	fs := rec.Frames()
+	if len(fs) != 3 || fs[2].Time != 1500*time.Millisecond || fs[2].Text() != "┌────┐\n│ac  │\n└────┘\n" {
+		t.Fatalf("expected the first screen and two frames, got %d", len(fs))
+	}
+
+	var out bytes.Buffer
+	if err := rec.WriteCast(&out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var hdr map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &hdr); err != nil || hdr["version"] != 2.0 || hdr["width"] != 6.0 {
+		t.Fatalf("expected an asciicast v2 header, got %s", lines[0])
+	}
+	if len(lines) != 4 {
+		t.Fatalf("expected an event per frame, got %q", lines[1:])
+	}
+	var ev []interface{}
+	json.Unmarshal([]byte(lines[3]), &ev)
+	if ev[0] != 1.5 || ev[1] != "o" || ev[2] != "\x1b[2;3H"+sgrOf(p.TextFgColor, p.TextBgColor)+"c\x1b[0m" {
+		t.Errorf("expected the changed cell written, got %q", ev)
+	}
+
+	// replaying draws the last frame again
+	p.Text = "zz"
+	Render(p)
+	if err := rec.Replay(context.Background(), 1000); err != nil {
+		t.Fatal(err)
+	}
+	if fb.cells[image.Pt(2, 1)].Ch != 'c' {
+		t.Error("expected the recorded frames replayed")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rec.Replay(ctx, 0.001); err != context.Canceled {
+		t.Errorf("expected the replay cancelled, got %v", err)
+	}
+}
@@ -5,11 +5,11 @@
 package termui
 
 import (
+	"context"
 	"image"
+	"sort"
 	"sync"
 	"time"
-
-	tm "github.com/nsf/termbox-go"
 )
 
 // Bufferer should be implemented by all renderable components.
@@ -20,12 +20,22 @@ type Bufferer interface {
 // Init initializes termui library. This function should be called before any others.
 // After initialization, the library must be finalized by 'Close' function.
 func Init() error {
-	if err := tm.Init(); err != nil {
+	return InitBackend(TermboxBackend{})
+}
+
+// InitBackend is like Init but draws on b, e.g. a StreamBackend to serve
+// an SSH session rather than the controlling terminal.
+func InitBackend(b Backend) error {
+	if err := b.Init(); err != nil {
 		return err
 	}
+	renderLock.Lock()
+	backend = b
+	renderLock.Unlock()
 
+	quit, polled = make(chan struct{}), make(chan struct{})
 	sysEvtChs = make([]chan Event, 0)
-	go hookTermboxEvt()
+	go hookBackendEvt(b, quit, polled)
 
 	renderJobs = make(chan []Bufferer)
 
@@ -33,49 +43,178 @@ func Init() error {
 	Body.X = 0
 	Body.Y = 0
 	Body.BgColor = ThemeAttr("bg")
-	Body.Width = TermWidth()
+	Body.Width = ViewportWidth()
 
 	DefaultEvtStream.Init()
 	DefaultEvtStream.Merge("termbox", NewSysEvtCh())
-	DefaultEvtStream.Merge("timer", NewTimerCh(time.Second))
+	DefaultEvtStream.Merge("timer", newTimerCh(time.Second, quit))
 	DefaultEvtStream.Merge("custom", usrEvtCh)
+	setCustomEvts(true)
 
 	DefaultEvtStream.Handle("/", DefualtHandler)
+	relayout := func(Event) {
+		Body.Width = viewportRect().Dx()
+		if Body.relative() || Body.responsive() {
+			Body.Align()
+		}
+		realignAnchors()
+		refillFlexes()
+	}
+	if ResizeDebounce > 0 {
+		relayout = DefaultEvtStream.Debounce(ResizeDebounce, relayout)
+	}
 	DefaultEvtStream.Handle("/sys/wnd/resize", func(e Event) {
 		w := e.Data.(EvtWnd)
-		Body.Width = w.Width
+		renderLock.Lock()
+		termWidth, termHeight = w.Width, w.Height
+		screen.reset(image.Rect(0, 0, w.Width, w.Height))
+		renderLock.Unlock()
+		relayout(e)
 	})
 
-	DefaultWgtMgr = NewWgtMgr()
 	DefaultEvtStream.Hook(DefaultWgtMgr.WgtHandlersHook())
 
-	go func() {
-		for bs := range renderJobs {
-			render(bs...)
+	go func(jobs chan []Bufferer, quit chan struct{}) {
+		for {
+			select {
+			case bs := <-jobs:
+				render(bs...)
+			case fn := <-updateJobs:
+				fn()
+			case <-quit:
+				return
+			}
 		}
-	}()
+	}(renderJobs, quit)
 
 	return nil
 }
 
 // Close finalizes termui library,
 // should be called after successful initialization when termui's functionality isn't required anymore.
+// It stops the goroutines started by Init, waiting for the one polling the
+// terminal when the backend is an InterruptibleBackend, and restores the
+// terminal; nothing is drawn afterwards.
 func Close() {
 	once.Do(func() {
+		stopRunLoop()
+		// no more widget events, their senders would block for good
+		setCustomEvts(false)
+		if quit != nil {
+			close(quit)
+			DefaultEvtStream.stop()
+			if ib, ok := backend.(InterruptibleBackend); ok {
+				ib.Interrupt()
+				<-polled
+			}
+		}
 		renderLock.Lock()
 		defer renderLock.Unlock()
-		tm.Close()
+		screen.closed = true
+		if !suspended {
+			backend.Close()
+		}
 	})
 }
 
+// Run runs the event loop until ctx is done or StopLoop is called, then
+// closes termui, so the goroutines started by Init are stopped and the
+// terminal is restored before it returns, even when a handler panics. It
+// returns ctx.Err(), nil when the loop was stopped.
+/*
+  ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+  defer cancel()
+  ui.Handle("/sys/kbd/q", func(ui.Event) { ui.StopLoop() })
+  if err := ui.Run(ctx); err != nil && err != context.Canceled {
+      log.Fatal(err)
+  }
+*/
+func Run(ctx context.Context) error {
+	defer Close()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			StopLoop()
+		case <-stop:
+		}
+	}()
+	Loop()
+	return ctx.Err()
+}
+
+// suspended is set between Suspend and Resume, nothing is drawn then.
+var suspended bool
+
+// Suspend hands the terminal back to the shell, closing the backend, which
+// restores the cursor and the terminal modes, so that an external program
+// such as an editor or a pager can run. Renders are dropped until Resume.
+// It is meant for the controlling terminal: a HeadlessBackend or a
+// StreamBackend cannot be initialized again.
+/*
+  ui.Handle("/sys/kbd/e", func(ui.Event) {
+      ui.Suspend()
+      cmd := exec.Command(os.Getenv("EDITOR"), path)
+      cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+      cmd.Run()
+      if err := ui.Resume(); err != nil {
+          log.Fatal(err)
+      }
+  })
+*/
+func Suspend() {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	if suspended || screen.closed {
+		return
+	}
+	suspended = true
+	backend.Close()
+}
+
+// Resume takes the terminal back after Suspend, initializing the backend
+// again, and repaints the last frame drawn.
+func Resume() error {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	if !suspended {
+		return nil
+	}
+	if err := backend.Init(); err != nil {
+		return err
+	}
+	suspended = false
+
+	// the terminal was cleared, and may have been resized meanwhile
+	termWidth, termHeight = backend.Size()
+	screen = newScreenState()
+	blank := Cell{' ', ColorDefault, clearBg(ColorDefault)}
+	cs, _ := screen.diff(viewportRect(), lastFrame, nil, false, blank)
+	for _, c := range cs {
+		setCell(c.p.X, c.p.Y, c.c)
+	}
+	err := backend.Flush()
+	screen.flush()
+	return err
+}
+
 var renderLock sync.Mutex
 var once sync.Once
 
+// ResizeDebounce delays the layout done on a terminal resize until no other
+// one came for that long, so a storm of resizes lays out once. Zero lays
+// out at every resize. Set it before Init.
+var ResizeDebounce time.Duration
+
+// quit is closed by Close to stop the goroutines started by InitBackend,
+// polled once the one polling the backend has returned.
+var quit, polled chan struct{}
+
 func termSync() {
 	renderLock.Lock()
 	defer renderLock.Unlock()
-	tm.Sync()
-	termWidth, termHeight = tm.Size()
+	termWidth, termHeight = backend.Size()
 }
 
 // TermWidth returns the current terminal's width.
@@ -90,47 +229,439 @@ func TermHeight() int {
 	return termHeight
 }
 
-// Render renders all Bufferer in the given order from left to right,
-// right could overlap on left ones.
-func render(bs ...Bufferer) {
+// viewport is the region of the terminal termui draws into, the zero
+// rectangle means the whole terminal.
+var viewport image.Rectangle
 
+// SetViewport confines all rendering to r, given in terminal coordinates.
+// Widgets keep being laid out from (0,0), which maps to r.Min, and cells
+// falling outside r are dropped. The zero rectangle restores the whole
+// terminal.
+func SetViewport(r image.Rectangle) {
 	renderLock.Lock()
 	defer renderLock.Unlock()
-	for _, b := range bs {
+	viewport = r.Canon()
+}
 
-		buf := b.Buffer()
-		// set cels in buf
-		for p, c := range buf.CellMap {
-			if p.In(buf.Area) {
+// viewportRect returns the effective viewport, the caller must make sure
+// termWidth and termHeight are current.
+func viewportRect() image.Rectangle {
+	if viewport.Empty() {
+		return image.Rect(0, 0, termWidth, termHeight)
+	}
+	return viewport
+}
 
-				tm.SetCell(p.X, p.Y, c.Ch, toTmAttr(c.Fg), toTmAttr(c.Bg))
+// Viewport returns the region of the terminal termui draws into.
+func Viewport() image.Rectangle {
+	termSync()
+	return viewportRect()
+}
 
+// ViewportWidth returns the width available to widgets.
+func ViewportWidth() int {
+	return Viewport().Dx()
+}
+
+// ViewportHeight returns the height available to widgets.
+func ViewportHeight() int {
+	return Viewport().Dy()
+}
+
+// compose merges the given buffers into one frame, right overlapping left,
+// keeping only the cells inside each buffer's area.
+func compose(bufs ...Buffer) Buffer {
+	frame := NewBuffer()
+	for _, buf := range bufs {
+		for p, c := range buf.CellMap {
+			switch {
+			case !p.In(buf.Area):
+			case c.Ch == wideCont:
+				// set along with its glyph, unless that is clipped
+				if l := p.Sub(image.Pt(1, 0)); !l.In(buf.Area) {
+					frame.Set(p.X, p.Y, Cell{' ', c.Fg, c.Bg})
+				}
+			case c.Width() == 2 && !p.Add(image.Pt(1, 0)).In(buf.Area):
+				frame.Set(p.X, p.Y, Cell{' ', c.Fg, c.Bg})
+			default:
+				frame.Set(p.X, p.Y, c)
 			}
 		}
+		frame.SetArea(frame.Area.Union(buf.Area))
+	}
+	return frame
+}
+
+func zIndex(b Bufferer) int {
+	if blk, ok := b.(blocker); ok {
+		k := blk.block()
+		k.lock.RLock()
+		defer k.lock.RUnlock()
+		return k.ZIndex
+	}
+	return 0
+}
+
+// byZIndex returns bs stably sorted by ZIndex, bufferers without a Block
+// counting as 0.
+func byZIndex(bs []Bufferer) []Bufferer {
+	zs := make([]int, len(bs))
+	sorted := true
+	for i, b := range bs {
+		zs[i] = zIndex(b)
+		if i > 0 && zs[i] < zs[i-1] {
+			sorted = false
+		}
+	}
+	if sorted {
+		return bs
+	}
+
+	idx := make([]int, len(bs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return zs[idx[i]] < zs[idx[j]] })
+	out := make([]Bufferer, len(bs))
+	for i, n := range idx {
+		out[i] = bs[n]
+	}
+	return out
+}
+
+// composeBufferers composes the buffers of bs ordered by ZIndex.
+func composeBufferers(bs []Bufferer) Buffer {
+	bs = byZIndex(bs)
+	bufs := make([]Buffer, len(bs))
+	for i, b := range bs {
+		bufs[i] = bufferOf(b)
+	}
+	return compose(bufs...)
+}
+
+// composeFrame composes bs like composeBufferers and returns the widgets
+// drawn, to be registered with registerHits once the frame is flushed. The
+// caller must hold renderLock.
+func composeFrame(bs []Bufferer) (buf Buffer, hs []drawnHit) {
+	hs = collectHits(func() { buf = composeBufferers(bs) })
+	return buf, hs
+}
+
+// collectHits returns the widgets bufferOf draws while fn runs. The caller
+// must hold renderLock.
+func collectHits(fn func()) []drawnHit {
+	var hs []drawnHit
+	hits.Lock()
+	hits.drawn = &hs
+	hits.Unlock()
+
+	fn()
+
+	hits.Lock()
+	hits.drawn = nil
+	hits.Unlock()
+	return hs
+}
+
+// PrerenderedFrame is a frame composed ahead of time by Prerender. It is a
+// Bufferer, so Render draws it in a single pass without calling any widget's
+// Buffer, and it can be rendered again as long as the widgets did not change.
+// Its widgets become clickable when it is rendered.
+type PrerenderedFrame struct {
+	buf  Buffer
+	hits []drawnHit
+}
+
+// Buffer implements Bufferer interface.
+func (f PrerenderedFrame) Buffer() Buffer {
+	return f.buf
+}
+
+// Prerender composes bs into a frame the way Render would, without touching
+// the terminal, e.g. to build the first frame of a heavy dashboard while
+// Init runs. It takes the render lock while composing, so the widgets are
+// not drawn meanwhile, and it must not be called from an Update function.
+func Prerender(bs ...Bufferer) PrerenderedFrame {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	buf, hs := composeFrame(bs)
+	return PrerenderedFrame{buf: buf, hits: hs}
+}
+
+// lastFrame is the frame composed by the last render.
+var lastFrame = NewBuffer()
+
+// LastFrame returns a copy of the frame composed by the last Render, in
+// widget coordinates. It is meant for tests and tools asserting on what
+// was drawn.
+func LastFrame() Buffer {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	buf := NewBuffer()
+	buf.Merge(lastFrame)
+	buf.SetArea(lastFrame.Area)
+	return buf
+}
+
+// RenderStats describes one rendered frame, see RenderHook.
+type RenderStats struct {
+	Bufferers      int           // number of bufferers composed
+	CellsDrawn     int           // cells that changed on screen
+	CellsSkipped   int           // cells identical to the previous frame
+	BufferDuration time.Duration // time spent in the Buffer methods
+	FlushDuration  time.Duration // time spent flushing to the terminal
+}
+
+// RenderHook, when set, is called after each frame is flushed. It runs on
+// the rendering goroutine, outside of the render lock.
+var RenderHook func(stats RenderStats)
+
+// Render renders all Bufferer in the given order from left to right,
+// right could overlap on left ones.
+func render(bs ...Bufferer) {
+	update(nil, bs)
+}
+
+// Update runs fn under the render lock, then renders bs in a single flush
+// before any other frame is drawn, so a goroutine producing data can
+// mutate widgets without racing the renderer or the frame loop. Within a
+// Batch or while RunLoop runs, bs are composed or queued like Render's.
+// fn must not call Render, Update, Batch, TermWidth, TermHeight or
+// Viewport, which would deadlock.
+/*
+  go func() {
+      for v := range values {
+          ui.Update(func() {
+              g.Percent = v
+              p.Text = fmt.Sprintf("%d%%", v)
+          }, g, p)
+      }
+  }()
+*/
+func Update(fn func(), bs ...Bufferer) {
+	update(fn, bs)
+}
+
+func update(fn func(), bs []Bufferer) {
+	renderLock.Lock()
+	if fn != nil {
+		fn()
+	}
+	if batchDepth > 0 {
+		buf, hs := composeFrame(bs)
+		batchFrame = compose(batchFrame, buf)
+		batchHits = append(batchHits, hs...)
+		batchCount += len(bs)
+		renderLock.Unlock()
+		return
+	}
+	if runLoop != nil {
+		runLoop.queue(bs)
+		renderLock.Unlock()
+		return
+	}
+	hook := RenderHook
+	stats := drawFrame(bs, hook != nil)
+	renderLock.Unlock()
 
+	if hook != nil {
+		hook(stats)
 	}
+}
+
+// batchDepth counts the running Batch calls, while it is positive renders
+// are composed into batchFrame instead of being flushed.
+var (
+	batchDepth int
+	batchFrame Buffer
+	batchHits  []drawnHit
+	batchCount int // bufferers composed into batchFrame
+)
+
+// Batch runs fn and draws every Render it makes in one flush once fn
+// returns, so updating several widgets does not show intermediate states.
+// Each Render still composes its widgets when called, later ones
+// overlapping earlier ones. Batch calls can be nested, the flush happens
+// when the outermost one returns.
+/*
+  ui.Batch(func() {
+      g.Percent = 40
+      ui.Render(g)
+      p.Text = "40%"
+      ui.Render(p)
+  })
+*/
+func Batch(fn func()) {
+	renderLock.Lock()
+	if batchDepth == 0 {
+		batchFrame = NewBuffer()
+		batchHits = nil
+		batchCount = 0
+	}
+	batchDepth++
+	renderLock.Unlock()
+
+	defer endBatch()
+	fn()
+}
+
+func endBatch() {
+	renderLock.Lock()
+	batchDepth--
+	if batchDepth > 0 || batchCount == 0 {
+		renderLock.Unlock()
+		return
+	}
+	frame := PrerenderedFrame{buf: batchFrame, hits: batchHits}
+	batchFrame, batchHits = NewBuffer(), nil
+	if runLoop != nil {
+		runLoop.queue([]Bufferer{frame})
+		renderLock.Unlock()
+		return
+	}
+	hook := RenderHook
+	stats := drawFrame([]Bufferer{frame}, hook != nil)
+	stats.Bufferers = batchCount
+	renderLock.Unlock()
+
+	if hook != nil {
+		hook(stats)
+	}
+}
+
+// drawFrame composes bs and flushes the result, timing is only measured
+// when timed is set. The caller must hold renderLock.
+func drawFrame(bs []Bufferer, timed bool) RenderStats {
+	stats := RenderStats{Bufferers: len(bs)}
+	var t time.Time
+	if timed {
+		t = time.Now()
+	}
+
+	vp := viewportRect()
+	// the terminal may transiently report no size, e.g. on a fresh pty
+	if vp.Empty() || screen.closed || suspended {
+		return stats
+	}
+	if clearPending {
+		// only what this frame draws remains on screen
+		resetHits()
+	}
+	// only the images of this frame
+	takeSixels()
+	base, drawn := composeFrame(bs)
+	stale := takeStaleAreas()
+	updateUnder(base, stale, clearPending)
+	// the overlays are drawn, and hit, last
+	drawn = append(drawn, collectHits(func() { lastFrame = overlayFrame(base) })...)
+	stale = append(stale, takeStaleAreas()...)
+	if timed {
+		stats.BufferDuration = time.Since(t)
+	}
+
+	// only write what changed since the last flush, blanking the areas
+	// widgets left and, on a soft Clear or after popping an overlay,
+	// whatever the frame does not cover
+	blank := Cell{' ', ColorDefault, clearBg(ColorDefault)}
+	cs, skipped := screen.diff(vp, lastFrame, stale, clearPending || restorePending, blank)
+	clearPending, restorePending = false, false
+	for _, c := range cs {
+		setCell(c.p.X, c.p.Y, c.c)
+	}
+	stats.CellsDrawn = len(cs)
+	stats.CellsSkipped = skipped
 
 	// render
-	tm.Flush()
+	if timed {
+		t = time.Now()
+	}
+	backend.Flush()
+	drawSixels(vp, takeSixels(), cs)
+	screen.flush()
+	registerHits(drawn)
+	recordFrame(vp)
+	if timed {
+		stats.FlushDuration = time.Since(t)
+	}
+	return stats
 }
 
+// clearPending asks the next render to wipe what it does not draw.
+var clearPending bool
+
+// Clear marks the screen for a full redraw: the next Render wipes every
+// cell it does not draw, rewriting only what actually changed instead of
+// blanking and flushing the whole terminal. Use HardClear to wipe the
+// terminal right away.
 func Clear() {
 	renderLock.Lock()
 	defer renderLock.Unlock()
-	tm.Clear(tm.ColorDefault, toTmAttr(ThemeAttr("bg")))
+	clearPending = true
 }
 
+// HardClear physically clears the terminal, or the viewport if one is set,
+// to the theme's background.
+func HardClear() {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	clearPending = false
+	under = NewBuffer()
+	if !viewport.Empty() {
+		clearArea(image.Rect(0, 0, viewport.Dx(), viewport.Dy()), ColorDefault)
+		return
+	}
+	vp := viewportRect()
+	clearArea(image.Rect(0, 0, vp.Dx(), vp.Dy()), ColorDefault)
+	screen.reset(vp)
+}
+
+// clearBg returns the background to clear with, ColorDefault standing for
+// the current theme's "bg" so every clearing path follows theme changes.
+func clearBg(bg Attribute) Attribute {
+	if bg == ColorDefault {
+		return ThemeAttr("bg")
+	}
+	return bg
+}
+
+// setCell writes c at terminal position (x, y), in the colours the
+// terminal supports.
+func setCell(x, y int, c Cell) {
+	fg, bg := quantizeAttr(c.Fg, colorDepth), quantizeAttr(c.Bg, colorDepth)
+	// the glyph on the left covers it
+	if c.Ch == wideCont {
+		return
+	}
+	rs := clusterOf(c.Ch)
+	if cb, ok := backend.(CombiningBackend); ok && len(rs) > 1 {
+		cb.SetCombiningCell(x, y, rs[0], rs[1:], fg, bg)
+		return
+	}
+	backend.SetCell(x, y, rs[0], fg, bg)
+}
+
+// clearArea clears r, given in viewport coordinates.
 func clearArea(r image.Rectangle, bg Attribute) {
+	bg = clearBg(bg)
+	vp := viewportRect()
+	r = r.Add(vp.Min).Intersect(vp)
 	for i := r.Min.X; i < r.Max.X; i++ {
 		for j := r.Min.Y; j < r.Max.Y; j++ {
-			tm.SetCell(i, j, ' ', tm.ColorDefault, toTmAttr(bg))
+			c := Cell{' ', ColorDefault, bg}
+			screen.set(image.Pt(i, j), c)
+			setCell(i, j, c)
 		}
 	}
 }
 
+// ClearArea clears r to bg right away, ColorDefault meaning the current
+// theme's background.
 func ClearArea(r image.Rectangle, bg Attribute) {
+	renderLock.Lock()
+	defer renderLock.Unlock()
 	clearArea(r, bg)
-	tm.Flush()
+	backend.Flush()
+	screen.flush()
 }
 
 var renderJobs chan []Bufferer
@@ -138,7 +669,42 @@ var renderJobs chan []Bufferer
 func Render(bs ...Bufferer) {
 	//go func() { renderJobs <- bs }()
 	//	renderJobs <- bs
-	for _, b := range bs {
-		render(b)
-	}
+	render(bs...)
+}
+
+// updateJobs carries widget updates to the rendering goroutine.
+var updateJobs = make(chan func())
+
+// Bind runs every function received from ch on the rendering goroutine,
+// under the render lock like Update, and re-renders w after each of them,
+// so a producer can update a widget from another goroutine without racing
+// the renderer. It returns immediately and stops when ch is closed. The
+// functions must not call Render, Update, Batch, TermWidth, TermHeight or
+// Viewport, which would deadlock.
+func Bind(w Bufferer, ch <-chan func()) {
+	BindContext(context.Background(), w, ch)
+}
+
+// BindContext is like Bind but also stops when ctx is done.
+func BindContext(ctx context.Context, w Bufferer, ch <-chan func()) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case fn, ok := <-ch:
+				if !ok {
+					return
+				}
+				// the change and the draw hold the render lock, so a
+				// Render elsewhere never sees w half updated
+				job := func() { update(fn, []Bufferer{w}) }
+				select {
+				case updateJobs <- job:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
 }
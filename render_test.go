@@ -0,0 +1,330 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"image"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestComposeAndLastFrame(t *testing.T) {
+	a := NewFilledBuffer(0, 0, 4, 2, 'a', ColorRed, ColorDefault)
+	b := NewFilledBuffer(2, 1, 6, 3, 'b', ColorBlue, ColorDefault)
+	// cells outside a buffer's area are dropped
+	b.Set(9, 9, Cell{Ch: 'x'})
+
+	lastFrame = compose(a, b)
+	defer func() { lastFrame = NewBuffer() }()
+
+	f := LastFrame()
+	if f.Area != image.Rect(0, 0, 6, 3) {
+		t.Errorf("unexpected frame area %v", f.Area)
+	}
+	if c := f.At(1, 0); c.Ch != 'a' || c.Fg != ColorRed {
+		t.Errorf("expected a at (1,0), got %+v", c)
+	}
+	if c := f.At(3, 1); c.Ch != 'b' {
+		t.Errorf("right buffer should overlap the left one, got %+v", c)
+	}
+	if _, ok := f.CellMap[image.Pt(9, 9)]; ok {
+		t.Error("cell outside of buffer area should not be composed")
+	}
+
+	f.Set(0, 0, Cell{Ch: 'z'})
+	if LastFrame().At(0, 0).Ch != 'a' {
+		t.Error("LastFrame should return a copy")
+	}
+}
+
+func TestClearBg(t *testing.T) {
+	old := ThemeAttr("bg")
+	defer func() { ColorMap["bg"] = old }()
+	ColorMap["bg"] = ColorBlue
+
+	if bg := clearBg(ColorDefault); bg != ColorBlue {
+		t.Errorf("ColorDefault should follow the theme, got %v", bg)
+	}
+	if bg := clearBg(ColorRed); bg != ColorRed {
+		t.Errorf("explicit colours should be kept, got %v", bg)
+	}
+}
+
+func TestByZIndex(t *testing.T) {
+	top := NewPar("top")
+	top.ZIndex = 1
+	a, b := NewPar("a"), NewPar("b")
+	for _, p := range []*Par{top, a, b} {
+		p.Width = 5
+		p.Height = 3
+	}
+
+	bs := byZIndex([]Bufferer{top, a, b})
+	if bs[0] != a || bs[1] != b || bs[2] != top {
+		t.Fatal("expected the argument order kept within a z-index and top last")
+	}
+
+	bufs := make([]Buffer, len(bs))
+	for i, b := range bs {
+		bufs[i] = b.Buffer()
+	}
+	if c := compose(bufs...).At(1, 1); c.Ch != 't' {
+		t.Errorf("the higher z-index should be drawn on top, got %q", c.Ch)
+	}
+}
+
+func dashboard() []Bufferer {
+	bs := []Bufferer{}
+	for i := 0; i < 8; i++ {
+		p := NewPar("lorem ipsum dolor sit amet, [consectetur](fg-red) adipiscing elit")
+		p.X = i % 4 * 20
+		p.Y = i / 4 * 12
+		p.Width = 20
+		p.Height = 12
+		bs = append(bs, p)
+	}
+	return bs
+}
+
+func TestPrerender(t *testing.T) {
+	bs := dashboard()
+	f := Prerender(bs...)
+	if c, want := f.Buffer().At(1, 1), compose(bs[0].Buffer()).At(1, 1); c != want {
+		t.Errorf("prerendered frame should match the widgets, got %+v want %+v", c, want)
+	}
+	if n := len(compose(f.Buffer()).CellMap); n != 80*24 {
+		t.Errorf("expected the whole dashboard in the frame, got %d cells", n)
+	}
+}
+
+// BenchmarkFirstFrame measures composing a frame from the widgets, the
+// work a first Render does before flushing.
+func BenchmarkFirstFrame(b *testing.B) {
+	bs := dashboard()
+	for i := 0; i < b.N; i++ {
+		composeBufferers(bs)
+	}
+}
+
+// BenchmarkPrerenderedFrame measures the same frame when prerendered, which
+// is all that is left to do on the render goroutine.
+func BenchmarkPrerenderedFrame(b *testing.B) {
+	f := Prerender(dashboard()...)
+	for i := 0; i < b.N; i++ {
+		composeBufferers([]Bufferer{f})
+	}
+}
+
+func TestRenderZeroSize(t *testing.T) {
+	w, h := termWidth, termHeight
+	defer func() { termWidth, termHeight = w, h }()
+	termWidth, termHeight = 0, 0
+
+	l := NewList()
+	l.Items = []string{"a", "b"}
+	bc := NewBarChart()
+	bc.Data = []int{1, 2}
+	bc.DataLabels = []string{"a", "b"}
+	lc := NewLineChart()
+	lc.Data = []float64{1, 2, 3}
+	bs := []Bufferer{NewPar("text"), l, bc, lc, NewGauge(), NewSparklines(NewSparkline())}
+
+	// widgets laid out on an empty terminal must not panic
+	for _, b := range bs {
+		blk := b.(blocker).block()
+		for _, sz := range [][2]int{{0, 0}, {1, 1}, {-1, -1}} {
+			blk.Width, blk.Height = sz[0], sz[1]
+			b.Buffer()
+		}
+	}
+
+	lastFrame = NewBuffer()
+	render(bs...)
+	if len(LastFrame().CellMap) != 0 || len(screen.dirty) != 0 {
+		t.Error("nothing should be drawn on a terminal without size")
+	}
+}
+
+func TestBatch(t *testing.T) {
+	w, h := termWidth, termHeight
+	defer func() { termWidth, termHeight = w, h }()
+	termWidth, termHeight = 0, 0
+
+	frames := []RenderStats{}
+	defer func() { RenderHook = nil }()
+	RenderHook = func(st RenderStats) { frames = append(frames, st) }
+
+	p0, p1 := NewPar("a"), NewPar("b")
+	p0.Width, p0.Height = 5, 3
+	p1.Width, p1.Height = 5, 3
+	p1.X = 5
+	Batch(func() {
+		Render(p0)
+		Batch(func() {
+			Render(p1)
+		})
+		if len(frames) != 0 {
+			t.Error("nested Batch should not flush")
+		}
+		p0.Text = "c"
+		Render(p0)
+		if c := batchFrame.At(1, 1); c.Ch != 'c' {
+			t.Errorf("later renders should overlap earlier ones, got %q", c.Ch)
+		}
+	})
+
+	if len(frames) != 1 || frames[0].Bufferers != 3 {
+		t.Errorf("expected one frame of 3 bufferers, got %+v", frames)
+	}
+	if batchDepth != 0 {
+		t.Errorf("batch depth left at %d", batchDepth)
+	}
+
+	Render(p0)
+	if len(frames) != 2 {
+		t.Error("Render should flush again after Batch")
+	}
+}
+
+func TestBatchHits(t *testing.T) {
+	_, done := useFakeBackend(20, 5)
+	defer done()
+	defer resetHits()
+	resetHits()
+
+	p0, p1 := NewPar("a"), NewPar("b")
+	p0.Width, p0.Height = 5, 3
+	p1.X, p1.Width, p1.Height = 5, 5, 3
+	Batch(func() {
+		Render(p0)
+		Render(p1)
+		if WidgetAt(1, 1) != nil {
+			t.Error("widgets should not be hit before the batch is flushed")
+		}
+	})
+	if WidgetAt(1, 1) != p0 || WidgetAt(6, 1) != p1 {
+		t.Error("expected the batched widgets hit once flushed")
+	}
+}
+
+func TestRun(t *testing.T) {
+	_, done := useFakeBackend(10, 3)
+	defer done()
+	oldEs, oldBody, oldCustom, oldQuit, oldPolled := DefaultEvtStream, Body, customEvts, quit, polled
+	defer func() {
+		DefaultEvtStream, Body, customEvts, quit, polled = oldEs, oldBody, oldCustom, oldQuit, oldPolled
+	}()
+	DefaultEvtStream = NewEvtStream()
+	once = sync.Once{}
+
+	hb := NewHeadlessBackend(10, 3)
+	if err := InitBackend(hb); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- Run(ctx) }()
+	cancel()
+	select {
+	case err := <-errc:
+		if err != context.Canceled {
+			t.Errorf("expected the context error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return once the context was done")
+	}
+
+	select {
+	case <-polled:
+	default:
+		t.Error("expected the backend no longer polled")
+	}
+	if customEvts {
+		t.Error("expected no widget events sent after Close")
+	}
+	p := NewPar("a")
+	p.Width, p.Height = 3, 3
+	Render(p)
+	if s := hb.Line(1); s != "          " {
+		t.Errorf("expected nothing drawn after Close, got %q", s)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	fb, done := useFakeBackend(10, 3)
+	defer done()
+
+	p := NewPar("")
+	p.Width, p.Height = 10, 3
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				Update(func() { p.Text = string(rune('a' + i)) }, p)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if fb.flushes != 40 {
+		t.Errorf("expected a flush per update, got %d", fb.flushes)
+	}
+
+	fb.flushes = 0
+	Batch(func() {
+		Update(func() { p.Text = "x" }, p)
+		Update(func() { p.Text = "y" }, p)
+	})
+	if fb.flushes != 1 || fb.cells[image.Pt(1, 1)].Ch != 'y' {
+		t.Errorf("expected the batched updates in one flush, got %d", fb.flushes)
+	}
+}
+
+// initBackend is a fakeBackend counting its Init and Close calls.
+type initBackend struct {
+	*fakeBackend
+	inits, closes int
+}
+
+func (b *initBackend) Init() error { b.inits++; return nil }
+func (b *initBackend) Close()      { b.closes++ }
+
+func TestSuspend(t *testing.T) {
+	fb, done := useFakeBackend(10, 3)
+	defer done()
+	ib := &initBackend{fakeBackend: fb}
+	backend = ib
+
+	p := NewPar("a")
+	p.Width, p.Height = 5, 3
+	Render(p)
+	Suspend()
+	Suspend()
+	if ib.closes != 1 {
+		t.Fatalf("expected the backend closed once, got %d", ib.closes)
+	}
+
+	fb.writes = 0
+	p.Text = "b"
+	Render(p)
+	if fb.writes != 0 {
+		t.Error("expected nothing drawn while suspended")
+	}
+
+	fb.cells = make(map[image.Point]Cell)
+	if err := Resume(); err != nil || ib.inits != 1 {
+		t.Fatalf("expected the backend initialized again, got %d, %v", ib.inits, err)
+	}
+	if c := fb.cells[image.Pt(1, 1)]; c.Ch != 'a' || fb.cells[image.Pt(0, 0)].Ch != TOP_LEFT {
+		t.Errorf("expected the last frame repainted, got %q", c.Ch)
+	}
+	Render(p)
+	if c := fb.cells[image.Pt(1, 1)]; c.Ch != 'b' {
+		t.Errorf("expected renders drawn again, got %q", c.Ch)
+	}
+}
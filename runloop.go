@@ -0,0 +1,149 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// frameLoop is the loop started by RunLoop. Its pending bufferers and
+// frame counts are guarded by renderLock.
+type frameLoop struct {
+	pending []Bufferer
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	since  time.Time // start of the second frames are counted over
+	frames int       // drawn since then
+	fps    float64   // measured over the last full second
+}
+
+// runLoop is the running frame loop, nil when Render draws right away.
+var runLoop *frameLoop
+
+// RunLoop draws at most fps frames per second until Close or the returned
+// stop function is called. On every tick it calls draw, if not nil, then
+// flushes what was rendered since the previous frame in one go: Render
+// only queues its bufferers while the loop runs, and a widget rendered
+// several times within a frame is drawn once. RunLoop returns immediately,
+// a new call replaces the running loop.
+/*
+  stop := ui.RunLoop(30, func() {
+      g.Percent = progress()
+      ui.Render(g)
+  })
+  defer stop()
+*/
+func RunLoop(fps int, draw func()) (stop func()) {
+	if fps <= 0 {
+		fps = 1
+	}
+	l := &frameLoop{stop: make(chan struct{}), done: make(chan struct{}), since: now()}
+	renderLock.Lock()
+	prev := runLoop
+	runLoop = l
+	renderLock.Unlock()
+	if prev != nil {
+		prev.halt()
+	}
+
+	tk := DefaultTimeSource.NewTicker(time.Second / time.Duration(fps))
+	go func() {
+		defer close(l.done)
+		defer tk.Stop()
+		for {
+			select {
+			case <-l.stop:
+				// draw what was rendered after the last frame
+				renderLock.Lock()
+				if runLoop == l {
+					runLoop = nil
+				}
+				renderLock.Unlock()
+				l.flush()
+				return
+			case <-tk.C():
+				if draw != nil {
+					draw()
+				}
+				l.flush()
+			}
+		}
+	}()
+	return l.halt
+}
+
+// halt stops l and waits for its last frame.
+func (l *frameLoop) halt() {
+	l.once.Do(func() { close(l.stop) })
+	<-l.done
+}
+
+// queue adds bs to the next frame, a widget queued again moving up to
+// its latest place.
+func (l *frameLoop) queue(bs []Bufferer) {
+	for _, b := range bs {
+		if reflect.ValueOf(b).Kind() == reflect.Ptr {
+			for i, p := range l.pending {
+				if p == b {
+					l.pending = append(l.pending[:i], l.pending[i+1:]...)
+					break
+				}
+			}
+		}
+		l.pending = append(l.pending, b)
+	}
+}
+
+// flush draws the queued bufferers, if any, and counts the frame.
+func (l *frameLoop) flush() {
+	renderLock.Lock()
+	bs := l.pending
+	l.pending = nil
+	if len(bs) > 0 {
+		l.frames++
+	}
+	if d := now().Sub(l.since); d >= time.Second {
+		l.fps = float64(l.frames) / d.Seconds()
+		l.since, l.frames = now(), 0
+	}
+	if len(bs) == 0 {
+		renderLock.Unlock()
+		return
+	}
+	hook := RenderHook
+	stats := drawFrame(bs, hook != nil)
+	renderLock.Unlock()
+
+	if hook != nil {
+		hook(stats)
+	}
+}
+
+// LastFPS returns the frames per second the running RunLoop drew over the
+// last full second, 0 before that second or without a loop. It can be
+// below the rate given to RunLoop when nothing was rendered for some of
+// the frames.
+func LastFPS() float64 {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	if runLoop == nil {
+		return 0
+	}
+	return runLoop.fps
+}
+
+// stopRunLoop stops the running frame loop, if any.
+func stopRunLoop() {
+	renderLock.Lock()
+	l := runLoop
+	renderLock.Unlock()
+	if l != nil {
+		l.halt()
+	}
+}
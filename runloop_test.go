@@ -0,0 +1,110 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+func TestRunLoop(t *testing.T) {
+	old := DefaultTimeSource
+	defer func() { DefaultTimeSource = old }()
+	ft := NewFakeTimeSource(time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC))
+	DefaultTimeSource = ft
+	fb, done := useFakeBackend(10, 3)
+	defer done()
+	frames := make(chan RenderStats, 4)
+	RenderHook = func(st RenderStats) { frames <- st }
+	defer func() { RenderHook = nil }()
+
+	p := NewPar("p")
+	p.Border = false
+	p.Width, p.Height = 1, 1
+	q := NewPar("q")
+	q.Border = false
+	q.X, q.Width, q.Height = 1, 1, 1
+
+	draws := 0
+	stop := RunLoop(10, func() {
+		draws++
+		Render(p)
+	})
+	Render(p)
+	Render(q, p)
+	if len(fb.cells) != 0 {
+		t.Error("expected nothing drawn before the first frame")
+	}
+
+	ft.Advance(100 * time.Millisecond)
+	st := <-frames
+	if draws != 1 || st.Bufferers != 2 {
+		t.Errorf("expected one frame of 2 bufferers, got %d draws and %+v", draws, st)
+	}
+	if fb.cells[image.Pt(0, 0)].Ch != 'p' || fb.cells[image.Pt(1, 0)].Ch != 'q' {
+		t.Error("expected the queued widgets drawn")
+	}
+
+	// once stopped, renders draw right away
+	stop()
+	q.Text = "r"
+	Render(q)
+	<-frames
+	if fb.cells[image.Pt(1, 0)].Ch != 'r' {
+		t.Error("expected a render after stop drawn")
+	}
+}
+
+func TestLastFPS(t *testing.T) {
+	old := DefaultTimeSource
+	defer func() { DefaultTimeSource = old }()
+	ft := NewFakeTimeSource(time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC))
+	DefaultTimeSource = ft
+	_, done := useFakeBackend(10, 3)
+	defer done()
+	frames := make(chan RenderStats, 1)
+	RenderHook = func(st RenderStats) { frames <- st }
+	defer func() { RenderHook = nil }()
+
+	p := NewPar("p")
+	p.Width, p.Height = 3, 3
+	ticks := make(chan bool, 1)
+	stop := RunLoop(10, func() {
+		// every other frame draws something
+		select {
+		case draw := <-ticks:
+			if draw {
+				Render(p)
+			}
+		default:
+		}
+	})
+	defer stop()
+	if LastFPS() != 0 {
+		t.Errorf("expected no rate before a second passed, got %v", LastFPS())
+	}
+
+	for i := 0; i < 10; i++ {
+		ticks <- i%2 == 0
+		ft.Advance(100 * time.Millisecond)
+		if i%2 == 0 {
+			<-frames
+		}
+	}
+	// the last tick, drawing nothing, closes the second
+	deadline := time.Now().Add(time.Second)
+	for LastFPS() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := LastFPS(); got != 5 {
+		t.Errorf("expected 5 frames drawn in the second, got %v", got)
+	}
+
+	stop()
+	if LastFPS() != 0 {
+		t.Error("expected no rate once the loop stopped")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sort"
+)
+
+// screenState mirrors the cells flushed to the terminal so every frame can
+// be compared with the previous one.
+type screenState struct {
+	cells   map[image.Point]Cell
+	dirty   map[image.Point]struct{}
+	cleared []image.Rectangle // areas wiped since the last flush
+	regions []image.Rectangle // changes of the last flushed frame
+	closed  bool              // the backend was closed, nothing more is drawn
+}
+
+func newScreenState() *screenState {
+	return &screenState{
+		cells: make(map[image.Point]Cell),
+		dirty: make(map[image.Point]struct{}),
+	}
+}
+
+// set records c at p and reports whether it differs from the screen.
+func (s *screenState) set(p image.Point, c Cell) bool {
+	if old, ok := s.cells[p]; ok && old == c {
+		return false
+	}
+	s.cells[p] = c
+	s.dirty[p] = struct{}{}
+	return true
+}
+
+// reset forgets the content of the screen after r has been wiped, e.g. by
+// a clear or a resize.
+func (s *screenState) reset(r image.Rectangle) {
+	s.cells = make(map[image.Point]Cell)
+	s.dirty = make(map[image.Point]struct{})
+	s.cleared = []image.Rectangle{r}
+	resetHits()
+}
+
+// uncovered returns the points of r holding content that frame, given in
+// coordinates relative to r.Min, does not draw over.
+func (s *screenState) uncovered(r image.Rectangle, frame Buffer) []image.Point {
+	ps := []image.Point{}
+	for p := range s.cells {
+		if !p.In(r) {
+			continue
+		}
+		if _, ok := frame.CellMap[p.Sub(r.Min)]; !ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// cellAt is a cell to write at p on the terminal.
+type cellAt struct {
+	p image.Point
+	c Cell
+}
+
+// diff records frame, given in coordinates relative to vp.Min, and returns
+// the cells that differ from the screen, along with the number of frame
+// cells left untouched. The cells of stale, also relative to vp.Min, that
+// frame does not draw are blanked, as is everything else in vp with wipe.
+func (s *screenState) diff(vp image.Rectangle, frame Buffer, stale []image.Rectangle, wipe bool, blank Cell) (cs []cellAt, skipped int) {
+	put := func(p image.Point, c Cell) bool {
+		old := s.cells[p]
+		if !s.set(p, c) {
+			return false
+		}
+		cs = append(cs, cellAt{p, c})
+		// the right half of a wide glyph replaced by a narrow one shows
+		// again, whether the frame draws it or not
+		q := p.Add(image.Pt(1, 0))
+		if old.Width() == 2 && c.Width() != 2 && s.cells[q].Ch == wideCont {
+			s.set(q, blank)
+			cs = append(cs, cellAt{q, blank})
+		}
+		return true
+	}
+
+	for _, r := range stale {
+		r = r.Add(vp.Min).Intersect(vp)
+		for x := r.Min.X; x < r.Max.X; x++ {
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				p := image.Pt(x, y)
+				if _, ok := frame.CellMap[p.Sub(vp.Min)]; !ok {
+					put(p, blank)
+				}
+			}
+		}
+	}
+	if wipe {
+		for _, p := range s.uncovered(vp, frame) {
+			put(p, blank)
+		}
+	}
+
+	for p, c := range frame.CellMap {
+		p = p.Add(vp.Min)
+		if p.In(vp) && !put(p, c) {
+			skipped++
+		}
+	}
+	return cs, skipped
+}
+
+// flush ends the current frame and computes its dirty regions.
+func (s *screenState) flush() {
+	ps := make([]image.Point, 0, len(s.dirty))
+	for p := range s.dirty {
+		ps = append(ps, p)
+	}
+	s.regions = append(s.cleared, coalescePoints(ps)...)
+	s.dirty = make(map[image.Point]struct{})
+	s.cleared = nil
+}
+
+// coalescePoints merges ps into bounding boxes: consecutive cells of a row
+// form a run and runs spanning the same columns on adjacent rows are merged.
+func coalescePoints(ps []image.Point) []image.Rectangle {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Y != ps[j].Y {
+			return ps[i].Y < ps[j].Y
+		}
+		return ps[i].X < ps[j].X
+	})
+
+	runs := []image.Rectangle{}
+	for _, p := range ps {
+		if n := len(runs) - 1; n >= 0 && runs[n].Min.Y == p.Y && runs[n].Max.X == p.X {
+			runs[n].Max.X++
+			continue
+		}
+		runs = append(runs, image.Rect(p.X, p.Y, p.X+1, p.Y+1))
+	}
+
+	rs := []image.Rectangle{}
+	// open holds the indices in rs of the rectangles ending on the last row
+	open := map[[2]int]int{}
+	for _, r := range runs {
+		k := [2]int{r.Min.X, r.Max.X}
+		if i, ok := open[k]; ok && rs[i].Max.Y == r.Min.Y {
+			rs[i].Max.Y = r.Max.Y
+			continue
+		}
+		open[k] = len(rs)
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+var screen = newScreenState()
+
+// DirtyRegions returns the areas, in terminal coordinates, that changed in
+// the last flushed frame compared to the one before. Clears and resizes
+// report the whole wiped area.
+func DirtyRegions() []image.Rectangle {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	rs := make([]image.Rectangle, len(screen.regions))
+	copy(rs, screen.regions)
+	return rs
+}
@@ -0,0 +1,122 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestScreenStateSingleCell(t *testing.T) {
+	s := newScreenState()
+	frame := func(ch rune) {
+		for x := 0; x < 10; x++ {
+			for y := 0; y < 5; y++ {
+				c := Cell{Ch: 'a'}
+				if x == 3 && y == 2 {
+					c.Ch = ch
+				}
+				s.set(image.Pt(x, y), c)
+			}
+		}
+		s.flush()
+	}
+
+	frame('a')
+	if !reflect.DeepEqual(s.regions, []image.Rectangle{image.Rect(0, 0, 10, 5)}) {
+		t.Errorf("first frame should be one region but got %v", s.regions)
+	}
+
+	frame('b')
+	if !reflect.DeepEqual(s.regions, []image.Rectangle{image.Rect(3, 2, 4, 3)}) {
+		t.Errorf("expected a single 1x1 region but got %v", s.regions)
+	}
+
+	frame('b')
+	if len(s.regions) != 0 {
+		t.Errorf("unchanged frame should have no regions but got %v", s.regions)
+	}
+
+	s.reset(image.Rect(0, 0, 10, 5))
+	s.flush()
+	if !reflect.DeepEqual(s.regions, []image.Rectangle{image.Rect(0, 0, 10, 5)}) {
+		t.Errorf("reset should report the wiped area but got %v", s.regions)
+	}
+}
+
+func TestCoalescePoints(t *testing.T) {
+	ps := []image.Point{{5, 1}, {1, 0}, {2, 0}, {1, 1}, {2, 1}, {7, 3}}
+	rs := coalescePoints(ps)
+	want := []image.Rectangle{
+		image.Rect(1, 0, 3, 2),
+		image.Rect(5, 1, 6, 2),
+		image.Rect(7, 3, 8, 4),
+	}
+	if !reflect.DeepEqual(rs, want) {
+		t.Errorf("expected %v but got %v", want, rs)
+	}
+}
+
+func TestScreenStateUncovered(t *testing.T) {
+	s := newScreenState()
+	for x := 0; x < 4; x++ {
+		s.set(image.Pt(x, 1), Cell{Ch: 'a'})
+	}
+
+	// the frame is drawn in a viewport starting at (1,0)
+	frame := NewBuffer()
+	frame.Set(0, 1, Cell{Ch: 'b'})
+	frame.Set(1, 1, Cell{Ch: 'b'})
+
+	ps := s.uncovered(image.Rect(1, 0, 4, 2), frame)
+	if len(ps) != 1 || ps[0] != image.Pt(3, 1) {
+		t.Errorf("expected only (3,1) to be wiped, got %v", ps)
+	}
+}
+
+func TestScreenStateDiff(t *testing.T) {
+	s := newScreenState()
+	vp := image.Rect(2, 1, 12, 6)
+	blank := Cell{Ch: ' '}
+
+	p := NewPar("abc")
+	p.Width, p.Height = 6, 3
+	takeStaleAreas()
+	cs, skipped := s.diff(vp, p.Buffer(), nil, false, blank)
+	if len(cs) != 18 || skipped != 0 {
+		t.Fatalf("first frame should write every cell, got %d written %d skipped", len(cs), skipped)
+	}
+	if cs[0].p.X < 2 || cs[0].p.Y < 1 {
+		t.Errorf("cells should be offset by the viewport, got %v", cs[0].p)
+	}
+	s.flush()
+
+	p.Text = "abd"
+	cs, skipped = s.diff(vp, p.Buffer(), takeStaleAreas(), false, blank)
+	if len(cs) != 1 || cs[0].p != image.Pt(5, 2) || cs[0].c.Ch != 'd' || skipped != 17 {
+		t.Errorf("only the changed cell should be written, got %v, %d skipped", cs, skipped)
+	}
+	s.flush()
+
+	// the widget shrinks: the column it left is blanked
+	p.Width = 5
+	cs, _ = s.diff(vp, p.Buffer(), takeStaleAreas(), false, blank)
+	left := 0
+	for _, c := range cs {
+		if c.p.X == 7 && c.c == blank {
+			left++
+		}
+	}
+	if left != 3 {
+		t.Errorf("expected the 3 cells left by the widget blanked, got %v", cs)
+	}
+	s.flush()
+
+	cs, skipped = s.diff(vp, NewBuffer(), nil, true, blank)
+	if len(cs) != 15 || skipped != 0 {
+		t.Errorf("a wipe should blank the remaining cells, got %d", len(cs))
+	}
+}
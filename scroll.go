@@ -0,0 +1,303 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sync"
+)
+
+// drawScrollbar draws a vertical scrollbar of height h at column x, starting
+// at row y, for a view showing h of total lines from offset.
+func drawScrollbar(buf Buffer, x, y, h, total, offset int, fg, bg Attribute) {
+	pos, size := scrollThumb(h, total, h, offset)
+	for i := 0; i < h && size > 0; i++ {
+		c := Cell{Ch: '░', Fg: fg, Bg: bg}
+		if i >= pos && i < pos+size {
+			c.Ch = '█'
+		}
+		buf.Set(x, y+i, c)
+	}
+}
+
+// scrollThumb returns the position and the size of the thumb in a track n
+// cells long, for a view showing shown of total lines from offset. The
+// size is 0 if all of them are shown.
+func scrollThumb(n, total, shown, offset int) (pos, size int) {
+	if n <= 0 || total <= shown {
+		return 0, 0
+	}
+	size = n * shown / total
+	if size < 1 {
+		size = 1
+	}
+	pos = offset * (n - size) / (total - shown)
+	if pos > n-size {
+		pos = n - size
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	return pos, size
+}
+
+// Scrollable is implemented by the widgets a Scrollbar attaches to: List,
+// Table, LogView and ScrollView. ScrollState returns, along the vertical
+// or the horizontal axis, the length of the content, the part of it shown
+// and the offset of the first part shown, as of the last draw.
+// SetScrollOffset moves the view to offset along the axis.
+type Scrollable interface {
+	Bufferer
+	ScrollState(vertical bool) (total, shown, offset int)
+	SetScrollOffset(vertical bool, offset int)
+}
+
+// Scrollbar shows where the view of a Scrollable is within its content, as
+// a thumb on a track along its right or bottom border, or its last inner
+// column or row without that border. A click on the track jumps there, a
+// drag moves the thumb along. It is drawn only when the content is
+// clipped.
+/*
+  ls := termui.NewList()
+  termui.AttachScrollbar(ls, termui.AlignRight)
+*/
+type Scrollbar struct {
+	Edge         Align // AlignRight or AlignBottom
+	Thumb, Track rune
+	Fg, Bg       Attribute // zero ones take the border colours of the widget
+
+	w     Scrollable
+	track image.Rectangle // of the last draw, guarded by scrollbars
+}
+
+// scrollbars holds the attached Scrollbars by widget and the one being
+// dragged.
+var scrollbars = struct {
+	sync.Mutex
+	m    map[*Block][]*Scrollbar
+	drag *Scrollbar
+}{m: make(map[*Block][]*Scrollbar)}
+
+// AttachScrollbar attaches a new Scrollbar to w along edge, AlignRight for
+// a vertical one or AlignBottom for a horizontal one, and returns it. It
+// replaces the Scrollbar w had on that edge.
+func AttachScrollbar(w Scrollable, edge Align) *Scrollbar {
+	if edge != AlignBottom {
+		edge = AlignRight
+	}
+	sb := &Scrollbar{Edge: edge, Thumb: '█', Track: '░', w: w}
+	k := blockOf(w)
+	scrollbars.Lock()
+	defer scrollbars.Unlock()
+	bs := scrollbars.m[k][:0:0]
+	for _, o := range scrollbars.m[k] {
+		if o.Edge != edge {
+			bs = append(bs, o)
+		}
+	}
+	scrollbars.m[k] = append(bs, sb)
+	return sb
+}
+
+// Detach removes sb from its widget.
+func (sb *Scrollbar) Detach() {
+	k := blockOf(sb.w)
+	scrollbars.Lock()
+	defer scrollbars.Unlock()
+	bs := scrollbars.m[k]
+	for i, o := range bs {
+		if o == sb {
+			scrollbars.m[k] = append(bs[:i:i], bs[i+1:]...)
+			break
+		}
+	}
+	if len(scrollbars.m[k]) == 0 {
+		delete(scrollbars.m, k)
+	}
+	if scrollbars.drag == sb {
+		scrollbars.drag = nil
+	}
+}
+
+func (sb *Scrollbar) vertical() bool {
+	return sb.Edge != AlignBottom
+}
+
+// drawScrollbars draws the Scrollbars attached to the widget k belongs to
+// on its buffer buf.
+func drawScrollbars(k *Block, buf Buffer) {
+	scrollbars.Lock()
+	bs := append([]*Scrollbar{}, scrollbars.m[k]...)
+	scrollbars.Unlock()
+	for _, sb := range bs {
+		sb.draw(k, buf)
+	}
+}
+
+func (sb *Scrollbar) draw(k *Block, buf Buffer) {
+	total, shown, offset := sb.w.ScrollState(sb.vertical())
+
+	k.RLock()
+	in, area := k.innerArea, k.area
+	fg, bg := sb.Fg, sb.Bg
+	if fg == ColorDefault {
+		fg = k.BorderFg
+	}
+	if bg == ColorDefault {
+		bg = k.BorderBg
+	}
+	track := image.Rect(in.Max.X-1, in.Min.Y, in.Max.X, in.Max.Y)
+	if k.Border && k.BorderRight {
+		track = track.Add(image.Pt(area.Max.X-in.Max.X, 0))
+	}
+	n := track.Dy()
+	if !sb.vertical() {
+		track = image.Rect(in.Min.X, in.Max.Y-1, in.Max.X, in.Max.Y)
+		if k.Border && k.BorderBottom {
+			track = track.Add(image.Pt(0, area.Max.Y-in.Max.Y))
+		}
+		n = track.Dx()
+	}
+	k.RUnlock()
+
+	pos, size := scrollThumb(n, total, shown, offset)
+	if size == 0 {
+		track = image.ZR
+	}
+	scrollbars.Lock()
+	sb.track = track
+	scrollbars.Unlock()
+
+	for i := 0; i < n && size > 0; i++ {
+		c := Cell{sb.Track, fg, bg}
+		if i >= pos && i < pos+size {
+			c.Ch = sb.Thumb
+		}
+		if sb.vertical() {
+			buf.Set(track.Min.X, track.Min.Y+i, c)
+		} else {
+			buf.Set(track.Min.X+i, track.Min.Y, c)
+		}
+	}
+}
+
+// jump moves the view of sb's widget to the part at p of the track.
+func (sb *Scrollbar) jump(p image.Point, track image.Rectangle) {
+	total, shown, _ := sb.w.ScrollState(sb.vertical())
+	i, n := p.Y-track.Min.Y, track.Dy()
+	if !sb.vertical() {
+		i, n = p.X-track.Min.X, track.Dx()
+	}
+	i = clampInt(i, 0, n-1)
+	off := 0
+	if n > 1 {
+		off = i * (total - shown) / (n - 1)
+	}
+	sb.w.SetScrollOffset(sb.vertical(), off)
+	Render(sb.w)
+}
+
+// dispatchScrollbar has a left click on a Scrollbar of the topmost widget
+// there, and the drag following it, move the view. It reports whether m
+// was taken.
+func dispatchScrollbar(m EvtMouse) bool {
+	p := image.Pt(m.X, m.Y).Sub(viewportOrigin())
+	if m.Press == "MouseRelease" {
+		scrollbars.Lock()
+		defer scrollbars.Unlock()
+		dragged := scrollbars.drag != nil
+		scrollbars.drag = nil
+		return dragged
+	}
+	if m.Press != "MouseLeft" {
+		return false
+	}
+
+	var sb *Scrollbar
+	var track image.Rectangle
+	scrollbars.Lock()
+	if m.Drag && scrollbars.drag != nil {
+		sb = scrollbars.drag
+		track = sb.track
+	} else if w, ok := WidgetAt(m.X, m.Y).(blocker); ok {
+		scrollbars.drag = nil
+		for _, o := range scrollbars.m[w.block()] {
+			if p.In(o.track) {
+				sb, track = o, o.track
+				scrollbars.drag = o
+			}
+		}
+	}
+	scrollbars.Unlock()
+	if sb == nil {
+		return false
+	}
+	sb.jump(p, track)
+	return true
+}
+
+// ScrollIndicatorStyle holds the marks scrollable widgets draw when their
+// content is clipped in a direction. A zero rune turns that mark off, zero
+// colours use the widget's border colours.
+type ScrollIndicatorStyle struct {
+	Up, Down, Left, Right rune
+	Fg, Bg                Attribute
+}
+
+// ScrollIndicators is the style used by all scrollable widgets, e.g. for
+// ASCII terminals:
+/*
+  ui.ScrollIndicators.Up, ui.ScrollIndicators.Down = '^', 'v'
+*/
+var ScrollIndicators = ScrollIndicatorStyle{
+	Up:    '▲',
+	Down:  '▼',
+	Left:  '◀',
+	Right: '▶',
+}
+
+// drawScrollIndicators marks the directions b's content is clipped in. Up
+// and down go at the right end of the top and bottom borders, left and
+// right on the side borders next to the last line. Without the border the
+// mark takes the matching inner corner.
+func (b *Block) drawScrollIndicators(buf Buffer, up, down, left, right bool) {
+	in := b.innerArea
+	if in.Empty() {
+		return
+	}
+	st := ScrollIndicators
+	fg, bg := st.Fg, st.Bg
+	if fg == ColorDefault {
+		fg = b.BorderFg
+	}
+	if bg == ColorDefault {
+		bg = b.BorderBg
+	}
+	mark := func(on bool, ch rune, x, y int) {
+		if on && ch != 0 {
+			buf.Set(x, y, Cell{Ch: ch, Fg: fg, Bg: bg})
+		}
+	}
+
+	top, bottom := in.Min.Y, in.Max.Y-1
+	if b.Border && b.BorderTop {
+		top = b.area.Min.Y
+	}
+	if b.Border && b.BorderBottom {
+		bottom = b.area.Max.Y - 1
+	}
+	mark(up, st.Up, in.Max.X-1, top)
+	mark(down, st.Down, in.Max.X-1, bottom)
+
+	l, r := in.Min.X, in.Max.X-1
+	if b.Border && b.BorderLeft {
+		l = b.area.Min.X
+	}
+	if b.Border && b.BorderRight {
+		r = b.area.Max.X - 1
+	}
+	mark(left, st.Left, l, in.Max.Y-1)
+	mark(right, st.Right, r, in.Max.Y-1)
+}
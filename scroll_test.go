@@ -0,0 +1,61 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestScrollbar(t *testing.T) {
+	fb, done := useFakeBackend(20, 10)
+	defer done()
+	defer resetHits()
+
+	l := NewList()
+	l.Width, l.Height = 10, 6
+	l.Items = []string{"0", "1", "2", "3", "4", "5", "6", "7"}
+	sb := AttachScrollbar(l, AlignRight)
+	defer sb.Detach()
+	Render(l)
+
+	col := func() string {
+		s := ""
+		for y := 1; y < 5; y++ {
+			s += string(fb.cells[image.Pt(9, y)].Ch)
+		}
+		return s
+	}
+	if got := col(); got != "██░░" {
+		t.Errorf("expected the thumb at the top of the right border, got %q", got)
+	}
+
+	// a click at the bottom of the track shows the last items
+	if !dispatchScrollbar(EvtMouse{X: 9, Y: 4, Press: "MouseLeft"}) || l.ScrollTop != 4 {
+		t.Fatalf("expected a jump to the end, got ScrollTop %d", l.ScrollTop)
+	}
+	if got := col(); got != "░░██" {
+		t.Errorf("expected the thumb moved down, got %q", got)
+	}
+
+	// the drag follows the pointer off the bar until the release
+	if !dispatchScrollbar(EvtMouse{X: 3, Y: 2, Press: "MouseLeft", Drag: true}) || l.ScrollTop != 1 {
+		t.Errorf("expected the drag to scroll, got ScrollTop %d", l.ScrollTop)
+	}
+	if !dispatchScrollbar(EvtMouse{X: 3, Y: 2, Press: "MouseRelease"}) {
+		t.Error("expected the release to end the drag")
+	}
+	if dispatchScrollbar(EvtMouse{X: 3, Y: 2, Press: "MouseLeft"}) {
+		t.Error("expected a click off the bar left to the list")
+	}
+
+	// nothing is drawn while everything fits
+	l.Items = l.Items[:2]
+	l.ScrollTop = 0
+	Render(l)
+	if got := col(); got != "││││" {
+		t.Errorf("expected the plain border, got %q", got)
+	}
+}
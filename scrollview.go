@@ -0,0 +1,198 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "image"
+
+// ScrollView shows the part of Content at OffsetX, OffsetY that fits in
+// its inner area. Content is laid out on its own, at any position, and
+// must be sized to all of what it draws, e.g. a Par as high as its text.
+// The arrow keys, <previous>/<next>, <home>/<end> and the mouse wheel
+// scroll it.
+/*
+  p := termui.NewPar(longText)
+  p.Border = false
+  p.Width, p.Height = 60, 200
+  sv := termui.NewScrollView(p)
+  sv.Width, sv.Height = 62, 20
+  sv.Scrollbar = true
+*/
+type ScrollView struct {
+	Block
+	Content   Bufferer
+	OffsetX   int
+	OffsetY   int
+	Scrollbar bool // draw a vertical scrollbar when Content is clipped
+	WheelStep int  // rows scrolled by a wheel notch
+	content   image.Point
+	view      image.Point
+}
+
+// NewScrollView returns a new *ScrollView of content.
+func NewScrollView(content Bufferer) *ScrollView {
+	sv := &ScrollView{
+		Block:     *NewBlock(),
+		Content:   content,
+		WheelStep: 3,
+	}
+	sv.Handle("/sys/mouse", func(e Event) {
+		m, ok := e.Data.(EvtMouse)
+		if ok && sv.HandleMouse(m) {
+			Render(sv)
+		}
+	})
+	return sv
+}
+
+// ScrollTo moves the view to column x and row y of Content, within its
+// bounds as of the last draw.
+func (sv *ScrollView) ScrollTo(x, y int) {
+	sv.Lock()
+	defer sv.Unlock()
+	sv.OffsetX, sv.OffsetY = x, y
+	sv.clamp()
+}
+
+// clamp keeps the offsets within the content.
+func (sv *ScrollView) clamp() {
+	if mx := sv.content.X - sv.view.X; sv.OffsetX > mx {
+		sv.OffsetX = mx
+	}
+	if my := sv.content.Y - sv.view.Y; sv.OffsetY > my {
+		sv.OffsetY = my
+	}
+	if sv.OffsetX < 0 {
+		sv.OffsetX = 0
+	}
+	if sv.OffsetY < 0 {
+		sv.OffsetY = 0
+	}
+}
+
+// ScrollState implements Scrollable, counting the rows or the columns of
+// Content.
+func (sv *ScrollView) ScrollState(vertical bool) (total, shown, offset int) {
+	sv.RLock()
+	defer sv.RUnlock()
+	if vertical {
+		return sv.content.Y, sv.view.Y, sv.OffsetY
+	}
+	return sv.content.X, sv.view.X, sv.OffsetX
+}
+
+// SetScrollOffset implements Scrollable.
+func (sv *ScrollView) SetScrollOffset(vertical bool, offset int) {
+	sv.Lock()
+	defer sv.Unlock()
+	if vertical {
+		sv.OffsetY = offset
+	} else {
+		sv.OffsetX = offset
+	}
+	sv.clamp()
+}
+
+// scroll moves the view by dx columns and dy rows.
+func (sv *ScrollView) scroll(dx, dy int) bool {
+	sv.Lock()
+	defer sv.Unlock()
+	x, y := sv.OffsetX, sv.OffsetY
+	sv.OffsetX += dx
+	sv.OffsetY += dy
+	sv.clamp()
+	return x != sv.OffsetX || y != sv.OffsetY
+}
+
+// HandleKey scrolls by a cell with the arrow keys, by a page with
+// <previous>/<next> and to the top or bottom with <home>/<end>. It reports
+// whether key was consumed.
+func (sv *ScrollView) HandleKey(key string) bool {
+	sv.RLock()
+	page, total := sv.view.Y, sv.content.Y
+	sv.RUnlock()
+	if page < 1 {
+		page = 1
+	}
+
+	switch key {
+	case KeyArrowUp:
+		sv.scroll(0, -1)
+	case KeyArrowDown:
+		sv.scroll(0, 1)
+	case KeyArrowLeft:
+		sv.scroll(-1, 0)
+	case KeyArrowRight:
+		sv.scroll(1, 0)
+	case KeyPgUp:
+		sv.scroll(0, -page)
+	case KeyPgDn:
+		sv.scroll(0, page)
+	case KeyHome:
+		sv.scroll(0, -total)
+	case KeyEnd:
+		sv.scroll(0, total)
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleMouse scrolls by WheelStep rows on a wheel notch. It returns true if
+// the view moved.
+func (sv *ScrollView) HandleMouse(m EvtMouse) bool {
+	switch m.Press {
+	case "MouseWheelUp":
+		return sv.scroll(0, -sv.WheelStep)
+	case "MouseWheelDown":
+		return sv.scroll(0, sv.WheelStep)
+	}
+	return false
+}
+
+// Buffer implements Bufferer interface.
+func (sv *ScrollView) Buffer() Buffer {
+	buf := sv.Block.Buffer()
+	sv.Lock()
+	defer sv.Unlock()
+	if sv.Content == nil {
+		return buf
+	}
+
+	// Content is not drawn where it was laid out: keep it off the hit
+	// registry and don't let it blank its old area on screen
+	cb := sv.Content.Buffer()
+	if b, ok := sv.Content.(blocker); ok {
+		k := b.block()
+		k.lock.Lock()
+		k.drawn = image.ZR
+		k.lock.Unlock()
+	}
+	sv.content = cb.Area.Size()
+	view := sv.innerArea
+	bar := sv.Scrollbar && sv.content.Y > view.Dy()
+	if bar {
+		view.Max.X--
+	}
+	sv.view = view.Size()
+	sv.clamp()
+
+	d := view.Min.Sub(cb.Area.Min).Sub(image.Pt(sv.OffsetX, sv.OffsetY))
+	for p, c := range cb.CellMap {
+		if !p.In(cb.Area) {
+			continue
+		}
+		if q := p.Add(d); q.In(view) {
+			buf.Set(q.X, q.Y, c)
+		}
+	}
+
+	if bar {
+		drawScrollbar(buf, view.Max.X, view.Min.Y, view.Dy(), sv.content.Y, sv.OffsetY, sv.BorderFg, sv.BorderBg)
+	}
+	sv.drawScrollIndicators(buf,
+		!bar && sv.OffsetY > 0, !bar && sv.OffsetY+sv.view.Y < sv.content.Y,
+		sv.OffsetX > 0, sv.OffsetX+sv.view.X < sv.content.X)
+	return buf
+}
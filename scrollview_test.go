@@ -0,0 +1,61 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScrollView(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %02d of the text", i))
+	}
+	p := NewPar(strings.Join(lines, "\n"))
+	p.Border = false
+	p.Width, p.Height = 20, 20
+	p.X, p.Y = 50, 50 // anywhere, the view does not care
+
+	sv := NewScrollView(p)
+	sv.Width, sv.Height = 12, 7
+	buf := sv.Buffer()
+	if got := bufferRow(buf, 1, 1, 11); got != "line 00 of" {
+		t.Errorf("top left: got %q", got)
+	}
+
+	sv.HandleKey(KeyPgDn)
+	sv.HandleKey(KeyArrowDown)
+	sv.HandleKey(KeyArrowRight)
+	buf = sv.Buffer()
+	if got := bufferRow(buf, 1, 1, 11); got != "ine 06 of " {
+		t.Errorf("scrolled: got %q", got)
+	}
+	if c := buf.At(0, 5); c.Ch != ScrollIndicators.Left {
+		t.Errorf("expected a left mark, got %q", c.Ch)
+	}
+
+	sv.HandleKey(KeyEnd)
+	sv.Scrollbar = true
+	buf = sv.Buffer()
+	if sv.OffsetY != 15 {
+		t.Errorf("expected the view clamped to the last page, got %d", sv.OffsetY)
+	}
+	if got := bufferRow(buf, 5, 1, 10); got != "ine 19 of" {
+		t.Errorf("bottom: got %q", got)
+	}
+	if c := buf.At(10, 5); c.Ch != '█' {
+		t.Errorf("expected the scrollbar thumb at the bottom, got %q", c.Ch)
+	}
+
+	if !sv.HandleMouse(EvtMouse{Press: "MouseWheelUp"}) || sv.OffsetY != 12 {
+		t.Errorf("expected the wheel to scroll up 3 rows, got %d", sv.OffsetY)
+	}
+	sv.ScrollTo(0, 0)
+	if sv.HandleMouse(EvtMouse{Press: "MouseWheelUp"}) {
+		t.Error("expected no move at the top")
+	}
+}
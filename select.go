@@ -0,0 +1,155 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+// Select shows the chosen one of its Options on a single row. <left> and
+// <right> pick the previous and next options, <enter> or <space> lists them
+// in a ContextMenu under it, as does a click.
+/*
+  s := termui.NewSelect("low", "normal", "high")
+  s.Selected = 1
+  s.Width = 20
+  termui.DefaultFocus.Add(s)
+*/
+type Select struct {
+	Block
+	Options  []string
+	Selected int // index of the chosen option, -1 for none
+	TextFg   Attribute
+	OnChange func(index int)
+	menu     *ContextMenu
+}
+
+// NewSelect returns a new *Select of options with current theme, the first
+// one chosen, 3 rows high so the option fits in the border.
+func NewSelect(options ...string) *Select {
+	s := &Select{Block: *NewBlock(), Options: options, menu: NewContextMenu()}
+	if len(options) == 0 {
+		s.Selected = -1
+	}
+	s.themeAttr(&s.TextFg, "select.fg")
+	s.Height = 3
+	s.Handle("/sys/mouse/left", func(e Event) {
+		if m, ok := e.Data.(EvtMouse); ok && !m.Drag {
+			s.open()
+		}
+	})
+	s.menu.Handle("/sys/mouse", func(e Event) {
+		if m, ok := e.Data.(EvtMouse); ok && s.menu.HandleMouse(m) {
+			Render(s)
+		}
+	})
+	return s
+}
+
+// SetSelected chooses option i and calls OnChange if it changed.
+func (s *Select) SetSelected(i int) {
+	s.Lock()
+	if i < 0 || i >= len(s.Options) || i == s.Selected {
+		s.Unlock()
+		return
+	}
+	s.Selected = i
+	cb := s.OnChange
+	s.Unlock()
+
+	if cb != nil {
+		cb(i)
+	}
+}
+
+// Value returns the chosen option, "" if none.
+func (s *Select) Value() string {
+	s.RLock()
+	defer s.RUnlock()
+	if s.Selected < 0 || s.Selected >= len(s.Options) {
+		return ""
+	}
+	return s.Options[s.Selected]
+}
+
+// FormValue implements FormValuer, the value is Value.
+func (s *Select) FormValue() interface{} {
+	return s.Value()
+}
+
+// open lists the options in the menu, under the row of the chosen one.
+func (s *Select) open() {
+	r := s.Bounds()
+	s.RLock()
+	items := make([]MenuItem, len(s.Options))
+	for i, o := range s.Options {
+		i := i
+		items[i] = MenuItem{Label: o, OnSelect: func() { s.SetSelected(i) }}
+	}
+	s.RUnlock()
+	if len(items) == 0 {
+		return
+	}
+	s.menu.Lock()
+	s.menu.Items = items
+	s.menu.Unlock()
+	s.menu.Open(r.Min.X, r.Max.Y-1)
+}
+
+// HandleKey picks an option with <left> and <right> and opens the list of
+// options with <enter> or <space>, passing the keys to it while it is
+// open. It reports whether key was consumed.
+func (s *Select) HandleKey(key string) bool {
+	if s.menu.IsOpen() {
+		if key == KeyTab || key == KeyBacktab {
+			s.menu.Close()
+			return false
+		}
+		// the list is modal
+		s.menu.HandleKey(key)
+		return true
+	}
+	s.RLock()
+	i, n := s.Selected, len(s.Options)
+	s.RUnlock()
+	switch key {
+	case KeyArrowLeft:
+		s.SetSelected(i - 1)
+	case KeyArrowRight:
+		s.SetSelected(i + 1)
+	case KeyEnter, KeySpace:
+		if n > 0 {
+			s.open()
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// Blur closes the list of options along with the focus.
+func (s *Select) Blur() {
+	s.Block.Blur()
+	s.menu.Close()
+}
+
+// Buffer implements Bufferer interface.
+func (s *Select) Buffer() Buffer {
+	buf := s.Block.Buffer()
+	v := s.Value()
+	s.RLock()
+	defer s.RUnlock()
+	in := s.innerArea
+	if in.Empty() {
+		return buf
+	}
+
+	cs := fitCells(TextCells(v, s.TextFg, s.Bg), in.Dx()-2)
+	x := in.Min.X
+	for _, c := range cs {
+		buf.Set(x, in.Min.Y, c)
+		x += c.Width()
+	}
+	if in.Dx() > 2 {
+		buf.Set(in.Max.X-1, in.Min.Y, Cell{'▾', s.TextFg, s.Bg})
+	}
+	return buf
+}
@@ -0,0 +1,418 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Snapshot is a capture of cells, e.g. of the screen, that can be exported
+// as plain text, ANSI-escaped text, HTML or a PNG image, for documentation
+// screenshots or bug reports.
+/*
+  termui.Render(dashboard...)
+  s := termui.ScreenSnapshot()
+  f, _ := os.Create("dashboard.png")
+  s.WritePNG(f)
+  f.Close()
+*/
+type Snapshot struct {
+	Buffer
+}
+
+// ScreenSnapshot captures what the viewport shows, as left by the renders
+// so far, overlays included.
+func ScreenSnapshot() Snapshot {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	vp := viewportRect()
+	buf := NewBuffer()
+	buf.SetArea(image.Rect(0, 0, vp.Dx(), vp.Dy()))
+	for p, c := range screen.cells {
+		if p.In(vp) {
+			buf.CellMap[p.Sub(vp.Min)] = c
+		}
+	}
+	return Snapshot{buf}
+}
+
+// NewSnapshot captures bs composed the way Render would draw them, without
+// touching the terminal. Like Render it takes the render lock, so it must
+// not be called from an Update function.
+func NewSnapshot(bs ...Bufferer) Snapshot {
+	renderLock.Lock()
+	defer renderLock.Unlock()
+	return Snapshot{composeBufferers(bs)}
+}
+
+// cells calls fn for every glyph of row y in the Area, cells never drawn
+// being blank and the covered half of a wide glyph skipped.
+func (s Snapshot) cells(y int, fn func(x int, c Cell)) {
+	for x := s.Area.Min.X; x < s.Area.Max.X; x++ {
+		c, ok := s.CellMap[image.Pt(x, y)]
+		switch {
+		case !ok:
+			c = Cell{' ', ColorDefault, ColorDefault}
+		case c.Ch == wideCont:
+			continue
+		}
+		fn(x, c)
+	}
+}
+
+// Text returns the rows of the snapshot, trailing spaces removed, each
+// ending with '\n'.
+func (s Snapshot) Text() string {
+	var sb strings.Builder
+	for y := s.Area.Min.Y; y < s.Area.Max.Y; y++ {
+		var l strings.Builder
+		s.cells(y, func(_ int, c Cell) {
+			l.WriteString(runesToStr([]rune{c.Ch}))
+		})
+		sb.WriteString(strings.TrimRight(l.String(), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// ANSI returns the rows of the snapshot in colour, as SGR escape sequences
+// a terminal or `less -R` shows, each row ending with a reset and '\n'.
+func (s Snapshot) ANSI() string {
+	var sb strings.Builder
+	for y := s.Area.Min.Y; y < s.Area.Max.Y; y++ {
+		fg, bg := ColorDefault, ColorDefault
+		s.cells(y, func(_ int, c Cell) {
+			if c.Fg != fg || c.Bg != bg {
+				fg, bg = c.Fg, c.Bg
+				sb.WriteString(sgrOf(fg, bg))
+			}
+			sb.WriteString(runesToStr([]rune{c.Ch}))
+		})
+		sb.WriteString("\x1b[0m\n")
+	}
+	return sb.String()
+}
+
+// sgrOf returns the SGR sequence drawing in fg on bg.
+func sgrOf(fg, bg Attribute) string {
+	ps := []string{"0"}
+	if fg&AttrBold != 0 {
+		ps = append(ps, "1")
+	}
+	if fg&AttrUnderline != 0 {
+		ps = append(ps, "4")
+	}
+	if (fg|bg)&AttrReverse != 0 {
+		ps = append(ps, "7")
+	}
+	if p := sgrColor(fg, 30); p != "" {
+		ps = append(ps, p)
+	}
+	if p := sgrColor(bg, 40); p != "" {
+		ps = append(ps, p)
+	}
+	return "\x1b[" + strings.Join(ps, ";") + "m"
+}
+
+// sgrColor returns the SGR parameters of the colour of a, base being 30
+// for the foreground and 40 for the background, "" for ColorDefault.
+func sgrColor(a Attribute, base int) string {
+	if a&attrTrueColor != 0 {
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, a>>48&0xFF, a>>40&0xFF, a>>32&0xFF)
+	}
+	switch n := int(a & 0x1FF); {
+	case n == 0 || n > 256:
+		return ""
+	case n <= 8:
+		return fmt.Sprint(base + n - 1)
+	default:
+		return fmt.Sprintf("%d;5;%d", base+8, n-1)
+	}
+}
+
+// The colours ColorDefault stands for in HTML and PNG snapshots.
+var (
+	SnapshotFg = ColorRGB24(229, 229, 229)
+	SnapshotBg = ColorRGB24(0, 0, 0)
+)
+
+// snapColors returns the colours c is drawn in, reverse video applied.
+func snapColors(c Cell) (fg, bg rgb) {
+	fg, ok := attrRGB(c.Fg)
+	if !ok {
+		fg, _ = attrRGB(SnapshotFg)
+	}
+	bg, ok = attrRGB(c.Bg)
+	if !ok {
+		bg, _ = attrRGB(SnapshotBg)
+	}
+	if (c.Fg|c.Bg)&AttrReverse != 0 {
+		fg, bg = bg, fg
+	}
+	return fg, bg
+}
+
+func (c rgb) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", int(c.r), int(c.g), int(c.b))
+}
+
+// HTML returns the snapshot as a <pre> element, each run of cells of the
+// same colours in a <span> styled inline.
+func (s Snapshot) HTML() string {
+	var sb strings.Builder
+	_, bg := snapColors(Cell{})
+	fmt.Fprintf(&sb, "<pre style=\"background:%s;font-family:monospace;line-height:1.2\">", bg.hex())
+	for y := s.Area.Min.Y; y < s.Area.Max.Y; y++ {
+		var run strings.Builder
+		var style string
+		flush := func() {
+			if run.Len() > 0 {
+				fmt.Fprintf(&sb, "<span style=\"%s\">%s</span>", style, html.EscapeString(run.String()))
+				run.Reset()
+			}
+		}
+		s.cells(y, func(_ int, c Cell) {
+			fg, bg := snapColors(c)
+			st := "color:" + fg.hex() + ";background:" + bg.hex()
+			if c.Fg&AttrBold != 0 {
+				st += ";font-weight:bold"
+			}
+			if c.Fg&AttrUnderline != 0 {
+				st += ";text-decoration:underline"
+			}
+			if st != style {
+				flush()
+				style = st
+			}
+			run.WriteString(runesToStr([]rune{c.Ch}))
+		})
+		flush()
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("</pre>")
+	return sb.String()
+}
+
+// The size in pixels of a cell of a PNG snapshot, the glyphs of the bundled
+// 5x7 font being drawn at twice their size.
+const (
+	snapScale = 2
+	snapCellW = 6 * snapScale
+	snapCellH = 10 * snapScale
+)
+
+// Image returns the snapshot drawn with the bundled monospace font, ASCII,
+// box drawing, block elements and braille being drawn as such and other
+// glyphs as a box.
+func (s Snapshot) Image() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, s.Area.Dx()*snapCellW, s.Area.Dy()*snapCellH))
+	for y := s.Area.Min.Y; y < s.Area.Max.Y; y++ {
+		s.cells(y, func(x int, c Cell) {
+			fg, bg := snapColors(c)
+			r := image.Rect(0, 0, snapCellW*c.Width(), snapCellH).Add(image.Pt(
+				(x-s.Area.Min.X)*snapCellW, (y-s.Area.Min.Y)*snapCellH))
+			drawGlyph(img, r, c, fg.rgba(), bg.rgba())
+		})
+	}
+	return img
+}
+
+// WritePNG writes the snapshot as a PNG image, see Image.
+func (s Snapshot) WritePNG(w io.Writer) error {
+	return png.Encode(w, s.Image())
+}
+
+func (c rgb) rgba() color.RGBA {
+	return color.RGBA{uint8(c.r), uint8(c.g), uint8(c.b), 0xff}
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawGlyph draws c in fg on bg over r.
+func drawGlyph(img *image.RGBA, r image.Rectangle, c Cell, fg, bg color.RGBA) {
+	fillRect(img, r, bg)
+	ch := clusterOf(c.Ch)[0]
+	w, h := r.Dx(), r.Dy()
+	switch {
+	case ch == ' ':
+	case ch > ' ' && ch < 0x7f:
+		g := snapFont[ch-' ']
+		bold := 0
+		if c.Fg&AttrBold != 0 {
+			bold = 1
+		}
+		for i, col := range g {
+			for j := 0; j < 7; j++ {
+				if col&(1<<uint(j)) == 0 {
+					continue
+				}
+				p := r.Min.Add(image.Pt(i*snapScale, (j+1)*snapScale))
+				fillRect(img, image.Rectangle{p, p.Add(image.Pt(snapScale+bold, snapScale))}, fg)
+			}
+		}
+	case boxArms[ch] != 0:
+		a := boxArms[ch]
+		cx, cy := r.Min.X+w/2, r.Min.Y+h/2
+		t := snapScale / 2
+		if a&armUp != 0 {
+			fillRect(img, image.Rect(cx-t, r.Min.Y, cx+t, cy+t), fg)
+		}
+		if a&armDown != 0 {
+			fillRect(img, image.Rect(cx-t, cy-t, cx+t, r.Max.Y), fg)
+		}
+		if a&armLeft != 0 {
+			fillRect(img, image.Rect(r.Min.X, cy-t, cx+t, cy+t), fg)
+		}
+		if a&armRight != 0 {
+			fillRect(img, image.Rect(cx-t, cy-t, r.Max.X, cy+t), fg)
+		}
+	case ch == '▀':
+		fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+h/2), fg)
+	case ch >= '▁' && ch <= '█': // lower eighths
+		n := int(ch - '▀')
+		fillRect(img, image.Rect(r.Min.X, r.Max.Y-h*n/8, r.Max.X, r.Max.Y), fg)
+	case ch >= '▉' && ch <= '▏': // left eighths
+		n := int('▐' - ch)
+		fillRect(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+w*n/8, r.Max.Y), fg)
+	case ch == '▐':
+		fillRect(img, image.Rect(r.Min.X+w/2, r.Min.Y, r.Max.X, r.Max.Y), fg)
+	case ch >= '░' && ch <= '▓':
+		t := float64(ch-'░'+1) / 4
+		mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*t) }
+		fillRect(img, r, color.RGBA{mix(bg.R, fg.R), mix(bg.G, fg.G), mix(bg.B, fg.B), 0xff})
+	case ch >= brailleBase && ch < brailleBase+0x100:
+		bits := ch - brailleBase
+		for y, row := range brailleOftMap {
+			for x, bit := range row {
+				if bits&bit == 0 {
+					continue
+				}
+				p := r.Min.Add(image.Pt(w*(2*x+1)/4, h*(2*y+1)/8))
+				fillRect(img, image.Rect(p.X-snapScale, p.Y-snapScale, p.X+snapScale, p.Y+snapScale), fg)
+			}
+		}
+	default:
+		// an outline for what the font lacks
+		in := image.Rect(r.Min.X+snapScale, r.Min.Y+2*snapScale, r.Max.X-snapScale, r.Max.Y-2*snapScale)
+		fillRect(img, in, fg)
+		fillRect(img, in.Inset(snapScale), bg)
+	}
+	if c.Fg&AttrUnderline != 0 {
+		fillRect(img, image.Rect(r.Min.X, r.Max.Y-snapScale, r.Max.X, r.Max.Y), fg)
+	}
+}
+
+// snapFont is a 5x7 font of the printable ASCII runes, a byte per column
+// left to right, bit 0 being the top row.
+var snapFont = [95][5]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00}, // ' '
+	{0x00, 0x00, 0x5f, 0x00, 0x00}, // !
+	{0x00, 0x07, 0x00, 0x07, 0x00}, // "
+	{0x14, 0x7f, 0x14, 0x7f, 0x14}, // #
+	{0x24, 0x2a, 0x7f, 0x2a, 0x12}, // $
+	{0x23, 0x13, 0x08, 0x64, 0x62}, // %
+	{0x36, 0x49, 0x55, 0x22, 0x50}, // &
+	{0x00, 0x05, 0x03, 0x00, 0x00}, // '
+	{0x00, 0x1c, 0x22, 0x41, 0x00}, // (
+	{0x00, 0x41, 0x22, 0x1c, 0x00}, // )
+	{0x14, 0x08, 0x3e, 0x08, 0x14}, // *
+	{0x08, 0x08, 0x3e, 0x08, 0x08}, // +
+	{0x00, 0x50, 0x30, 0x00, 0x00}, // ,
+	{0x08, 0x08, 0x08, 0x08, 0x08}, // -
+	{0x00, 0x60, 0x60, 0x00, 0x00}, // .
+	{0x20, 0x10, 0x08, 0x04, 0x02}, // /
+	{0x3e, 0x51, 0x49, 0x45, 0x3e}, // 0
+	{0x00, 0x42, 0x7f, 0x40, 0x00}, // 1
+	{0x42, 0x61, 0x51, 0x49, 0x46}, // 2
+	{0x21, 0x41, 0x45, 0x4b, 0x31}, // 3
+	{0x18, 0x14, 0x12, 0x7f, 0x10}, // 4
+	{0x27, 0x45, 0x45, 0x45, 0x39}, // 5
+	{0x3c, 0x4a, 0x49, 0x49, 0x30}, // 6
+	{0x01, 0x71, 0x09, 0x05, 0x03}, // 7
+	{0x36, 0x49, 0x49, 0x49, 0x36}, // 8
+	{0x06, 0x49, 0x49, 0x29, 0x1e}, // 9
+	{0x00, 0x36, 0x36, 0x00, 0x00}, // :
+	{0x00, 0x56, 0x36, 0x00, 0x00}, // ;
+	{0x08, 0x14, 0x22, 0x41, 0x00}, // <
+	{0x14, 0x14, 0x14, 0x14, 0x14}, // =
+	{0x00, 0x41, 0x22, 0x14, 0x08}, // >
+	{0x02, 0x01, 0x51, 0x09, 0x06}, // ?
+	{0x32, 0x49, 0x79, 0x41, 0x3e}, // @
+	{0x7e, 0x11, 0x11, 0x11, 0x7e}, // A
+	{0x7f, 0x49, 0x49, 0x49, 0x36}, // B
+	{0x3e, 0x41, 0x41, 0x41, 0x22}, // C
+	{0x7f, 0x41, 0x41, 0x22, 0x1c}, // D
+	{0x7f, 0x49, 0x49, 0x49, 0x41}, // E
+	{0x7f, 0x09, 0x09, 0x09, 0x01}, // F
+	{0x3e, 0x41, 0x49, 0x49, 0x7a}, // G
+	{0x7f, 0x08, 0x08, 0x08, 0x7f}, // H
+	{0x00, 0x41, 0x7f, 0x41, 0x00}, // I
+	{0x20, 0x40, 0x41, 0x3f, 0x01}, // J
+	{0x7f, 0x08, 0x14, 0x22, 0x41}, // K
+	{0x7f, 0x40, 0x40, 0x40, 0x40}, // L
+	{0x7f, 0x02, 0x0c, 0x02, 0x7f}, // M
+	{0x7f, 0x04, 0x08, 0x10, 0x7f}, // N
+	{0x3e, 0x41, 0x41, 0x41, 0x3e}, // O
+	{0x7f, 0x09, 0x09, 0x09, 0x06}, // P
+	{0x3e, 0x41, 0x51, 0x21, 0x5e}, // Q
+	{0x7f, 0x09, 0x19, 0x29, 0x46}, // R
+	{0x46, 0x49, 0x49, 0x49, 0x31}, // S
+	{0x01, 0x01, 0x7f, 0x01, 0x01}, // T
+	{0x3f, 0x40, 0x40, 0x40, 0x3f}, // U
+	{0x1f, 0x20, 0x40, 0x20, 0x1f}, // V
+	{0x3f, 0x40, 0x38, 0x40, 0x3f}, // W
+	{0x63, 0x14, 0x08, 0x14, 0x63}, // X
+	{0x07, 0x08, 0x70, 0x08, 0x07}, // Y
+	{0x61, 0x51, 0x49, 0x45, 0x43}, // Z
+	{0x00, 0x7f, 0x41, 0x41, 0x00}, // [
+	{0x02, 0x04, 0x08, 0x10, 0x20}, // \
+	{0x00, 0x41, 0x41, 0x7f, 0x00}, // ]
+	{0x04, 0x02, 0x01, 0x02, 0x04}, // ^
+	{0x40, 0x40, 0x40, 0x40, 0x40}, // _
+	{0x00, 0x01, 0x02, 0x04, 0x00}, // `
+	{0x20, 0x54, 0x54, 0x54, 0x78}, // a
+	{0x7f, 0x48, 0x44, 0x44, 0x38}, // b
+	{0x38, 0x44, 0x44, 0x44, 0x20}, // c
+	{0x38, 0x44, 0x44, 0x48, 0x7f}, // d
+	{0x38, 0x54, 0x54, 0x54, 0x18}, // e
+	{0x08, 0x7e, 0x09, 0x01, 0x02}, // f
+	{0x0c, 0x52, 0x52, 0x52, 0x3e}, // g
+	{0x7f, 0x08, 0x04, 0x04, 0x78}, // h
+	{0x00, 0x44, 0x7d, 0x40, 0x00}, // i
+	{0x20, 0x40, 0x44, 0x3d, 0x00}, // j
+	{0x7f, 0x10, 0x28, 0x44, 0x00}, // k
+	{0x00, 0x41, 0x7f, 0x40, 0x00}, // l
+	{0x7c, 0x04, 0x18, 0x04, 0x78}, // m
+	{0x7c, 0x08, 0x04, 0x04, 0x78}, // n
+	{0x38, 0x44, 0x44, 0x44, 0x38}, // o
+	{0x7c, 0x14, 0x14, 0x14, 0x08}, // p
+	{0x08, 0x14, 0x14, 0x18, 0x7c}, // q
+	{0x7c, 0x08, 0x04, 0x04, 0x08}, // r
+	{0x48, 0x54, 0x54, 0x54, 0x20}, // s
+	{0x04, 0x3f, 0x44, 0x40, 0x20}, // t
+	{0x3c, 0x40, 0x40, 0x20, 0x7c}, // u
+	{0x1c, 0x20, 0x40, 0x20, 0x1c}, // v
+	{0x3c, 0x40, 0x30, 0x40, 0x3c}, // w
+	{0x44, 0x28, 0x10, 0x28, 0x44}, // x
+	{0x0c, 0x50, 0x50, 0x50, 0x3c}, // y
+	{0x44, 0x64, 0x54, 0x4c, 0x44}, // z
+	{0x00, 0x08, 0x36, 0x41, 0x00}, // {
+	{0x00, 0x00, 0x7f, 0x00, 0x00}, // |
+	{0x00, 0x41, 0x36, 0x08, 0x00}, // }
+	{0x08, 0x04, 0x08, 0x10, 0x08}, // ~
+}
@@ -0,0 +1,63 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	p := NewPar("a<b")
+	p.Width, p.Height = 5, 3
+	p.TextFgColor = ColorRed
+	s := NewSnapshot(p)
+
+	if got := s.Text(); got != "┌───┐\n│a<b│\n└───┘\n" {
+		t.Errorf("unexpected text %q", got)
+	}
+	if got := s.ANSI(); !strings.Contains(got, "\x1b[0;31ma<b") {
+		t.Errorf("expected the text in red, got %q", got)
+	}
+	if got := s.HTML(); !strings.Contains(got, "color:#cd0000;background:#000000\">a&lt;b</span>") {
+		t.Errorf("expected the text escaped in red, got %q", got)
+	}
+
+	var out bytes.Buffer
+	if err := s.WritePNG(&out); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 5*snapCellW || b.Dy() != 3*snapCellH {
+		t.Errorf("expected a pixel image of 5x3 cells, got %v", b)
+	}
+	// the middle of the top border, and the space above the 'a'
+	white := color.RGBAModel.Convert(img.At(snapCellW+2, snapCellH/2))
+	if white != (color.RGBA{229, 229, 229, 255}) {
+		t.Errorf("expected the border drawn, got %v", white)
+	}
+	if c := color.RGBAModel.Convert(img.At(snapCellW+1, snapCellH)); c != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("expected the background, got %v", c)
+	}
+}
+
+func TestScreenSnapshot(t *testing.T) {
+	_, done := useFakeBackend(6, 2)
+	defer done()
+
+	p := NewPar("hi")
+	p.Border = false
+	p.X, p.Y, p.Width, p.Height = 2, 1, 2, 1
+	Render(p)
+	if got := ScreenSnapshot().Text(); got != "\n  hi\n" {
+		t.Errorf("unexpected screen %q", got)
+	}
+}
@@ -4,23 +4,103 @@
 
 package termui
 
-// Sparkline is like: ▅▆▂▂▅▇▂▂▃▆▆▆▅▃. The data points should be non-negative integers.
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Sparkline is like: ▅▆▂▂▅▇▂▂▃▆▆▆▅▃. Bars grow up from Baseline, and the
+// rows are shared with bars growing down from it when some data points
+// are below. Thresholds colour each point by value, and ShowExtremes
+// annotates the title row with the highest and lowest points shown,
+// drawing them in ExtremesColor.
 /*
   data := []int{4, 2, 1, 6, 3, 9, 1, 4, 2, 15, 14, 9, 8, 6, 10, 13, 15, 12, 10, 5, 3, 6, 1}
   spl := termui.NewSparkline()
   spl.Data = data
   spl.Title = "Sparkline 0"
   spl.LineColor = termui.ColorGreen
+  spl.Thresholds = []termui.Threshold{{10, termui.ColorGreen}}
+  spl.ShowExtremes = true
 */
 type Sparkline struct {
 	Data          []int
+	Times         []time.Time   // optional timestamps of Data, enables the time axis
+	Window        time.Duration // time span shown when Times is set, zero fits all samples
+	GapThreshold  time.Duration // samples farther apart are not connected, zero never breaks
 	Height        int
 	Title         string
 	TitleColor    Attribute
 	LineColor     Attribute
+	Baseline      int         // the value bars grow from, up or down
+	Thresholds    []Threshold // the colour of a point by value, LineColor above them
+	ShowExtremes  bool        // annotate the highest and lowest points shown
+	ExtremesColor Attribute
 	displayHeight int
-	scale         float32
-	max           int
+	lo, hi        int // the range of the data, Baseline included
+}
+
+// sparkGap marks the columns of a Sparkline with no data, see columns.
+const sparkGap = math.MinInt32
+
+// columns returns the value plotted in each of the w columns. Without Times
+// the last w samples are evenly spaced, otherwise samples are placed by
+// timestamp and each holds until the next one, except across gaps longer
+// than GapThreshold which stay blank (sparkGap).
+func (sl Sparkline) columns(w int) []int {
+	if sl.Times == nil {
+		data := sl.Data
+		if len(data) > w {
+			data = data[len(data)-w:]
+		}
+		return data
+	}
+
+	n := len(sl.Data)
+	if len(sl.Times) < n {
+		n = len(sl.Times)
+	}
+	cols := make([]int, w)
+	for i := range cols {
+		cols[i] = sparkGap
+	}
+	if n == 0 || w <= 0 {
+		return cols
+	}
+
+	end := sl.Times[n-1]
+	start := sl.Times[0]
+	if sl.Window > 0 {
+		start = end.Add(-sl.Window)
+	}
+	span := end.Sub(start)
+	col := func(t time.Time) int {
+		if span <= 0 {
+			return w - 1
+		}
+		return int(int64(t.Sub(start)) * int64(w-1) / int64(span))
+	}
+
+	prev := -1
+	for i := 0; i < n; i++ {
+		t := sl.Times[i]
+		if t.Before(start) {
+			continue
+		}
+		x := col(t)
+		if prev >= 0 && x > prev+1 {
+			gap := sl.GapThreshold > 0 && t.Sub(sl.Times[i-1]) > sl.GapThreshold
+			for j := prev + 1; j < x; j++ {
+				if !gap {
+					cols[j] = cols[prev]
+				}
+			}
+		}
+		cols[x] = sl.Data[i]
+		prev = x
+	}
+	return cols
 }
 
 // Sparklines is a renderable widget which groups together the given sparklines.
@@ -46,9 +126,10 @@ func (s *Sparklines) Add(sl Sparkline) {
 // NewSparkline returns a unrenderable single sparkline that intended to be added into Sparklines.
 func NewSparkline() Sparkline {
 	return Sparkline{
-		Height:     1,
-		TitleColor: ThemeAttr("sparkline.title.fg"),
-		LineColor:  ThemeAttr("sparkline.line.fg")}
+		Height:        1,
+		TitleColor:    ThemeAttr("sparkline.title.fg"),
+		LineColor:     ThemeAttr("sparkline.line.fg"),
+		ExtremesColor: ThemeAttr("sparkline.extremes.fg")}
 }
 
 // NewSparklines return a new *Spaklines with given Sparkline(s), you can always add a new Sparkline later.
@@ -57,11 +138,22 @@ func NewSparklines(ss ...Sparkline) *Sparklines {
 	return s
 }
 
+// restyle moves the lines to the current theme, see SetTheme.
+func (s *Sparklines) restyle(old map[string]Attribute) {
+	s.Lock()
+	defer s.Unlock()
+	for i := range s.Lines {
+		restyleAttr(&s.Lines[i].TitleColor, "sparkline.title.fg", 0, old)
+		restyleAttr(&s.Lines[i].LineColor, "sparkline.line.fg", 0, old)
+		restyleAttr(&s.Lines[i].ExtremesColor, "sparkline.extremes.fg", 0, old)
+	}
+}
+
 func (sl *Sparklines) update() {
 	sl.Lock()
 	defer sl.Unlock()
 	for i, v := range sl.Lines {
-		if v.Title == "" {
+		if v.Title == "" && !v.ShowExtremes {
 			sl.Lines[i].displayHeight = v.Height
 		} else {
 			sl.Lines[i].displayHeight = v.Height + 1
@@ -82,21 +174,35 @@ func (sl *Sparklines) update() {
 	}
 
 	for i := 0; i < sl.displayLines; i++ {
-		data := sl.Lines[i].Data
-
-		max := 0
-		for _, v := range data {
-			if max < v {
-				max = v
+		l := &sl.Lines[i]
+		l.lo, l.hi = l.Baseline, l.Baseline
+		for _, v := range l.Data {
+			if v > l.hi {
+				l.hi = v
 			}
+			if v < l.lo {
+				l.lo = v
+			}
+		}
+	}
+}
+
+// extremes returns the columns of the highest and lowest points of data,
+// -1 when there is none.
+func extremes(data []int) (max, min int) {
+	max, min = -1, -1
+	for j, v := range data {
+		if v == sparkGap {
+			continue
+		}
+		if max < 0 || v > data[max] {
+			max = j
 		}
-		sl.Lines[i].max = max
-		if max != 0 {
-			sl.Lines[i].scale = float32(8*sl.Lines[i].Height) / float32(max)
-		} else { // when all negative
-			sl.Lines[i].scale = 0
+		if min < 0 || v < data[min] {
+			min = j
 		}
 	}
+	return max, min
 }
 
 // Buffer implements Bufferer interface.
@@ -109,11 +215,7 @@ func (sl *Sparklines) Buffer() Buffer {
 	oftY := 0
 	for i := 0; i < sl.displayLines; i++ {
 		l := sl.Lines[i]
-		data := l.Data
-
-		if len(data) > sl.innerArea.Dx() {
-			data = data[len(data)-sl.innerArea.Dx():]
-		}
+		data := l.columns(sl.innerArea.Dx())
 
 		if l.Title != "" {
 			rs := trimStr2Runes(l.Title, sl.innerArea.Dx())
@@ -132,35 +234,55 @@ func (sl *Sparklines) Buffer() Buffer {
 			}
 		}
 
+		maxCol, minCol := extremes(data)
+		if l.ShowExtremes && maxCol >= 0 {
+			ann := str2runes(fmt.Sprintf("▲%d ▼%d", data[maxCol], data[minCol]))
+			x := sl.innerArea.Max.X - runesWidth(ann)
+			for _, r := range ann {
+				c := Cell{r, l.ExtremesColor, sl.Bg}
+				buf.Set(x, sl.innerArea.Min.Y+oftY, c)
+				x += c.Width()
+			}
+		}
+
+		// the rows below the baseline, in proportion to the data range
+		down := 0
+		if l.hi > l.lo {
+			down = int(float64(l.Height)*float64(l.Baseline-l.lo)/float64(l.hi-l.lo) + 0.5)
+		}
+		up := l.Height - down
+		baseY := sl.innerArea.Min.Y + oftY + l.Height - down
 		for j, v := range data {
-			// display height of the data point, zero when data is negative
-			h := int(float32(v)*l.scale + 0.5)
-			if v < 0 {
-				h = 0
+			if v == sparkGap {
+				continue
 			}
+			fg := ThresholdColor(l.Thresholds, float64(v), l.LineColor)
+			if l.ShowExtremes && (j == maxCol || j == minCol) {
+				fg = l.ExtremesColor
+			}
+			x := sl.innerArea.Min.X + j
 
-			barCnt := h / 8
-			barMod := h % 8
-			for jj := 0; jj < barCnt; jj++ {
-				c := Cell{
-					Ch: ' ', // => sparks[7]
-					Bg: l.LineColor,
+			// display height of the data point in eighths of a cell
+			if v >= l.Baseline {
+				h := 0
+				if l.hi > l.Baseline {
+					h = int(float64(v-l.Baseline)*float64(8*up)/float64(l.hi-l.Baseline) + 0.5)
 				}
-				x := sl.innerArea.Min.X + j
-				y := sl.innerArea.Min.Y + oftY + l.Height - jj
-
-				//p.Bg = sl.BgColor
-				buf.Set(x, y, c)
-			}
-			if barMod != 0 {
-				c := Cell{
-					Ch: sparks[barMod-1],
-					Fg: l.LineColor,
-					Bg: sl.Bg,
+				for jj := 0; jj < h/8; jj++ {
+					buf.Set(x, baseY-jj, Cell{' ', ColorDefault, fg}) // => sparks[7]
 				}
-				x := sl.innerArea.Min.X + j
-				y := sl.innerArea.Min.Y + oftY + l.Height - barCnt
-				buf.Set(x, y, c)
+				if h%8 != 0 {
+					buf.Set(x, baseY-h/8, Cell{sparks[h%8-1], fg, sl.Bg})
+				}
+				continue
+			}
+			h := int(float64(l.Baseline-v)*float64(8*down)/float64(l.Baseline-l.lo) + 0.5)
+			for jj := 0; jj < h/8; jj++ {
+				buf.Set(x, baseY+1+jj, Cell{' ', ColorDefault, fg})
+			}
+			if h%8 != 0 {
+				// the top of the cell, as the reverse of its bottom
+				buf.Set(x, baseY+1+h/8, Cell{sparks[7-h%8], fg, sl.Bg | AttrReverse})
 			}
 		}
 
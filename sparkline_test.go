@@ -0,0 +1,82 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSparklineColumns(t *testing.T) {
+	t0 := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(secs ...int) []time.Time {
+		ts := make([]time.Time, len(secs))
+		for i, s := range secs {
+			ts[i] = t0.Add(time.Duration(s) * time.Second)
+		}
+		return ts
+	}
+
+	sl := NewSparkline()
+	sl.Data = []int{1, 2, 3, 4, 5, 6}
+	if cs := sl.columns(4); !reflect.DeepEqual(cs, []int{3, 4, 5, 6}) {
+		t.Errorf("even spacing should keep the last samples, got %v", cs)
+	}
+
+	sl.Data = []int{1, 2, 3}
+	sl.Times = at(0, 1, 9)
+	if cs := sl.columns(10); !reflect.DeepEqual(cs, []int{1, 2, 2, 2, 2, 2, 2, 2, 2, 3}) {
+		t.Errorf("samples should be placed by time, got %v", cs)
+	}
+
+	sl.GapThreshold = 5 * time.Second
+	if cs := sl.columns(10); !reflect.DeepEqual(cs, []int{1, 2, sparkGap, sparkGap, sparkGap, sparkGap, sparkGap, sparkGap, sparkGap, 3}) {
+		t.Errorf("long gaps should stay blank, got %v", cs)
+	}
+
+	sl.Window = 4 * time.Second
+	if cs := sl.columns(5); !reflect.DeepEqual(cs, []int{sparkGap, sparkGap, sparkGap, sparkGap, 3}) {
+		t.Errorf("samples outside the window should be dropped, got %v", cs)
+	}
+}
+
+func TestSparklineBaseline(t *testing.T) {
+	sl := NewSparkline()
+	sl.Height = 2
+	sl.Data = []int{4, -4, 2}
+	sl.LineColor = ColorGreen
+	sl.Thresholds = []Threshold{{0, ColorRed}}
+	sl.ShowExtremes = true
+	sl.ExtremesColor = ColorYellow
+	sls := NewSparklines(sl)
+	sls.Border = false
+	sls.Width, sls.Height = 8, 3
+	buf := sls.Buffer()
+
+	// the annotation row, a row above the baseline and one below
+	ann := ""
+	for x := 2; x < 8; x++ {
+		ann += string(buf.At(x, 0).Ch)
+	}
+	if ann != "▲4 ▼-4" {
+		t.Errorf("expected the extremes annotated, got %q", ann)
+	}
+	if c := buf.At(0, 1); c.Bg != ColorYellow {
+		t.Errorf("expected the highest point marked, got %+v", c)
+	}
+	if c := buf.At(1, 2); c.Bg != ColorYellow || buf.At(1, 1).Bg != ColorDefault {
+		t.Errorf("expected the lowest point below the baseline, got %+v", c)
+	}
+	if c := buf.At(2, 1); c.Ch != '▄' || c.Fg != ColorGreen {
+		t.Errorf("expected a half bar in LineColor, got %+v", c)
+	}
+
+	sls.Lines[0].ShowExtremes = false
+	sls.Lines[0].Height = 3
+	if c := sls.Buffer().At(1, 2); c.Bg != ColorRed {
+		t.Errorf("expected the threshold colour under 0, got %+v", c)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"sync"
+	"time"
+)
+
+// Frame sets for Spinner.Frames.
+var (
+	SpinnerDots    = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerLine    = []string{"-", "\\", "|", "/"}
+	SpinnerBraille = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+)
+
+// Spinner shows a "working…" indicator, its frames following each other
+// every Interval next to Label while it runs, see Start. A stopped spinner
+// shows Label alone.
+/*
+  s := termui.NewSpinner()
+  s.Label = "Fetching…"
+  s.Frames = termui.SpinnerLine
+  s.Start()
+  defer s.Stop()
+*/
+type Spinner struct {
+	Block
+	Frames    []string
+	Interval  time.Duration
+	Label     string
+	SpinnerFg Attribute
+	LabelFg   Attribute
+	frame     int
+	ticking   string // path of the timer events animating it, "" when stopped
+}
+
+// NewSpinner returns a new *Spinner with current theme.
+func NewSpinner() *Spinner {
+	s := &Spinner{
+		Block:    *NewBlock(),
+		Frames:   SpinnerDots,
+		Interval: 100 * time.Millisecond,
+	}
+	s.themeAttr(&s.SpinnerFg, "spinner.fg")
+	s.themeAttr(&s.LabelFg, "spinner.text.fg")
+	s.Width = 20
+	s.Height = 3
+	return s
+}
+
+// Start animates s until Stop.
+func (s *Spinner) Start() {
+	s.Lock()
+	defer s.Unlock()
+	if s.ticking == "" {
+		s.ticking = s.startAnim(s.Interval, s.Step, s)
+	}
+}
+
+// Stop stops the animation started by Start.
+func (s *Spinner) Stop() {
+	s.Lock()
+	defer s.Unlock()
+	s.stopAnim(s.ticking)
+	s.ticking = ""
+}
+
+// Running tells if s is animated.
+func (s *Spinner) Running() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.ticking != ""
+}
+
+// Step moves s to its next frame.
+func (s *Spinner) Step() {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.Frames) > 0 {
+		s.frame = (s.frame + 1) % len(s.Frames)
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (s *Spinner) Buffer() Buffer {
+	buf := s.Block.Buffer()
+	s.RLock()
+	defer s.RUnlock()
+
+	var cs []Cell
+	if s.ticking != "" && len(s.Frames) > 0 {
+		cs = TextCells(s.Frames[s.frame%len(s.Frames)], s.SpinnerFg, s.Bg)
+		if s.Label != "" {
+			cs = append(cs, Cell{' ', s.LabelFg, s.Bg})
+		}
+	}
+	cs = append(cs, TextCells(s.Label, s.LabelFg, s.Bg)...)
+	cs = fitCells(cs, s.innerArea.Dx())
+	for i, x := 0, s.innerArea.Min.X; i < len(cs); i++ {
+		buf.Set(x, s.innerArea.Min.Y, cs[i])
+		x += cs[i].Width()
+	}
+	return buf
+}
+
+// animTimers are the intervals of the timers merged into DefaultEvtStream
+// for animations, the /timer/1s one being merged by Init.
+var animTimers = struct {
+	sync.Mutex
+	merged map[time.Duration]bool
+}{merged: map[time.Duration]bool{time.Second: true}}
+
+// animTimer merges a timer of du into DefaultEvtStream unless there is one
+// and returns the path of its events.
+func animTimer(du time.Duration) string {
+	animTimers.Lock()
+	defer animTimers.Unlock()
+	if !animTimers.merged[du] {
+		animTimers.merged[du] = true
+		DefaultEvtStream.Merge("timer/"+du.String(), newTimerCh(du, quit))
+	}
+	return "/timer/" + du.String()
+}
+
+// startAnim makes b call step and re-render w every du, and returns the
+// path to give stopAnim.
+func (b *Block) startAnim(du time.Duration, step func(), w Bufferer) string {
+	if du <= 0 {
+		du = 100 * time.Millisecond
+	}
+	path := animTimer(du)
+	b.Handle(path, func(Event) {
+		step()
+		Render(w)
+	})
+	return path
+}
+
+// stopAnim stops the animation of b started on path.
+func (b *Block) stopAnim(path string) {
+	if path != "" {
+		DefaultWgtMgr.RmWgtHandler(b.Id(), path)
+	}
+}
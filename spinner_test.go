@@ -0,0 +1,65 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+func TestSpinner(t *testing.T) {
+	fb, done := useFakeBackend(12, 1)
+	defer done()
+
+	s := NewSpinner()
+	s.Border = false
+	s.Width, s.Height = 12, 1
+	s.Frames = SpinnerLine
+	s.Label = "wait"
+	if buf := s.Buffer(); buf.At(0, 0).Ch != 'w' {
+		t.Error("expected a stopped spinner to show its label only")
+	}
+
+	s.Start()
+	h, ok := DefaultWgtMgr[s.Id()].Handlers["/timer/100ms"]
+	if !ok || !s.Running() {
+		t.Fatal("expected the spinner to handle its animation timer")
+	}
+	h(Event{})
+	if fb.cells[image.Pt(0, 0)].Ch != '\\' || fb.cells[image.Pt(2, 0)].Ch != 'w' {
+		t.Error("expected a tick to draw the next frame")
+	}
+
+	s.Stop()
+	if _, ok := DefaultWgtMgr[s.Id()].Handlers["/timer/100ms"]; ok || s.Running() {
+		t.Error("expected Stop to drop the animation handler")
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	_, done := useFakeBackend(8, 3)
+	defer done()
+
+	pb := NewProgressBar()
+	pb.Width = 8
+	pb.BarColor = ColorRed
+	pb.SegmentWidth = 2
+	pb.IndeterminateLabel = ""
+	pb.Interval = 20 * time.Millisecond
+	if s := gaugeBar(&pb.Gauge); s != "##...." {
+		t.Fatalf("expected the segment at the start, got %s", s)
+	}
+
+	pb.Start()
+	if _, ok := DefaultWgtMgr[pb.Id()].Handlers["/timer/1s"]; ok {
+		t.Error("expected the bar not to step on /timer/1s")
+	}
+	DefaultWgtMgr[pb.Id()].Handlers["/timer/20ms"](Event{})
+	if s := gaugeBar(&pb.Gauge); s != ".##..." {
+		t.Errorf("expected a tick to move the segment, got %s", s)
+	}
+	pb.Stop()
+}
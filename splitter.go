@@ -0,0 +1,213 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+// Splitter is a draggable divider between two adjacent grid columns, or two
+// stacked grid rows when Horizontal is set. It is drawn over the last
+// column (row) of First and, when dragged with the left mouse button,
+// moves span from one column to the other, or height from one row's widget
+// to the other's, then re-aligns and renders Grid (Body if nil). Rows must
+// hold a single widget, possibly nested in single column rows.
+/*
+  left, right := ui.NewCol(6, 0, w0), ui.NewCol(6, 0, w1)
+  ui.Body.AddRows(ui.NewRow(left, right))
+  ui.Body.Align()
+  s := ui.NewSplitter(left, right)
+  ui.Render(ui.Body, s)
+*/
+type Splitter struct {
+	Block
+	First      *Row
+	Second     *Row
+	Horizontal bool
+	MinSpan    int // least span left to each column
+	MinHeight  int // least height left to each row's widget
+	Grid       *Grid
+	dragging   bool
+}
+
+// NewSplitter returns a vertical *Splitter between the columns first and
+// second.
+func NewSplitter(first, second *Row) *Splitter {
+	s := &Splitter{
+		Block:     *NewBlock(),
+		First:     first,
+		Second:    second,
+		MinSpan:   1,
+		MinHeight: 3,
+	}
+	s.Border = false
+
+	s.Handle("/sys/mouse", func(e Event) {
+		if m, ok := e.Data.(EvtMouse); ok && s.HandleMouse(m) {
+			s.relayout()
+		}
+	})
+	return s
+}
+
+func (s *Splitter) relayout() {
+	g := s.Grid
+	if g == nil {
+		g = Body
+	}
+	if g == nil {
+		return
+	}
+	g.Align()
+	Render(g, s)
+}
+
+// at tells if terminal cell (x, y) is on the divider.
+func (s *Splitter) at(x, y int) bool {
+	f := s.First
+	if s.Horizontal {
+		return y == f.Y+f.Height-1 && x >= f.X && x < f.X+f.Width
+	}
+	return x == f.X+f.Width-1 && y >= f.Y && y < f.Y+f.Height
+}
+
+// HandleMouse starts a drag on a left press over the divider, moves the
+// divider while the button is held and ends the drag on release. It
+// returns true if the layout changed.
+func (s *Splitter) HandleMouse(m EvtMouse) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	switch m.Press {
+	case "MouseLeft":
+		if !s.dragging {
+			s.dragging = s.at(m.X, m.Y)
+			return false
+		}
+		if s.Horizontal {
+			return s.moveTo(m.Y - s.First.Y + 1)
+		}
+		return s.moveTo(m.X - s.First.X + 1)
+	case "MouseRelease":
+		s.dragging = false
+	}
+	return false
+}
+
+// HandleKey grows First by one span (or row) on "+" and shrinks it on "-".
+// It returns true if the layout changed.
+func (s *Splitter) HandleKey(key string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	d := 0
+	switch key {
+	case "+":
+		d = 1
+	case "-":
+		d = -1
+	default:
+		return false
+	}
+
+	if s.Horizontal {
+		return s.moveTo(s.First.Height + d)
+	}
+	total := s.First.Width + s.Second.Width
+	sum := s.First.Span + s.Second.Span
+	if total <= 0 || sum <= 0 {
+		return false
+	}
+	return s.moveTo((s.First.Span + d) * total / sum)
+}
+
+// moveTo resizes First to about size cells, honouring the minimum sizes.
+func (s *Splitter) moveTo(size int) bool {
+	if s.Horizontal {
+		return s.resizeRows(size)
+	}
+
+	total := s.First.Width + s.Second.Width
+	sum := s.First.Span + s.Second.Span
+	if total <= 0 {
+		return false
+	}
+	span := (size*sum + total/2) / total
+	if span > sum-s.MinSpan {
+		span = sum - s.MinSpan
+	}
+	if span < s.MinSpan {
+		span = s.MinSpan
+	}
+	if span == s.First.Span || span < 0 || span > sum {
+		return false
+	}
+	s.First.Span = span
+	s.Second.Span = sum - span
+	return true
+}
+
+// rowBlock returns the Block of the widget sizing r, following rows made of
+// a single column.
+func rowBlock(r *Row) *Block {
+	for r.Widget == nil && len(r.Cols) == 1 {
+		r = r.Cols[0]
+	}
+	if b, ok := r.Widget.(blocker); ok {
+		return b.block()
+	}
+	return nil
+}
+
+func (s *Splitter) resizeRows(h int) bool {
+	b0, b1 := rowBlock(s.First), rowBlock(s.Second)
+	if b0 == nil || b1 == nil {
+		return false
+	}
+
+	b0.Lock()
+	defer b0.Unlock()
+	b1.Lock()
+	defer b1.Unlock()
+
+	total := b0.Height + b1.Height
+	if h > total-s.MinHeight {
+		h = total - s.MinHeight
+	}
+	if h < s.MinHeight {
+		h = s.MinHeight
+	}
+	if h == b0.Height || h < 0 || h > total {
+		return false
+	}
+	b0.Height = h
+	b1.Height = total - h
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (s *Splitter) Buffer() Buffer {
+	s.Lock()
+	f := s.First
+	if s.Horizontal {
+		s.X, s.Y = f.X, f.Y+f.Height-1
+		s.Width, s.Height = f.Width, 1
+	} else {
+		s.X, s.Y = f.X+f.Width-1, f.Y
+		s.Width, s.Height = 1, f.Height
+	}
+	s.Unlock()
+
+	buf := s.Block.Buffer()
+	s.RLock()
+	defer s.RUnlock()
+
+	ch := VERTICAL_LINE
+	if s.Horizontal {
+		ch = HORIZONTAL_LINE
+	}
+	fg := s.BorderFg
+	if s.dragging {
+		fg |= AttrBold
+	}
+	buf.Fill(ch, fg, s.BorderBg)
+	return buf
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestSplitterDrag(t *testing.T) {
+	w0, w1 := NewBlock(), NewBlock()
+	left, right := NewCol(6, 0, w0), NewCol(6, 0, w1)
+	g := NewGrid(NewRow(left, right))
+	g.Width = 120
+	g.Align()
+
+	s := NewSplitter(left, right)
+	s.Grid = g
+	s.MinSpan = 2
+
+	if s.HandleMouse(EvtMouse{X: 30, Y: 0, Press: "MouseLeft"}) || s.dragging {
+		t.Fatal("a press away from the divider should not start a drag")
+	}
+	s.HandleMouse(EvtMouse{Press: "MouseRelease"})
+
+	if s.HandleMouse(EvtMouse{X: 59, Y: 1, Press: "MouseLeft"}) || !s.dragging {
+		t.Fatal("a press on the divider should start a drag")
+	}
+	if !s.HandleMouse(EvtMouse{X: 89, Y: 1, Press: "MouseLeft"}) {
+		t.Fatal("dragging should move the divider")
+	}
+	if left.Span != 9 || right.Span != 3 {
+		t.Errorf("expected spans 9/3 but got %d/%d", left.Span, right.Span)
+	}
+
+	s.HandleMouse(EvtMouse{X: 119, Y: 1, Press: "MouseLeft"})
+	if left.Span != 10 || right.Span != 2 {
+		t.Errorf("MinSpan should be honoured, got %d/%d", left.Span, right.Span)
+	}
+
+	s.HandleMouse(EvtMouse{Press: "MouseRelease"})
+	if s.dragging || s.HandleMouse(EvtMouse{X: 50, Y: 1, Press: "MouseLeft"}) {
+		t.Error("release should end the drag")
+	}
+
+	g.Align()
+	if !s.HandleKey("-") || left.Span != 9 {
+		t.Errorf("- should shrink the first column, got span %d", left.Span)
+	}
+}
+
+func TestSplitterHorizontal(t *testing.T) {
+	w0, w1 := NewBlock(), NewBlock()
+	w0.Height, w1.Height = 10, 10
+	top, bottom := NewRow(NewCol(12, 0, w0)), NewRow(NewCol(12, 0, w1))
+	g := NewGrid(top, bottom)
+	g.Width = 40
+	g.Align()
+
+	s := NewSplitter(top, bottom)
+	s.Horizontal = true
+	s.Grid = g
+
+	s.HandleMouse(EvtMouse{X: 5, Y: 9, Press: "MouseLeft"})
+	s.HandleMouse(EvtMouse{X: 5, Y: 4, Press: "MouseLeft"})
+	if w0.Height != 5 || w1.Height != 15 {
+		t.Errorf("expected heights 5/15 but got %d/%d", w0.Height, w1.Height)
+	}
+}
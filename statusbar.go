@@ -0,0 +1,123 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+// StatusSegment is a piece of styled text shown in a StatusBar.
+type StatusSegment struct {
+	Text string
+	Fg   Attribute
+	Bg   Attribute
+}
+
+// StatusBar is a one-line bar with left, center and right aligned segments.
+// When the segments do not fit, the center is truncated first, then the
+// right and finally the left segments.
+/*
+  sb := termui.NewStatusBar()
+  sb.Left = []termui.StatusSegment{{Text: "myapp", Fg: termui.ColorGreen}}
+  sb.Center = []termui.StatusSegment{{Text: time.Now().Format("15:04")}}
+  sb.Right = []termui.StatusSegment{{Text: "q: quit"}}
+  sb.Width = termui.TermWidth()
+  sb.Y = termui.TermHeight() - 1
+*/
+type StatusBar struct {
+	Block
+	Left   []StatusSegment
+	Center []StatusSegment
+	Right  []StatusSegment
+}
+
+// NewStatusBar returns a new *StatusBar with current theme.
+func NewStatusBar() *StatusBar {
+	sb := &StatusBar{Block: *NewBlock()}
+	sb.Border = false
+	sb.Height = 1
+	sb.themeAttr(&sb.Bg, "statusbar.bg")
+	return sb
+}
+
+// segmentCells builds the cells of segs, separated by a single space.
+func (sb *StatusBar) segmentCells(segs []StatusSegment) []Cell {
+	cs := []Cell{}
+	for i, s := range segs {
+		fg, bg := s.Fg, s.Bg
+		if bg == ColorDefault {
+			bg = sb.Bg
+		}
+		if i > 0 {
+			cs = append(cs, Cell{' ', fg, sb.Bg})
+		}
+		cs = append(cs, DefaultTxBuilder.Build(s.Text, fg, bg)...)
+	}
+	return cs
+}
+
+func cellsWidth(cs []Cell) int {
+	w := 0
+	for _, c := range cs {
+		w += c.Width()
+	}
+	return w
+}
+
+// fitCells trims cs with a trailing … when it is wider than w.
+func fitCells(cs []Cell, w int) []Cell {
+	if w <= 0 {
+		return []Cell{}
+	}
+	if cellsWidth(cs) <= w {
+		return cs
+	}
+	return DTrimTxCls(cs, w)
+}
+
+// Buffer implements Bufferer interface.
+func (sb *StatusBar) Buffer() Buffer {
+	buf := sb.Block.Buffer()
+	sb.RLock()
+	defer sb.RUnlock()
+
+	w := sb.innerArea.Dx()
+	left := sb.segmentCells(sb.Left)
+	right := sb.segmentCells(sb.Right)
+	center := sb.segmentCells(sb.Center)
+
+	// one column gap between the groups
+	gap := func(cs []Cell) int {
+		if len(cs) == 0 {
+			return 0
+		}
+		return 1
+	}
+
+	left = fitCells(left, w)
+	wl := cellsWidth(left)
+	right = fitCells(right, w-wl-gap(left))
+	wr := cellsWidth(right)
+	center = fitCells(center, w-wl-wr-gap(left)-gap(right))
+	wc := cellsWidth(center)
+
+	cx := (w - wc) / 2
+	if min := wl + gap(left); cx < min {
+		cx = min
+	}
+	if max := w - wr - gap(right) - wc; cx > max {
+		cx = max
+	}
+
+	y := sb.innerArea.Min.Y
+	draw := func(cs []Cell, x int) {
+		x += sb.innerArea.Min.X
+		for _, c := range cs {
+			buf.Set(x, y, c)
+			x += c.Width()
+		}
+	}
+	draw(left, 0)
+	draw(center, cx)
+	draw(right, w-wr)
+
+	return buf
+}
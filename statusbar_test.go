@@ -0,0 +1,33 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func statusBarLine(sb *StatusBar) string {
+	buf := sb.Buffer()
+	rs := []rune{}
+	for x := 0; x < sb.Width; x++ {
+		rs = append(rs, buf.At(x, 0).Ch)
+	}
+	return string(rs)
+}
+
+func TestStatusBarLayout(t *testing.T) {
+	sb := NewStatusBar()
+	sb.Width = 20
+	sb.Left = []StatusSegment{{Text: "app"}}
+	sb.Center = []StatusSegment{{Text: "12:00"}}
+	sb.Right = []StatusSegment{{Text: "q"}, {Text: "quit"}}
+
+	if s := statusBarLine(sb); s != "app    12:00  q quit" {
+		t.Errorf("unexpected layout %q", s)
+	}
+
+	sb.Center = []StatusSegment{{Text: "a long center text"}}
+	if s := statusBarLine(sb); s != "app a long c… q quit" {
+		t.Errorf("center should be truncated first, got %q", s)
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// StreamBackend is a Backend drawing on the terminal at the other end of
+// an io.ReadWriter with raw escape sequences and reading its keys and
+// mouse events from it, e.g. an SSH session or the master side of a pty,
+// instead of the process's controlling terminal. The stream must be in raw
+// mode, as an SSH session with a pty is. The size of the remote terminal
+// is given by NewStreamBackend and Resize. termui's state being global, a
+// process serves a single session at a time.
+/*
+  ssh.Handle(func(s ssh.Session) {
+      pty, winCh, _ := s.Pty()
+      sb := termui.NewStreamBackend(s, pty.Window.Width, pty.Window.Height)
+      go func() {
+          for w := range winCh {
+              sb.Resize(w.Width, w.Height)
+          }
+      }()
+      termui.InitBackend(sb)
+      defer termui.Close()
+      ...
+  })
+*/
+type StreamBackend struct {
+	mu     sync.Mutex
+	rw     io.ReadWriter
+	w, h   int
+	back   map[image.Point]Cell
+	front  map[image.Point]Cell // what the remote terminal shows
+	events chan Event
+	wake   chan struct{} // Interrupt
+	closed chan struct{}
+	once   sync.Once
+	drags  dragTracker
+}
+
+// NewStreamBackend returns a StreamBackend on rw, a terminal of w columns
+// and h rows.
+func NewStreamBackend(rw io.ReadWriter, w, h int) *StreamBackend {
+	return &StreamBackend{
+		rw:     rw,
+		w:      w,
+		h:      h,
+		back:   make(map[image.Point]Cell),
+		front:  make(map[image.Point]Cell),
+		events: make(chan Event),
+		wake:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+}
+
+// The escapes switching the remote terminal to the alternate screen with
+// the SGR mouse reports on, and back.
+const (
+	streamEnter = "\x1b[?1049h\x1b[?25l\x1b[?1000h\x1b[?1002h\x1b[?1006h\x1b[0m\x1b[2J"
+	streamLeave = "\x1b[?1006l\x1b[?1002l\x1b[?1000l\x1b[0m\x1b[?25h\x1b[?1049l"
+)
+
+// Init implements Backend, it starts reading the input of the stream.
+func (sb *StreamBackend) Init() error {
+	if _, err := io.WriteString(sb.rw, streamEnter); err != nil {
+		return err
+	}
+	go sb.read()
+	return nil
+}
+
+// Close implements Backend, restoring the remote terminal. The stream is
+// left open.
+func (sb *StreamBackend) Close() {
+	sb.once.Do(func() {
+		close(sb.closed)
+		sb.mu.Lock()
+		defer sb.mu.Unlock()
+		io.WriteString(sb.rw, streamLeave)
+	})
+}
+
+// Size implements Backend.
+func (sb *StreamBackend) Size() (int, int) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.w, sb.h
+}
+
+// SetCell implements Backend.
+func (sb *StreamBackend) SetCell(x, y int, ch rune, fg, bg Attribute) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.back[image.Pt(x, y)] = Cell{ch, fg, bg}
+}
+
+// SetCombiningCell implements CombiningBackend.
+func (sb *StreamBackend) SetCombiningCell(x, y int, ch rune, comb []rune, fg, bg Attribute) {
+	sb.SetCell(x, y, clusterRune(append([]rune{ch}, comb...)), fg, bg)
+}
+
+// Flush implements Backend, writing the cells changed since the last flush
+// in one go.
+func (sb *StreamBackend) Flush() error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	ps := make([]image.Point, 0, len(sb.back))
+	for p, c := range sb.back {
+		if f, ok := sb.front[p]; (!ok || f != c) && p.X < sb.w && p.Y < sb.h {
+			ps = append(ps, p)
+		}
+	}
+	if len(ps) == 0 {
+		return nil
+	}
+	sortPoints(ps)
+
+	var out bytes.Buffer
+	writeANSICells(&out, ps, sb.back)
+	for _, p := range ps {
+		sb.front[p] = sb.back[p]
+	}
+	_, err := sb.rw.Write(out.Bytes())
+	return err
+}
+
+// sortPoints sorts ps by row, then column.
+func sortPoints(ps []image.Point) {
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Y != ps[j].Y {
+			return ps[i].Y < ps[j].Y
+		}
+		return ps[i].X < ps[j].X
+	})
+}
+
+// writeANSICells writes to out the escapes drawing the cells at ps, sorted
+// by sortPoints, followed by a reset.
+func writeANSICells(out *bytes.Buffer, ps []image.Point, cells map[image.Point]Cell) {
+	cursor, style := image.Pt(-1, -1), ""
+	for _, p := range ps {
+		c := cells[p]
+		if p != cursor {
+			fmt.Fprintf(out, "\x1b[%d;%dH", p.Y+1, p.X+1)
+		}
+		if s := sgrOf(c.Fg, c.Bg); s != style {
+			out.WriteString(s)
+			style = s
+		}
+		out.WriteString(runesToStr([]rune{c.Ch}))
+		cursor = image.Pt(p.X+c.Width(), p.Y)
+	}
+	out.WriteString("\x1b[0m")
+}
+
+// PollEvent implements Backend, returning the events read from the stream
+// and those of Resize. Once the stream fails, e.g. at the end of the
+// session, it returns a "/sys/err" event and then blocks until Interrupt.
+func (sb *StreamBackend) PollEvent() Event {
+	select {
+	case e := <-sb.events:
+		return e
+	case <-sb.wake:
+		return Event{Type: "interrupt", From: "/sys", Time: now().Unix()}
+	}
+}
+
+// Interrupt implements InterruptibleBackend.
+func (sb *StreamBackend) Interrupt() {
+	select {
+	case sb.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Resize changes the size of the remote terminal, redrawing it whole, and
+// sends the resize event. It blocks until the event is polled.
+func (sb *StreamBackend) Resize(w, h int) {
+	sb.mu.Lock()
+	sb.w, sb.h = w, h
+	sb.front = make(map[image.Point]Cell)
+	for p := range sb.back {
+		if p.X >= w || p.Y >= h {
+			delete(sb.back, p)
+		}
+	}
+	io.WriteString(sb.rw, "\x1b[0m\x1b[2J")
+	sb.mu.Unlock()
+	sb.send(Event{
+		Type: "window",
+		From: "/sys",
+		Path: "/sys/wnd/resize",
+		Data: EvtWnd{Width: w, Height: h},
+		Time: now().Unix(),
+	})
+}
+
+// SetClipboard implements ClipboardBackend with an OSC 52 escape.
+func (sb *StreamBackend) SetClipboard(text string) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	_, err := io.WriteString(sb.rw, osc52(text))
+	return err
+}
+
+// DrawSixel implements SixelBackend.
+func (sb *StreamBackend) DrawSixel(x, y int, data []byte) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	fmt.Fprintf(sb.rw, "\x1b7\x1b[%d;%dH%s\x1b8", y+1, x+1, data)
+}
+
+// send queues e for PollEvent, it is dropped once sb is closed.
+func (sb *StreamBackend) send(e Event) {
+	select {
+	case sb.events <- e:
+	case <-sb.closed:
+	}
+}
+
+// read turns the input of the stream into events until it fails.
+func (sb *StreamBackend) read() {
+	buf := make([]byte, 4096)
+	var pending []byte
+	for {
+		n, err := sb.rw.Read(buf)
+		pending = append(pending, buf[:n]...)
+		var es []Event
+		es, pending = sb.parseInput(pending, err != nil)
+		for _, e := range es {
+			sb.send(e)
+		}
+		if err != nil {
+			sb.send(Event{
+				Type: "error",
+				From: "/sys",
+				Path: "/sys/err",
+				Data: EvtErr(err),
+				Time: now().Unix(),
+			})
+			return
+		}
+	}
+}
+
+// csiKeys are the keys of the CSI and SS3 sequences, by their parameter
+// and final byte.
+var csiKeys = map[string]string{
+	"A": KeyArrowUp, "B": KeyArrowDown, "C": KeyArrowRight, "D": KeyArrowLeft,
+	"H": KeyHome, "F": KeyEnd, "P": "<f1>", "Q": "<f2>", "R": "<f3>", "S": "<f4>",
+	"1~": KeyHome, "7~": KeyHome, "4~": KeyEnd, "8~": KeyEnd,
+	"2~": "<insert>", "3~": "<delete>", "5~": KeyPgUp, "6~": KeyPgDn,
+	"11~": "<f1>", "12~": "<f2>", "13~": "<f3>", "14~": "<f4>", "15~": "<f5>",
+	"17~": "<f6>", "18~": "<f7>", "19~": "<f8>", "20~": "<f9>", "21~": "<f10>",
+	"23~": "<f11>", "24~": "<f12>",
+}
+
+// ctrlKeys are the control characters with a key of their own.
+var ctrlKeys = map[byte]string{
+	0x00: "C-<space>", 0x08: KeyBackspace, 0x09: "<tab>", 0x0d: KeyEnter,
+	0x1b: KeyEsc, 0x1c: "C-\\", 0x1d: "C-]", 0x1f: "C-/", 0x20: KeySpace,
+	0x7f: KeyBackspace,
+}
+
+// parseInput returns the events of the input in p and what is left of it,
+// an incomplete sequence, unless no more input follows with final.
+func (sb *StreamBackend) parseInput(p []byte, final bool) ([]Event, []byte) {
+	var es []Event
+	key := func(k string) {
+		k = normalizeKeyStr(k)
+		es = append(es, Event{
+			Type: "keyboard",
+			From: "/sys",
+			Path: "/sys/kbd/" + k,
+			Data: EvtKbd{KeyStr: k},
+			Time: now().Unix(),
+		})
+	}
+
+	for len(p) > 0 {
+		b := p[0]
+		switch {
+		case b == 0x1b && len(p) > 1 && (p[1] == '[' || p[1] == 'O'):
+			// the parameters then the final byte of a CSI or SS3 sequence
+			end := 2
+			for end < len(p) && (p[end] < 0x40 || p[end] > 0x7e) {
+				end++
+			}
+			if end == len(p) {
+				if !final {
+					return es, p
+				}
+				p = nil
+				continue
+			}
+			seq := string(p[2 : end+1])
+			p = p[end+1:]
+			if strings.HasPrefix(seq, "<") && (seq[len(seq)-1] == 'M' || seq[len(seq)-1] == 'm') {
+				if m, ok := sb.parseMouse(seq); ok {
+					es = append(es, Event{Type: "mouse", From: "/sys", Path: MousePath(m), Data: m, Time: now().Unix()})
+				}
+			} else if k, ok := csiKeys[seq]; ok {
+				key(k)
+			}
+		case b == 0x1b && len(p) > 1 && p[1] != 0x1b:
+			// alt and a key
+			r, n := utf8.DecodeRune(p[1:])
+			k, ok := ctrlKeys[p[1]]
+			switch {
+			case !ok && p[1] < 0x20:
+				k = "C-" + string(rune('a'-1+p[1]))
+			case !ok:
+				k = string(r)
+			}
+			if strings.HasPrefix(k, "C-") {
+				key("C-M-" + k[2:])
+			} else {
+				key("M-" + k)
+			}
+			p = p[1+n:]
+		case ctrlKeys[b] != "":
+			key(ctrlKeys[b])
+			p = p[1:]
+		case b < 0x20:
+			key("C-" + string(rune('a'-1+b)))
+			p = p[1:]
+		default:
+			if !utf8.FullRune(p) && !final {
+				return es, p
+			}
+			r, n := utf8.DecodeRune(p)
+			key(string(r))
+			p = p[n:]
+		}
+	}
+	return es, nil
+}
+
+// parseMouse parses the "<b;x;yM" SGR mouse report seq.
+func (sb *StreamBackend) parseMouse(seq string) (EvtMouse, bool) {
+	fs := strings.Split(seq[1:len(seq)-1], ";")
+	if len(fs) != 3 {
+		return EvtMouse{}, false
+	}
+	var ns [3]int
+	for i, f := range fs {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return EvtMouse{}, false
+		}
+		ns[i] = n
+	}
+	m := EvtMouse{X: ns[1] - 1, Y: ns[2] - 1}
+	switch {
+	case seq[len(seq)-1] == 'm':
+		m.Press = "MouseRelease"
+	case ns[0]&64 != 0 && ns[0]&1 == 0:
+		m.Press = "MouseWheelUp"
+	case ns[0]&64 != 0:
+		m.Press = "MouseWheelDown"
+	default:
+		m.Press = [...]string{"MouseLeft", "MouseMiddle", "MouseRight", "MouseRelease"}[ns[0]&3]
+	}
+	return sb.drags.track(m), true
+}
@@ -0,0 +1,74 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamBackendFlush(t *testing.T) {
+	var out bytes.Buffer
+	sb := NewStreamBackend(struct {
+		io.Reader
+		io.Writer
+	}{strings.NewReader(""), &out}, 10, 2)
+
+	sb.SetCell(0, 1, 'a', ColorRed, ColorDefault)
+	sb.SetCell(1, 1, 'b', ColorRed, ColorDefault)
+	sb.SetCell(5, 1, 'c', ColorDefault, ColorBlue)
+	sb.Flush()
+	want := "\x1b[2;1H\x1b[0;31mab\x1b[2;6H\x1b[0;44mc\x1b[0m"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	sb.SetCell(1, 1, 'b', ColorRed, ColorDefault)
+	sb.Flush()
+	if out.Len() != 0 {
+		t.Errorf("expected unchanged cells not written, got %q", out.String())
+	}
+}
+
+func TestStreamBackendInput(t *testing.T) {
+	var out bytes.Buffer
+	sb := NewStreamBackend(struct {
+		io.Reader
+		io.Writer
+	}{strings.NewReader("q"), &out}, 10, 2)
+
+	in := "a\x1b[A\x1b[<0;3;4M\x1bx\x03\x7f\x1b[5~\x1b["
+	es, rest := sb.parseInput([]byte(in), false)
+	var paths []string
+	for _, e := range es {
+		paths = append(paths, e.Path)
+	}
+	want := []string{"/sys/kbd/a", "/sys/kbd/<up>", "/sys/mouse/left", "/sys/kbd/M-x", "/sys/kbd/C-c", "/sys/kbd/<backspace>", "/sys/kbd/<previous>"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("expected %v, got %v", want, paths)
+	}
+	if m := es[2].Data.(EvtMouse); m.X != 2 || m.Y != 3 {
+		t.Errorf("expected the mouse at (2,3), got %+v", m)
+	}
+	if string(rest) != "\x1b[" {
+		t.Errorf("expected the incomplete sequence kept, got %q", rest)
+	}
+
+	// the input is polled, then the end of the stream
+	sb.Init()
+	defer sb.Close()
+	if e := sb.PollEvent(); e.Path != "/sys/kbd/q" {
+		t.Errorf("expected the key read, got %q", e.Path)
+	}
+	if e := sb.PollEvent(); e.Path != "/sys/err" {
+		t.Errorf("expected the end of the stream, got %q", e.Path)
+	}
+	if !strings.HasPrefix(out.String(), "\x1b[?1049h") {
+		t.Error("expected the alternate screen entered")
+	}
+}
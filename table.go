@@ -0,0 +1,898 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Table displays Rows of cells under a Header line. Columns are as wide as
+// their widest cell, or ColWidths when given, and the widest ones shrink
+// when they do not fit, truncating their cells with "…". The selected row
+// is highlighted and kept visible as the keys move it. Cells may style
+// parts of their text with markup such as "[down](fg-red,fg-bold)", see
+// MarkdownTxBuilder.
+/*
+  t := termui.NewTable()
+  t.Header = []string{"PID", "Name", "CPU"}
+  t.Rows = [][]string{{"1", "init", "0.1"}, {"42", "termui", "7.5"}}
+  t.ColAlign = []termui.Align{termui.AlignRight, termui.AlignLeft, termui.AlignRight}
+  t.Width = 30
+  t.Height = 10
+  termui.Handle("/sys/kbd", func(e termui.Event) {
+      if t.HandleKey(termui.NormalizeKey(e)) {
+          termui.Render(t)
+      }
+  })
+*/
+type Table struct {
+	Block
+	Header      []string
+	Rows        [][]string
+	ColWidths   []int   // fixed column widths, 0 sizes the column to fit
+	ColAlign    []Align // AlignLeft (default), AlignCenter, AlignRight or AlignJustify
+	TextFgColor Attribute
+	TextBgColor Attribute
+	HeaderFg    Attribute
+	HeaderBg    Attribute
+	SelectedRow int             // index in Rows of the highlighted row, -1 for none
+	ScrollTop   int             // index of the first visible row
+	SortCol     int             // column Rows were last sorted by, -1 for none
+	SortDesc    bool            // the last sort was descending
+	OnSelect    func(index int) // called after the keys or a click moved the selected row
+	OnSort      func(col int, desc bool)
+	ColumnGap   int // blank columns between two columns
+
+	// Wrap breaks the cells wider than their column over more rows: at the
+	// last cell that fits (WrapChar) or at spaces (WrapWord), the row then
+	// being as tall as its tallest cell. By default, or with WrapNone, they
+	// are cut with "…".
+	Wrap              string
+	WrapWordHyphenate bool
+
+	// ShowColSep draws SepRune between columns, in the middle of their gap,
+	// which then is at least a column wide. ShowRowSep draws a line of
+	// RowSepRune under the header and between rows, crossed by SepRune.
+	ShowColSep bool
+	ShowRowSep bool
+	SepRune    rune
+	RowSepRune rune
+	SepFg      Attribute
+
+	// HScroll scrolls the columns sideways when they do not fit, instead of
+	// shrinking them. The first PinnedCols columns stay in place and the
+	// ScrollCol columns after them are scrolled out on the left. The
+	// <left>/<right> keys, ScrollLeft and ScrollRight move it by a column,
+	// and ScrollIndicators.Left and Right, e.g. '‹' and '›', mark the
+	// sides hiding columns.
+	HScroll    bool
+	PinnedCols int
+	ScrollCol  int
+
+	// Footer is drawn on the last rows, under the scrolled ones, in the
+	// columns of Rows, e.g. for totals.
+	Footer   []string
+	FooterFg Attribute
+	FooterBg Attribute
+
+	EmptyText string // shown centered under the header when Rows is empty
+	EmptyFg   Attribute
+
+	headerHeight int // rows taken by Header and its separator in the last draw
+	footerHeight int // rows taken by Footer and its separator in the last draw
+	shownCols    int // columns after PinnedCols drawn in the last draw
+	hiddenRight  int // columns after the last whole one drawn
+}
+
+// NewTable returns a new *Table with current theme.
+func NewTable() *Table {
+	t := &Table{
+		Block:      *NewBlock(),
+		SortCol:    -1,
+		ColumnGap:  1,
+		SepRune:    '│',
+		RowSepRune: '─',
+	}
+	t.themeAttr(&t.TextFgColor, "table.text.fg")
+	t.themeAttr(&t.TextBgColor, "table.text.bg")
+	t.themeAttr(&t.HeaderFg, "table.header.fg", AttrBold)
+	t.themeAttr(&t.HeaderBg, "table.header.bg")
+	t.themeAttr(&t.FooterFg, "table.footer.fg", AttrBold)
+	t.themeAttr(&t.FooterBg, "table.footer.bg")
+	t.themeAttr(&t.SepFg, "table.sep.fg")
+	return t
+}
+
+// Select highlights row i, scrolls to it and calls OnSelect if it changed.
+func (t *Table) Select(i int) {
+	t.Lock()
+	if i >= len(t.Rows) {
+		i = len(t.Rows) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	moved := i != t.SelectedRow
+	t.SelectedRow = i
+	t.scrollToSelected()
+	cb := t.OnSelect
+	t.Unlock()
+
+	if moved && cb != nil {
+		cb(i)
+	}
+}
+
+// CopyText implements Copier, returning the cells of the selected row
+// separated by tabs.
+func (t *Table) CopyText() string {
+	t.RLock()
+	defer t.RUnlock()
+	if t.SelectedRow < 0 || t.SelectedRow >= len(t.Rows) {
+		return ""
+	}
+	return strings.Join(t.Rows[t.SelectedRow], "\t")
+}
+
+// Sort orders Rows by column col, numerically when both cells are numbers,
+// descending with desc. The selected row follows its data. OnSort is
+// called afterwards.
+func (t *Table) Sort(col int, desc bool) {
+	t.Lock()
+	if col < 0 {
+		t.Unlock()
+		return
+	}
+	t.sortRows(col, desc)
+	cb := t.OnSort
+	t.Unlock()
+
+	if cb != nil {
+		cb(col, desc)
+	}
+}
+
+// sortRows does the work of Sort, the lock held.
+func (t *Table) sortRows(col int, desc bool) {
+	cell := func(r []string) string {
+		if col < len(r) {
+			return r[col]
+		}
+		return ""
+	}
+	idx := make([]int, len(t.Rows))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := PlainText(cell(t.Rows[idx[i]])), PlainText(cell(t.Rows[idx[j]]))
+		if desc {
+			a, b = b, a
+		}
+		return lessCell(a, b)
+	})
+	rows := make([][]string, len(idx))
+	sel := t.SelectedRow
+	for i, k := range idx {
+		rows[i] = t.Rows[k]
+		if k == sel {
+			t.SelectedRow = i
+		}
+	}
+	t.Rows = rows
+	t.SortCol, t.SortDesc = col, desc
+	t.scrollToSelected()
+}
+
+// TableState is the scroll, selection and sort state of a Table, see
+// Table.State.
+type TableState struct {
+	ScrollTop   int  `json:"scroll_top"`
+	ScrollCol   int  `json:"scroll_col,omitempty"`
+	SelectedRow int  `json:"selected_row"`
+	SortCol     int  `json:"sort_col"`
+	SortDesc    bool `json:"sort_desc,omitempty"`
+}
+
+// State returns a snapshot of t's scroll position, selection and sort
+// order, it can be saved, e.g. as JSON, and given back to RestoreState.
+func (t *Table) State() TableState {
+	t.RLock()
+	defer t.RUnlock()
+	return TableState{
+		ScrollTop:   t.ScrollTop,
+		ScrollCol:   t.ScrollCol,
+		SelectedRow: t.SelectedRow,
+		SortCol:     t.SortCol,
+		SortDesc:    t.SortDesc,
+	}
+}
+
+// RestoreState restores a snapshot taken by State, sorting the current
+// rows again and clamping the rest to them. OnSort and OnSelect are not
+// called.
+func (t *Table) RestoreState(st TableState) {
+	t.Lock()
+	defer t.Unlock()
+	if st.SortCol >= 0 && st.SortCol < t.numCols() {
+		t.sortRows(st.SortCol, st.SortDesc)
+	}
+	t.SelectedRow = clampInt(st.SelectedRow, -1, len(t.Rows)-1)
+	t.ScrollTop = clampInt(st.ScrollTop, 0, max0(len(t.Rows)-1))
+	t.ScrollCol = clampInt(st.ScrollCol, 0, max0(t.numCols()-t.PinnedCols-1))
+}
+
+// lessCell compares two cells as numbers if both are, as strings otherwise.
+func lessCell(a, b string) bool {
+	fa, ea := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, eb := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if ea == nil && eb == nil {
+		return fa < fb
+	}
+	return a < b
+}
+
+// toggleSort sorts by col, reversing the order if it already was.
+func (t *Table) toggleSort(col int) {
+	t.RLock()
+	desc := t.SortCol == col && !t.SortDesc
+	t.RUnlock()
+	t.Sort(col, desc)
+}
+
+// ScrollLeft scrolls the columns right of PinnedCols back by one.
+func (t *Table) ScrollLeft() {
+	t.Lock()
+	defer t.Unlock()
+	if t.ScrollCol > 0 {
+		t.ScrollCol--
+	}
+}
+
+// ScrollRight scrolls the columns right of PinnedCols on by one, as long
+// as the last one was not fully shown.
+func (t *Table) ScrollRight() {
+	t.Lock()
+	defer t.Unlock()
+	if t.hiddenRight > 0 {
+		t.ScrollCol++
+		t.hiddenRight--
+	}
+}
+
+// HandleKey moves the selected row with <up>/<down>, <previous>/<next> by
+// a page and <home>/<end>, and with HScroll the columns with
+// <left>/<right>. The digits "1" to "9" sort by that column, again to
+// reverse the order. It reports whether key was consumed.
+func (t *Table) HandleKey(key string) bool {
+	t.RLock()
+	row, page, ncol, hscroll := t.SelectedRow, t.pageRows(), len(t.Header), t.HScroll
+	t.RUnlock()
+	if page < 1 {
+		page = 1
+	}
+
+	switch key {
+	case KeyArrowLeft, KeyArrowRight:
+		if !hscroll {
+			return false
+		}
+		if key == KeyArrowLeft {
+			t.ScrollLeft()
+		} else {
+			t.ScrollRight()
+		}
+		return true
+	case KeyArrowUp:
+		row--
+	case KeyArrowDown:
+		row++
+	case KeyPgUp:
+		row -= page
+	case KeyPgDn:
+		row += page
+	case KeyHome:
+		row = 0
+	case KeyEnd:
+		row = len(t.Rows) - 1
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' && int(key[0]-'1') < ncol {
+			t.toggleSort(int(key[0] - '1'))
+			return true
+		}
+		return false
+	}
+	t.Select(row)
+	return true
+}
+
+// ScrollState implements Scrollable, counting rows under the header and,
+// with HScroll, columns right of PinnedCols.
+func (t *Table) ScrollState(vertical bool) (total, shown, offset int) {
+	t.RLock()
+	defer t.RUnlock()
+	if !vertical {
+		if !t.HScroll {
+			return 0, 0, 0
+		}
+		return max0(t.numCols() - t.PinnedCols), t.shownCols, t.ScrollCol
+	}
+	return len(t.Rows), t.pageRows(), t.ScrollTop
+}
+
+// SetScrollOffset implements Scrollable, showing the rows or, with
+// HScroll, the columns from offset.
+func (t *Table) SetScrollOffset(vertical bool, offset int) {
+	t.Lock()
+	defer t.Unlock()
+	if vertical {
+		t.ScrollTop = clampInt(offset, 0, max0(len(t.Rows)-t.pageRows()))
+	} else if t.HScroll {
+		t.ScrollCol = clampInt(offset, 0, max0(t.numCols()-t.PinnedCols-1))
+	}
+}
+
+// pageRows returns how many rows of a line fit between the header and the
+// footer.
+func (t *Table) pageRows() int {
+	h := t.innerArea.Dy() - t.headerHeight - t.footerHeight
+	if t.ShowRowSep {
+		h = (h + 1) / 2
+	}
+	return h
+}
+
+// gap returns the columns between two columns.
+func (t *Table) gap() int {
+	if t.ShowColSep && t.ColumnGap < 1 {
+		return 1
+	}
+	return t.ColumnGap
+}
+
+// scrollToSelected moves ScrollTop so the selected row is visible.
+func (t *Table) scrollToSelected() {
+	h := t.pageRows()
+	if h < 1 || t.SelectedRow < 0 {
+		return
+	}
+	if t.SelectedRow < t.ScrollTop {
+		t.ScrollTop = t.SelectedRow
+	}
+	if t.SelectedRow >= t.ScrollTop+h {
+		t.ScrollTop = t.SelectedRow - h + 1
+	}
+}
+
+// header returns Header with the sort order marked on SortCol.
+func (t *Table) header() []string {
+	hdr := make([]string, len(t.Header))
+	copy(hdr, t.Header)
+	if t.SortCol >= 0 && t.SortCol < len(hdr) {
+		mark := " ▲"
+		if t.SortDesc {
+			mark = " ▼"
+		}
+		hdr[t.SortCol] += mark
+	}
+	return hdr
+}
+
+// numCols returns the number of columns of Header, Rows and Footer.
+func (t *Table) numCols() int {
+	n := len(t.Header)
+	if len(t.Footer) > n {
+		n = len(t.Footer)
+	}
+	for _, r := range t.Rows {
+		if len(r) > n {
+			n = len(r)
+		}
+	}
+	return n
+}
+
+// columnWidths returns the width of every column within w cells, shrinking
+// the widest sized-to-fit columns first. With HScroll the columns keep
+// their widths, only those wider than w being cut to it.
+func (t *Table) columnWidths(w int) []int {
+	n := t.numCols()
+	hdr := t.header()
+	ws := make([]int, n)
+	fixed := make([]bool, n)
+	for i := range ws {
+		if i < len(t.ColWidths) && t.ColWidths[i] > 0 {
+			ws[i], fixed[i] = t.ColWidths[i], true
+			continue
+		}
+		if i < len(hdr) {
+			ws[i] = strWidth(PlainText(hdr[i]))
+		}
+		if i < len(t.Footer) {
+			if cw := strWidth(PlainText(t.Footer[i])); cw > ws[i] {
+				ws[i] = cw
+			}
+		}
+		for _, r := range t.Rows {
+			if i < len(r) {
+				if cw := strWidth(PlainText(r[i])); cw > ws[i] {
+					ws[i] = cw
+				}
+			}
+		}
+	}
+
+	if t.HScroll {
+		for i := range ws {
+			if ws[i] > w {
+				ws[i] = w
+			}
+		}
+		return ws
+	}
+
+	total := t.gap() * (n - 1)
+	for _, cw := range ws {
+		total += cw
+	}
+	for total > w {
+		widest := -1
+		for i, cw := range ws {
+			if !fixed[i] && cw > 1 && (widest < 0 || cw > ws[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		ws[widest]--
+		total--
+	}
+	return ws
+}
+
+// visibleCols returns the columns drawn within w cells out of those of
+// widths ws, and their widths. With HScroll they are the pinned ones and
+// those from ScrollCol on, the last one cut to the cells left.
+func (t *Table) visibleCols(ws []int, w int) ([]int, []int) {
+	cols := make([]int, 0, len(ws))
+	if !t.HScroll {
+		for i := range ws {
+			cols = append(cols, i)
+		}
+		t.shownCols, t.hiddenRight = len(ws), 0
+		return cols, ws
+	}
+
+	p := clampInt(t.PinnedCols, 0, len(ws))
+	t.ScrollCol = clampInt(t.ScrollCol, 0, max0(len(ws)-p-1))
+	for i := 0; i < len(ws); i++ {
+		if i == p {
+			i += t.ScrollCol
+		}
+		cols = append(cols, i)
+	}
+	vws := make([]int, 0, len(cols))
+	x, whole := 0, 0
+	for k, c := range cols {
+		if k > 0 {
+			x += t.gap()
+		}
+		if x >= w {
+			break
+		}
+		cw := ws[c]
+		if x+cw > w {
+			cw = w - x
+		} else if c >= p {
+			whole++
+		}
+		vws = append(vws, cw)
+		x += cw
+	}
+	cols = cols[:len(vws)]
+	t.shownCols = max0(len(cols) - p)
+	t.hiddenRight = max0(len(ws)-p-t.ScrollCol) - whole
+	return cols, vws
+}
+
+// alignCells pads cs to w cells as a says, trimming it if it is wider.
+// end tells whether cs ends a paragraph, see alignLine.
+func alignCells(cs []Cell, w int, a Align, end bool, fg, bg Attribute) []Cell {
+	left, cs := alignLine(fitCells(cs, w), w, a, end)
+	rs := make([]Cell, 0, w)
+	for i := 0; i < left; i++ {
+		rs = append(rs, Cell{' ', fg, bg})
+	}
+	rs = append(rs, cs...)
+	for i := left + cellsWidth(cs); i < w; i++ {
+		rs = append(rs, Cell{' ', fg, bg})
+	}
+	return rs
+}
+
+// cellLines returns the display lines of cell s in a column of width w and
+// whether each one ends a paragraph.
+func (t *Table) cellLines(s string, w int, fg, bg Attribute) ([][]Cell, []bool) {
+	cs := DefaultTxBuilder.Build(s, fg, bg)
+	if t.Wrap != WrapChar && t.Wrap != WrapWord {
+		return [][]Cell{cs}, []bool{true}
+	}
+	return wrapLines(cs, w, t.Wrap, t.WrapWordHyphenate)
+}
+
+// rowHeight returns the rows cells take in columns cols of widths ws.
+func (t *Table) rowHeight(cells []string, cols, ws []int) int {
+	h := 1
+	for k, cw := range ws {
+		if i := cols[k]; i < len(cells) {
+			if ls, _ := t.cellLines(cells[i], cw, 0, 0); len(ls) > h {
+				h = len(ls)
+			}
+		}
+	}
+	return h
+}
+
+// drawRow draws columns cols of cells, of widths ws, from row y of the
+// inner area down to row bottom at most. It returns the rows drawn, cells
+// wrapping over more rows with Wrap.
+func (t *Table) drawRow(buf Buffer, y, bottom int, cells []string, cols, ws []int, fg, bg Attribute) int {
+	lines := make([][][]Cell, len(ws))
+	ends := make([][]bool, len(ws))
+	h := 1
+	for k, cw := range ws {
+		s := ""
+		if i := cols[k]; i < len(cells) {
+			s = cells[i]
+		}
+		lines[k], ends[k] = t.cellLines(s, cw, fg, bg)
+		if len(lines[k]) > h {
+			h = len(lines[k])
+		}
+	}
+	if y+h > bottom {
+		h = bottom - y
+	}
+
+	for r := 0; r < h; r++ {
+		x := t.innerArea.Min.X
+		for k, cw := range ws {
+			if x >= t.innerArea.Max.X {
+				break
+			}
+			a := AlignLeft
+			if i := cols[k]; i < len(t.ColAlign) && t.ColAlign[i] != AlignNone {
+				a = t.ColAlign[i]
+			}
+			var line []Cell
+			end := true
+			if r < len(lines[k]) {
+				line, end = lines[k][r], ends[k][r]
+			}
+			for _, c := range alignCells(line, cw, a, end, fg, bg) {
+				if x < t.innerArea.Max.X {
+					buf.Set(x, y+r, c)
+				}
+				x += c.Width()
+			}
+			for g, gap := 0, t.gap(); g < gap && k < len(ws)-1 && x < t.innerArea.Max.X; g++ {
+				if t.ShowColSep && g == gap/2 {
+					buf.Set(x, y+r, Cell{t.SepRune, t.SepFg, bg})
+				} else {
+					buf.Set(x, y+r, Cell{' ', fg, bg})
+				}
+				x++
+			}
+		}
+		// the selection highlight spans the whole row
+		for ; x < t.innerArea.Max.X && fg&AttrReverse != 0; x++ {
+			buf.Set(x, y+r, Cell{' ', fg, bg})
+		}
+	}
+	return h
+}
+
+// drawRowSep draws the line of RowSepRune on row y, crossed by SepRune
+// where the columns of widths ws meet.
+func (t *Table) drawRowSep(buf Buffer, y int, ws []int) {
+	x := t.innerArea.Min.X
+	for ; x < t.innerArea.Max.X; x++ {
+		buf.Set(x, y, Cell{t.RowSepRune, t.SepFg, t.TextBgColor})
+	}
+	if !t.ShowColSep {
+		return
+	}
+	x = t.innerArea.Min.X
+	for _, cw := range ws[:max0(len(ws)-1)] {
+		x += cw
+		if mid := x + t.gap()/2; mid < t.innerArea.Max.X {
+			buf.Set(mid, y, Cell{t.SepRune, t.SepFg, t.TextBgColor})
+		}
+		x += t.gap()
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (t *Table) Buffer() Buffer {
+	buf := t.Block.Buffer()
+	t.Lock()
+	defer t.Unlock()
+
+	cols, ws := t.visibleCols(t.columnWidths(t.innerArea.Dx()), t.innerArea.Dx())
+	y, bottom := t.innerArea.Min.Y, t.innerArea.Max.Y
+	t.headerHeight = 0
+	if len(t.Header) > 0 && y < t.innerArea.Max.Y {
+		t.headerHeight = t.drawRow(buf, y, bottom, t.header(), cols, ws, t.HeaderFg, t.HeaderBg)
+		t.addHeaderHotspots(cols, ws)
+		y += t.headerHeight
+		if t.ShowRowSep && y < t.innerArea.Max.Y {
+			t.drawRowSep(buf, y, ws)
+			t.headerHeight++
+			y++
+		}
+	}
+	t.footerHeight = 0
+	if len(t.Footer) > 0 {
+		t.footerHeight = t.rowHeight(t.Footer, cols, ws)
+		if t.ShowRowSep {
+			t.footerHeight++
+		}
+		t.footerHeight = clampInt(t.footerHeight, 0, bottom-y)
+		bottom -= t.footerHeight
+		fy := bottom
+		if t.ShowRowSep && fy < t.innerArea.Max.Y {
+			t.drawRowSep(buf, fy, ws)
+			fy++
+		}
+		if fy < t.innerArea.Max.Y {
+			t.drawRow(buf, fy, t.innerArea.Max.Y, t.Footer, cols, ws, t.FooterFg, t.FooterBg)
+		}
+	}
+
+	if len(t.Rows) == 0 {
+		r := t.innerArea
+		r.Min.Y, r.Max.Y = y, bottom
+		t.drawEmptyTextIn(buf, r, t.EmptyText, t.EmptyFg)
+	}
+
+	h := t.pageRows()
+	top := t.ScrollTop
+	if top > len(t.Rows)-h {
+		top = len(t.Rows) - h
+	}
+	if top < 0 {
+		top = 0
+	}
+	if t.Wrap == WrapChar || t.Wrap == WrapWord {
+		// rows of more lines may push the selected one out of sight
+		for top < t.SelectedRow && t.SelectedRow < len(t.Rows) {
+			n := 0
+			for i := top; i <= t.SelectedRow; i++ {
+				n += t.rowHeight(t.Rows[i], cols, ws)
+				if t.ShowRowSep && i > top {
+					n++
+				}
+			}
+			if n <= bottom-y {
+				break
+			}
+			top++
+		}
+	}
+	i := top
+	for ; i < len(t.Rows) && y < bottom; i++ {
+		if t.ShowRowSep && i > top {
+			t.drawRowSep(buf, y, ws)
+			if y++; y >= bottom {
+				break
+			}
+		}
+		fg := t.TextFgColor
+		if i == t.SelectedRow {
+			fg |= AttrReverse
+		}
+		n := t.drawRow(buf, y, bottom, t.Rows[i], cols, ws, fg, t.TextBgColor)
+		t.addRowHotspot(i, y, n)
+		y += n
+	}
+	t.drawScrollIndicators(buf, top > 0, i < len(t.Rows), t.ScrollCol > 0, t.hiddenRight > 0)
+	return buf
+}
+
+// addHeaderHotspots makes a click on a column header sort by it.
+func (t *Table) addHeaderHotspots(cols, ws []int) {
+	x, y := t.innerArea.Min.X, t.innerArea.Min.Y
+	for k, cw := range ws {
+		col := cols[k]
+		t.AddHotspot(image.Rect(x, y, x+cw, y+t.headerHeight), func(int, int) { t.toggleSort(col) })
+		x += cw + t.gap()
+	}
+}
+
+// addRowHotspot makes a click on row i, drawn over h rows from y, select
+// it.
+func (t *Table) addRowHotspot(i, y, h int) {
+	r := t.innerArea
+	r.Min.Y, r.Max.Y = y, y+h
+	t.AddHotspot(r, func(int, int) { t.Select(i) })
+}
+
+// SetStructs fills Header and Rows from v, a slice of structs or of
+// pointers to them, a row for each. columns names the fields shown, in
+// order, a dotted path such as "Addr.City" reaching into nested structs.
+// Without columns every exported field is shown, those of nested structs
+// included. The tag `termui:"Header,fmt=%.2f"` sets the header of a field
+// and the fmt verb of its cells, a layout for a time.Time, and
+// `termui:"-"` leaves the field out. v not being a slice of structs, or a
+// column naming no exported field, is an error.
+/*
+  type proc struct {
+      PID  int
+      Name string
+      CPU  float64 `termui:"CPU %,fmt=%.1f"`
+  }
+  err := t.SetStructs(procs, "Name", "CPU")
+*/
+func (t *Table) SetStructs(v interface{}, columns ...string) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("termui: SetStructs needs a slice of structs, got %T", v)
+	}
+	et := rv.Type().Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return fmt.Errorf("termui: SetStructs needs a slice of structs, got %T", v)
+	}
+
+	var cols []structCol
+	if len(columns) == 0 {
+		cols = structFields(et, nil)
+	}
+	for _, name := range columns {
+		c, err := structColumn(et, name)
+		if err != nil {
+			return err
+		}
+		cols = append(cols, c)
+	}
+
+	hdr := make([]string, len(cols))
+	for i, c := range cols {
+		hdr[i] = c.header
+	}
+	rows := make([][]string, rv.Len())
+	for i := range rows {
+		rows[i] = make([]string, len(cols))
+		for j, c := range cols {
+			rows[i][j] = c.cell(rv.Index(i))
+		}
+	}
+
+	t.Lock()
+	t.Header, t.Rows = hdr, rows
+	if t.SelectedRow >= len(rows) {
+		t.SelectedRow = len(rows) - 1
+	}
+	t.Unlock()
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structCol is a column SetStructs fills from a field.
+type structCol struct {
+	index  []int // of the field and the nested structs leading to it
+	header string
+	format string
+}
+
+// tagCol returns the column of field f reached with index, reading its
+// termui tag.
+func tagCol(f reflect.StructField, index []int) structCol {
+	c := structCol{index: index, header: f.Name}
+	parts := strings.Split(f.Tag.Get("termui"), ",")
+	if parts[0] != "" {
+		c.header = parts[0]
+	}
+	for _, p := range parts[1:] {
+		if strings.HasPrefix(p, "fmt=") {
+			c.format = p[len("fmt="):]
+		}
+	}
+	return c
+}
+
+// structFields returns the columns of the exported fields of struct type
+// st, reached with index, going into nested structs.
+func structFields(st reflect.Type, index []int) []structCol {
+	var cols []structCol
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("termui") == "-" {
+			continue
+		}
+		idx := append(append([]int{}, index...), i)
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			cols = append(cols, structFields(ft, idx)...)
+			continue
+		}
+		cols = append(cols, tagCol(f, idx))
+	}
+	return cols
+}
+
+// structColumn returns the column of the field of struct type st named by
+// the dotted path name.
+func structColumn(st reflect.Type, name string) (structCol, error) {
+	var index []int
+	var f reflect.StructField
+	t := st
+	for _, part := range strings.Split(name, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		var ok bool
+		if t.Kind() == reflect.Struct {
+			f, ok = t.FieldByName(part)
+		}
+		if !ok || f.PkgPath != "" {
+			return structCol{}, fmt.Errorf("termui: %s has no exported field %s", st, name)
+		}
+		index = append(index, f.Index...)
+		t = f.Type
+	}
+	return tagCol(f, index), nil
+}
+
+// cell returns the text of c in the struct v, empty if a nil pointer is
+// on the way to the field or it cannot be read.
+func (c structCol) cell(v reflect.Value) string {
+	for _, i := range c.index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.CanInterface() {
+		return "" // promoted from an unexported embedded struct
+	}
+
+	if v.Type() == timeType {
+		layout := c.format
+		if layout == "" {
+			layout = "2006-01-02 15:04:05"
+		}
+		return v.Interface().(time.Time).Format(layout)
+	}
+	if c.format != "" {
+		return fmt.Sprintf(c.format, v.Interface())
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,424 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTableLayout(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 16, 5
+	tb.Header = []string{"ID", "Name"}
+	tb.Rows = [][]string{{"1", "alpha"}, {"22", "a very long name"}}
+	tb.ColAlign = []Align{AlignRight}
+	buf := tb.Buffer()
+
+	// 14 inner columns: 2 for the IDs, a gap and 11 for the names
+	if got := bufferRow(buf, 1, 1, 15); got != "ID Name       " {
+		t.Errorf("header: got %q", got)
+	}
+	if got := bufferRow(buf, 2, 1, 15); got != " 1 alpha      " {
+		t.Errorf("first row: got %q", got)
+	}
+	if got := bufferRow(buf, 3, 1, 15); got != "22 a very lon…" {
+		t.Errorf("second row: got %q", got)
+	}
+	if c := buf.At(14, 2); c.Fg&AttrReverse == 0 {
+		t.Errorf("expected the selected row highlighted to the edge, got %+v", c)
+	}
+	if c := buf.At(1, 3); c.Fg&AttrReverse != 0 {
+		t.Errorf("expected other rows plain, got %+v", c)
+	}
+
+	tb.ColWidths = []int{4}
+	buf = tb.Buffer()
+	if got := bufferRow(buf, 3, 1, 15); got != "  22 a very l…" {
+		t.Errorf("fixed width: got %q", got)
+	}
+}
+
+func TestTableSortAndSelect(t *testing.T) {
+	_, done := useFakeBackend(30, 8)
+	defer done()
+	tb := NewTable()
+	tb.Width, tb.Height = 20, 6
+	tb.Header = []string{"N", "Name"}
+	tb.Rows = [][]string{{"10", "c"}, {"9", "a"}, {"100", "b"}, {"1", "d"}}
+	tb.Buffer()
+
+	picked := []int{}
+	tb.OnSelect = func(i int) { picked = append(picked, i) }
+	tb.HandleKey(KeyArrowDown)
+	tb.HandleKey(KeyArrowDown)
+	tb.HandleKey(KeyArrowDown)
+	// 3 rows fit under the header
+	if tb.SelectedRow != 3 || tb.ScrollTop != 1 {
+		t.Errorf("expected row 3 scrolled to 1, got %d %d", tb.SelectedRow, tb.ScrollTop)
+	}
+
+	tb.HandleKey("1")
+	col := func(c int) []string {
+		s := []string{}
+		for _, r := range tb.Rows {
+			s = append(s, r[c])
+		}
+		return s
+	}
+	if got := col(0); !reflect.DeepEqual(got, []string{"1", "9", "10", "100"}) {
+		t.Errorf("expected a numeric sort, got %v", got)
+	}
+	if tb.SelectedRow != 0 || tb.Rows[0][1] != "d" {
+		t.Errorf("expected the selection to follow its row, got %d", tb.SelectedRow)
+	}
+	tb.HandleKey("1")
+	if got := col(0); !reflect.DeepEqual(got, []string{"100", "10", "9", "1"}) || !tb.SortDesc {
+		t.Errorf("expected a descending sort, got %v", got)
+	}
+	buf := tb.Buffer()
+	if got := bufferRow(buf, 1, 1, 6); got != "N ▼ N" {
+		t.Errorf("expected the sort mark in the header, got %q", got)
+	}
+
+	defer resetHits()
+	Render(tb)
+	// a click on the Name header sorts by it
+	dispatchHotspot(click(6, 1))
+	if got := col(1); tb.SortCol != 1 || !reflect.DeepEqual(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected a sort by name, got %v", got)
+	}
+	tb.ScrollTop = 0
+	Render(tb)
+	dispatchHotspot(click(3, 3))
+	if tb.SelectedRow != 1 {
+		t.Errorf("expected a click to select row 1, got %d", tb.SelectedRow)
+	}
+	if want := []int{1, 2, 3, 1}; !reflect.DeepEqual(picked, want) {
+		t.Errorf("expected OnSelect calls %v, got %v", want, picked)
+	}
+}
+
+func TestTableMarkup(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 14, 5
+	tb.SelectedRow = -1
+	tb.Header = []string{"Val", "State"}
+	tb.Rows = [][]string{{"[10](fg-red)", "[down](fg-red,fg-bold)"}, {"9", "up"}}
+	tb.Sort(0, false)
+	buf := tb.Buffer()
+
+	// the columns fit the text, not the markup, and sort by it; the
+	// header is "Val ▲"
+	if got := bufferRow(buf, 2, 1, 12); got != "9     up   " {
+		t.Errorf("first row: got %q", got)
+	}
+	if got := bufferRow(buf, 3, 1, 12); got != "10    down " {
+		t.Errorf("second row: got %q", got)
+	}
+	if c := buf.At(7, 3); c.Ch != 'd' || c.Fg != ColorRed|AttrBold {
+		t.Errorf("expected a bold red cell, got %+v", c)
+	}
+	if c := buf.At(11, 3); c.Fg != tb.TextFgColor {
+		t.Errorf("expected the padding unstyled, got %+v", c)
+	}
+}
+
+func TestTableWrap(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 12, 6
+	tb.Rows = [][]string{{"1", "one two three"}, {"2", "four"}}
+	tb.ColWidths = []int{1, 8}
+	tb.ColAlign = []Align{AlignLeft, AlignRight}
+	tb.Wrap = WrapWord
+	buf := tb.Buffer()
+
+	want := []string{"1  one two", "     three", "2     four"}
+	for i, w := range want {
+		if got := strings.TrimRight(bufferRow(buf, 1+i, 1, 11), " "); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+	if c := buf.At(10, 2); c.Fg&AttrReverse == 0 {
+		t.Error("expected the selected row highlighted over its lines")
+	}
+}
+
+func TestTableSeparators(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 12, 7
+	tb.Header = []string{"N", "Name"}
+	tb.Rows = [][]string{{"2", "b"}, {"1", "a"}, {"3", "c"}}
+	tb.ShowColSep = true
+	tb.ShowRowSep = true
+	tb.SepFg = ColorRed
+	tb.Sort(0, false)
+	buf := tb.Buffer()
+
+	// the header is "N ▲", the gap of one column holds the separator and
+	// only two of the three rows fit between the lines
+	want := []string{"N ▲│Name  ", "───│──────", "1  │a     ", "───│──────", "2  │b     "}
+	for i, w := range want {
+		if got := bufferRow(buf, 1+i, 1, 11); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+	if c := buf.At(4, 2); c.Fg != ColorRed {
+		t.Errorf("expected a red separator, got %+v", c)
+	}
+	if _, shown, _ := tb.ScrollState(true); shown != 2 {
+		t.Errorf("expected 2 rows a page, got %d", shown)
+	}
+
+	// scrolling to the last row keeps the lines between the rows
+	tb.Select(2)
+	buf = tb.Buffer()
+	if got := bufferRow(buf, 3, 1, 11); got != "2  │b     " {
+		t.Errorf("scrolled: got %q", got)
+	}
+	if got := bufferRow(buf, 5, 1, 11); got != "3  │c     " {
+		t.Errorf("scrolled: got %q", got)
+	}
+
+	tb.ShowRowSep = false
+	tb.SepRune = '|'
+	tb.ColumnGap = 3
+	buf = tb.Buffer()
+	if got := bufferRow(buf, 2, 1, 11); got != "1   | a   " {
+		t.Errorf("column separator: got %q", got)
+	}
+}
+
+func TestTableHScroll(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 14, 4
+	tb.Header = []string{"ID", "Alpha", "Bravo", "Charl"}
+	tb.Rows = [][]string{{"1", "aaaaa", "bbbbb", "ccccc"}}
+	tb.HScroll = true
+	tb.PinnedCols = 1
+	buf := tb.Buffer()
+
+	// 12 inner columns: the pinned IDs, Alpha and Bravo cut at the edge
+	if got := bufferRow(buf, 1, 1, 13); got != "ID Alpha Br…" {
+		t.Errorf("header: got %q", got)
+	}
+	if c := buf.At(13, 2); c.Ch != ScrollIndicators.Right {
+		t.Errorf("expected the right mark, got %+v", c)
+	}
+	if c := buf.At(0, 2); c.Ch == ScrollIndicators.Left {
+		t.Error("expected no left mark before scrolling")
+	}
+	if total, shown, off := tb.ScrollState(false); total != 3 || shown != 2 || off != 0 {
+		t.Errorf("expected 3 columns, 2 shown from 0, got %d %d %d", total, shown, off)
+	}
+
+	if !tb.HandleKey(KeyArrowRight) {
+		t.Fatal("expected <right> consumed")
+	}
+	buf = tb.Buffer()
+	if got := bufferRow(buf, 2, 1, 13); got != "1  bbbbb cc…" {
+		t.Errorf("scrolled once: got %q", got)
+	}
+	tb.ScrollRight()
+	buf = tb.Buffer()
+	if got := bufferRow(buf, 2, 1, 13); got != "1  ccccc    " {
+		t.Errorf("scrolled twice: got %q", got)
+	}
+	if c := buf.At(0, 2); c.Ch != ScrollIndicators.Left {
+		t.Errorf("expected the left mark, got %+v", c)
+	}
+	if c := buf.At(13, 2); c.Ch == ScrollIndicators.Right {
+		t.Error("expected no right mark at the last column")
+	}
+	// the last column is fully shown, there is nothing more to the right
+	tb.ScrollRight()
+	if tb.ScrollCol != 2 {
+		t.Errorf("expected to stop at column 2, got %d", tb.ScrollCol)
+	}
+
+	tb.ScrollLeft()
+	tb.ScrollLeft()
+	tb.ScrollLeft()
+	if tb.ScrollCol != 0 {
+		t.Errorf("expected to stop at column 0, got %d", tb.ScrollCol)
+	}
+	tb.HScroll = false
+	if tb.HandleKey(KeyArrowLeft) {
+		t.Error("expected <left> ignored without HScroll")
+	}
+}
+
+func TestTableFooter(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 14, 6
+	tb.Header = []string{"Item", "N"}
+	tb.Rows = [][]string{{"a", "1"}, {"b", "2"}, {"c", "3"}, {"d", "4"}}
+	tb.Footer = []string{"Total", "10"}
+	tb.ColAlign = []Align{AlignLeft, AlignRight}
+	buf := tb.Buffer()
+
+	// the footer widens the first column and takes the last inner row,
+	// leaving two rows to scroll
+	if got := bufferRow(buf, 4, 1, 13); got != "Total 10    " {
+		t.Errorf("footer: got %q", got)
+	}
+	if c := buf.At(1, 4); c.Fg != tb.FooterFg || c.Fg&AttrBold == 0 {
+		t.Errorf("expected a bold footer, got %+v", c)
+	}
+	if _, shown, _ := tb.ScrollState(true); shown != 2 {
+		t.Errorf("expected 2 rows a page, got %d", shown)
+	}
+
+	// scrolling never hides it
+	last := []string{"b      2    ", "b      2    ", "c      3    ", "d      4    "}
+	for i := range tb.Rows {
+		tb.Select(i)
+		buf = tb.Buffer()
+		if got := bufferRow(buf, 4, 1, 13); got != "Total 10    " {
+			t.Errorf("row %d selected: footer got %q", i, got)
+		}
+		if got := bufferRow(buf, 3, 1, 13); got != last[i] {
+			t.Errorf("row %d selected: expected %q over the footer, got %q", i, last[i], got)
+		}
+	}
+
+	tb.ShowRowSep = true
+	buf = tb.Buffer()
+	if got := bufferRow(buf, 3, 1, 13); got != "────────────" {
+		t.Errorf("expected a line over the footer, got %q", got)
+	}
+	if got := bufferRow(buf, 4, 1, 13); got != "Total 10    " {
+		t.Errorf("footer under the line: got %q", got)
+	}
+}
+
+func TestTableSetStructs(t *testing.T) {
+	type addr struct {
+		City string
+	}
+	type rec struct {
+		Name  string
+		Score float64 `termui:"Pts,fmt=%.2f"`
+		OK    bool
+		When  time.Time `termui:",fmt=2006-01-02"`
+		Addr  *addr
+		note  string
+		Skip  int `termui:"-"`
+	}
+	when := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	recs := []*rec{
+		{Name: "ann", Score: 1.5, OK: true, When: when, Addr: &addr{"Oslo"}, note: "x"},
+		{Name: "bob", Score: 2, When: when},
+	}
+
+	tb := NewTable()
+	if err := tb.SetStructs(recs); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Name", "Pts", "OK", "When", "City"}; !reflect.DeepEqual(tb.Header, want) {
+		t.Errorf("expected the headers %v, got %v", want, tb.Header)
+	}
+	// a nil pointer on the way leaves the cell empty
+	want := [][]string{
+		{"ann", "1.50", "true", "2024-03-01", "Oslo"},
+		{"bob", "2.00", "false", "2024-03-01", ""},
+	}
+	if !reflect.DeepEqual(tb.Rows, want) {
+		t.Errorf("expected the rows %v, got %v", want, tb.Rows)
+	}
+
+	if err := tb.SetStructs(recs, "Addr.City", "Score"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"City", "Pts"}; !reflect.DeepEqual(tb.Header, want) {
+		t.Errorf("expected the named columns %v, got %v", want, tb.Header)
+	}
+	if want := []string{"Oslo", "1.50"}; !reflect.DeepEqual(tb.Rows[0], want) {
+		t.Errorf("expected the named cells %v, got %v", want, tb.Rows[0])
+	}
+
+	for _, c := range []struct {
+		v    interface{}
+		cols []string
+	}{
+		{rec{}, nil},
+		{[]int{1}, nil},
+		{recs, []string{"Nope"}},
+		{recs, []string{"note"}},
+		{recs, []string{"Name.First"}},
+	} {
+		if err := tb.SetStructs(c.v, c.cols...); err == nil {
+			t.Errorf("expected an error for %T %v", c.v, c.cols)
+		}
+	}
+	if len(tb.Header) != 2 {
+		t.Errorf("expected a failed call to leave the table, got %v", tb.Header)
+	}
+}
+
+func TestTableState(t *testing.T) {
+	tb := NewTable()
+	tb.Header = []string{"N", "Name"}
+	tb.Rows = [][]string{{"3", "c"}, {"1", "a"}, {"2", "b"}, {"4", "d"}}
+	tb.Sort(0, true)
+	tb.SelectedRow, tb.ScrollTop = 3, 2
+
+	data, err := json.Marshal(tb.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st TableState
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatal(err)
+	}
+
+	// fewer rows, in another order, get the sort back and the rest clamped
+	r := NewTable()
+	r.Header = []string{"N", "Name"}
+	r.Rows = [][]string{{"1", "a"}, {"2", "b"}}
+	sorted := false
+	r.OnSort = func(int, bool) { sorted = true }
+	r.RestoreState(st)
+	if r.Rows[0][0] != "2" || r.SortCol != 0 || !r.SortDesc || sorted {
+		t.Errorf("expected the rows sorted down quietly, got %v", r.Rows)
+	}
+	if r.SelectedRow != 1 || r.ScrollTop != 1 {
+		t.Errorf("expected the state clamped to 2 rows, got row %d top %d", r.SelectedRow, r.ScrollTop)
+	}
+
+	st.SelectedRow = -1
+	r.RestoreState(st)
+	if r.SelectedRow != -1 {
+		t.Errorf("expected no selection kept, got %d", r.SelectedRow)
+	}
+}
+
+func TestTableEmptyText(t *testing.T) {
+	tb := NewTable()
+	tb.Width, tb.Height = 12, 4
+	tb.Header = []string{"ID", "Name"}
+	tb.EmptyText = "No data"
+	tb.EmptyFg = ColorBlue
+
+	// the header stays, the placeholder takes the row under it
+	buf := tb.Buffer()
+	if got := bufferRow(buf, 1, 1, 11); got != "ID Name   " {
+		t.Errorf("header: got %q", got)
+	}
+	if got := bufferRow(buf, 2, 1, 11); got != " No data  " {
+		t.Errorf("expected the centered placeholder, got %q", got)
+	}
+	if c := buf.At(2, 2); c.Fg != ColorBlue {
+		t.Errorf("expected the placeholder in EmptyFg, got %+v", c)
+	}
+
+	tb.Rows = [][]string{{"1", "a"}}
+	if got := bufferRow(tb.Buffer(), 2, 1, 11); got != "1  a      " {
+		t.Errorf("placeholder should go away once rows are set, got %q", got)
+	}
+}
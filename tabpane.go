@@ -0,0 +1,308 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "image"
+
+// Tab is a page of a TabPane.
+type Tab struct {
+	Label string
+	Body  Bufferer
+}
+
+// EvtTab is the data of the "/tabpane/change" event sent after a TabPane
+// switched to another tab.
+type EvtTab struct {
+	Id    string // of the TabPane
+	Prev  int
+	Index int
+	Label string
+}
+
+// Marks of the active label of a TabPane, on top of its ActiveFg and
+// ActiveBg colours.
+const (
+	TabActiveColor     = ""          // the colours only
+	TabActiveUnderline = "underline" // the label is underlined
+	TabActiveInverse   = "inverse"   // the label is in reverse video
+	TabActiveRule      = "rule"      // a line under the label, the bar taking two rows
+)
+
+// TabPane draws a bar with the labels of its Tabs and, under it, the body
+// of the active tab. The digits "1" to "9" jump to a tab, C-<left> and
+// C-<right> go to the previous and next ones and other keys go to the
+// active body if it handles keys. A click on a label activates its tab.
+/*
+  tp := termui.NewTabPane(
+      termui.Tab{Label: "cpu", Body: cpuChart},
+      termui.Tab{Label: "mem", Body: memChart})
+  tp.Width = 50
+  tp.Height = 12
+  termui.Handle("/sys/kbd", func(e termui.Event) {
+      if tp.HandleKey(termui.NormalizeKey(e)) {
+          termui.Render(tp)
+      }
+  })
+*/
+type TabPane struct {
+	Block
+	Tabs       []Tab
+	Active     int
+	ActiveFg   Attribute
+	ActiveBg   Attribute
+	InactiveFg Attribute
+	InactiveBg Attribute
+	FitBody    bool // place the active body under the bar, filling the pane
+	OnChange   func(prev, next int)
+
+	// ActiveStyle marks the active label: TabActiveColor (default),
+	// TabActiveUnderline, TabActiveInverse or TabActiveRule, which draws
+	// RuleRune under it in RuleFg.
+	ActiveStyle string
+	RuleRune    rune
+	RuleFg      Attribute
+
+	// Separator is drawn between two labels in SepFg, a blank cell
+	// separating them when empty.
+	Separator string
+	SepFg     Attribute
+
+	offset int // columns of the bar scrolled out on the left
+}
+
+// NewTabPane returns a new *TabPane of tabs with current theme, the first
+// one active.
+func NewTabPane(tabs ...Tab) *TabPane {
+	tp := &TabPane{
+		Block:    *NewBlock(),
+		Tabs:     tabs,
+		FitBody:  true,
+		RuleRune: '━',
+	}
+	tp.themeAttr(&tp.ActiveFg, "tab.active.fg")
+	tp.themeAttr(&tp.ActiveBg, "tab.active.bg")
+	tp.themeAttr(&tp.InactiveFg, "tab.fg")
+	tp.themeAttr(&tp.InactiveBg, "tab.bg")
+	tp.themeAttr(&tp.RuleFg, "tab.rule.fg")
+	tp.themeAttr(&tp.SepFg, "tab.sep.fg")
+	return tp
+}
+
+// SetActive switches to tab i, calls OnChange and sends a
+// "/tabpane/change" event if it changed.
+func (tp *TabPane) SetActive(i int) {
+	tp.Lock()
+	if i < 0 || i >= len(tp.Tabs) || i == tp.Active {
+		tp.Unlock()
+		return
+	}
+	prev := tp.Active
+	tp.Active = i
+	var old Bufferer
+	if prev >= 0 && prev < len(tp.Tabs) {
+		old = tp.Tabs[prev].Body
+	}
+	e := EvtTab{Id: tp.Id(), Prev: prev, Index: i, Label: tp.Tabs[i].Label}
+	cb := tp.OnChange
+	tp.Unlock()
+
+	// the old body is no longer drawn, nor clickable
+	if b, ok := old.(blocker); ok {
+		unregisterHit(b.block())
+	}
+	if cb != nil {
+		cb(prev, i)
+	}
+	emitEvt("/tabpane/change", e)
+}
+
+// TabPaneState is the active tab of a TabPane, see TabPane.State.
+type TabPaneState struct {
+	Active int `json:"active"`
+}
+
+// State returns a snapshot of tp's active tab, it can be saved, e.g. as
+// JSON, and given back to RestoreState.
+func (tp *TabPane) State() TabPaneState {
+	tp.RLock()
+	defer tp.RUnlock()
+	return TabPaneState{Active: tp.Active}
+}
+
+// RestoreState restores a snapshot taken by State, clamped to the current
+// tabs. OnChange is not called and no event is sent.
+func (tp *TabPane) RestoreState(st TabPaneState) {
+	tp.Lock()
+	prev := tp.Active
+	tp.Active = clampInt(st.Active, 0, max0(len(tp.Tabs)-1))
+	var old Bufferer
+	if prev != tp.Active && prev >= 0 && prev < len(tp.Tabs) {
+		old = tp.Tabs[prev].Body
+	}
+	tp.Unlock()
+
+	if b, ok := old.(blocker); ok {
+		unregisterHit(b.block())
+	}
+}
+
+// HandleKey switches tabs with "1" to "9", C-<left> and C-<right>, wrapping
+// around, and passes any other key to the active body. It reports whether
+// key was consumed.
+func (tp *TabPane) HandleKey(key string) bool {
+	tp.RLock()
+	cur, n := tp.Active, len(tp.Tabs)
+	var body Bufferer
+	if cur >= 0 && cur < n {
+		body = tp.Tabs[cur].Body
+	}
+	tp.RUnlock()
+	if n == 0 {
+		return false
+	}
+
+	switch {
+	case key == KeyCtrlLeft:
+		tp.SetActive((cur + n - 1) % n)
+	case key == KeyCtrlRight:
+		tp.SetActive((cur + 1) % n)
+	case len(key) == 1 && key[0] >= '1' && key[0] <= '9' && int(key[0]-'1') < n:
+		tp.SetActive(int(key[0] - '1'))
+	default:
+		if h, ok := body.(interface {
+			HandleKey(string) bool
+		}); ok {
+			return h.HandleKey(key)
+		}
+		return false
+	}
+	return true
+}
+
+// labelCells returns the cells of the label of tab i.
+func (tp *TabPane) labelCells(i int) []Cell {
+	fg, bg := tp.InactiveFg, tp.InactiveBg
+	if i == tp.Active {
+		fg, bg = tp.ActiveFg, tp.ActiveBg
+		switch tp.ActiveStyle {
+		case TabActiveUnderline:
+			fg |= AttrUnderline
+		case TabActiveInverse:
+			fg |= AttrReverse
+		}
+	}
+	return TextCells(" "+tp.Tabs[i].Label+" ", fg, bg)
+}
+
+// sepCells returns the cells drawn between two labels.
+func (tp *TabPane) sepCells() []Cell {
+	if tp.Separator == "" {
+		return []Cell{{' ', tp.InactiveFg, tp.InactiveBg}}
+	}
+	return TextCells(tp.Separator, tp.SepFg, tp.InactiveBg)
+}
+
+// barHeight returns the rows taken by the bar.
+func (tp *TabPane) barHeight() int {
+	if tp.ActiveStyle == TabActiveRule {
+		return 2
+	}
+	return 1
+}
+
+// drawBar draws the labels on the first inner row, scrolled to show the
+// active one, and makes them clickable.
+func (tp *TabPane) drawBar(buf Buffer) {
+	r := tp.innerArea
+	w := r.Dx()
+	sep := tp.sepCells()
+	gap := cellsWidth(sep)
+	xs := make([]int, len(tp.Tabs)+1) // start of each label on the bar
+	for i := range tp.Tabs {
+		xs[i+1] = xs[i] + cellsWidth(tp.labelCells(i)) + gap
+	}
+	if a := tp.Active; a >= 0 && a < len(tp.Tabs) {
+		if xs[a] < tp.offset {
+			tp.offset = xs[a]
+		}
+		if end := xs[a+1] - gap; end > tp.offset+w {
+			tp.offset = end - w
+		}
+	}
+
+	set := func(x, y int, c Cell) {
+		if x >= r.Min.X && x+c.Width() <= r.Max.X && y < r.Max.Y {
+			buf.Set(x, y, c)
+		}
+	}
+	for i := range tp.Tabs {
+		x := r.Min.X + xs[i] - tp.offset
+		for _, c := range tp.labelCells(i) {
+			set(x, r.Min.Y, c)
+			x += c.Width()
+		}
+		x0, x1 := r.Min.X+xs[i]-tp.offset, x
+		if i == tp.Active && tp.ActiveStyle == TabActiveRule {
+			for rx := x0; rx < x1; rx++ {
+				set(rx, r.Min.Y+1, Cell{tp.RuleRune, tp.RuleFg, tp.InactiveBg})
+			}
+		}
+		if i < len(tp.Tabs)-1 {
+			for _, c := range sep {
+				set(x, r.Min.Y, c)
+				x += c.Width()
+			}
+		}
+		if x0 < r.Min.X {
+			x0 = r.Min.X
+		}
+		if x1 > r.Max.X {
+			x1 = r.Max.X
+		}
+		if x0 < x1 {
+			tab := i
+			tp.AddHotspot(image.Rect(x0, r.Min.Y, x1, r.Min.Y+tp.barHeight()), func(int, int) { tp.SetActive(tab) })
+		}
+	}
+	// mark the ends of the bar scrolled out of view
+	fg := ScrollIndicators.Fg
+	if fg == ColorDefault {
+		fg = tp.BorderFg
+	}
+	if tp.offset > 0 {
+		buf.Set(r.Min.X, r.Min.Y, Cell{ScrollIndicators.Left, fg, tp.InactiveBg})
+	}
+	if xs[len(tp.Tabs)]-gap-tp.offset > w {
+		buf.Set(r.Max.X-1, r.Min.Y, Cell{ScrollIndicators.Right, fg, tp.InactiveBg})
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (tp *TabPane) Buffer() Buffer {
+	buf := tp.Block.Buffer()
+	tp.Lock()
+	if tp.innerArea.Dy() <= 0 {
+		tp.Unlock()
+		return buf
+	}
+	tp.drawBar(buf)
+	var body Bufferer
+	if tp.Active >= 0 && tp.Active < len(tp.Tabs) {
+		body = tp.Tabs[tp.Active].Body
+	}
+	r := tp.innerArea
+	r.Min.Y += tp.barHeight()
+	fit := tp.FitBody
+	tp.Unlock()
+
+	if body == nil {
+		return buf
+	}
+	if fit {
+		placeIn(body, r)
+	}
+	buf.Merge(bufferOf(body))
+	return buf
+}
@@ -0,0 +1,151 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTabPane(t *testing.T) {
+	_, done := useFakeBackend(30, 10)
+	defer done()
+	defer resetHits()
+
+	l := NewList()
+	l.Items = []string{"x", "y"}
+	l.Selectable = true
+	p := NewPar("text")
+	tp := NewTabPane(Tab{"one", l}, Tab{"two", p}, Tab{"three", nil})
+	tp.Width, tp.Height = 20, 8
+
+	buf := tp.Buffer()
+	if got := bufferRow(buf, 1, 1, 17); got != " one   two   thr" {
+		t.Errorf("bar: got %q", got)
+	}
+	if c := buf.At(2, 1); c.Bg != tp.ActiveBg {
+		t.Errorf("expected the active label highlighted, got %+v", c)
+	}
+	if in := l.InnerBounds(); in.Min.Y != 3 || in.Dx() != 16 {
+		t.Errorf("expected the body under the bar, got %v", in)
+	}
+
+	changes := [][2]int{}
+	tp.OnChange = func(prev, next int) { changes = append(changes, [2]int{prev, next}) }
+	if !tp.HandleKey(KeyArrowDown) || l.SelectedRow != 1 {
+		t.Error("expected other keys to reach the active body")
+	}
+	tp.HandleKey("2")
+	tp.HandleKey(KeyCtrlRight)
+	tp.HandleKey(KeyCtrlRight)
+	if tp.Active != 0 || len(changes) != 3 || changes[2] != [2]int{2, 0} {
+		t.Errorf("expected to wrap around to the first tab, got %d %v", tp.Active, changes)
+	}
+	if tp.HandleKey("4") {
+		t.Error("there is no fourth tab")
+	}
+
+	Render(tp)
+	dispatchHotspot(click(8, 1))
+	if tp.Active != 1 {
+		t.Errorf("expected a click on a label to switch tabs, got %d", tp.Active)
+	}
+	buf = tp.Buffer()
+	if got := bufferRow(buf, 3, 2, 6); got != "text" {
+		t.Errorf("expected the second body, got %q", got)
+	}
+
+	tp.Width = 10
+	tp.SetActive(2)
+	buf = tp.Buffer()
+	if got := bufferRow(buf, 1, 1, 9); got != "◀ three " {
+		t.Errorf("expected the bar scrolled to the last tab, got %q", got)
+	}
+}
+
+func TestTabPaneActiveStyle(t *testing.T) {
+	_, done := useFakeBackend(30, 10)
+	defer done()
+	defer resetHits()
+
+	p := NewPar("text")
+	p.Border = false
+	tp := NewTabPane(Tab{"one", p}, Tab{"两", nil}, Tab{"three", nil})
+	tp.Width, tp.Height = 20, 6
+	tp.Separator = "│"
+	tp.SepFg = ColorRed
+
+	buf := tp.Buffer()
+	// the wide label takes two cells
+	if got := bufferRow(buf, 1, 1, 19); got != " one │ 两 │ three " {
+		t.Errorf("bar: got %q", got)
+	}
+	if c := buf.At(6, 1); c.Ch != '│' || c.Fg != ColorRed {
+		t.Errorf("expected a red separator, got %+v", c)
+	}
+
+	tp.ActiveStyle = TabActiveUnderline
+	buf = tp.Buffer()
+	if c := buf.At(2, 1); c.Fg&AttrUnderline == 0 || c.Bg != tp.ActiveBg {
+		t.Errorf("expected the active label underlined, got %+v", c)
+	}
+	if c := buf.At(8, 1); c.Fg&AttrUnderline != 0 {
+		t.Errorf("expected the other labels plain, got %+v", c)
+	}
+	tp.ActiveStyle = TabActiveInverse
+	if c := tp.Buffer().At(2, 1); c.Fg&AttrReverse == 0 {
+		t.Errorf("expected the active label inverted, got %+v", c)
+	}
+
+	// the rule takes a second row, under the active label only
+	tp.ActiveStyle = TabActiveRule
+	tp.SetActive(1)
+	buf = tp.Buffer()
+	if got := bufferRow(buf, 2, 1, 19); got != "      ━━━━        " {
+		t.Errorf("rule: got %q", got)
+	}
+	if c := buf.At(7, 2); c.Fg != tp.RuleFg {
+		t.Errorf("expected the rule in RuleFg, got %+v", c)
+	}
+	tp.SetActive(0)
+	tp.Buffer()
+	if in := p.InnerBounds(); in.Min.Y != 3 {
+		t.Errorf("expected the body under the rule, got %v", in)
+	}
+
+	// the active label stays in view with the wider separators
+	tp.Width = 12
+	tp.SetActive(2)
+	buf = tp.Buffer()
+	if got := bufferRow(buf, 1, 1, 11); got != "◀ │ three " {
+		t.Errorf("expected the bar scrolled to the last tab, got %q", got)
+	}
+	Render(tp)
+	dispatchHotspot(click(5, 2))
+	if tp.Active != 2 {
+		t.Errorf("expected a click on the rule to keep the last tab, got %d", tp.Active)
+	}
+}
+
+func TestTabPaneState(t *testing.T) {
+	tp := NewTabPane(Tab{"a", nil}, Tab{"b", nil}, Tab{"c", nil})
+	tp.SetActive(2)
+	data, err := json.Marshal(tp.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st TabPaneState
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewTabPane(Tab{"a", nil}, Tab{"b", nil})
+	changed := false
+	r.OnChange = func(int, int) { changed = true }
+	r.RestoreState(st)
+	if r.Active != 1 || changed {
+		t.Errorf("expected the last tab quietly, got %d %v", r.Active, changed)
+	}
+}
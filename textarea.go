@@ -0,0 +1,420 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TextArea is a multi-line text editor scrolling to keep its cursor
+// visible. Besides the arrow, <home>/<end> and page keys it moves by word
+// with M-<left>/M-<right> (or M-b/M-f). <C-space> starts a selection that
+// moving the cursor extends and <escape> drops; S- prefixed moves, from
+// backends that report them, select too. Typing or deleting replaces the
+// selection.
+/*
+  ta := termui.NewTextArea()
+  ta.SetValue("Subject\n\nBody")
+  ta.Width = 40
+  ta.Height = 10
+  termui.DefaultFocus.Add(ta)
+*/
+type TextArea struct {
+	Block
+	TextFgColor Attribute
+	TextBgColor Attribute
+	OnChange    func(value string) // called after each edit
+
+	lines     [][]rune
+	cur       textPos
+	anchor    textPos // other end of the selection
+	selecting bool
+	shiftSel  bool // the selection ends with the next move without shift
+	top, left int  // first visible row and column
+}
+
+// textPos is a position in a TextArea, col counts runes.
+type textPos struct {
+	row, col int
+}
+
+func (p textPos) before(q textPos) bool {
+	return p.row < q.row || (p.row == q.row && p.col < q.col)
+}
+
+// NewTextArea returns a new empty *TextArea with current theme.
+func NewTextArea() *TextArea {
+	ta := &TextArea{
+		Block: *NewBlock(),
+		lines: [][]rune{{}},
+	}
+	ta.themeAttr(&ta.TextFgColor, "textarea.text.fg")
+	ta.themeAttr(&ta.TextBgColor, "textarea.text.bg")
+	return ta
+}
+
+// Value returns the text, lines joined by '\n'.
+func (ta *TextArea) Value() string {
+	ta.RLock()
+	defer ta.RUnlock()
+	return ta.text(textPos{}, ta.end())
+}
+
+// SetValue replaces the text, moving the cursor to the start and dropping
+// the selection.
+func (ta *TextArea) SetValue(s string) {
+	ta.Lock()
+	defer ta.Unlock()
+	ta.lines = ta.lines[:0]
+	for _, l := range strings.Split(s, "\n") {
+		ta.lines = append(ta.lines, []rune(l))
+	}
+	ta.cur, ta.anchor, ta.selecting = textPos{}, textPos{}, false
+	ta.top, ta.left = 0, 0
+}
+
+// CursorPos returns the row and the column, in runes, of the cursor.
+func (ta *TextArea) CursorPos() (row, col int) {
+	ta.RLock()
+	defer ta.RUnlock()
+	return ta.cur.row, ta.cur.col
+}
+
+// SetCursor moves the cursor, clamped to the text, dropping the selection.
+func (ta *TextArea) SetCursor(row, col int) {
+	ta.Lock()
+	defer ta.Unlock()
+	ta.cur = ta.clamp(textPos{row, col})
+	ta.selecting = false
+}
+
+// Selection returns the selected text, "" if nothing is selected.
+func (ta *TextArea) Selection() string {
+	ta.RLock()
+	defer ta.RUnlock()
+	if !ta.selecting {
+		return ""
+	}
+	from, to := ta.selRange()
+	return ta.text(from, to)
+}
+
+// SelectAll selects the whole text.
+func (ta *TextArea) SelectAll() {
+	ta.Lock()
+	defer ta.Unlock()
+	ta.anchor, ta.cur = textPos{}, ta.end()
+	ta.selecting, ta.shiftSel = true, true
+}
+
+// ClearSelection drops the selection, keeping the text.
+func (ta *TextArea) ClearSelection() {
+	ta.Lock()
+	defer ta.Unlock()
+	ta.selecting = false
+}
+
+func (ta *TextArea) end() textPos {
+	n := len(ta.lines) - 1
+	return textPos{n, len(ta.lines[n])}
+}
+
+func (ta *TextArea) clamp(p textPos) textPos {
+	if p.row >= len(ta.lines) {
+		p.row = len(ta.lines) - 1
+	}
+	if p.row < 0 {
+		p.row = 0
+	}
+	if p.col > len(ta.lines[p.row]) {
+		p.col = len(ta.lines[p.row])
+	}
+	if p.col < 0 {
+		p.col = 0
+	}
+	return p
+}
+
+// selRange returns the ends of the selection in order.
+func (ta *TextArea) selRange() (from, to textPos) {
+	if ta.cur.before(ta.anchor) {
+		return ta.cur, ta.anchor
+	}
+	return ta.anchor, ta.cur
+}
+
+// text returns the text between from and to.
+func (ta *TextArea) text(from, to textPos) string {
+	if from.row == to.row {
+		return string(ta.lines[from.row][from.col:to.col])
+	}
+	ss := []string{string(ta.lines[from.row][from.col:])}
+	for r := from.row + 1; r < to.row; r++ {
+		ss = append(ss, string(ta.lines[r]))
+	}
+	ss = append(ss, string(ta.lines[to.row][:to.col]))
+	return strings.Join(ss, "\n")
+}
+
+// remove deletes the text between from and to and puts the cursor there.
+func (ta *TextArea) remove(from, to textPos) {
+	tail := ta.lines[to.row][to.col:]
+	line := append(append([]rune{}, ta.lines[from.row][:from.col]...), tail...)
+	ta.lines = append(ta.lines[:from.row+1], ta.lines[to.row+1:]...)
+	ta.lines[from.row] = line
+	ta.cur = from
+}
+
+// insert adds rs, which may hold '\n', at the cursor.
+func (ta *TextArea) insert(rs []rune) {
+	row, col := ta.cur.row, ta.cur.col
+	line := ta.lines[row]
+	tail := append([]rune{}, line[col:]...)
+	ta.lines[row] = line[:col]
+
+	for _, r := range rs {
+		if r == '\n' {
+			row++
+			ta.lines = append(ta.lines[:row], append([][]rune{{}}, ta.lines[row:]...)...)
+			col = 0
+			continue
+		}
+		ta.lines[row] = append(ta.lines[row], r)
+		col++
+	}
+	ta.lines[row] = append(ta.lines[row], tail...)
+	ta.cur = textPos{row, col}
+}
+
+// deleteSelection removes the selected text, it reports whether there was
+// one.
+func (ta *TextArea) deleteSelection() bool {
+	if !ta.selecting {
+		return false
+	}
+	ta.selecting = false
+	from, to := ta.selRange()
+	if from == to {
+		return false
+	}
+	ta.remove(from, to)
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// at returns the rune before (d < 0) or at (d > 0) p, '\n' across lines and
+// false past the ends of the text.
+func (ta *TextArea) at(p textPos, d int) (rune, bool) {
+	if d < 0 {
+		if p.col > 0 {
+			return ta.lines[p.row][p.col-1], true
+		}
+		return '\n', p.row > 0
+	}
+	if p.col < len(ta.lines[p.row]) {
+		return ta.lines[p.row][p.col], true
+	}
+	return '\n', p.row < len(ta.lines)-1
+}
+
+// step moves p by one rune in direction d, across lines.
+func (ta *TextArea) step(p textPos, d int) textPos {
+	if d < 0 {
+		if p.col > 0 {
+			return textPos{p.row, p.col - 1}
+		}
+		if p.row > 0 {
+			return textPos{p.row - 1, len(ta.lines[p.row-1])}
+		}
+		return p
+	}
+	if p.col < len(ta.lines[p.row]) {
+		return textPos{p.row, p.col + 1}
+	}
+	if p.row < len(ta.lines)-1 {
+		return textPos{p.row + 1, 0}
+	}
+	return p
+}
+
+// word moves p past the next word in direction d.
+func (ta *TextArea) word(p textPos, d int) textPos {
+	for r, ok := ta.at(p, d); ok && !isWordRune(r); r, ok = ta.at(p, d) {
+		p = ta.step(p, d)
+	}
+	for r, ok := ta.at(p, d); ok && isWordRune(r); r, ok = ta.at(p, d) {
+		p = ta.step(p, d)
+	}
+	return p
+}
+
+// moveTo returns where the movement key takes the cursor, ok is false if
+// key is not a movement.
+func (ta *TextArea) moveTo(key string) (p textPos, ok bool) {
+	p, h := ta.cur, ta.innerArea.Dy()
+	if h < 1 {
+		h = 1
+	}
+	switch key {
+	case KeyArrowLeft:
+		p = ta.step(p, -1)
+	case KeyArrowRight:
+		p = ta.step(p, 1)
+	case KeyArrowUp:
+		p.row--
+	case KeyArrowDown:
+		p.row++
+	case KeyPgUp:
+		p.row -= h
+	case KeyPgDn:
+		p.row += h
+	case KeyHome, "C-a":
+		p.col = 0
+	case KeyEnd, "C-e":
+		p.col = len(ta.lines[p.row])
+	case "M-<left>", "M-b":
+		p = ta.word(p, -1)
+	case "M-<right>", "M-f":
+		p = ta.word(p, 1)
+	default:
+		return p, false
+	}
+	return ta.clamp(p), true
+}
+
+// HandleKey edits the text or moves the cursor, see TextArea. It reports
+// whether key was used.
+func (ta *TextArea) HandleKey(key string) bool {
+	ta.Lock()
+	ta.cur = ta.clamp(ta.cur)
+	edited := false
+
+	switch key {
+	case KeyCtrlSpace:
+		ta.selecting, ta.shiftSel = !ta.selecting, false
+		ta.anchor = ta.cur
+	case KeyEsc:
+		ta.selecting = false
+	case KeyEnter:
+		ta.deleteSelection()
+		ta.insert([]rune{'\n'})
+		edited = true
+	case KeyBackspace, KeyDelete:
+		edited = ta.deleteSelection()
+		if !edited {
+			d := -1
+			if key == KeyDelete {
+				d = 1
+			}
+			p := ta.step(ta.cur, d)
+			from, to := p, ta.cur
+			if d > 0 {
+				from, to = ta.cur, p
+			}
+			if from != to {
+				ta.remove(from, to)
+				edited = true
+			}
+		}
+	default:
+		shift := strings.HasPrefix(key, "S-")
+		if p, ok := ta.moveTo(strings.TrimPrefix(key, "S-")); ok {
+			switch {
+			case shift && !ta.selecting:
+				ta.selecting, ta.shiftSel, ta.anchor = true, true, ta.cur
+			case !shift && ta.shiftSel:
+				ta.selecting = false
+			}
+			ta.cur = p
+			break
+		}
+		r := []rune(key)
+		if key == KeySpace {
+			r = []rune{' '}
+		}
+		if len(r) != 1 || r[0] < ' ' {
+			ta.Unlock()
+			return false
+		}
+		ta.deleteSelection()
+		ta.insert(r)
+		edited = true
+	}
+
+	cb := ta.OnChange
+	var v string
+	if edited && cb != nil {
+		v = ta.text(textPos{}, ta.end())
+	}
+	ta.Unlock()
+
+	if edited && cb != nil {
+		cb(v)
+	}
+	return true
+}
+
+// scroll moves the view so the cursor is visible in a w x h area.
+func (ta *TextArea) scroll(w, h int) {
+	if ta.cur.row < ta.top {
+		ta.top = ta.cur.row
+	}
+	if ta.cur.row >= ta.top+h {
+		ta.top = ta.cur.row - h + 1
+	}
+	line := ta.lines[ta.cur.row]
+	if ta.cur.col < ta.left {
+		ta.left = ta.cur.col
+	}
+	for ta.left < ta.cur.col && runesWidth(line[ta.left:ta.cur.col])+1 > w {
+		ta.left++
+	}
+}
+
+// Buffer implements Bufferer interface.
+func (ta *TextArea) Buffer() Buffer {
+	buf := ta.Block.Buffer()
+	show := ta.Focused() || !DefaultFocus.contains(ta)
+	ta.Lock()
+	defer ta.Unlock()
+
+	in := ta.innerArea
+	if in.Empty() {
+		return buf
+	}
+	ta.cur = ta.clamp(ta.cur)
+	ta.scroll(in.Dx(), in.Dy())
+
+	from, to := ta.selRange()
+	for y := 0; y < in.Dy() && ta.top+y < len(ta.lines); y++ {
+		row := ta.top + y
+		line := ta.lines[row]
+		x := in.Min.X
+		for col := ta.left; col <= len(line); col++ {
+			p := textPos{row, col}
+			isCur := show && p == ta.cur
+			if col == len(line) && !isCur {
+				break
+			}
+			c := Cell{' ', ta.TextFgColor, ta.TextBgColor}
+			if col < len(line) {
+				c.Ch = line[col]
+			}
+			if x+c.Width() > in.Max.X {
+				break
+			}
+			if isCur || (ta.selecting && !p.before(from) && p.before(to)) {
+				c.Fg |= AttrReverse
+			}
+			buf.Set(x, in.Min.Y+y, c)
+			x += c.Width()
+		}
+	}
+	return buf
+}
@@ -0,0 +1,103 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func typeKeys(ta *TextArea, keys ...string) {
+	for _, k := range keys {
+		ta.HandleKey(k)
+	}
+}
+
+func TestTextAreaEdit(t *testing.T) {
+	ta := NewTextArea()
+	typeKeys(ta, "a", "b", KeyEnter, "c", KeyArrowUp, KeyEnd, KeySpace, "x")
+	if v := ta.Value(); v != "ab x\nc" {
+		t.Errorf("got %q", v)
+	}
+
+	// backspace joins lines
+	ta.SetCursor(1, 0)
+	typeKeys(ta, KeyBackspace)
+	if v := ta.Value(); v != "ab xc" {
+		t.Errorf("backspace at a line start should join lines, got %q", v)
+	}
+	typeKeys(ta, KeyDelete, KeyHome, KeyDelete)
+	if v := ta.Value(); v != "b x" {
+		t.Errorf("got %q", v)
+	}
+
+	ta.SetValue("one two\nthree")
+	if r, c := ta.CursorPos(); r != 0 || c != 0 {
+		t.Errorf("SetValue should move the cursor to the start, got %d,%d", r, c)
+	}
+	typeKeys(ta, "M-<right>")
+	if _, c := ta.CursorPos(); c != 3 {
+		t.Errorf("expected the cursor after the first word, got %d", c)
+	}
+	typeKeys(ta, "M-<right>", "M-<right>")
+	if r, c := ta.CursorPos(); r != 1 || c != 5 {
+		t.Errorf("word moves should cross lines, got %d,%d", r, c)
+	}
+	typeKeys(ta, "M-b")
+	if r, c := ta.CursorPos(); r != 1 || c != 0 {
+		t.Errorf("expected the start of the word, got %d,%d", r, c)
+	}
+}
+
+func TestTextAreaSelection(t *testing.T) {
+	ta := NewTextArea()
+	ta.SetValue("hello world\nfoo")
+
+	ta.SetCursor(0, 6)
+	typeKeys(ta, KeyCtrlSpace, KeyArrowDown)
+	if s := ta.Selection(); s != "world\nfoo" {
+		t.Errorf("got selection %q", s)
+	}
+	typeKeys(ta, "!")
+	if v := ta.Value(); v != "hello !" || ta.Selection() != "" {
+		t.Errorf("typing should replace the selection, got %q", v)
+	}
+
+	typeKeys(ta, "S-<left>", "S-<left>")
+	if s := ta.Selection(); s != " !" {
+		t.Errorf("shift moves should select, got %q", s)
+	}
+	typeKeys(ta, KeyArrowLeft)
+	if s := ta.Selection(); s != "" {
+		t.Errorf("a move without shift should drop the selection, got %q", s)
+	}
+
+	ta.SelectAll()
+	typeKeys(ta, KeyBackspace)
+	if v := ta.Value(); v != "" {
+		t.Errorf("deleting a full selection should empty the text, got %q", v)
+	}
+}
+
+func TestTextAreaScroll(t *testing.T) {
+	ta := NewTextArea()
+	ta.Width, ta.Height = 6, 4 // 4x2 inside the border
+	ta.SetValue("a\nb\nc\nabcdefg")
+
+	ta.SetCursor(3, 7)
+	buf := ta.Buffer()
+	// all lines scroll horizontally together, leaving "c" out of view
+	if s := bufferRow(buf, 1, 1, 5); s != "    " {
+		t.Errorf("expected the view scrolled, got %q", s)
+	}
+	if s := bufferRow(buf, 2, 1, 5); s != "efg " {
+		t.Errorf("expected the line scrolled to the cursor, got %q", s)
+	}
+	if c := buf.At(4, 2); c.Fg&AttrReverse == 0 {
+		t.Error("expected the cursor at the line end")
+	}
+
+	ta.SetCursor(0, 0)
+	if s := bufferRow(ta.Buffer(), 1, 1, 5); s != "a   " {
+		t.Errorf("expected the view scrolled back, got %q", s)
+	}
+}
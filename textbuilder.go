@@ -57,7 +57,8 @@ func rmSpc(s string) string {
 	return reg.ReplaceAllString(s, "")
 }
 
-// readAttr translates strings like `fg-red,fg-bold,bg-white` to fg and bg Attribute
+// readAttr translates strings like `fg-red,fg-bold,bg-white` to fg and bg
+// Attribute, `red,bold` standing for `fg-red,fg-bold`.
 func (mtb MarkdownTxBuilder) readAttr(s string) (Attribute, Attribute) {
 	fg := mtb.baseFg
 	bg := mtb.baseBg
@@ -65,9 +66,13 @@ func (mtb MarkdownTxBuilder) readAttr(s string) (Attribute, Attribute) {
 	updateAttr := func(a Attribute, attrs []string) Attribute {
 		for _, s := range attrs {
 			// replace the color
-			if c, ok := colorMap[s]; ok {
-				a &= 0xFF00 // erase clr 0 ~ 8 bits
-				a |= c      // set clr
+			c, ok := colorMap[s]
+			if !ok {
+				c, ok = hexColor(s)
+			}
+			if ok {
+				a &^= attrColorMask // erase clr
+				a |= c              // set clr
 			}
 			// add attrs
 			if c, ok := attrMap[s]; ok {
@@ -77,11 +82,15 @@ func (mtb MarkdownTxBuilder) readAttr(s string) (Attribute, Attribute) {
 		return a
 	}
 
-	ss := strings.Split(s, ",")
+	ss := strings.Split(rmSpc(s), ",")
 	fgs := []string{}
 	bgs := []string{}
 	for _, v := range ss {
 		subs := strings.Split(v, "-")
+		// a bare "red" or "bold" styles the foreground
+		if len(subs) == 1 {
+			fgs = append(fgs, v)
+		}
 		if len(subs) > 1 {
 			if subs[0] == "fg" {
 				fgs = append(fgs, subs[1])
@@ -195,8 +204,12 @@ func wrapTx(cs []Cell, wl int) []Cell {
 	tmpCell := make([]Cell, len(cs))
 	copy(tmpCell, cs)
 
-	// get the plaintext
-	plain := CellsToStr(cs)
+	// get the plaintext, a rune per cell
+	rs := make([]rune, len(cs))
+	for i, c := range cs {
+		rs[i] = c.Ch
+	}
+	plain := string(rs)
 
 	// wrap
 	plainWrapped := wordwrap.WrapString(plain, uint(wl))
@@ -272,6 +285,12 @@ func (mtb MarkdownTxBuilder) Build(s string, fg, bg Attribute) []Cell {
 	return cs
 }
 
+// PlainText returns s as DefaultTxBuilder shows it, without the markup,
+// e.g. "error occurred" for "[error](fg-red,fg-bold) occurred".
+func PlainText(s string) string {
+	return CellsToStr(DefaultTxBuilder.Build(s, ColorDefault, ColorDefault))
+}
+
 // NewMarkdownTxBuilder returns a TextBuilder employing markdown syntax.
 func NewMarkdownTxBuilder() TextBuilder {
 	return MarkdownTxBuilder{}
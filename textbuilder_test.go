@@ -14,6 +14,9 @@ func TestReadAttr(t *testing.T) {
 	if fg != ColorRed|AttrUnderline || bg != ColorBlue|AttrBold|AttrReverse {
 		t.Error("readAttr failed")
 	}
+	if fg, _ := m.readAttr("red, bold"); fg != ColorRed|AttrUnderline|AttrBold {
+		t.Error("readAttr failed on bare attributes")
+	}
 }
 
 func TestMTBParse(t *testing.T) {
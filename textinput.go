@@ -0,0 +1,228 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "unicode/utf8"
+
+// TextInput is a single line text editor. The text scrolls horizontally
+// to keep the cursor visible.
+/*
+  in := termui.NewTextInput()
+  in.BorderLabel = "Password"
+  in.Mask = '*'
+  in.Width = 20
+  in.OnSubmit = func(s string) { ... }
+  termui.DefaultFocus.Add(in)
+*/
+type TextInput struct {
+	Block
+	Text          string
+	Cursor        int    // position of the cursor, in runes
+	Placeholder   string // shown while Text is empty
+	Mask          rune   // when set, drawn instead of every rune of Text
+	TextFgColor   Attribute
+	TextBgColor   Attribute
+	PlaceholderFg Attribute
+	OnChange      func(text string) // called after each edit
+	OnSubmit      func(text string) // called on <enter>
+	offset        int               // first visible rune
+	inForm        bool              // the focus is managed by a Form
+}
+
+// NewTextInput returns a new *TextInput with current theme, 3 rows high so
+// the text fits in the border.
+func NewTextInput() *TextInput {
+	t := &TextInput{
+		Block: *NewBlock(),
+	}
+	t.themeAttr(&t.TextFgColor, "textinput.text.fg")
+	t.themeAttr(&t.TextBgColor, "textinput.text.bg")
+	t.themeAttr(&t.PlaceholderFg, "textinput.placeholder.fg")
+	t.Height = 3
+	return t
+}
+
+// SetText replaces the text and moves the cursor to its end.
+func (t *TextInput) SetText(s string) {
+	t.Lock()
+	defer t.Unlock()
+	t.Text = s
+	t.Cursor = utf8.RuneCountInString(s)
+}
+
+// HandleKey edits the text: printable keys and <space> insert at the
+// cursor, <left>/<right>/<home>/<end> move it and <backspace>/<delete>
+// remove a rune. <enter> calls OnSubmit. It reports whether key was used.
+func (t *TextInput) HandleKey(key string) bool {
+	t.Lock()
+	rs := []rune(t.Text)
+	cur := t.Cursor
+	if cur > len(rs) {
+		cur = len(rs)
+	}
+	if cur < 0 {
+		cur = 0
+	}
+	edited := false
+
+	switch key {
+	case KeyArrowLeft:
+		if cur > 0 {
+			cur--
+		}
+	case KeyArrowRight:
+		if cur < len(rs) {
+			cur++
+		}
+	case KeyHome:
+		cur = 0
+	case KeyEnd:
+		cur = len(rs)
+	case KeyBackspace:
+		if cur > 0 {
+			rs = append(rs[:cur-1], rs[cur:]...)
+			cur--
+			edited = true
+		}
+	case KeyDelete:
+		if cur < len(rs) {
+			rs = append(rs[:cur], rs[cur+1:]...)
+			edited = true
+		}
+	case KeyEnter:
+		cb, s := t.OnSubmit, t.Text
+		t.Unlock()
+		if cb != nil {
+			cb(s)
+		}
+		return true
+	default:
+		r := []rune(key)
+		if key == KeySpace {
+			r = []rune{' '}
+		}
+		if len(r) != 1 || r[0] < ' ' {
+			t.Unlock()
+			return false
+		}
+		rs = append(rs[:cur], append(r, rs[cur:]...)...)
+		cur++
+		edited = true
+	}
+
+	t.Text = string(rs)
+	t.Cursor = cur
+	cb, s := t.OnChange, t.Text
+	t.Unlock()
+
+	if edited && cb != nil {
+		cb(s)
+	}
+	return true
+}
+
+// FormValue implements FormValuer, the value is Text.
+func (t *TextInput) FormValue() interface{} {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Text
+}
+
+// scroll moves offset so the cursor cell fits in w columns, without
+// leaving columns empty at the end while text is hidden at the start.
+func (t *TextInput) scroll(rs []rune, w int) {
+	if t.offset > t.Cursor {
+		t.offset = t.Cursor
+	}
+	for t.offset < t.Cursor && runesWidth(rs[t.offset:t.Cursor])+1 > w {
+		t.offset++
+	}
+	for t.offset > 0 && runesWidth(rs[t.offset-1:])+1 <= w {
+		t.offset--
+	}
+}
+
+func runesWidth(rs []rune) int {
+	w := 0
+	for _, r := range rs {
+		w += charWidth(r)
+	}
+	return w
+}
+
+// showCursor tells if the cursor is drawn: unless the input is managed by
+// DefaultFocus or a Form, in which case only while it is focused.
+func (t *TextInput) showCursor() bool {
+	if t.Focused() {
+		return true
+	}
+	t.RLock()
+	inForm := t.inForm
+	t.RUnlock()
+	return !inForm && !DefaultFocus.contains(t)
+}
+
+// Buffer implements Bufferer interface.
+func (t *TextInput) Buffer() Buffer {
+	buf := t.Block.Buffer()
+	show := t.showCursor()
+	t.Lock()
+	defer t.Unlock()
+
+	in := t.innerArea
+	if in.Empty() {
+		return buf
+	}
+	y := in.Min.Y
+
+	rs := []rune(t.Text)
+	if t.Cursor > len(rs) {
+		t.Cursor = len(rs)
+	}
+	if t.Cursor < 0 {
+		t.Cursor = 0
+	}
+	cursor := Cell{' ', t.TextFgColor | AttrReverse, t.TextBgColor}
+
+	if len(rs) == 0 {
+		cs := fitCells(TextCells(t.Placeholder, t.PlaceholderFg, t.TextBgColor), in.Dx())
+		for i, x := 0, in.Min.X; i < len(cs); i++ {
+			buf.Set(x, y, cs[i])
+			x += cs[i].Width()
+		}
+		if show {
+			if len(cs) > 0 {
+				cursor.Ch = cs[0].Ch
+			}
+			buf.Set(in.Min.X, y, cursor)
+		}
+		return buf
+	}
+
+	if t.Mask != 0 {
+		for i := range rs {
+			rs[i] = t.Mask
+		}
+	}
+	t.scroll(rs, in.Dx())
+
+	x := in.Min.X
+	for i := t.offset; i < len(rs) || (show && i == t.Cursor); i++ {
+		c := Cell{' ', t.TextFgColor, t.TextBgColor}
+		if i < len(rs) {
+			c.Ch = rs[i]
+		}
+		if x+c.Width() > in.Max.X {
+			break
+		}
+		if show && i == t.Cursor {
+			cursor.Ch = c.Ch
+			c = cursor
+		}
+		buf.Set(x, y, c)
+		x += c.Width()
+	}
+	return buf
+}
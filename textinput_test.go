@@ -0,0 +1,72 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestTextInputEdit(t *testing.T) {
+	in := NewTextInput()
+	changes := []string{}
+	in.OnChange = func(s string) { changes = append(changes, s) }
+	submitted := ""
+	in.OnSubmit = func(s string) { submitted = s }
+
+	for _, k := range []string{"a", "c", KeyArrowLeft, "b", KeyEnd, KeySpace, "d", KeyHome, KeyDelete, KeyArrowRight, KeyBackspace} {
+		if !in.HandleKey(k) {
+			t.Errorf("key %q should be used", k)
+		}
+	}
+	if in.Text != "c d" || in.Cursor != 0 {
+		t.Errorf("got %q with cursor at %d", in.Text, in.Cursor)
+	}
+	if len(changes) != 7 || changes[2] != "abc" {
+		t.Errorf("OnChange should follow every edit, got %q", changes)
+	}
+	if in.HandleKey("C-x") {
+		t.Error("control keys should not be inserted")
+	}
+	in.HandleKey(KeyEnter)
+	if submitted != "c d" {
+		t.Errorf("expected the text submitted, got %q", submitted)
+	}
+}
+
+func TestTextInputBuffer(t *testing.T) {
+	in := NewTextInput()
+	in.Width = 7 // 5 columns inside the border
+	in.Placeholder = "name"
+
+	buf := in.Buffer()
+	if s := bufferRow(buf, 1, 1, 6); s != "name " {
+		t.Errorf("expected the placeholder, got %q", s)
+	}
+	if c := buf.At(1, 1); c.Fg&AttrReverse == 0 {
+		t.Error("the cursor should be drawn over the placeholder")
+	}
+
+	in.SetText("abcdefgh")
+	buf = in.Buffer()
+	if s := bufferRow(buf, 1, 1, 6); s != "efgh " {
+		t.Errorf("expected the text scrolled to the cursor, got %q", s)
+	}
+	in.HandleKey(KeyHome)
+	buf = in.Buffer()
+	if s := bufferRow(buf, 1, 1, 6); s != "abcde" {
+		t.Errorf("expected the text scrolled back, got %q", s)
+	}
+
+	in.Mask = '*'
+	in.HandleKey(KeyEnd)
+	if s := bufferRow(in.Buffer(), 1, 1, 6); s != "**** " {
+		t.Errorf("expected masked text, got %q", s)
+	}
+
+	in.Mask = 0
+	in.SetText("你好你好")
+	buf = in.Buffer()
+	if s := bufferRow(buf, 1, 1, 6); s != "你好 " {
+		t.Errorf("expected wide runes to scroll by width, got %q", s)
+	}
+}
@@ -4,7 +4,12 @@
 
 package termui
 
-import "strings"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
 
 /*
 // A ColorScheme represents the current look-and-feel of the dashboard.
@@ -89,15 +94,36 @@ func UseTheme(th string) {
 }
 */
 
+// ColorMap is the palette in use, the attributes widgets pick by name when
+// they are created, see ThemeAttr. SetTheme replaces it.
 var ColorMap = map[string]Attribute{
-	"fg":           ColorWhite,
-	"bg":           ColorDefault,
-	"border.fg":    ColorWhite,
-	"label.fg":     ColorGreen,
-	"par.fg":       ColorYellow,
-	"par.label.bg": ColorWhite,
+	"fg":              ColorWhite,
+	"bg":              ColorDefault,
+	"border.fg":       ColorWhite,
+	"border.focus.fg": ColorCyan,
+	"label.fg":        ColorGreen,
+	"par.fg":          ColorYellow,
+	"placeholder.fg":  ColorBlue,
+	"tab.active.bg":   ColorBlue,
+	"tab.rule.fg":     ColorBlue,
+	"par.label.bg":    ColorWhite,
+	"grid.fg":         ColorBlack | AttrBold,
+
+	"candlestick.up.fg":   ColorGreen,
+	"candlestick.down.fg": ColorRed,
+
+	"logview.match.current.fg": ColorYellow,
+
+	"menu.selected.fg": ColorBlack,
+	"menu.selected.bg": ColorCyan,
+	"menu.disabled.fg": ColorBlack | AttrBold,
+
+	"form.error.fg": ColorRed,
 }
 
+// ThemeAttr returns the attribute name of the current theme. A name with no
+// entry falls back to its shorter dotted suffixes, so "list.item.fg" takes
+// "item.fg", then "fg".
 func ThemeAttr(name string) Attribute {
 	return lookUpAttr(ColorMap, name)
 }
@@ -112,7 +138,7 @@ func lookUpAttr(clrmap map[string]Attribute, name string) Attribute {
 	ns := strings.Split(name, ".")
 	for i := range ns {
 		nn := strings.Join(ns[i:len(ns)], ".")
-		a, ok = ColorMap[nn]
+		a, ok = clrmap[nn]
 		if ok {
 			break
 		}
@@ -121,6 +147,7 @@ func lookUpAttr(clrmap map[string]Attribute, name string) Attribute {
 	return a
 }
 
+// ColorRGB returns the colour of the 6x6x6 cube in the 256-colour palette,
 // 0<=r,g,b <= 5
 func ColorRGB(r, g, b int) Attribute {
 	within := func(n int) int {
@@ -136,5 +163,170 @@ func ColorRGB(r, g, b int) Attribute {
 	}
 
 	r, b, g = within(r), within(b), within(g)
-	return Attribute(0x0f + 36*r + 6*g + b)
+	// the cube starts at palette index 16, attributes are index+1
+	return Attribute(0x11 + 36*r + 6*g + b)
+}
+
+// themes holds the palettes SetTheme switches between, by name.
+var themes = struct {
+	sync.Mutex
+	m       map[string]map[string]Attribute
+	current string
+}{m: map[string]map[string]Attribute{"default": copyColorMap(ColorMap)}, current: "default"}
+
+func copyColorMap(m map[string]Attribute) map[string]Attribute {
+	c := make(map[string]Attribute, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// RegisterTheme makes the palette m available to SetTheme as name. Its
+// entries are laid over the default palette, so a theme only lists what it
+// changes.
+func RegisterTheme(name string, m map[string]Attribute) {
+	themes.Lock()
+	defer themes.Unlock()
+	t := copyColorMap(themes.m["default"])
+	for k, v := range m {
+		t[k] = v
+	}
+	themes.m[name] = t
+}
+
+// ThemeNames returns the registered themes, sorted.
+func ThemeNames() []string {
+	themes.Lock()
+	defer themes.Unlock()
+	ns := make([]string, 0, len(themes.m))
+	for n := range themes.m {
+		ns = append(ns, n)
+	}
+	sort.Strings(ns)
+	return ns
+}
+
+// CurrentTheme returns the name of the theme in use.
+func CurrentTheme() string {
+	themes.Lock()
+	defer themes.Unlock()
+	return themes.current
+}
+
+// SetTheme switches to the registered theme name. The widgets on screen
+// take the new palette and are rendered again, but not their attributes
+// that were changed from the old theme's.
+func SetTheme(name string) error {
+	themes.Lock()
+	t, ok := themes.m[name]
+	if !ok {
+		themes.Unlock()
+		return fmt.Errorf("termui: unknown theme %q", name)
+	}
+	themes.current = name
+	themes.Unlock()
+
+	old := ColorMap
+	ColorMap = copyColorMap(t)
+	if Body != nil {
+		restyleAttr(&Body.BgColor, "bg", 0, old)
+	}
+
+	renderLock.Lock()
+	over := make(map[Bufferer]bool, len(overlays))
+	for _, b := range overlays {
+		over[b] = true
+	}
+	clearPending = true
+	renderLock.Unlock()
+
+	// the widgets drawn, in draw order
+	hits.Lock()
+	hs := make([]hit, 0, len(hits.ws))
+	for _, h := range hits.ws {
+		hs = append(hs, h)
+	}
+	hits.Unlock()
+	sort.Slice(hs, func(i, j int) bool { return hs[i].seq < hs[j].seq })
+
+	ws := make([]Bufferer, 0, len(hs))
+	for _, h := range hs {
+		if b, ok := h.w.(blocker); ok {
+			b.block().restyle(old)
+		}
+		if r, ok := h.w.(restyler); ok {
+			r.restyle(old)
+		}
+		if !over[h.w] {
+			ws = append(ws, h.w)
+		}
+	}
+	Render(ws...)
+	return nil
+}
+
+// themedAttr is a widget attribute taken from the theme, see themeAttr.
+type themedAttr struct {
+	p    *Attribute
+	name string
+	mods Attribute
+}
+
+// themeAttr sets *p, an attribute of the widget embedding b, to the theme's
+// name combined with mods, and has SetTheme update it.
+func (b *Block) themeAttr(p *Attribute, name string, mods ...Attribute) {
+	a := themedAttr{p: p, name: name}
+	for _, m := range mods {
+		a.mods |= m
+	}
+	*p = ThemeAttr(name) | a.mods
+	b.themed = append(b.themed, a)
+}
+
+// blockAttrs are the attributes NewBlock takes from the theme.
+var blockAttrs = []struct {
+	name  string
+	field func(b *Block) *Attribute
+}{
+	{"border.bg", func(b *Block) *Attribute { return &b.BorderBg }},
+	{"border.fg", func(b *Block) *Attribute { return &b.BorderFg }},
+	{"border.focus.fg", func(b *Block) *Attribute { return &b.FocusBorderFg }},
+	{"label.bg", func(b *Block) *Attribute { return &b.BorderLabelBg }},
+	{"label.fg", func(b *Block) *Attribute { return &b.BorderLabelFg }},
+	{"label.bg", func(b *Block) *Attribute { return &b.BorderCaptionBg }},
+	{"label.fg", func(b *Block) *Attribute { return &b.BorderCaptionFg }},
+	{"block.bg", func(b *Block) *Attribute { return &b.Bg }},
+}
+
+// restyler is implemented by widgets holding themed attributes themeAttr
+// can't track, e.g. in values copied around.
+type restyler interface {
+	restyle(old map[string]Attribute)
+}
+
+// restyle moves the themed attributes of b and of the widget embedding it
+// from the palette old to the current one.
+func (b *Block) restyle(old map[string]Attribute) {
+	b.Lock()
+	defer b.Unlock()
+	own := make(map[*Attribute]bool, len(b.themed))
+	for _, a := range b.themed {
+		restyleAttr(a.p, a.name, a.mods, old)
+		own[a.p] = true
+	}
+	// a widget may theme a Block attribute its own way, e.g. StatusBar's Bg
+	for _, a := range blockAttrs {
+		if p := a.field(b); !own[p] {
+			restyleAttr(p, a.name, 0, old)
+		}
+	}
+}
+
+// restyleAttr sets *p to the current theme's name with mods, unless it was
+// changed from what the palette old gave it.
+func restyleAttr(p *Attribute, name string, mods Attribute, old map[string]Attribute) {
+	if *p == lookUpAttr(old, name)|mods {
+		*p = ThemeAttr(name) | mods
+	}
 }
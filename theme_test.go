@@ -4,7 +4,12 @@
 
 package termui
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 var cmap = map[string]Attribute{
 	"fg":           ColorWhite,
@@ -33,3 +38,93 @@ func TestLoopUpAttr(t *testing.T) {
 		}
 	}
 }
+
+func TestThemeLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "termui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	toml := filepath.Join(dir, "dark.toml")
+	ioutil.WriteFile(toml, []byte(`# a dark theme
+bg = "black"
+[border]
+fg = "cyan" # trailing comment
+focus.fg = 'yellow, bold'
+["list.item"]
+fg = "#ff0000"
+`), 0644)
+	name, err := ThemeLoad(toml)
+	if err != nil || name != "dark" {
+		t.Fatalf("expected theme dark, got %q, %v", name, err)
+	}
+	js := filepath.Join(dir, "x.json")
+	ioutil.WriteFile(js, []byte(`{"name": "light", "fg": "black", "border": {"fg": "blue"}}`), 0644)
+	if name, err = ThemeLoad(js); err != nil || name != "light" {
+		t.Fatalf("expected theme light, got %q, %v", name, err)
+	}
+
+	themes.Lock()
+	dark, light := themes.m["dark"], themes.m["light"]
+	themes.Unlock()
+	tbl := []struct {
+		m      map[string]Attribute
+		name   string
+		should Attribute
+	}{
+		{dark, "bg", ColorBlack},
+		{dark, "border.fg", ColorCyan},
+		{dark, "border.focus.fg", ColorYellow | AttrBold},
+		{dark, "list.item.fg", ColorRGB24(255, 0, 0)},
+		{dark, "label.fg", ColorGreen}, // from the default theme
+		{light, "fg", ColorBlack},
+		{light, "border.fg", ColorBlue},
+	}
+	for _, v := range tbl {
+		if a := lookUpAttr(v.m, v.name); a != v.should {
+			t.Errorf("%s: expected %v, got %v", v.name, v.should, a)
+		}
+	}
+
+	ioutil.WriteFile(toml, []byte(`fg = "purple"`), 0644)
+	if _, err := ThemeLoad(toml); err == nil {
+		t.Error("expected an unknown colour to fail")
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	_, done := useFakeBackend(20, 10)
+	defer done()
+	defer SetTheme("default")
+
+	RegisterTheme("test", map[string]Attribute{
+		"border.fg":       ColorMagenta,
+		"table.header.fg": ColorRed,
+		"list.item.fg":    ColorCyan,
+	})
+	l := NewList()
+	l.Items = []string{"a"}
+	l.Width, l.Height = 5, 3
+	tb := NewTable()
+	tb.Y = 3
+	tb.Width, tb.Height = 5, 3
+	tb.BorderFg = ColorGreen
+	Render(l, tb)
+
+	if err := SetTheme("test"); err != nil {
+		t.Fatal(err)
+	}
+	if l.BorderFg != ColorMagenta || l.ItemFgColor != ColorCyan {
+		t.Errorf("expected the list restyled, got %v %v", l.BorderFg, l.ItemFgColor)
+	}
+	if tb.BorderFg != ColorGreen || tb.HeaderFg != ColorRed|AttrBold {
+		t.Errorf("expected the table restyled but its border, got %v %v", tb.BorderFg, tb.HeaderFg)
+	}
+	if c := lastFrame.At(0, 0); c.Fg != ColorMagenta {
+		t.Errorf("expected the list drawn again, got %v", c.Fg)
+	}
+	if CurrentTheme() != "test" || SetTheme("nope") == nil {
+		t.Error("expected an unknown theme refused")
+	}
+}
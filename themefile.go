@@ -0,0 +1,185 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ThemeLoad reads a theme file and registers it, see RegisterTheme, under
+// the "name" it sets or else the file name without extension, which it
+// returns. Files ending in ".json" are JSON, others TOML. Keys are the
+// attribute names of ThemeAttr, written flat or nested in tables, and
+// values are attributes as StringToAttribute reads them, "#rrggbb" colours
+// included.
+/*
+  # dark.toml
+  name = "dark"
+  bg = "black"
+  fg = "white"
+
+  [border]
+  fg = "cyan"
+  focus.fg = "yellow, bold"
+
+  [list.item]
+  fg = "#87d7ff"
+*/
+func ThemeLoad(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var name string
+	var vals map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		name, vals, err = parseThemeJSON(data)
+	} else {
+		name, vals, err = parseThemeTOML(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("termui: theme %s: %v", path, err)
+	}
+
+	m := make(map[string]Attribute, len(vals))
+	for k, v := range vals {
+		a, err := parseThemeAttr(v)
+		if err != nil {
+			return "", fmt.Errorf("termui: theme %s: %s: %v", path, k, err)
+		}
+		m[k] = a
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	RegisterTheme(name, m)
+	return name, nil
+}
+
+// parseThemeAttr is StringToAttribute failing on unknown words.
+func parseThemeAttr(s string) (Attribute, error) {
+	for _, w := range strings.Split(s, ",") {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if StringToAttribute(w) == 0 && w != "reset" && w != "default" {
+			return 0, fmt.Errorf("bad attribute %q", w)
+		}
+	}
+	return StringToAttribute(s), nil
+}
+
+// parseThemeJSON flattens the objects of a JSON theme into dotted keys.
+func parseThemeJSON(data []byte) (string, map[string]string, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return "", nil, err
+	}
+	name := ""
+	if n, ok := root["name"].(string); ok {
+		name = n
+		delete(root, "name")
+	}
+	vals := make(map[string]string)
+	var walk func(prefix string, m map[string]interface{}) error
+	walk = func(prefix string, m map[string]interface{}) error {
+		for k, v := range m {
+			switch v := v.(type) {
+			case string:
+				vals[prefix+k] = v
+			case map[string]interface{}:
+				if err := walk(prefix+k+".", v); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s%s: value is not a string", prefix, k)
+			}
+		}
+		return nil
+	}
+	if err := walk("", root); err != nil {
+		return "", nil, err
+	}
+	return name, vals, nil
+}
+
+// parseThemeTOML reads the part of TOML themes need: comments, [table]
+// headers and key = "string" pairs, keys being bare, dotted or quoted.
+func parseThemeTOML(data []byte) (string, map[string]string, error) {
+	name, table := "", ""
+	vals := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(stripTOMLComment(sc.Text()))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return "", nil, fmt.Errorf("line %d: bad table header", n)
+			}
+			table = tomlKey(line[1:len(line)-1]) + "."
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return "", nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		k := tomlKey(line[:i])
+		v, err := strconv.Unquote(tomlString(strings.TrimSpace(line[i+1:])))
+		if k == "" || err != nil {
+			return "", nil, fmt.Errorf("line %d: expected key = \"value\"", n)
+		}
+		if table == "" && k == "name" {
+			name = v
+			continue
+		}
+		vals[strings.TrimPrefix(table+k, ".")] = v
+	}
+	return name, vals, sc.Err()
+}
+
+// stripTOMLComment cuts line at a '#' outside of quotes.
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// tomlKey joins the parts of a dotted key, unquoting quoted ones.
+func tomlKey(s string) string {
+	s = strings.TrimSpace(s)
+	if uq, err := strconv.Unquote(tomlString(s)); err == nil {
+		return uq
+	}
+	ps := strings.Split(s, ".")
+	for i, p := range ps {
+		ps[i] = strings.TrimSpace(p)
+	}
+	return strings.Join(ps, ".")
+}
+
+// tomlString turns a literal 'string' into a "string" strconv can unquote.
+func tomlString(s string) string {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strconv.Quote(s[1 : len(s)-1])
+	}
+	return s
+}
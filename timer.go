@@ -0,0 +1,159 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer is a timer merged into an EvtStream by Every or After.
+type Timer struct {
+	Path string // of its events
+	stop chan struct{}
+	once sync.Once
+}
+
+// Stop stops t, no event is sent after it returns but the one being
+// handed to the stream.
+func (t *Timer) Stop() {
+	t.once.Do(func() { close(t.stop) })
+}
+
+// Every merges a timer into es sending an EvtTimer event on path every du,
+// "/timer/<du>" if path is "", until it is stopped. It may be called while
+// Loop runs, from a handler too.
+/*
+  termui.Every(200*time.Millisecond, "/timer/poll")
+  termui.Handle("/timer/poll", func(termui.Event) {
+      g.Percent = progress()
+      termui.Render(g)
+  })
+*/
+func (es *EvtStream) Every(du time.Duration, path string) *Timer {
+	return es.startTimer(du, path, 0)
+}
+
+// After merges a timer into es sending a single EvtTimer event on path
+// once du passed, unless it is stopped before.
+func (es *EvtStream) After(du time.Duration, path string) *Timer {
+	return es.startTimer(du, path, 1)
+}
+
+func (es *EvtStream) startTimer(du time.Duration, path string, limit uint64) *Timer {
+	if path == "" {
+		path = "/timer/" + du.String()
+	}
+	t := &Timer{Path: cleanPath(path), stop: make(chan struct{})}
+	es.Merge("timer"+t.Path, timerCh(du, t.Path, limit, es.done, t.stop))
+	return t
+}
+
+// Debounce returns a handler calling h with the last event of each burst
+// of events, once du passed without a new one. h runs on Loop like the
+// other handlers.
+/*
+  // lay out once the window stopped being resized
+  termui.Handle("/sys/wnd/resize", termui.Debounce(100*time.Millisecond, func(e termui.Event) {
+      termui.Body.Width = termui.TermWidth()
+      termui.Body.Align()
+      termui.Render(termui.Body)
+  }))
+*/
+func (es *EvtStream) Debounce(du time.Duration, h func(Event)) func(Event) {
+	var mu sync.Mutex
+	var last Event
+	gen := 0
+	return func(e Event) {
+		mu.Lock()
+		gen++
+		g := gen
+		last = e
+		mu.Unlock()
+		es.after(du, func() {
+			mu.Lock()
+			stale, e := g != gen, last
+			mu.Unlock()
+			if !stale {
+				h(e)
+			}
+		})
+	}
+}
+
+// Throttle returns a handler calling h at most once every du: with the
+// first event, then with the last one of those coming within du of the
+// call, once du passed.
+func (es *EvtStream) Throttle(du time.Duration, h func(Event)) func(Event) {
+	var mu sync.Mutex
+	var last Event
+	var next time.Time // of the next call on time
+	pending := false
+	flush := func() {
+		mu.Lock()
+		e := last
+		pending = false
+		next = now().Add(du)
+		mu.Unlock()
+		h(e)
+	}
+	return func(e Event) {
+		mu.Lock()
+		last = e
+		if t := now(); !t.Before(next) {
+			mu.Unlock()
+			flush()
+			return
+		}
+		if pending {
+			mu.Unlock()
+			return
+		}
+		pending = true
+		wait := next.Sub(now())
+		mu.Unlock()
+		es.after(wait, flush)
+	}
+}
+
+// after has Loop call f once du passed, by DefaultTimeSource, unless es
+// stopped before.
+func (es *EvtStream) after(du time.Duration, f func()) {
+	if du <= 0 {
+		go es.call(f)
+		return
+	}
+	tk := DefaultTimeSource.NewTicker(du)
+	go func() {
+		defer tk.Stop()
+		select {
+		case <-tk.C():
+			es.call(f)
+		case <-es.done:
+		}
+	}()
+}
+
+// Every merges an interval timer into DefaultEvtStream, see
+// EvtStream.Every.
+func Every(du time.Duration, path string) *Timer {
+	return DefaultEvtStream.Every(du, path)
+}
+
+// After merges a one-shot timer into DefaultEvtStream, see
+// EvtStream.After.
+func After(du time.Duration, path string) *Timer {
+	return DefaultEvtStream.After(du, path)
+}
+
+// Debounce wraps h for DefaultEvtStream, see EvtStream.Debounce.
+func Debounce(du time.Duration, h func(Event)) func(Event) {
+	return DefaultEvtStream.Debounce(du, h)
+}
+
+// Throttle wraps h for DefaultEvtStream, see EvtStream.Throttle.
+func Throttle(du time.Duration, h func(Event)) func(Event) {
+	return DefaultEvtStream.Throttle(du, h)
+}
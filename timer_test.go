@@ -0,0 +1,103 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+	"time"
+)
+
+// useTimerStream returns a running EvtStream on a FakeTimeSource.
+func useTimerStream(t *testing.T) (*EvtStream, *FakeTimeSource, func()) {
+	old := DefaultTimeSource
+	ft := NewFakeTimeSource(time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC))
+	DefaultTimeSource = ft
+	es := NewEvtStream()
+	es.Init()
+	go es.Loop()
+	return es, ft, func() {
+		es.stop()
+		DefaultTimeSource = old
+	}
+}
+
+func recv(t *testing.T, ch chan Event) Event {
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("expected an event")
+	}
+	return Event{}
+}
+
+func TestEvery(t *testing.T) {
+	es, ft, done := useTimerStream(t)
+	defer done()
+
+	got := make(chan Event, 10)
+	es.Handle("/timer/poll", func(e Event) { got <- e })
+	tm := es.Every(200*time.Millisecond, "timer/poll")
+	for i := uint64(1); i <= 2; i++ {
+		ft.Advance(200 * time.Millisecond)
+		if e := recv(t, got); e.Path != "/timer/poll" || e.Data.(EvtTimer).Count != i {
+			t.Errorf("expected tick %d on the path, got %v", i, e)
+		}
+	}
+	tm.Stop()
+	ft.Advance(time.Second)
+
+	es.Handle("/timer/once", func(e Event) { got <- e })
+	es.After(time.Second, "/timer/once")
+	ft.Advance(time.Second)
+	recv(t, got)
+	ft.Advance(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if len(got) != 0 {
+		t.Errorf("expected no tick after Stop nor a second one of After, got %d", len(got))
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	es, ft, done := useTimerStream(t)
+	defer done()
+
+	got := make(chan Event, 10)
+	h := es.Debounce(100*time.Millisecond, func(e Event) { got <- e })
+	h(Event{Path: "/a"})
+	ft.Advance(50 * time.Millisecond)
+	h(Event{Path: "/b"})
+	ft.Advance(50 * time.Millisecond)
+	ft.Advance(50 * time.Millisecond)
+	if e := recv(t, got); e.Path != "/b" {
+		t.Errorf("expected the last event of the burst, got %s", e.Path)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(got) != 0 {
+		t.Errorf("expected a single call, got %d more", len(got))
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	es, ft, done := useTimerStream(t)
+	defer done()
+
+	got := make(chan Event, 10)
+	h := es.Throttle(100*time.Millisecond, func(e Event) { got <- e })
+	h(Event{Path: "/a"})
+	if e := recv(t, got); e.Path != "/a" {
+		t.Errorf("expected the first event at once, got %s", e.Path)
+	}
+	ft.Advance(10 * time.Millisecond)
+	h(Event{Path: "/b"})
+	h(Event{Path: "/c"})
+	if len(got) != 0 {
+		t.Fatal("expected the events within the interval held")
+	}
+	ft.Advance(90 * time.Millisecond)
+	if e := recv(t, got); e.Path != "/c" {
+		t.Errorf("expected the last held event at the end of the interval, got %s", e.Path)
+	}
+}
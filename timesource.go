@@ -0,0 +1,140 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"sync"
+	"time"
+)
+
+// TimeSource provides the current time and tickers to the timer event
+// sources and the widgets reading time, so tests can drive them with a
+// FakeTimeSource.
+type TimeSource interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks like a time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// DefaultTimeSource is used by NewTimerCh, event timestamps and Clock. Set
+// it before Init.
+var DefaultTimeSource TimeSource = SystemTimeSource{}
+
+func now() time.Time {
+	return DefaultTimeSource.Now()
+}
+
+// SystemTimeSource is the TimeSource of the wall clock.
+type SystemTimeSource struct{}
+
+// Now implements TimeSource.
+func (SystemTimeSource) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker implements TimeSource.
+func (SystemTimeSource) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTicker) Stop() {
+	t.t.Stop()
+}
+
+// FakeTimeSource is a TimeSource whose time only moves with Advance.
+type FakeTimeSource struct {
+	sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// NewFakeTimeSource returns a *FakeTimeSource set to start.
+func NewFakeTimeSource(start time.Time) *FakeTimeSource {
+	return &FakeTimeSource{now: start}
+}
+
+// Now implements TimeSource.
+func (ft *FakeTimeSource) Now() time.Time {
+	ft.Lock()
+	defer ft.Unlock()
+	return ft.now
+}
+
+// NewTicker implements TimeSource.
+func (ft *FakeTimeSource) NewTicker(d time.Duration) Ticker {
+	ft.Lock()
+	defer ft.Unlock()
+	t := &fakeTicker{
+		src:  ft,
+		c:    make(chan time.Time, 1),
+		d:    d,
+		next: ft.now.Add(d),
+	}
+	ft.tickers = append(ft.tickers, t)
+	return t
+}
+
+// Advance moves the time forward by d and fires the tickers that became
+// due, one tick per elapsed period. Like time.Ticker, a tick is dropped if
+// the previous one was not received yet.
+func (ft *FakeTimeSource) Advance(d time.Duration) {
+	ft.Lock()
+	end := ft.now.Add(d)
+	for {
+		// fire the earliest due ticker so ticks come in time order
+		var t *fakeTicker
+		for _, v := range ft.tickers {
+			if !v.next.After(end) && (t == nil || v.next.Before(t.next)) {
+				t = v
+			}
+		}
+		if t == nil {
+			break
+		}
+		ft.now = t.next
+		t.next = t.next.Add(t.d)
+		select {
+		case t.c <- ft.now:
+		default:
+		}
+	}
+	ft.now = end
+	ft.Unlock()
+}
+
+type fakeTicker struct {
+	src  *FakeTimeSource
+	c    chan time.Time
+	d    time.Duration
+	next time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.src.Lock()
+	defer t.src.Unlock()
+	for i, v := range t.src.tickers {
+		if v == t {
+			t.src.tickers = append(t.src.tickers[:i], t.src.tickers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeTimeSource(t *testing.T) {
+	start := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+	ft := NewFakeTimeSource(start)
+	tk := ft.NewTicker(time.Second)
+
+	select {
+	case <-tk.C():
+		t.Fatal("tick before Advance")
+	default:
+	}
+
+	ft.Advance(1500 * time.Millisecond)
+	if got := <-tk.C(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("tick at %v, want %v", got, start.Add(time.Second))
+	}
+	if got := ft.Now(); !got.Equal(start.Add(1500 * time.Millisecond)) {
+		t.Errorf("Now() = %v", got)
+	}
+
+	tk.Stop()
+	ft.Advance(time.Hour)
+	select {
+	case <-tk.C():
+		t.Error("tick after Stop")
+	default:
+	}
+}
+
+func TestTimerChWithFakeTimeSource(t *testing.T) {
+	old := DefaultTimeSource
+	defer func() { DefaultTimeSource = old }()
+
+	start := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+	ft := NewFakeTimeSource(start)
+	DefaultTimeSource = ft
+
+	ch := NewTimerCh(time.Second)
+	for i := 1; i <= 3; i++ {
+		ft.Advance(time.Second)
+		e := <-ch
+		if e.Path != "/timer/1s" || e.Data.(EvtTimer).Count != uint64(i) {
+			t.Errorf("event %d: %+v", i, e)
+		}
+		if e.Time != start.Add(time.Duration(i)*time.Second).Unix() {
+			t.Errorf("event %d at %d", i, e.Time)
+		}
+	}
+
+	c := NewClock()
+	c.Location = time.UTC
+	if got := c.Text(); got != "03:04:08" {
+		t.Errorf("Clock.Text() = %q, want %q", got, "03:04:08")
+	}
+}
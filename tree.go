@@ -0,0 +1,439 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "image"
+
+// TreeNode is a node of a Tree. Children are loaded on the first expand by
+// LoadChildren when it is set and Children is nil, which lets large or
+// remote hierarchies be read on demand.
+type TreeNode struct {
+	Text         string // may contain markup
+	Value        interface{}
+	Children     []*TreeNode
+	Expanded     bool
+	Fg           Attribute // zero keeps the tree's colour
+	Bg           Attribute
+	LoadChildren func(n *TreeNode) []*TreeNode
+	loaded       bool
+}
+
+// expandable tells if n has, or may load, children.
+func (n *TreeNode) expandable() bool {
+	return len(n.Children) > 0 || (n.LoadChildren != nil && !n.loaded && n.Children == nil)
+}
+
+// Tree displays a hierarchy of TreeNode, one visible node per row, indented
+// by depth with a mark telling if the node is expanded. The arrow keys move
+// the cursor, <right> expands and <left> collapses.
+/*
+  root := &termui.TreeNode{Text: "/", Expanded: true}
+  root.Children = []*termui.TreeNode{{Text: "etc"}, {Text: "usr"}}
+  t := termui.NewTree(root)
+  t.Height = 10
+  t.OnSelect = func(n *termui.TreeNode) { ... }
+*/
+type Tree struct {
+	Block
+	Roots       []*TreeNode
+	TextFgColor Attribute
+	TextBgColor Attribute
+	Indent      int               // columns per level
+	Cursor      int               // visible row under the cursor
+	ScrollTop   int               // first visible row drawn
+	OnSelect    func(n *TreeNode) // called after the cursor moved to n
+	OnToggle    func(n *TreeNode) // called after n was expanded or collapsed
+	NodeStyle   func(n *TreeNode, selected bool) (fg, bg Attribute)
+}
+
+// treeRow is a visible node and its depth.
+type treeRow struct {
+	n     *TreeNode
+	depth int
+}
+
+const (
+	treeExpanded  = "▾ "
+	treeCollapsed = "▸ "
+	treeLeaf      = "  "
+)
+
+// NewTree returns a new *Tree of roots with current theme.
+func NewTree(roots ...*TreeNode) *Tree {
+	t := &Tree{
+		Block:  *NewBlock(),
+		Roots:  roots,
+		Indent: 2,
+	}
+	t.themeAttr(&t.TextFgColor, "tree.text.fg")
+	t.themeAttr(&t.TextBgColor, "tree.text.bg")
+	return t
+}
+
+// rows returns the visible nodes in display order.
+func (t *Tree) rows() []treeRow {
+	rs := []treeRow{}
+	var walk func(ns []*TreeNode, d int)
+	walk = func(ns []*TreeNode, d int) {
+		for _, n := range ns {
+			rs = append(rs, treeRow{n, d})
+			if n.Expanded {
+				walk(n.Children, d+1)
+			}
+		}
+	}
+	walk(t.Roots, 0)
+	return rs
+}
+
+// parentRow returns the row of the parent of row i, -1 for a root.
+func parentRow(rs []treeRow, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if rs[j].depth < rs[i].depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// Selected returns the node under the cursor, or nil for an empty tree.
+func (t *Tree) Selected() *TreeNode {
+	t.RLock()
+	defer t.RUnlock()
+	rs := t.rows()
+	if t.Cursor < 0 || t.Cursor >= len(rs) {
+		return nil
+	}
+	return rs[t.Cursor].n
+}
+
+// Expand shows the children of n, loading them first if needed, and calls
+// OnToggle if n was collapsed.
+func (t *Tree) Expand(n *TreeNode) {
+	changed := t.expand(n)
+	t.RLock()
+	cb := t.OnToggle
+	t.RUnlock()
+
+	if changed && cb != nil {
+		cb(n)
+	}
+}
+
+// expand does the work of Expand, reporting whether n was collapsed.
+func (t *Tree) expand(n *TreeNode) bool {
+	t.RLock()
+	load := n.LoadChildren != nil && !n.loaded && n.Children == nil
+	t.RUnlock()
+
+	// LoadChildren may be slow, don't block drawing meanwhile
+	var cs []*TreeNode
+	if load {
+		cs = n.LoadChildren(n)
+	}
+
+	t.Lock()
+	if load {
+		n.Children, n.loaded = cs, true
+	}
+	changed := !n.Expanded
+	n.Expanded = true
+	t.Unlock()
+	return changed
+}
+
+// Collapse hides the children of n and calls OnToggle if it was expanded.
+// A cursor within the children moves to n.
+func (t *Tree) Collapse(n *TreeNode) {
+	t.Lock()
+	if !n.Expanded {
+		t.Unlock()
+		return
+	}
+	// the children of n are the deeper rows right after it
+	rs := t.rows()
+	for i, r := range rs {
+		if r.n != n {
+			continue
+		}
+		for j := i + 1; j < len(rs) && rs[j].depth > r.depth; j++ {
+			if j == t.Cursor {
+				t.Cursor = i
+			}
+		}
+		break
+	}
+	n.Expanded = false
+	cb := t.OnToggle
+	t.Unlock()
+
+	if cb != nil {
+		cb(n)
+	}
+}
+
+// Toggle expands n if it is collapsed and collapses it otherwise.
+func (t *Tree) Toggle(n *TreeNode) {
+	t.RLock()
+	exp := n.Expanded
+	t.RUnlock()
+	if exp {
+		t.Collapse(n)
+	} else {
+		t.Expand(n)
+	}
+}
+
+// TreeState is the scroll and cursor state of a Tree and the nodes
+// expanded in it, see Tree.State. A node is named by the Texts of the
+// nodes from its root down to it.
+type TreeState struct {
+	ScrollTop int        `json:"scroll_top"`
+	Cursor    int        `json:"cursor"`
+	Expanded  [][]string `json:"expanded,omitempty"`
+}
+
+// State returns a snapshot of t's scroll position, cursor and expanded
+// nodes, it can be saved, e.g. as JSON, and given back to RestoreState.
+func (t *Tree) State() TreeState {
+	t.RLock()
+	defer t.RUnlock()
+	st := TreeState{ScrollTop: t.ScrollTop, Cursor: t.Cursor}
+	var walk func(ns []*TreeNode, path []string)
+	walk = func(ns []*TreeNode, path []string) {
+		for _, n := range ns {
+			if !n.Expanded {
+				continue
+			}
+			p := append(append([]string{}, path...), n.Text)
+			st.Expanded = append(st.Expanded, p)
+			walk(n.Children, p)
+		}
+	}
+	walk(t.Roots, nil)
+	return st
+}
+
+// RestoreState restores a snapshot taken by State: the named nodes still
+// in the tree are expanded, loading their children if needed, the others
+// collapsed, and the cursor and scroll position are clamped to the rows.
+// OnToggle and OnSelect are not called.
+func (t *Tree) RestoreState(st TreeState) {
+	t.Lock()
+	var collapse func(ns []*TreeNode)
+	collapse = func(ns []*TreeNode) {
+		for _, n := range ns {
+			n.Expanded = false
+			collapse(n.Children)
+		}
+	}
+	collapse(t.Roots)
+	t.Unlock()
+
+	// parents come first, their children are loaded when they are reached
+	for _, path := range st.Expanded {
+		t.RLock()
+		var n *TreeNode
+		ns := t.Roots
+		for _, text := range path {
+			n = nil
+			for _, c := range ns {
+				if c.Text == text {
+					n = c
+					break
+				}
+			}
+			if n == nil {
+				break
+			}
+			ns = n.Children
+		}
+		t.RUnlock()
+		if n != nil {
+			t.expand(n)
+		}
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	rs := t.rows()
+	t.Cursor = clampInt(st.Cursor, 0, max0(len(rs)-1))
+	t.ScrollTop = clampInt(st.ScrollTop, 0, max0(len(rs)-1))
+}
+
+// moveTo puts the cursor on visible row i, scrolling to keep it in view,
+// and calls OnSelect if it moved.
+func (t *Tree) moveTo(i int) {
+	t.Lock()
+	rs := t.rows()
+	if i >= len(rs) {
+		i = len(rs) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	moved := i != t.Cursor
+	t.Cursor = i
+	if h := t.innerArea.Dy(); h > 0 {
+		if i < t.ScrollTop {
+			t.ScrollTop = i
+		}
+		if i >= t.ScrollTop+h {
+			t.ScrollTop = i - h + 1
+		}
+	}
+	cb := t.OnSelect
+	t.Unlock()
+
+	if moved && cb != nil && i < len(rs) {
+		cb(rs[i].n)
+	}
+}
+
+// HandleKey moves the cursor with <up>/<down>, <previous>/<next> by a page
+// and <home>/<end>. <right> expands the current node, or goes to its first
+// child, <left> collapses it, or goes to its parent, and <enter> or
+// <space> toggles it. It reports whether key was consumed.
+func (t *Tree) HandleKey(key string) bool {
+	t.RLock()
+	rs := t.rows()
+	cur, page := t.Cursor, t.innerArea.Dy()
+	t.RUnlock()
+	if page < 1 {
+		page = 1
+	}
+	var n *TreeNode
+	if cur >= 0 && cur < len(rs) {
+		n = rs[cur].n
+	}
+
+	switch key {
+	case KeyArrowUp:
+		t.moveTo(cur - 1)
+	case KeyArrowDown:
+		t.moveTo(cur + 1)
+	case KeyPgUp:
+		t.moveTo(cur - page)
+	case KeyPgDn:
+		t.moveTo(cur + page)
+	case KeyHome:
+		t.moveTo(0)
+	case KeyEnd:
+		t.moveTo(len(rs) - 1)
+	case KeyArrowRight:
+		switch {
+		case n == nil:
+		case !n.Expanded && n.expandable():
+			t.Expand(n)
+		case n.Expanded && len(n.Children) > 0:
+			t.moveTo(cur + 1)
+		}
+	case KeyArrowLeft:
+		switch {
+		case n == nil:
+		case n.Expanded:
+			t.Collapse(n)
+		case parentRow(rs, cur) >= 0:
+			t.moveTo(parentRow(rs, cur))
+		}
+	case KeyEnter, KeySpace:
+		if n != nil && (n.Expanded || n.expandable()) {
+			t.Toggle(n)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// nodeStyle returns the colours of n, falling back to the tree's.
+func (t *Tree) nodeStyle(n *TreeNode, selected bool) (Attribute, Attribute) {
+	fg, bg := n.Fg, n.Bg
+	if t.NodeStyle != nil {
+		f, b := t.NodeStyle(n, selected)
+		if f != 0 {
+			fg = f
+		}
+		if b != 0 {
+			bg = b
+		}
+	}
+	if fg == 0 {
+		fg = t.TextFgColor
+	}
+	if bg == 0 {
+		bg = t.TextBgColor
+	}
+	return fg, bg
+}
+
+// Buffer implements Bufferer interface.
+func (t *Tree) Buffer() Buffer {
+	buf := t.Block.Buffer()
+	t.RLock()
+	defer t.RUnlock()
+
+	rs := t.rows()
+	h := t.innerArea.Dy()
+	top := t.ScrollTop
+	if top > len(rs)-h {
+		top = len(rs) - h
+	}
+	if top < 0 {
+		top = 0
+	}
+
+	for y := 0; y < h && top+y < len(rs); y++ {
+		i := top + y
+		n, d := rs[i].n, rs[i].depth
+		sel := i == t.Cursor
+		fg, bg := t.nodeStyle(n, sel)
+
+		mark := treeLeaf
+		switch {
+		case n.Expanded:
+			mark = treeExpanded
+		case n.expandable():
+			mark = treeCollapsed
+		}
+		cs := []Cell{}
+		for j := 0; j < d*t.Indent; j++ {
+			cs = append(cs, Cell{' ', t.TextFgColor, t.TextBgColor})
+		}
+		cs = append(cs, TextCells(mark, fg, bg)...)
+		text := DefaultTxBuilder.Build(n.Text, fg, bg)
+		if sel {
+			for j := range text {
+				text[j].Fg |= AttrReverse
+			}
+		}
+		cs = fitCells(append(cs, text...), t.innerArea.Dx())
+
+		x := t.innerArea.Min.X
+		for _, c := range cs {
+			buf.Set(x, t.innerArea.Min.Y+y, c)
+			x += c.Width()
+		}
+		t.addNodeHotspot(i, n, d, t.innerArea.Min.Y+y)
+	}
+	t.drawScrollIndicators(buf, top > 0, top+h < len(rs), false, false)
+	return buf
+}
+
+// addNodeHotspot makes a click on row i, drawn at y, move the cursor there
+// and a click on its mark toggle n.
+func (t *Tree) addNodeHotspot(i int, n *TreeNode, depth, y int) {
+	r := t.innerArea
+	r.Min.Y, r.Max.Y = y, y+1
+	t.AddHotspot(r, func(int, int) { t.moveTo(i) })
+
+	x := r.Min.X + depth*t.Indent
+	t.AddHotspot(image.Rect(x, y, x+strWidth(treeLeaf), y+1), func(int, int) {
+		t.moveTo(i)
+		if n.Expanded || n.expandable() {
+			t.Toggle(n)
+		}
+	})
+}
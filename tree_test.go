@@ -0,0 +1,142 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestTreeNavigation(t *testing.T) {
+	loads := 0
+	usr := &TreeNode{Text: "usr", LoadChildren: func(*TreeNode) []*TreeNode {
+		loads++
+		return []*TreeNode{{Text: "bin"}, {Text: "lib"}}
+	}}
+	root := &TreeNode{Text: "/", Expanded: true, Children: []*TreeNode{{Text: "etc"}, usr}}
+	tr := NewTree(root)
+	tr.Width, tr.Height = 12, 6
+	tr.Buffer()
+
+	toggled := []string{}
+	tr.OnToggle = func(n *TreeNode) { toggled = append(toggled, n.Text) }
+
+	tr.HandleKey(KeyArrowDown)
+	tr.HandleKey(KeyArrowDown)
+	if n := tr.Selected(); n != usr {
+		t.Fatalf("expected usr under the cursor, got %v", n)
+	}
+	if c := tr.Buffer().At(3, 3); c.Ch != '▸' {
+		t.Errorf("expected a collapsed mark, got %q", c.Ch)
+	}
+
+	tr.HandleKey(KeyArrowRight)
+	if loads != 1 || len(usr.Children) != 2 || !usr.Expanded {
+		t.Errorf("expected the children loaded once, got %d loads", loads)
+	}
+	tr.HandleKey(KeyArrowRight)
+	if n := tr.Selected(); n == nil || n.Text != "bin" {
+		t.Errorf("expected <right> to go to the first child, got %v", n)
+	}
+	buf := tr.Buffer()
+	if got := bufferRow(buf, 4, 1, 10); got != "      bin" {
+		t.Errorf("expected bin indented twice and marked as a leaf, got %q", got)
+	}
+	if c := buf.At(7, 4); c.Fg&AttrReverse == 0 {
+		t.Errorf("expected the selected node highlighted, got %+v", c)
+	}
+
+	tr.HandleKey(KeyArrowLeft)
+	if tr.Selected() != usr {
+		t.Errorf("expected <left> to go to the parent")
+	}
+	tr.HandleKey(KeyArrowDown)
+	tr.Collapse(usr)
+	if tr.Selected() != usr || usr.Expanded {
+		t.Errorf("expected a collapse to pull the cursor up to usr")
+	}
+	tr.HandleKey(KeyEnter)
+	if loads != 1 || !usr.Expanded {
+		t.Errorf("expected <enter> to expand without reloading, got %d loads", loads)
+	}
+	if want := "[usr usr usr]"; fmt.Sprint(toggled) != want {
+		t.Errorf("expected OnToggle calls %s, got %v", want, toggled)
+	}
+}
+
+func TestTreeNodeStyle(t *testing.T) {
+	tr := NewTree(&TreeNode{Text: "a", Fg: ColorRed}, &TreeNode{Text: "b"})
+	tr.Width, tr.Height = 8, 4
+	tr.TextFgColor = ColorWhite
+	tr.NodeStyle = func(n *TreeNode, sel bool) (Attribute, Attribute) {
+		if n.Text == "b" {
+			return ColorGreen, ColorBlue
+		}
+		return 0, 0
+	}
+	buf := tr.Buffer()
+	if c := buf.At(3, 1); c.Fg != ColorRed|AttrReverse {
+		t.Errorf("expected the node's colour, got %+v", c)
+	}
+	if c := buf.At(3, 2); c.Fg != ColorGreen || c.Bg != ColorBlue {
+		t.Errorf("expected NodeStyle's colours, got %+v", c)
+	}
+}
+
+func TestTreeState(t *testing.T) {
+	build := func(lib bool) *Tree {
+		leaf := &TreeNode{Text: "bin"}
+		usr := &TreeNode{Text: "usr", Children: []*TreeNode{leaf}}
+		if lib {
+			usr.Children = append(usr.Children, &TreeNode{Text: "lib", Children: []*TreeNode{{Text: "x"}}})
+		}
+		return NewTree(&TreeNode{Text: "/", Children: []*TreeNode{usr, {Text: "etc"}}})
+	}
+	tr := build(true)
+	tr.Expand(tr.Roots[0])
+	tr.Expand(tr.Roots[0].Children[0])
+	tr.Expand(tr.Roots[0].Children[0].Children[1])
+	tr.Cursor, tr.ScrollTop = 5, 2
+
+	data, err := json.Marshal(tr.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st TreeState
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatal(err)
+	}
+
+	// "lib" is gone: the rows are /, usr, bin and etc
+	r := build(false)
+	toggled := 0
+	r.OnToggle = func(*TreeNode) { toggled++ }
+	r.RestoreState(st)
+	rows := []string{}
+	for _, row := range r.rows() {
+		rows = append(rows, row.n.Text)
+	}
+	if fmt.Sprint(rows) != "[/ usr bin etc]" {
+		t.Errorf("expected the saved nodes expanded, got %v", rows)
+	}
+	if r.Cursor != 3 || r.ScrollTop != 2 {
+		t.Errorf("expected the cursor clamped to 3 and the scroll kept, got %d %d", r.Cursor, r.ScrollTop)
+	}
+	if toggled != 0 {
+		t.Errorf("expected no OnToggle calls, got %d", toggled)
+	}
+
+	// lazy children are loaded on the way, and other nodes collapse
+	lazy := &TreeNode{Text: "usr", LoadChildren: func(*TreeNode) []*TreeNode {
+		return []*TreeNode{{Text: "bin"}}
+	}}
+	etc := &TreeNode{Text: "etc", Expanded: true, Children: []*TreeNode{{Text: "hosts"}}}
+	l := NewTree(&TreeNode{Text: "/", Children: []*TreeNode{lazy, etc}})
+	l.RestoreState(st)
+	if len(l.rows()) != 4 || etc.Expanded {
+		t.Errorf("expected the lazy node loaded and etc collapsed, got %d rows", len(l.rows()))
+	}
+}
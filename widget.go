@@ -30,6 +30,10 @@ func NewWgtInfo(wgt Widget) WgtInfo {
 	}
 }
 
+// wgtLock guards the WgtMgrs and the handler maps of their widgets, which
+// are written by Handle from any goroutine while Loop dispatches.
+var wgtLock sync.RWMutex
+
 func NewWgtMgr() WgtMgr {
 	wm := WgtMgr(make(map[string]WgtInfo))
 	return wm
@@ -37,6 +41,8 @@ func NewWgtMgr() WgtMgr {
 }
 
 func (wm WgtMgr) AddWgt(wgt Widget) {
+	wgtLock.Lock()
+	defer wgtLock.Unlock()
 	wm[wgt.Id()] = NewWgtInfo(wgt)
 }
 
@@ -45,18 +51,29 @@ func (wm WgtMgr) RmWgt(wgt Widget) {
 }
 
 func (wm WgtMgr) RmWgtById(id string) {
+	wgtLock.Lock()
+	defer wgtLock.Unlock()
 	delete(wm, id)
 }
 
 func (wm WgtMgr) AddWgtHandler(id, path string, h func(Event)) {
+	wgtLock.Lock()
+	defer wgtLock.Unlock()
 	if w, ok := wm[id]; ok {
 		w.Handlers[path] = h
 	}
 }
 
+// RmWgtHandler removes the handler of the widget id on path, and the
+// widget with its last handler so it is not kept in memory.
 func (wm WgtMgr) RmWgtHandler(id, path string) {
+	wgtLock.Lock()
+	defer wgtLock.Unlock()
 	if w, ok := wm[id]; ok {
 		delete(w.Handlers, path)
+		if len(w.Handlers) == 0 {
+			delete(wm, id)
+		}
 	}
 }
 
@@ -75,20 +92,49 @@ func GenId() string {
 
 func (wm WgtMgr) WgtHandlersHook() func(Event) {
 	return func(e Event) {
+		// mouse events go to the widget under the pointer only
+		mouse, target := isMouseEvt(e), ""
+		if mouse {
+			m := e.Data.(EvtMouse)
+			target = mouseTarget(m)
+			if dispatchScrollbar(m) {
+				return
+			}
+			dispatchHotspot(e)
+		}
+		// chords take their keys from everyone else
+		if routeKeyMaps(e) {
+			return
+		}
+		// keys go to the focused widget only, see FocusManager
+		skip := DefaultFocus.route(e)
+		// the handlers may add or remove others, they run unlocked
+		var hs []func(Event)
+		wgtLock.RLock()
 		for _, v := range wm {
+			if skip[v.Id] || (mouse && v.Id != target) {
+				continue
+			}
 			if k := findMatch(v.Handlers, e.Path); k != "" {
-				v.Handlers[k](e)
+				hs = append(hs, v.Handlers[k])
 			}
 		}
+		wgtLock.RUnlock()
+		for _, h := range hs {
+			h(e)
+		}
 	}
 }
 
-var DefaultWgtMgr WgtMgr
+var DefaultWgtMgr = NewWgtMgr()
 
 func (b *Block) Handle(path string, handler func(Event)) {
-	if _, ok := DefaultWgtMgr[b.Id()]; !ok {
-		DefaultWgtMgr.AddWgt(b)
+	wgtLock.Lock()
+	defer wgtLock.Unlock()
+	w, ok := DefaultWgtMgr[b.Id()]
+	if !ok {
+		w = NewWgtInfo(b)
+		DefaultWgtMgr[b.Id()] = w
 	}
-
-	DefaultWgtMgr.AddWgtHandler(b.Id(), path, handler)
+	w.Handlers[path] = handler
 }
@@ -0,0 +1,357 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"sync"
+)
+
+// Window is a frame around Content placed anywhere by its X and Y rather
+// than by a Grid, its border label being its title bar. Content is laid
+// out over the inner area of the window on every draw. Windows are stacked,
+// raised, moved and resized by a WindowManager, the active one, on top,
+// having its border in FocusBorderFg.
+type Window struct {
+	Block
+	Content   Bufferer
+	Movable   bool // the title bar can be dragged
+	Resizable bool // the bottom right corner can be dragged
+	MinWidth  int
+	MinHeight int
+}
+
+// NewWindow returns a new *Window titled title around content.
+func NewWindow(title string, content Bufferer) *Window {
+	w := &Window{
+		Block:     *NewBlock(),
+		Content:   content,
+		Movable:   true,
+		Resizable: true,
+		MinWidth:  8,
+		MinHeight: 3,
+	}
+	w.BorderLabel = title
+	w.Width, w.Height = 30, 10
+	return w
+}
+
+// Buffer implements Bufferer interface.
+func (w *Window) Buffer() Buffer {
+	buf := w.Block.Buffer()
+	w.RLock()
+	inner, content := w.innerArea, w.Content
+	w.RUnlock()
+	if content == nil || inner.Empty() {
+		return buf
+	}
+
+	if b, ok := content.(blocker); ok {
+		k := b.block()
+		k.Lock()
+		k.X, k.Y = inner.Min.X, inner.Min.Y
+		k.Width, k.Height = inner.Dx(), inner.Dy()
+		k.Float = AlignNone
+		k.Unlock()
+	}
+	cb := bufferOf(content)
+	for p, c := range cb.CellMap {
+		if p.In(inner) {
+			buf.Set(p.X, p.Y, c)
+		}
+	}
+	return buf
+}
+
+// place moves w to (x, y), keeping a part of its title bar within the
+// viewport. A window moved this way is no longer kept centered or aligned,
+// see Unanchor. It reports whether w moved.
+func (w *Window) place(x, y int) bool {
+	vp := Viewport()
+	Unanchor(w)
+	w.Lock()
+	defer w.Unlock()
+	if !vp.Empty() {
+		x = clampInt(x, 1-w.Width, vp.Dx()-1)
+		y = clampInt(y, 0, vp.Dy()-1)
+	}
+	if x == w.X && y == w.Y {
+		return false
+	}
+	w.X, w.Y = x, y
+	return true
+}
+
+// resize sizes w to width x height cells, no smaller than its minimum
+// size. It reports whether the size changed.
+func (w *Window) resize(width, height int) bool {
+	w.Lock()
+	defer w.Unlock()
+	if width < w.MinWidth {
+		width = w.MinWidth
+	}
+	if height < w.MinHeight {
+		height = w.MinHeight
+	}
+	if width == w.Width && height == w.Height {
+		return false
+	}
+	w.Width, w.Height = width, height
+	return true
+}
+
+func clampInt(n, lo, hi int) int {
+	if n > hi {
+		n = hi
+	}
+	if n < lo {
+		n = lo
+	}
+	return n
+}
+
+// WindowManager stacks Windows, the last one being on top and active. It
+// is a Bufferer drawing them bottom first, e.g. over Body as a member of
+// its FreeLayer. It does not listen to events on its own: feed it the
+// mouse events and the keys, and render it with what is below it again
+// whenever they change the layout.
+/*
+  wm := ui.NewWindowManager(ui.NewWindow("Inspector", list))
+  ui.Body.AddFree(wm)
+  ui.Handle("/sys/mouse", func(e ui.Event) {
+      if wm.HandleMouse(e.Data.(ui.EvtMouse)) {
+          ui.Render(ui.Body)
+      }
+  })
+*/
+type WindowManager struct {
+	sync.RWMutex
+	Windows []*Window // bottom first
+
+	drag     *Window // the window being dragged, if any
+	resizing bool    // the drag resizes rather than moves
+	grab     int     // column of the title bar held by a move
+}
+
+// NewWindowManager returns a *WindowManager stacking ws, the last on top.
+func NewWindowManager(ws ...*Window) *WindowManager {
+	wm := &WindowManager{}
+	wm.Add(ws...)
+	return wm
+}
+
+// Add puts ws on top of the stack, in the order given.
+func (wm *WindowManager) Add(ws ...*Window) {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.Windows = append(wm.Windows, ws...)
+	wm.activate()
+}
+
+// Remove takes w out of the stack.
+func (wm *WindowManager) Remove(w *Window) {
+	wm.Lock()
+	defer wm.Unlock()
+	if i := wm.index(w); i >= 0 {
+		wm.Windows = append(wm.Windows[:i:i], wm.Windows[i+1:]...)
+		w.Blur()
+		unregisterHit(&w.Block)
+		if wm.drag == w {
+			wm.drag = nil
+		}
+		wm.activate()
+	}
+}
+
+// Top returns the active window, nil if there is none.
+func (wm *WindowManager) Top() *Window {
+	wm.RLock()
+	defer wm.RUnlock()
+	if len(wm.Windows) == 0 {
+		return nil
+	}
+	return wm.Windows[len(wm.Windows)-1]
+}
+
+// Raise puts w on top of the stack. It reports whether the stack changed.
+func (wm *WindowManager) Raise(w *Window) bool {
+	wm.Lock()
+	defer wm.Unlock()
+	return wm.raise(w)
+}
+
+func (wm *WindowManager) raise(w *Window) bool {
+	i := wm.index(w)
+	if i < 0 || i == len(wm.Windows)-1 {
+		return false
+	}
+	wm.Windows = append(append(wm.Windows[:i:i], wm.Windows[i+1:]...), w)
+	wm.activate()
+	return true
+}
+
+// Lower puts w at the bottom of the stack. It reports whether the stack
+// changed.
+func (wm *WindowManager) Lower(w *Window) bool {
+	wm.Lock()
+	defer wm.Unlock()
+	i := wm.index(w)
+	if i <= 0 {
+		return false
+	}
+	wm.Windows = append([]*Window{w}, append(wm.Windows[:i:i], wm.Windows[i+1:]...)...)
+	wm.activate()
+	return true
+}
+
+// Cycle raises the bottom window, so that repeated calls activate every
+// window in turn.
+func (wm *WindowManager) Cycle() bool {
+	wm.Lock()
+	defer wm.Unlock()
+	if len(wm.Windows) < 2 {
+		return false
+	}
+	return wm.raise(wm.Windows[0])
+}
+
+// WindowAt returns the topmost window covering the terminal cell (x, y),
+// or nil.
+func (wm *WindowManager) WindowAt(x, y int) *Window {
+	wm.RLock()
+	defer wm.RUnlock()
+	return wm.at(x, y)
+}
+
+func (wm *WindowManager) at(x, y int) *Window {
+	p := image.Pt(x, y)
+	for i := len(wm.Windows) - 1; i >= 0; i-- {
+		w := wm.Windows[i]
+		w.RLock()
+		visible := w.Visible
+		w.RUnlock()
+		if visible && p.In(w.Bounds()) {
+			return w
+		}
+	}
+	return nil
+}
+
+func (wm *WindowManager) index(w *Window) int {
+	for i, v := range wm.Windows {
+		if v == w {
+			return i
+		}
+	}
+	return -1
+}
+
+// activate focuses the window on top and blurs the others.
+func (wm *WindowManager) activate() {
+	for i, w := range wm.Windows {
+		if i == len(wm.Windows)-1 {
+			w.Focus()
+		} else {
+			w.Blur()
+		}
+	}
+}
+
+// HandleMouse raises the window pressed, then moves it while its title bar
+// is dragged with the left button or resizes it while its bottom right
+// corner is. It returns true if the layout changed.
+func (wm *WindowManager) HandleMouse(m EvtMouse) bool {
+	wm.Lock()
+	defer wm.Unlock()
+
+	switch {
+	case m.Press == "MouseRelease":
+		wm.drag = nil
+	case isButton(m.Press) && !m.Drag:
+		wm.drag = nil
+		w := wm.at(m.X, m.Y)
+		if w == nil {
+			return false
+		}
+		raised := wm.raise(w)
+		r := w.Bounds()
+		w.RLock()
+		movable, resizable := w.Movable, w.Resizable
+		w.RUnlock()
+		switch {
+		case m.Press != "MouseLeft":
+		case resizable && m.X == r.Max.X-1 && m.Y == r.Max.Y-1:
+			wm.drag, wm.resizing = w, true
+		case movable && m.Y == r.Min.Y:
+			wm.drag, wm.resizing, wm.grab = w, false, m.X-r.Min.X
+		}
+		return raised
+	case m.Press == "MouseLeft" && wm.drag != nil:
+		w := wm.drag
+		r := w.Bounds()
+		if wm.resizing {
+			return w.resize(m.X-r.Min.X+1, m.Y-r.Min.Y+1)
+		}
+		o := viewportOrigin()
+		return w.place(m.X-o.X-wm.grab, m.Y-o.Y)
+	}
+	return false
+}
+
+// HandleKey moves the active window by a cell with Alt and the arrow keys,
+// and resizes it with Alt and H, J, K or L, i.e. shifted vi keys: H and L
+// narrow and widen it, K and J shorten and heighten it. It reports whether
+// key was consumed.
+func (wm *WindowManager) HandleKey(key string) bool {
+	w := wm.Top()
+	if w == nil {
+		return false
+	}
+	w.RLock()
+	x, y, width, height := w.X, w.Y, w.Width, w.Height
+	movable, resizable := w.Movable, w.Resizable
+	w.RUnlock()
+	ox, oy := x, y
+
+	switch key {
+	case "M-" + KeyArrowUp:
+		y--
+	case "M-" + KeyArrowDown:
+		y++
+	case "M-" + KeyArrowLeft:
+		x--
+	case "M-" + KeyArrowRight:
+		x++
+	case "M-H":
+		width--
+	case "M-L":
+		width++
+	case "M-K":
+		height--
+	case "M-J":
+		height++
+	default:
+		return false
+	}
+	if x != ox || y != oy {
+		if movable {
+			w.place(x, y)
+		}
+	} else if resizable {
+		w.resize(width, height)
+	}
+	return true
+}
+
+// Buffer implements Bufferer interface.
+func (wm *WindowManager) Buffer() Buffer {
+	wm.RLock()
+	defer wm.RUnlock()
+	bufs := make([]Buffer, len(wm.Windows))
+	for i, w := range wm.Windows {
+		bufs[i] = bufferOf(w)
+	}
+	return compose(bufs...)
+}
@@ -0,0 +1,81 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import "testing"
+
+func TestWindowManager(t *testing.T) {
+	_, done := useFakeBackend(40, 20)
+	defer done()
+
+	a := NewWindow("a", NewPar("1"))
+	b := NewWindow("b", NewPar("2"))
+	a.X, a.Y, a.Width, a.Height = 0, 0, 10, 5
+	b.X, b.Y, b.Width, b.Height = 5, 2, 10, 5
+	wm := NewWindowManager(a, b)
+	if wm.Top() != b || !b.Focused() || a.Focused() {
+		t.Fatal("expected the last window on top and active")
+	}
+
+	buf := wm.Buffer()
+	if c := buf.At(7, 4); c.Ch != '2' {
+		t.Errorf("expected the content of b laid out in it, got %q", c.Ch)
+	}
+
+	// a press on a raises it, dragging its title bar moves it
+	if !wm.HandleMouse(EvtMouse{X: 2, Y: 0, Press: "MouseLeft"}) || wm.Top() != a {
+		t.Fatal("expected a raised")
+	}
+	wm.HandleMouse(EvtMouse{X: 12, Y: 6, Press: "MouseLeft", Drag: true})
+	wm.HandleMouse(EvtMouse{X: 12, Y: 6, Press: "MouseRelease"})
+	if a.X != 10 || a.Y != 6 {
+		t.Errorf("expected a moved to (10,6), got (%d,%d)", a.X, a.Y)
+	}
+	if wm.HandleMouse(EvtMouse{X: 20, Y: 9, Press: "MouseLeft", Drag: true}) {
+		t.Error("expected the drag over after the release")
+	}
+
+	// dragging the bottom right corner resizes, down to the minimum size
+	wm.HandleMouse(EvtMouse{X: 19, Y: 10, Press: "MouseLeft"})
+	wm.HandleMouse(EvtMouse{X: 11, Y: 7, Press: "MouseLeft", Drag: true})
+	if a.Width != a.MinWidth || a.Height != a.MinHeight {
+		t.Errorf("expected a at its minimum size, got %dx%d", a.Width, a.Height)
+	}
+
+	if !wm.HandleKey("M-"+KeyArrowLeft) || a.X != 9 {
+		t.Errorf("expected a moved left, got x=%d", a.X)
+	}
+	if !wm.HandleKey("M-J") || a.Height != a.MinHeight+1 {
+		t.Errorf("expected a heightened, got %d", a.Height)
+	}
+	if !wm.Cycle() || wm.Top() != b || !wm.Lower(b) || wm.Top() != a {
+		t.Error("expected the stack reordered")
+	}
+	wm.Remove(a)
+	if wm.Top() != b || !b.Focused() {
+		t.Error("expected b active once a is removed")
+	}
+}
+
+func TestWindowMoveUnanchors(t *testing.T) {
+	_, done := useFakeBackend(40, 20)
+	defer done()
+
+	w := NewWindow("w", NewPar("1"))
+	w.Width, w.Height = 10, 4
+	wm := NewWindowManager(w)
+	Center(w)
+	if w.X != 15 || w.Y != 8 {
+		t.Fatalf("expected w centered, got (%d,%d)", w.X, w.Y)
+	}
+
+	// once moved by hand it stays put on resize
+	wm.HandleKey("M-" + KeyArrowRight)
+	termWidth, termHeight = 60, 30
+	realignAnchors()
+	if w.X != 16 || w.Y != 8 {
+		t.Errorf("expected w left where it was moved, got (%d,%d)", w.X, w.Y)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2016 Zack Guo <gizak@icloud.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+// Wrap modes of the lines of text wider than a Par, a List item or a Table
+// cell.
+const (
+	WrapNone = "none" // the line is cut and ends with …
+	WrapChar = "char" // the line breaks at the last cell that fits
+	WrapWord = "word" // the line breaks at spaces, only words wider than it are cut
+)
+
+// wrapLines breaks cs into display lines of width w at '\n' and as mode
+// says. ends[i] tells whether line i ends a paragraph rather than being
+// broken for the width. With hyphenate, words cut by WrapWord end with '-'.
+func wrapLines(cs []Cell, w int, mode string, hyphenate bool) (lines [][]Cell, ends []bool) {
+	if w <= 0 {
+		return nil, nil
+	}
+	for _, para := range splitCells(cs, '\n') {
+		var ls [][]Cell
+		switch mode {
+		case WrapNone:
+			ls = [][]Cell{fitCells(para, w)}
+		case WrapWord:
+			ls = wrapWords(para, w, hyphenate)
+		default:
+			ls = breakLines(para, w, false)
+		}
+		for i, l := range ls {
+			lines = append(lines, l)
+			ends = append(ends, i == len(ls)-1)
+		}
+	}
+	return lines, ends
+}
+
+// splitCells splits cs at every sep cell, there is one part at least.
+func splitCells(cs []Cell, sep rune) [][]Cell {
+	parts := [][]Cell{}
+	i := 0
+	for j, c := range cs {
+		if c.Ch == sep {
+			parts = append(parts, cs[i:j])
+			i = j + 1
+		}
+	}
+	return append(parts, cs[i:])
+}
+
+// wrapWords breaks the paragraph cs into lines of width w at spaces, which
+// are dropped at the breaks. Words wider than w are cut, ending with '-'
+// if hyphenate is set.
+func wrapWords(cs []Cell, w int, hyphenate bool) [][]Cell {
+	lines := [][]Cell{}
+	line, spaces := []Cell{}, []Cell{}
+	x := 0
+	for i := 0; i < len(cs); {
+		j := i
+		for j < len(cs) && isSpace(cs[j]) == isSpace(cs[i]) {
+			j++
+		}
+		run := cs[i:j]
+		i = j
+		if isSpace(run[0]) {
+			spaces = run
+			continue
+		}
+
+		sw, ww := cellsWidth(spaces), cellsWidth(run)
+		if x+sw+ww <= w {
+			line = append(append(line, spaces...), run...)
+			x += sw + ww
+			spaces = nil
+			continue
+		}
+		if x > 0 {
+			lines = append(lines, line)
+			line, x = []Cell{}, 0
+		}
+		spaces = nil
+		// cut the words wider than the line
+		for cellsWidth(run) > w {
+			room := w
+			if hyphenate && w > 1 {
+				room--
+			}
+			n, cw := 0, 0
+			for n < len(run) && cw+run[n].Width() <= room {
+				cw += run[n].Width()
+				n++
+			}
+			if n == 0 {
+				n = 1
+			}
+			cut := append([]Cell{}, run[:n]...)
+			if hyphenate && w > 1 {
+				cut = append(cut, Cell{Ch: '-', Fg: run[n-1].Fg, Bg: run[n-1].Bg})
+			}
+			lines = append(lines, cut)
+			run = run[n:]
+		}
+		line = append(line, run...)
+		x = cellsWidth(run)
+	}
+	return append(lines, line)
+}
+
+// alignLine returns the offset to draw line at within a width of w as a
+// says and the cells to draw. AlignJustify widens the gaps between the
+// words of a line not ending a paragraph, see wrapLines, to fill w.
+func alignLine(line []Cell, w int, a Align, end bool) (int, []Cell) {
+	if a&(AlignRight|AlignCenterHorizontal|AlignJustify) == 0 {
+		return 0, line
+	}
+	for len(line) > 0 && isSpace(line[len(line)-1]) {
+		line = line[:len(line)-1]
+	}
+	pad := w - cellsWidth(line)
+	if pad <= 0 {
+		return 0, line
+	}
+	switch {
+	case a&AlignJustify == AlignJustify:
+		if end {
+			return 0, line
+		}
+		return 0, justifyLine(line, pad)
+	case a&AlignCenterHorizontal == AlignCenterHorizontal:
+		return pad / 2, line
+	default:
+		return pad, line
+	}
+}
+
+// justifyLine spreads pad more spaces over the gaps between the words of
+// line, the leftmost gaps getting the remainder.
+func justifyLine(line []Cell, pad int) []Cell {
+	lead := 0
+	for lead < len(line) && isSpace(line[lead]) {
+		lead++
+	}
+	gaps := []int{}
+	for i := lead + 1; i < len(line); i++ {
+		if isSpace(line[i]) && !isSpace(line[i-1]) {
+			gaps = append(gaps, i)
+		}
+	}
+	if len(gaps) == 0 {
+		return line
+	}
+
+	cs := make([]Cell, 0, len(line)+pad)
+	g := 0
+	for i, c := range line {
+		if g < len(gaps) && gaps[g] == i {
+			n := pad / len(gaps)
+			if g < pad%len(gaps) {
+				n++
+			}
+			for ; n > 0; n-- {
+				cs = append(cs, Cell{' ', c.Fg, c.Bg})
+			}
+			g++
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}